@@ -2,203 +2,1997 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 
 	"personal-cockpit/database"
+	"personal-cockpit/events"
+	"personal-cockpit/handlers"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
 	"personal-cockpit/models"
 	"personal-cockpit/services"
+	"personal-cockpit/timezone"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
 	ctx context.Context
 	db  *database.DB
+	bus *events.Bus
+
+	// Services
+	taskService        *services.TaskService
+	noteService        *services.NoteService
+	eventService       *services.EventService
+	categoryService    *services.CategoryService
+	notebookService    *services.NotebookService
+	templateService    *services.TemplateService
+	journalService     *services.JournalService
+	financeService     *services.FinanceService
+	attachmentService  *services.AttachmentService
+	settingsService    *services.SettingsService
+	webhookService     *services.WebhookService
+	caldavService      *services.CalDAVService
+	noteFolderService  *services.NoteFolderService
+	changeNotifier     *services.ChangeNotifier
+	quickAddService    *services.QuickAddService
+	taskImportService  *services.TaskImportService
+	noteImportService  *services.NoteImportService
+	schedulingService  *services.SchedulingService
+	diagnosticsService *services.DiagnosticsService
+
+	// API HTTP local (opcional)
+	apiServer *handlers.APIServer
+
+	// Estado da inicialização. Se o banco não abrir, startupErr guarda o
+	// motivo e o app fica em modo de recuperação.
+	mu          sync.RWMutex
+	dbPath      string
+	logDir      string
+	startupErr  error
+	stopSession context.CancelFunc
+
+	// Operações longas em andamento, canceláveis pelo ID escolhido no frontend
+	opsMu      sync.Mutex
+	operations map[string]context.CancelFunc
+}
+
+func NewApp() *App {
+	return &App{}
+}
+
+func (a *App) startup(ctx context.Context) {
+	a.ctx = ctx
+
+	// Idioma do sistema até ler o escolhido nas configurações
+	i18n.SetLocale(i18n.FromEnvironment())
+
+	dataDir, err := database.GetAppDataDir()
+	if err != nil {
+		slog.Error(i18n.T("Erro ao obter diretório de dados:"), "err", err)
+		a.setStartupError(err)
+		return
+	}
+	a.logDir = logging.Dir(dataDir)
+	a.dbPath = filepath.Join(dataDir, "cockpit.db")
+
+	if err := a.initialize(); err == nil {
+		slog.Info(i18n.T("App inicializado com sucesso!"), "version", GetFullVersion())
+	}
+}
+
+// initialize abre o banco em a.dbPath e monta os services. Se o banco não
+// abrir, o app fica em modo de recuperação (GetStartupStatus) e os métodos
+// passam a retornar UnavailableError em vez de usar services nulos.
+func (a *App) initialize() error {
+	db, err := database.Open(a.dbPath)
+	if err != nil {
+		slog.Error(i18n.T("Erro ao inicializar banco:"), "err", err, "path", a.dbPath)
+		a.setStartupError(err)
+		return err
+	}
+
+	session, stopSession := context.WithCancel(a.ctx)
+	a.stopSession = stopSession
+
+	// Barramento de eventos de domínio: o frontend recebe todos para
+	// atualizar as telas e os webhooks repassam para fora
+	bus := events.NewBus()
+	bus.Subscribe(a.forwardToFrontend)
+
+	// Inicializar todos os services. Os arquivos anexados ficam ao lado do banco.
+	conn := db.GetConnection()
+	taskService := services.NewTaskService(conn, bus)
+	noteService := services.NewNoteService(conn, bus, taskService)
+	eventService := services.NewEventService(conn, bus)
+	categoryService := services.NewCategoryService(conn, bus)
+	notebookService := services.NewNotebookService(conn, bus)
+	templateService := services.NewTemplateService(conn, bus, noteService, taskService)
+	journalService := services.NewJournalService(conn, bus, taskService, eventService)
+	financeService := services.NewFinanceService(conn, bus)
+	attachmentService := services.NewAttachmentService(conn, bus, filepath.Dir(a.dbPath))
+	quickAddService := services.NewQuickAddService(taskService, eventService, categoryService)
+	taskImportService := services.NewTaskImportService(taskService, categoryService)
+	noteImportService := services.NewNoteImportService(noteService, notebookService, categoryService, attachmentService)
+	schedulingService := services.NewSchedulingService(conn, bus, eventService)
+	settingsService := services.NewSettingsService(conn)
+	diagnosticsService := services.NewDiagnosticsService(conn, a.logDir, GetFullVersion())
+	a.loadLocale(settingsService)
+	if name, _ := settingsService.Get(a.ctx, timezone.SettingTimezone, ""); name != "" {
+		if _, err := timezone.Set(name); err != nil {
+			slog.Warn("fuso horário salvo ignorado", "timezone", name, "err", err)
+		}
+	}
+	if level, _ := settingsService.Get(a.ctx, logging.SettingLevel, ""); level != "" {
+		logging.SetLevel(level)
+	}
+	webhookService := services.NewWebhookService(conn, bus)
+	webhookService.Start()
+	secrets := services.NewSecretBox(filepath.Dir(a.dbPath))
+	caldavService := services.NewCalDAVService(conn, bus, settingsService, secrets, eventService, taskService)
+	caldavService.Start()
+	noteFolderService := services.NewNoteFolderService(conn, bus, settingsService, noteService, categoryService)
+	if err := noteFolderService.Start(); err != nil {
+		slog.Error(i18n.T("Erro ao iniciar sincronização da pasta de notas:"), "err", err)
+	}
+
+	// Avisos "tasks:changed", "notes:changed"... para as telas se atualizarem,
+	// inclusive quando a CLI ou outro programa grava no banco
+	changeNotifier := services.NewChangeNotifier(conn, bus, a.emitToFrontend)
+	if err := changeNotifier.Start(); err != nil {
+		slog.Error(i18n.T("Erro ao iniciar observador de mudanças:"), "err", err)
+	}
+
+	apiServer := handlers.NewAPIServer(taskService, noteService, eventService, categoryService, settingsService)
+
+	// Os services só ficam visíveis junto com o banco, sob o mesmo lock de
+	// ready(), para RetryStartup não trocá-los enquanto outro método os usa
+	a.mu.Lock()
+	a.bus = bus
+	a.taskService = taskService
+	a.noteService = noteService
+	a.eventService = eventService
+	a.categoryService = categoryService
+	a.notebookService = notebookService
+	a.templateService = templateService
+	a.journalService = journalService
+	a.financeService = financeService
+	a.attachmentService = attachmentService
+	a.settingsService = settingsService
+	a.webhookService = webhookService
+	a.caldavService = caldavService
+	a.noteFolderService = noteFolderService
+	a.changeNotifier = changeNotifier
+	a.quickAddService = quickAddService
+	a.taskImportService = taskImportService
+	a.noteImportService = noteImportService
+	a.schedulingService = schedulingService
+	a.diagnosticsService = diagnosticsService
+	a.apiServer = apiServer
+	a.db = db
+	a.startupErr = nil
+	a.mu.Unlock()
+
+	go a.watchStartingEvents(session)
+
+	if enabled, _ := a.settingsService.GetBool(a.ctx, handlers.SettingAPIEnabled, false); enabled {
+		if _, err := a.startAPIServer(); err != nil {
+			slog.Error(i18n.T("Erro ao iniciar API local:"), "err", err)
+		}
+	}
+
+	// Gerar lançamentos recorrentes vencidos desde a última execução
+	if created, err := a.financeService.ProcessRecurring(a.ctx, timezone.In(time.Now())); err != nil {
+		slog.Error(i18n.T("Erro ao processar recorrências:"), "err", err)
+	} else if created > 0 {
+		slog.Info(i18n.Sprintf("%d lançamentos recorrentes gerados", created))
+	}
+
+	// Cópia diária, usada pelo modo de recuperação
+	if autoBackup, _ := a.settingsService.GetBool(a.ctx, "auto_backup", true); autoBackup {
+		if err := db.BackupIfDue(); err != nil {
+			slog.Error(i18n.T("Erro ao copiar banco:"), "err", err)
+		}
+	}
+
+	return nil
+}
+
+// teardown para o que initialize iniciou e fecha o banco
+func (a *App) teardown() {
+	a.mu.Lock()
+	db := a.db
+	a.db = nil
+	a.mu.Unlock()
+
+	a.cancelOperations()
+	if a.stopSession != nil {
+		a.stopSession()
+		a.stopSession = nil
+	}
+	if a.changeNotifier != nil {
+		a.changeNotifier.Stop()
+	}
+	if a.webhookService != nil {
+		a.webhookService.Stop()
+	}
+	if a.caldavService != nil {
+		a.caldavService.Stop()
+	}
+	if a.noteFolderService != nil {
+		a.noteFolderService.Stop()
+	}
+	if a.apiServer != nil {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		a.apiServer.Stop(stopCtx)
+		cancel()
+	}
+	if db != nil {
+		db.Close()
+	}
+}
+
+func (a *App) setStartupError(err error) {
+	a.mu.Lock()
+	a.startupErr = err
+	a.mu.Unlock()
+}
+
+// ready é a guarda dos métodos que usam o banco: em modo de recuperação
+// retorna UnavailableError
+func (a *App) ready() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.startupErr != nil {
+		return &services.UnavailableError{Cause: a.startupErr}
+	}
+	if a.db == nil {
+		return &services.UnavailableError{Cause: errors.New(i18n.T("o banco não está aberto"))}
+	}
+	return nil
+}
+
+// forwardToFrontend repassa o evento de domínio para o React, tanto no canal
+// geral "domain:event" quanto no nome do tipo (ex.: "task.completed")
+func (a *App) forwardToFrontend(event events.Event) {
+	a.emitToFrontend("domain:event", event)
+	a.emitToFrontend(string(event.Type), event)
+
+	// Lembrete já com o texto no idioma do app
+	if starting, ok := event.Data.(models.Event); ok && event.Type == events.EventStarting {
+		a.emitToFrontend("event:reminder", models.Reminder{
+			EventID:   starting.ID,
+			Title:     starting.Title,
+			Message:   services.ReminderText(starting),
+			StartDate: starting.StartDate,
+		})
+	}
+}
+
+// loadLocale aplica o idioma salvo nas configurações; sem escolha, vale o do
+// sistema e, por último, o português
+func (a *App) loadLocale(settings *services.SettingsService) {
+	locale, err := settings.Get(a.ctx, i18n.SettingLocale, "")
+	if err != nil || locale == "" {
+		locale = i18n.FromEnvironment()
+	}
+	i18n.SetLocale(locale)
+}
+
+// formatError entrega os erros ao React como JSON ({code, message, fields...}),
+// para a interface destacar os campos e traduzir as mensagens
+func formatError(err error) any {
+	payload := services.NewErrorPayload(err)
+	if payload.Code == services.ErrorCodeInternal {
+		slog.Error("erro retornado ao frontend", "err", err)
+	}
+	return payload
+}
+
+// emitToFrontend envia um evento do runtime do Wails para o React
+func (a *App) emitToFrontend(name string, data interface{}) {
+	runtime.EventsEmit(a.ctx, name, data)
+}
+
+// watchStartingEvents publica "event.starting" quando um evento da agenda começa
+func (a *App) watchStartingEvents(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := a.eventService.PublishStartingEvents(a.ctx, last, now); err != nil {
+				slog.Error(i18n.T("Erro ao verificar eventos:"), "err", err)
+			}
+			last = now
+		}
+	}
+}
+
+func (a *App) domReady(ctx context.Context) {
+
+}
+
+func (a *App) beforeClose(ctx context.Context) (prevent bool) {
+	a.teardown()
+	return false
+}
+
+func (a *App) shutdown(ctx context.Context) {
+
+}
+
+// ═══════════════════════════════════════════════════════════
+// STARTUP METHODS
+// ═══════════════════════════════════════════════════════════
+
+// GetStartupStatus informa se o banco abriu. Se não abriu, traz o erro, as
+// cópias disponíveis e as ações de recuperação que o frontend pode oferecer.
+func (a *App) GetStartupStatus() *models.StartupStatus {
+	a.mu.RLock()
+	startupErr := a.startupErr
+	a.mu.RUnlock()
+
+	status := &models.StartupStatus{Ready: startupErr == nil, DatabasePath: a.dbPath}
+	if startupErr == nil {
+		return status
+	}
+
+	status.Error = startupErr.Error()
+	status.Actions = []string{models.StartupActionRetry, models.StartupActionOpenDatabase}
+
+	if a.dbPath == "" {
+		return status
+	}
+
+	if backups, err := database.ListBackups(a.dbPath); err == nil && len(backups) > 0 {
+		status.Backups = backups
+		status.Actions = append(status.Actions, models.StartupActionRestoreBackup)
+	}
+	status.Actions = append(status.Actions, models.StartupActionReset)
+
+	return status
+}
+
+// RetryStartup tenta abrir o banco de novo (ex.: depois de liberar espaço ou
+// fechar outro programa que travava o arquivo)
+func (a *App) RetryStartup() *models.StartupStatus {
+	a.teardown()
+	a.initialize()
+	return a.GetStartupStatus()
+}
+
+// RestoreBackup troca o banco pela cópia informada (nome de
+// StartupStatus.Backups). O banco atual é preservado como cockpit.db.broken-*.
+func (a *App) RestoreBackup(name string) (*models.StartupStatus, error) {
+	a.teardown()
+
+	if err := database.RestoreBackup(a.dbPath, name); err != nil {
+		a.initialize()
+		return nil, err
+	}
+
+	a.initialize()
+	return a.GetStartupStatus(), nil
+}
+
+// OpenDatabaseFile passa a usar outro arquivo de banco até fechar o app. Com
+// path vazio, abre o seletor de arquivos.
+func (a *App) OpenDatabaseFile(path string) (*models.StartupStatus, error) {
+	if path == "" {
+		selected, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   i18n.T("Abrir banco de dados"),
+			Filters: []runtime.FileFilter{{DisplayName: "SQLite (*.db)", Pattern: "*.db;*.sqlite"}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if selected == "" {
+			return a.GetStartupStatus(), nil
+		}
+		path = selected
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, i18n.Errorf("erro ao abrir banco: %w", err)
+	}
+
+	a.teardown()
+	a.dbPath = path
+	a.initialize()
+	return a.GetStartupStatus(), nil
+}
+
+// ResetDatabase começa com um banco vazio. Nada é apagado: o banco atual é
+// renomeado para cockpit.db.broken-* e pode ser recuperado manualmente.
+func (a *App) ResetDatabase() (*models.StartupStatus, error) {
+	a.teardown()
+
+	if _, err := database.MoveAside(a.dbPath); err != nil {
+		a.initialize()
+		return nil, err
+	}
+
+	a.initialize()
+	return a.GetStartupStatus(), nil
+}
+
+// GetBackups lista as cópias do banco, da mais recente para a mais antiga
+func (a *App) GetBackups() ([]models.Backup, error) {
+	return database.ListBackups(a.dbPath)
+}
+
+// CreateBackup faz uma cópia do banco agora e retorna o caminho
+func (a *App) CreateBackup() (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return a.db.Backup("manual")
+}
+
+// ═══════════════════════════════════════════════════════════
+// OPERATION METHODS
+// ═══════════════════════════════════════════════════════════
+
+// progressInterval limita os eventos de andamento enviados ao frontend
+const progressInterval = 100 * time.Millisecond
+
+// startOperation cria o contexto de uma operação longa, cancelável por
+// CancelOperation(id) e com o andamento enviado em "operation:progress". Com
+// id vazio, a operação usa o contexto do app e não informa andamento. finish
+// deve ser chamado ao terminar.
+func (a *App) startOperation(id string) (context.Context, func(), error) {
+	if id == "" {
+		return a.ctx, func() {}, nil
+	}
+
+	a.opsMu.Lock()
+	defer a.opsMu.Unlock()
+
+	if _, running := a.operations[id]; running {
+		return nil, nil, &services.ConflictError{Entity: "operation", Field: "id", Message: i18n.Sprintf("a operação %s já está em andamento", id)}
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+
+	var last time.Time
+	ctx = services.WithProgress(ctx, func(done, total int) {
+		if done < total && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		a.emitToFrontend("operation:progress", models.OperationProgress{ID: id, Done: done, Total: total})
+	})
+
+	if a.operations == nil {
+		a.operations = make(map[string]context.CancelFunc)
+	}
+	a.operations[id] = cancel
+
+	finish := func() {
+		a.opsMu.Lock()
+		delete(a.operations, id)
+		a.opsMu.Unlock()
+		cancel()
+	}
+
+	return ctx, finish, nil
+}
+
+// cancelOperations cancela tudo o que está em andamento (ao fechar o banco)
+func (a *App) cancelOperations() {
+	a.opsMu.Lock()
+	defer a.opsMu.Unlock()
+
+	for _, cancel := range a.operations {
+		cancel()
+	}
+}
+
+// CancelOperation cancela a operação em andamento com esse ID. A operação
+// retorna um erro com código "canceled"; o que ela gravava em transação é
+// desfeito. Retorna false se a operação já terminou ou não existe.
+func (a *App) CancelOperation(id string) bool {
+	a.opsMu.Lock()
+	defer a.opsMu.Unlock()
+
+	cancel, running := a.operations[id]
+	if running {
+		cancel()
+	}
+
+	return running
+}
+
+// GetRunningOperations lista os IDs das operações em andamento
+func (a *App) GetRunningOperations() []string {
+	a.opsMu.Lock()
+	defer a.opsMu.Unlock()
+
+	ids := make([]string, 0, len(a.operations))
+	for id := range a.operations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// ═══════════════════════════════════════════════════════════
+// TASK METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateTask(task models.Task) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.taskService.CreateTask(a.ctx, task)
+}
+
+func (a *App) GetAllTasks() ([]models.Task, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskService.GetAllTasks(a.ctx)
+}
+
+func (a *App) GetTaskByID(id int) (*models.Task, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskService.GetTaskByID(a.ctx, id)
+}
+
+func (a *App) UpdateTask(task models.Task) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.taskService.UpdateTask(a.ctx, task)
+}
+
+func (a *App) DeleteTask(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.taskService.DeleteTask(a.ctx, id)
+}
+
+func (a *App) ToggleTaskStatus(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.taskService.ToggleTaskStatus(a.ctx, id)
+}
+
+func (a *App) GetPendingTasks() ([]models.Task, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskService.GetPendingTasks(a.ctx)
+}
+
+func (a *App) GetCompletedTasks() ([]models.Task, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskService.GetCompletedTasks(a.ctx)
+}
+
+func (a *App) GetTasksByFilter(filter models.TaskFilter) ([]models.Task, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskService.GetTasksByFilter(a.ctx, filter)
+}
+
+// ═══════════════════════════════════════════════════════════
+// QUICK ADD METHODS
+// ═══════════════════════════════════════════════════════════
+
+// PreviewQuickAdd mostra como o texto será interpretado, sem gravar
+func (a *App) PreviewQuickAdd(text string) (*models.QuickAddResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.quickAddService.Preview(a.ctx, text, timezone.In(time.Now()))
+}
+
+// QuickAdd cria a tarefa ou o evento descrito no texto
+func (a *App) QuickAdd(text string) (*models.QuickAddResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.quickAddService.Create(a.ctx, text, timezone.In(time.Now()))
+}
+
+// ═══════════════════════════════════════════════════════════
+// TASK IMPORT METHODS
+// ═══════════════════════════════════════════════════════════
+
+// GetTaskImportFormats lista os formatos aceitos (todotxt, todoist, trello, csv)
+func (a *App) GetTaskImportFormats() ([]string, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskImportService.Formats(), nil
+}
+
+// SelectTaskImportFile abre o seletor de arquivos e retorna o caminho
+// escolhido (vazio se cancelado), usado na prévia e na importação
+func (a *App) SelectTaskImportFile() (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: i18n.T("Importar tarefas"),
+		Filters: []runtime.FileFilter{
+			{DisplayName: i18n.T("Listas de tarefas (*.txt, *.json, *.csv)"), Pattern: "*.txt;*.json;*.csv;*.tsv"},
+		},
+	})
+}
+
+// PreviewTaskImportCSV lê as colunas do CSV para a escolha do mapeamento
+func (a *App) PreviewTaskImportCSV(path string) (*models.CSVPreview, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.taskImportService.PreviewCSV(a.ctx, path)
+}
+
+// ImportTasks importa as tarefas do arquivo; com options.DryRun só retorna a
+// prévia. O andamento sai em "operation:progress" com operationID.
+func (a *App) ImportTasks(path string, options models.TaskImportOptions, operationID string) (*models.TaskImportReport, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.taskImportService.ImportFile(ctx, path, options)
+}
+
+// ═══════════════════════════════════════════════════════════
+// SCHEDULING METHODS
+// ═══════════════════════════════════════════════════════════
+
+// ScheduleTask reserva um bloco na agenda para a tarefa, a partir de start.
+// minutes = 0 usa a duração estimada da tarefa.
+func (a *App) ScheduleTask(taskID int, start time.Time, minutes int) (*models.EventSaveResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.schedulingService.ScheduleTask(a.ctx, taskID, start, minutes)
+}
+
+// AutoSchedule encaixa as tarefas nos horários livres entre from e to, dentro
+// do expediente configurado
+func (a *App) AutoSchedule(taskIDs []int, from time.Time, to time.Time) (*models.AutoScheduleResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	hours, err := a.settingsService.GetWorkingHours(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.schedulingService.AutoSchedule(a.ctx, taskIDs, from, to, hours)
+}
+
+// ═══════════════════════════════════════════════════════════
+// NOTE METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateNote(note models.Note) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.noteService.CreateNote(a.ctx, note)
+}
+
+func (a *App) GetAllNotes() ([]models.Note, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.noteService.GetAllNotes(a.ctx)
+}
+
+func (a *App) GetNoteByID(id int) (*models.Note, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.noteService.GetNoteByID(a.ctx, id)
+}
+
+func (a *App) UpdateNote(note models.Note) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.noteService.UpdateNote(a.ctx, note)
+}
+
+func (a *App) DeleteNote(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.noteService.DeleteNote(a.ctx, id)
+}
+
+func (a *App) ToggleNoteFavorite(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.noteService.ToggleFavorite(a.ctx, id)
+}
+
+func (a *App) GetFavoriteNotes() ([]models.Note, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.noteService.GetFavoriteNotes(a.ctx)
+}
+
+// SearchNotes busca notas. Com operationID, a busca anterior pode ser
+// cancelada (CancelOperation) quando o usuário continua digitando.
+func (a *App) SearchNotes(query string, operationID string) ([]models.Note, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.noteService.SearchNotes(ctx, query)
+}
+
+func (a *App) ExtractTasksFromNote(noteID int) ([]models.Task, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.noteService.ExtractTasks(a.ctx, noteID)
+}
+
+// ═══════════════════════════════════════════════════════════
+// NOTEBOOK METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateNotebook(notebook models.Notebook) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.notebookService.CreateNotebook(a.ctx, notebook)
+}
+
+func (a *App) GetNotebookTree() ([]models.Notebook, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.notebookService.GetNotebookTree(a.ctx)
+}
+
+func (a *App) RenameNotebook(id int, name string) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.notebookService.RenameNotebook(a.ctx, id, name)
+}
+
+// MoveNotebook move o caderno para dentro de parentID (0 = raiz)
+func (a *App) MoveNotebook(id int, parentID int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.notebookService.MoveNotebook(a.ctx, id, optionalID(parentID))
+}
+
+// MoveNote move a nota para o caderno notebookID (0 = sem caderno)
+func (a *App) MoveNote(noteID int, notebookID int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.notebookService.MoveNote(a.ctx, noteID, optionalID(notebookID))
+}
+
+// DeleteNotebook remove o caderno; com deleteContents apaga subcadernos e notas,
+// senão eles passam para o caderno pai
+func (a *App) DeleteNotebook(id int, deleteContents bool) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.notebookService.DeleteNotebook(a.ctx, id, deleteContents)
+}
+
+func (a *App) GetFavoriteNotesInNotebook(notebookID int) ([]models.Note, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.noteService.GetFavoriteNotesInNotebook(a.ctx, notebookID)
+}
+
+func (a *App) SearchNotesInNotebook(notebookID int, query string, operationID string) ([]models.Note, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.noteService.SearchNotesInNotebook(ctx, notebookID, query)
+}
+
+// ═══════════════════════════════════════════════════════════
+// NOTE FOLDER METHODS
+// ═══════════════════════════════════════════════════════════
+
+// GetNotesFolder retorna a pasta sincronizada com as notas (vazia = desligada)
+func (a *App) GetNotesFolder() (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return a.noteFolderService.GetFolder(a.ctx)
+}
+
+// SetNotesFolder escolhe a pasta onde cada nota vira um arquivo .md; vazia
+// desliga a sincronização
+func (a *App) SetNotesFolder(folder string) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.noteFolderService.SetFolder(a.ctx, folder)
+}
+
+// SyncNotesFolder sincroniza a pasta agora (ela também é observada sozinha)
+func (a *App) SyncNotesFolder() (*models.NoteFolderSyncResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.noteFolderService.SyncNow(a.ctx)
+}
+
+// ═══════════════════════════════════════════════════════════
+// NOTE IMPORT METHODS
+// ═══════════════════════════════════════════════════════════
+
+// SelectNoteImportFile abre o seletor de exportações do Evernote (.enex) e
+// retorna o caminho escolhido (vazio se cancelado)
+func (a *App) SelectNoteImportFile() (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: i18n.T("Importar notas do Evernote"),
+		Filters: []runtime.FileFilter{
+			{DisplayName: i18n.T("Exportação do Evernote (*.enex)"), Pattern: "*.enex"},
+		},
+	})
+}
+
+// SelectNoteImportFolder abre o seletor de pastas, para importar um cofre do
+// Obsidian ou uma pasta de arquivos .md/.txt
+func (a *App) SelectNoteImportFolder() (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: i18n.T("Importar pasta de notas"),
+	})
+}
+
+// ImportNotes importa as notas do arquivo .enex ou da pasta; com
+// options.DryRun só retorna a prévia. O andamento sai em "operation:progress"
+// com operationID.
+func (a *App) ImportNotes(path string, options models.NoteImportOptions, operationID string) (*models.NoteImportReport, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.noteImportService.Import(ctx, path, options)
+}
+
+// ═══════════════════════════════════════════════════════════
+// TEMPLATE METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateTemplate(template models.Template) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.templateService.CreateTemplate(a.ctx, template)
+}
+
+func (a *App) GetAllTemplates() ([]models.Template, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.templateService.GetAllTemplates(a.ctx)
+}
+
+func (a *App) GetTemplateByID(id int) (*models.Template, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.templateService.GetTemplateByID(a.ctx, id)
+}
+
+func (a *App) UpdateTemplate(template models.Template) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.templateService.UpdateTemplate(a.ctx, template)
+}
+
+func (a *App) DeleteTemplate(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.templateService.DeleteTemplate(a.ctx, id)
+}
+
+// GetTemplatePrompts lista os valores que o usuário precisa informar ({{prompt:...}})
+func (a *App) GetTemplatePrompts(id int) ([]string, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.templateService.GetTemplatePrompts(a.ctx, id)
+}
+
+// RenderTemplate mostra o resultado do modelo sem salvar nada
+func (a *App) RenderTemplate(templateID int, vars map[string]string) (*models.RenderedTemplate, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.templateService.RenderTemplate(a.ctx, templateID, vars)
+}
+
+func (a *App) CreateNoteFromTemplate(templateID int, vars map[string]string) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.templateService.CreateNoteFromTemplate(a.ctx, templateID, vars)
+}
+
+func (a *App) CreateTasksFromTemplate(templateID int, vars map[string]string) ([]int64, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.templateService.CreateTasksFromTemplate(a.ctx, templateID, vars)
+}
+
+// ═══════════════════════════════════════════════════════════
+// EVENT METHODS
+// ═══════════════════════════════════════════════════════════
+
+// CreateEvent grava o evento; os conflitos de horário voltam como aviso
+func (a *App) CreateEvent(event models.Event) (*models.EventSaveResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.CreateEvent(a.ctx, event)
+}
+
+func (a *App) GetAllEvents() ([]models.Event, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.GetAllEvents(a.ctx)
+}
+
+func (a *App) GetEventByID(id int) (*models.Event, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.GetEventByID(a.ctx, id)
+}
+
+// UpdateEvent altera o evento; os conflitos de horário voltam como aviso
+func (a *App) UpdateEvent(event models.Event) (*models.EventSaveResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.UpdateEvent(a.ctx, event)
+}
+
+func (a *App) DeleteEvent(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.eventService.DeleteEvent(a.ctx, id)
+}
+
+func (a *App) GetTodayEvents() ([]models.Event, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.GetTodayEvents(a.ctx)
+}
+
+func (a *App) GetUpcomingEvents() ([]models.Event, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.GetUpcomingEvents(a.ctx)
+}
+
+// FindEventConflicts lista os eventos no mesmo horário, para avisar no
+// formulário antes de salvar
+func (a *App) FindEventConflicts(event models.Event) ([]models.Event, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.eventService.FindConflicts(a.ctx, event)
+}
+
+// FindFreeSlots lista os intervalos livres de pelo menos durationMinutes
+// entre from e to, dentro do expediente configurado
+func (a *App) FindFreeSlots(from time.Time, to time.Time, durationMinutes int) ([]models.TimeSlot, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	hours, err := a.settingsService.GetWorkingHours(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.eventService.FindFreeSlots(a.ctx, from, to, time.Duration(durationMinutes)*time.Minute, hours)
+}
+
+func (a *App) GetWorkingHours() (models.WorkingHours, error) {
+	if err := a.ready(); err != nil {
+		return models.WorkingHours{}, err
+	}
+
+	return a.settingsService.GetWorkingHours(a.ctx)
+}
+
+func (a *App) SetWorkingHours(hours models.WorkingHours) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.settingsService.SetWorkingHours(a.ctx, hours)
+}
+
+// GetCalendarView monta a grade "month", "week" ou "day" em torno de
+// anchorDate (AAAA-MM-DD; vazio é hoje), com a semana começando no dia
+// configurado em week_start
+func (a *App) GetCalendarView(kind string, anchorDate string) (*models.CalendarView, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	weekStart, err := a.settingsService.Get(a.ctx, services.SettingWeekStart, services.DefaultWeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.eventService.GetCalendarView(a.ctx, kind, anchorDate, weekStart)
+}
+
+// ═══════════════════════════════════════════════════════════
+// JOURNAL METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) GetTodayJournalEntry() (*models.JournalEntry, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.journalService.GetOrCreateToday(a.ctx)
+}
+
+// GetJournalEntry retorna (ou cria) a entrada da data AAAA-MM-DD
+func (a *App) GetJournalEntry(date string) (*models.JournalEntry, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.journalService.GetOrCreateEntry(a.ctx, date)
+}
+
+func (a *App) GetPreviousJournalEntry(date string) (*models.JournalEntry, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.journalService.GetPreviousEntry(a.ctx, date)
+}
+
+func (a *App) GetNextJournalEntry(date string) (*models.JournalEntry, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.journalService.GetNextEntry(a.ctx, date)
+}
+
+func (a *App) UpdateJournalEntry(entry models.JournalEntry) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.journalService.UpdateEntry(a.ctx, entry)
+}
+
+func (a *App) DeleteJournalEntry(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.journalService.DeleteEntry(a.ctx, id)
+}
+
+func (a *App) SearchJournalEntries(filter models.JournalFilter, operationID string) ([]models.JournalEntry, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.journalService.SearchEntries(ctx, filter)
+}
+
+func (a *App) GetMoodTrend(startDate, endDate string) ([]models.MoodPoint, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.journalService.GetMoodTrend(a.ctx, startDate, endDate)
+}
+
+// ═══════════════════════════════════════════════════════════
+// CATEGORY METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateCategory(category models.Category) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.categoryService.CreateCategory(a.ctx, category)
+}
+
+func (a *App) GetAllCategories() ([]models.Category, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.categoryService.GetAllCategories(a.ctx)
+}
+
+func (a *App) GetCategoryByID(id int) (*models.Category, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.categoryService.GetCategoryByID(a.ctx, id)
+}
+
+func (a *App) UpdateCategory(category models.Category) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.categoryService.UpdateCategory(a.ctx, category)
+}
+
+func (a *App) DeleteCategory(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.categoryService.DeleteCategory(a.ctx, id)
+}
+
+func (a *App) GetTaskCategories() ([]models.Category, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.categoryService.GetTaskCategories(a.ctx)
+}
+
+func (a *App) GetNoteCategories() ([]models.Category, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.categoryService.GetNoteCategories(a.ctx)
+}
+
+func (a *App) GetFinanceCategories() ([]models.Category, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.categoryService.GetFinanceCategories(a.ctx)
+}
+
+// ═══════════════════════════════════════════════════════════
+// FINANCE METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateAccount(account models.Account) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.financeService.CreateAccount(a.ctx, account)
+}
+
+func (a *App) GetAllAccounts() ([]models.Account, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.financeService.GetAllAccounts(a.ctx)
+}
+
+func (a *App) UpdateAccount(account models.Account) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
 
-	// Services
-	taskService     *services.TaskService
-	noteService     *services.NoteService
-	eventService    *services.EventService
-	categoryService *services.CategoryService
+	return a.financeService.UpdateAccount(a.ctx, account)
 }
 
-func NewApp() *App {
-	return &App{}
+func (a *App) DeleteAccount(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.financeService.DeleteAccount(a.ctx, id)
 }
 
-func (a *App) startup(ctx context.Context) {
-	a.ctx = ctx
+// CreateTransaction registra a transação e retorna os orçamentos do mês em alerta
+func (a *App) CreateTransaction(transaction models.Transaction) (*models.TransactionResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
 
-	// Inicializar banco de dados
-	db, err := database.NewDB()
+	id, err := a.financeService.CreateTransaction(a.ctx, transaction)
 	if err != nil {
-		fmt.Println("❌ Erro ao inicializar banco:", err)
-		return
+		return nil, err
 	}
-	a.db = db
 
-	// Inicializar todos os services
-	conn := db.GetConnection()
-	a.taskService = services.NewTaskService(conn)
-	a.noteService = services.NewNoteService(conn)
-	a.eventService = services.NewEventService(conn)
-	a.categoryService = services.NewCategoryService(conn)
+	date, _ := time.Parse("2006-01-02", transaction.Date)
+	alerts, err := a.financeService.GetBudgetAlerts(a.ctx, date.Year(), int(date.Month()))
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Println("✅ App inicializado com sucesso!")
+	return &models.TransactionResult{ID: id, Alerts: alerts}, nil
 }
 
-func (a App) domReady(ctx context.Context) {
+func (a *App) GetTransactions(filter models.TransactionFilter) ([]models.Transaction, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
 
+	return a.financeService.GetTransactions(a.ctx, filter)
 }
 
-func (a *App) beforeClose(ctx context.Context) (prevent bool) {
-	if a.db != nil {
-		a.db.Close()
+func (a *App) UpdateTransaction(transaction models.Transaction) error {
+	if err := a.ready(); err != nil {
+		return err
 	}
-	return false
+
+	return a.financeService.UpdateTransaction(a.ctx, transaction)
 }
 
-func (a *App) shutdown(ctx context.Context) {
+func (a *App) DeleteTransaction(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.financeService.DeleteTransaction(a.ctx, id)
+}
+
+func (a *App) GetMonthlySummary(year, month int) (*models.MonthlySummary, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.financeService.GetMonthlySummary(a.ctx, year, month)
+}
+
+func (a *App) SetBudget(budget models.Budget) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.financeService.SetBudget(a.ctx, budget)
+}
+
+func (a *App) DeleteBudget(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.financeService.DeleteBudget(a.ctx, id)
+}
+
+func (a *App) GetBudgetStatus(year, month int) ([]models.BudgetStatus, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.financeService.GetBudgetStatus(a.ctx, year, month)
+}
+
+func (a *App) CreateRecurringTransaction(recurring models.RecurringTransaction) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.financeService.CreateRecurring(a.ctx, recurring)
+}
+
+func (a *App) GetRecurringTransactions() ([]models.RecurringTransaction, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.financeService.GetAllRecurring(a.ctx)
+}
+
+func (a *App) DeleteRecurringTransaction(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.financeService.DeleteRecurring(a.ctx, id)
+}
+
+// ImportStatement abre o seletor de arquivos e importa um extrato OFX ou CSV
+// para a conta. O andamento sai em "operation:progress" com operationID.
+func (a *App) ImportStatement(accountID int, operationID string) (*models.ImportResult, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: i18n.T("Importar extrato"),
+		Filters: []runtime.FileFilter{
+			{DisplayName: i18n.T("Extratos (*.ofx, *.qfx, *.csv)"), Pattern: "*.ofx;*.qfx;*.csv"},
+		},
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
 
+	return a.financeService.ImportStatement(ctx, accountID, path)
 }
 
 // ═══════════════════════════════════════════════════════════
-// TASK METHODS
+// ATTACHMENT METHODS
 // ═══════════════════════════════════════════════════════════
 
-func (a *App) CreateTask(task models.Task) (int64, error) {
-	return a.taskService.CreateTask(task)
+// AddAttachment anexa um arquivo do disco. entityType vazio e entityID 0
+// adicionam o arquivo ao gerenciador sem vínculo.
+func (a *App) AddAttachment(path string, entityType string, entityID int, tags []string) (*models.Attachment, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.attachmentService.AddFromPath(a.ctx, path, entityType, optionalID(entityID), tags)
 }
 
-func (a *App) GetAllTasks() ([]models.Task, error) {
-	return a.taskService.GetAllTasks()
+// SelectAndAddAttachments abre o seletor de arquivos do sistema e anexa os
+// escolhidos, informando o andamento por arquivo com operationID
+func (a *App) SelectAndAddAttachments(entityType string, entityID int, operationID string) ([]models.Attachment, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	paths, err := runtime.OpenMultipleFilesDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: i18n.T("Selecionar arquivos"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	var attachments []models.Attachment
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return attachments, err
+		}
+
+		attachment, err := a.attachmentService.AddFromPath(ctx, path, entityType, optionalID(entityID), nil)
+		if err != nil {
+			return attachments, err
+		}
+		attachments = append(attachments, *attachment)
+		services.ReportProgress(ctx, i+1, len(paths))
+	}
+
+	return attachments, nil
 }
 
-func (a *App) GetTaskByID(id int) (*models.Task, error) {
-	return a.taskService.GetTaskByID(id)
+func (a *App) GetAllAttachments() ([]models.Attachment, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.attachmentService.GetAllAttachments(a.ctx)
 }
 
-func (a *App) UpdateTask(task models.Task) error {
-	return a.taskService.UpdateTask(task)
+func (a *App) GetAttachmentsFor(entityType string, entityID int) ([]models.Attachment, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.attachmentService.GetAttachmentsFor(a.ctx, entityType, entityID)
 }
 
-func (a *App) DeleteTask(id int) error {
-	return a.taskService.DeleteTask(id)
+func (a *App) SearchAttachments(filter models.AttachmentFilter, operationID string) ([]models.Attachment, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.attachmentService.SearchAttachments(ctx, filter)
 }
 
-func (a *App) ToggleTaskStatus(id int) error {
-	return a.taskService.ToggleTaskStatus(id)
+func (a *App) UpdateAttachment(attachment models.Attachment) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.attachmentService.UpdateAttachment(a.ctx, attachment)
 }
 
-func (a *App) GetPendingTasks() ([]models.Task, error) {
-	return a.taskService.GetPendingTasks()
+func (a *App) DeleteAttachment(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.attachmentService.DeleteAttachment(a.ctx, id)
 }
 
-func (a *App) GetCompletedTasks() ([]models.Task, error) {
-	return a.taskService.GetCompletedTasks()
+func (a *App) GetAttachmentThumbnail(id int) (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return a.attachmentService.GetThumbnail(a.ctx, id)
 }
 
-func (a *App) GetTasksByFilter(filter models.TaskFilter) ([]models.Task, error) {
-	return a.taskService.GetTasksByFilter(filter)
+func (a *App) OpenAttachment(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.attachmentService.OpenAttachment(a.ctx, id)
 }
 
 // ═══════════════════════════════════════════════════════════
-// NOTE METHODS
+// SETTINGS METHODS
 // ═══════════════════════════════════════════════════════════
 
-func (a *App) CreateNote(note models.Note) (int64, error) {
-	return a.noteService.CreateNote(note)
+func (a *App) GetSetting(key string, defaultValue string) (string, error) {
+	if err := a.ready(); err != nil {
+		return "", err
+	}
+
+	return a.settingsService.Get(a.ctx, key, defaultValue)
 }
 
-func (a *App) GetAllNotes() ([]models.Note, error) {
-	return a.noteService.GetAllNotes()
+func (a *App) SetSetting(key string, value string) error {
+	switch key {
+	case i18n.SettingLocale:
+		_, err := a.SetLocale(value)
+		return err
+	case logging.SettingLevel:
+		_, err := a.SetLogLevel(value)
+		return err
+	case timezone.SettingTimezone:
+		_, err := a.SetTimezone(value)
+		return err
+	case services.SettingWeekStart:
+		if err := services.ValidateWeekStart(value); err != nil {
+			return err
+		}
+	}
+
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.settingsService.Set(a.ctx, key, value)
 }
 
-func (a *App) GetNoteByID(id int) (*models.Note, error) {
-	return a.noteService.GetNoteByID(id)
+func (a *App) GetAllSettings() ([]models.Setting, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.settingsService.GetAll(a.ctx)
 }
 
-func (a *App) UpdateNote(note models.Note) error {
-	return a.noteService.UpdateNote(note)
+// ═══════════════════════════════════════════════════════════
+// LOCALE METHODS
+// ═══════════════════════════════════════════════════════════
+
+// SetLocale troca o idioma de erros, exportações, lembretes e datas e salva
+// a escolha. Retorna o idioma aplicado ("en-GB" vira "en-US").
+func (a *App) SetLocale(locale string) (string, error) {
+	applied := i18n.SetLocale(locale)
+
+	// Em modo de recuperação vale só até fechar o app
+	if a.ready() != nil {
+		return applied, nil
+	}
+
+	if err := a.settingsService.Set(a.ctx, i18n.SettingLocale, applied); err != nil {
+		return "", err
+	}
+	return applied, nil
 }
 
-func (a *App) DeleteNote(id int) error {
-	return a.noteService.DeleteNote(id)
+// GetLocale retorna o idioma em uso
+func (a *App) GetLocale() string {
+	return i18n.Locale()
 }
 
-func (a *App) ToggleNoteFavorite(id int) error {
-	return a.noteService.ToggleFavorite(id)
+// GetSupportedLocales lista os idiomas disponíveis
+func (a *App) GetSupportedLocales() []string {
+	return i18n.Supported()
 }
 
-func (a *App) GetFavoriteNotes() ([]models.Note, error) {
-	return a.noteService.GetFavoriteNotes()
+// SetTimezone troca o fuso usado em "hoje", nos eventos de dia inteiro e nas
+// datas devolvidas ao frontend, e salva a escolha. Vazio ou "Local" segue o
+// fuso do sistema. Retorna o nome aplicado.
+func (a *App) SetTimezone(name string) (string, error) {
+	applied, err := timezone.Set(name)
+	if err != nil {
+		return "", &services.ValidationError{Fields: []services.FieldError{
+			{Field: "timezone", Code: services.CodeInvalid, Message: err.Error()},
+		}}
+	}
+
+	// Em modo de recuperação vale só até fechar o app
+	if a.ready() != nil {
+		return applied, nil
+	}
+
+	saved := applied
+	if saved == timezone.System {
+		saved = ""
+	}
+	if err := a.settingsService.Set(a.ctx, timezone.SettingTimezone, saved); err != nil {
+		return "", err
+	}
+	return applied, nil
 }
 
-func (a *App) SearchNotes(query string) ([]models.Note, error) {
-	return a.noteService.SearchNotes(query)
+// GetTimezone retorna o fuso em uso ("Local" se for o do sistema)
+func (a *App) GetTimezone() string {
+	return timezone.Name()
 }
 
 // ═══════════════════════════════════════════════════════════
-// EVENT METHODS
+// API METHODS
 // ═══════════════════════════════════════════════════════════
 
-func (a *App) CreateEvent(event models.Event) (int64, error) {
-	return a.eventService.CreateEvent(event)
+// GetAPIStatus informa se a API local está ativa, o endereço e o token
+func (a *App) GetAPIStatus() (*models.APIStatus, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	enabled, err := a.settingsService.GetBool(a.ctx, handlers.SettingAPIEnabled, false)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := a.settingsService.Get(a.ctx, handlers.SettingAPIToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.APIStatus{
+		Enabled: enabled,
+		Address: a.apiServer.Address(),
+		Token:   token,
+	}
+	status.Running = status.Address != ""
+	if status.Running {
+		status.OpenAPIURL = "http://" + status.Address + "/openapi.json"
+	}
+
+	return status, nil
 }
 
-func (a *App) GetAllEvents() ([]models.Event, error) {
-	return a.eventService.GetAllEvents()
+// StartAPIServer ativa a API local (também nas próximas inicializações)
+func (a *App) StartAPIServer() (*models.APIStatus, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	if _, err := a.startAPIServer(); err != nil {
+		return nil, err
+	}
+
+	if err := a.settingsService.Set(a.ctx, handlers.SettingAPIEnabled, "true"); err != nil {
+		return nil, err
+	}
+
+	return a.GetAPIStatus()
 }
 
-func (a *App) GetEventByID(id int) (*models.Event, error) {
-	return a.eventService.GetEventByID(id)
+// StopAPIServer desativa a API local
+func (a *App) StopAPIServer() error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	if err := a.settingsService.Set(a.ctx, handlers.SettingAPIEnabled, "false"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+	defer cancel()
+
+	return a.apiServer.Stop(ctx)
+}
+
+// SetAPIPort muda a porta da API, reiniciando o servidor se estiver rodando
+func (a *App) SetAPIPort(port int) (*models.APIStatus, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	if port < 1024 || port > 65535 {
+		return nil, fmt.Errorf("porta deve estar entre 1024 e 65535")
+	}
+
+	if err := a.settingsService.Set(a.ctx, handlers.SettingAPIPort, fmt.Sprint(port)); err != nil {
+		return nil, err
+	}
+
+	if a.apiServer.Address() != "" {
+		ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+		defer cancel()
+
+		if err := a.apiServer.Stop(ctx); err != nil {
+			return nil, err
+		}
+		if _, err := a.startAPIServer(); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.GetAPIStatus()
 }
 
-func (a *App) UpdateEvent(event models.Event) error {
-	return a.eventService.UpdateEvent(event)
+// RegenerateAPIToken gera um novo token; o anterior deixa de funcionar na hora
+func (a *App) RegenerateAPIToken() (*models.APIStatus, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	token, err := handlers.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.settingsService.Set(a.ctx, handlers.SettingAPIToken, token); err != nil {
+		return nil, err
+	}
+
+	return a.GetAPIStatus()
 }
 
-func (a *App) DeleteEvent(id int) error {
-	return a.eventService.DeleteEvent(id)
+// startAPIServer sobe o servidor na porta configurada, gerando o token na primeira vez
+func (a *App) startAPIServer() (string, error) {
+	token, err := a.settingsService.Get(a.ctx, handlers.SettingAPIToken, "")
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		if token, err = handlers.GenerateToken(); err != nil {
+			return "", err
+		}
+		if err := a.settingsService.Set(a.ctx, handlers.SettingAPIToken, token); err != nil {
+			return "", err
+		}
+	}
+
+	port, err := a.settingsService.GetInt(a.ctx, handlers.SettingAPIPort, handlers.DefaultAPIPort)
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := a.apiServer.Start(port)
+	if err != nil {
+		return "", err
+	}
+
+	slog.Info("API local iniciada", "addr", "http://"+addr)
+	return addr, nil
 }
 
-func (a *App) GetTodayEvents() ([]models.Event, error) {
-	return a.eventService.GetTodayEvents()
+// ═══════════════════════════════════════════════════════════
+// WEBHOOK METHODS
+// ═══════════════════════════════════════════════════════════
+
+func (a *App) CreateWebhook(webhook models.Webhook) (int64, error) {
+	if err := a.ready(); err != nil {
+		return 0, err
+	}
+
+	return a.webhookService.CreateWebhook(a.ctx, webhook)
 }
 
-func (a *App) GetUpcomingEvents() ([]models.Event, error) {
-	return a.eventService.GetUpcomingEvents()
+func (a *App) GetAllWebhooks() ([]models.Webhook, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.webhookService.GetAllWebhooks(a.ctx)
+}
+
+func (a *App) UpdateWebhook(webhook models.Webhook) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.webhookService.UpdateWebhook(a.ctx, webhook)
+}
+
+func (a *App) DeleteWebhook(id int) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.webhookService.DeleteWebhook(a.ctx, id)
+}
+
+func (a *App) GetWebhookDeliveries(webhookID int, limit int) ([]models.WebhookDelivery, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.webhookService.GetDeliveries(a.ctx, webhookID, limit)
+}
+
+// TestWebhook envia um "webhook.ping" e retorna o resultado da entrega
+func (a *App) TestWebhook(id int) (*models.WebhookDelivery, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.webhookService.TestWebhook(a.ctx, id)
+}
+
+// GetEventTypes lista os tipos de evento disponíveis para os filtros
+func (a *App) GetEventTypes() []string {
+	types := make([]string, len(events.AllTypes))
+	for i, eventType := range events.AllTypes {
+		types[i] = string(eventType)
+	}
+	return types
 }
 
 // ═══════════════════════════════════════════════════════════
-// CATEGORY METHODS
+// CALDAV METHODS
 // ═══════════════════════════════════════════════════════════
 
-func (a *App) CreateCategory(category models.Category) (int64, error) {
-	return a.categoryService.CreateCategory(category)
+// GetCalDAVConfig retorna a conta CalDAV (sem a senha)
+func (a *App) GetCalDAVConfig() (*models.CalDAVConfig, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.caldavService.GetConfig(a.ctx)
 }
 
-func (a *App) GetAllCategories() ([]models.Category, error) {
-	return a.categoryService.GetAllCategories()
+// SetCalDAVConfig grava a conta CalDAV; senha vazia mantém a salva
+func (a *App) SetCalDAVConfig(config models.CalDAVConfig) error {
+	if err := a.ready(); err != nil {
+		return err
+	}
+
+	return a.caldavService.SetConfig(a.ctx, config)
 }
 
-func (a *App) GetCategoryByID(id int) (*models.Category, error) {
-	return a.categoryService.GetCategoryByID(id)
+// SyncCalDAV sincroniza agora com o servidor CalDAV. Com operationID, pode
+// ser cancelada por CancelOperation.
+func (a *App) SyncCalDAV(operationID string) (*models.CalDAVSyncLog, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	return a.caldavService.SyncNow(ctx)
 }
 
-func (a *App) UpdateCategory(category models.Category) error {
-	return a.categoryService.UpdateCategory(category)
+// GetCalDAVSyncLog lista as últimas sincronizações
+func (a *App) GetCalDAVSyncLog(limit int) ([]models.CalDAVSyncLog, error) {
+	if err := a.ready(); err != nil {
+		return nil, err
+	}
+
+	return a.caldavService.GetSyncLog(a.ctx, limit)
 }
 
-func (a *App) DeleteCategory(id int) error {
-	return a.categoryService.DeleteCategory(id)
+// ═══════════════════════════════════════════════════════════
+// DIAGNOSTICS METHODS
+// ═══════════════════════════════════════════════════════════
+
+// ExportDiagnostics gera o zip para anexar em relatos de bug (logs, versões,
+// estatísticas do banco e integrity_check) e retorna o caminho do arquivo. Em
+// modo de recuperação o zip traz só os logs e as versões.
+func (a *App) ExportDiagnostics(operationID string) (string, error) {
+	diagnostics := a.diagnosticsService
+	if a.ready() != nil {
+		diagnostics = services.NewDiagnosticsService(nil, a.logDir, GetFullVersion())
+	}
+
+	ctx, finish, err := a.startOperation(operationID)
+	if err != nil {
+		return "", err
+	}
+	defer finish()
+
+	dataDir, err := database.GetAppDataDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(dataDir, "diagnostics")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "diagnostics-"+time.Now().Format("20060102-150405")+".zip")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := diagnostics.Export(ctx, file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	slog.Info("diagnóstico exportado", "path", path)
+	return path, nil
 }
 
-func (a *App) GetTaskCategories() ([]models.Category, error) {
-	return a.categoryService.GetTaskCategories()
+// SetLogLevel troca o nível do log (debug, info, warn, error) e salva a escolha
+func (a *App) SetLogLevel(level string) (string, error) {
+	applied := logging.SetLevel(level)
+
+	// Em modo de recuperação vale só até fechar o app
+	if a.ready() != nil {
+		return applied, nil
+	}
+
+	if err := a.settingsService.Set(a.ctx, logging.SettingLevel, applied); err != nil {
+		return "", err
+	}
+	return applied, nil
 }
 
-func (a *App) GetNoteCategories() ([]models.Category, error) {
-	return a.categoryService.GetNoteCategories()
+func (a *App) GetLogLevel() string {
+	return logging.Level()
 }
 
 // ═══════════════════════════════════════════════════════════
@@ -214,7 +2008,7 @@ func (a *App) GetAppInfo() map[string]string {
 }
 
 func (a *App) Greet(name string) string {
-	return fmt.Sprintf("Olá %s! Bem-vindo ao Personal Cockpit v%s", name, GetFullVersion())
+	return i18n.Sprintf("Olá %s! Bem-vindo ao Personal Cockpit v%s", name, GetFullVersion())
 }
 
 // ═══════════════════════════════════════════════════════════
@@ -251,6 +2045,14 @@ func (a *App) SetTheme(theme string) error {
 	// Isso precisa ser configurado no wails.json
 	// Mas podemos retornar sucesso para o frontend saber que o tema foi "aplicado"
 
-	fmt.Printf("✅ Tema alterado para: %s (bg: %x)\n", theme, selectedTheme.bg)
+	slog.Info("tema alterado", "theme", theme, "bg", fmt.Sprintf("%x", selectedTheme.bg))
 	return nil
 }
+
+// optionalID converte o ID 0 enviado pelo frontend em "sem vínculo"
+func optionalID(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,296 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+)
+
+// ErrPreconditionFailed indica que o item mudou no servidor desde o ETag
+// conhecido (HTTP 412): outro cliente gravou antes
+var ErrPreconditionFailed = errors.New("item alterado no servidor")
+
+// ErrNotFound indica que o item não existe mais no servidor
+var ErrNotFound = errors.New("item não encontrado no servidor")
+
+// Client fala WebDAV com uma coleção de calendário, com autenticação básica
+type Client struct {
+	http     *http.Client
+	username string
+	password string
+}
+
+// NewClient cria o cliente com as credenciais da conta
+func NewClient(username, password string) *Client {
+	return &Client{
+		http:     &http.Client{Timeout: 30 * time.Second},
+		username: username,
+		password: password,
+	}
+}
+
+// Resource é um item da coleção com seu ETag
+type Resource struct {
+	Href string
+	ETag string
+}
+
+// CollectionState retorna o sync-token da coleção (RFC 6578) ou, se o
+// servidor não tiver, o getctag. Se o valor não mudou desde a última
+// sincronização, nada mudou no servidor. Vazio quando o servidor não expõe
+// nenhum dos dois.
+func (c *Client) CollectionState(ctx context.Context, collection string) (string, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">
+  <d:prop><d:sync-token/><cs:getctag/></d:prop>
+</d:propfind>`
+
+	responses, err := c.propfind(ctx, collection, "0", body)
+	if err != nil {
+		return "", err
+	}
+
+	for _, response := range responses {
+		for _, propstat := range response.Propstats {
+			if !propstat.ok() {
+				continue
+			}
+			if token := strings.TrimSpace(propstat.Prop.SyncToken); token != "" {
+				return token, nil
+			}
+			if ctag := strings.TrimSpace(propstat.Prop.CTag); ctag != "" {
+				return ctag, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// List retorna os itens da coleção (sem subcoleções) com seus ETags
+func (c *Client) List(ctx context.Context, collection string) ([]Resource, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:">
+  <d:prop><d:getetag/><d:resourcetype/></d:prop>
+</d:propfind>`
+
+	responses, err := c.propfind(ctx, collection, "1", body)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, response := range responses {
+		href, err := base.Parse(response.Href)
+		if err != nil {
+			continue
+		}
+		if strings.TrimSuffix(href.Path, "/") == strings.TrimSuffix(base.Path, "/") {
+			continue
+		}
+
+		for _, propstat := range response.Propstats {
+			if !propstat.ok() || propstat.Prop.ResourceType.Collection != nil {
+				continue
+			}
+			resources = append(resources, Resource{Href: href.String(), ETag: propstat.Prop.ETag})
+		}
+	}
+
+	return resources, nil
+}
+
+// Get baixa um item e retorna o conteúdo e o ETag
+func (c *Client) Get(ctx context.Context, href string) (string, string, error) {
+	resp, err := c.do(ctx, http.MethodGet, href, nil, nil)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return "", "", err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(data), resp.Header.Get("ETag"), nil
+}
+
+// Put grava um item. Com etag vazio o item precisa ser novo (If-None-Match);
+// com etag, ele precisa estar naquela versão (If-Match). force grava sem
+// condição nenhuma, para a política "local vence". Retorna o novo ETag,
+// buscado com PROPFIND se o servidor não o devolver no PUT.
+func (c *Client) Put(ctx context.Context, href, data, etag string, force bool) (string, error) {
+	headers := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	switch {
+	case force:
+	case etag == "":
+		headers["If-None-Match"] = "*"
+	default:
+		headers["If-Match"] = etag
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, href, strings.NewReader(data), headers)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if err := checkStatus(resp); err != nil {
+		return "", err
+	}
+
+	if newETag := resp.Header.Get("ETag"); newETag != "" {
+		return newETag, nil
+	}
+	return c.etag(ctx, href)
+}
+
+// Delete apaga um item, se ele ainda estiver na versão etag (vazio apaga
+// sem condição). Um item que já não existe não é erro.
+func (c *Client) Delete(ctx context.Context, href, etag string) error {
+	var headers map[string]string
+	if etag != "" {
+		headers = map[string]string{"If-Match": etag}
+	}
+
+	resp, err := c.do(ctx, http.MethodDelete, href, nil, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if err := checkStatus(resp); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// etag busca o ETag atual de um item
+func (c *Client) etag(ctx context.Context, href string) (string, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/></d:prop></d:propfind>`
+
+	responses, err := c.propfind(ctx, href, "0", body)
+	if err != nil {
+		return "", err
+	}
+	for _, response := range responses {
+		for _, propstat := range response.Propstats {
+			if propstat.ok() && propstat.Prop.ETag != "" {
+				return propstat.Prop.ETag, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// AUXILIARES
+// ═══════════════════════════════════════════════════════════
+
+type multistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"DAV: href"`
+	Propstats []davPropstat `xml:"DAV: propstat"`
+}
+
+type davPropstat struct {
+	Status string  `xml:"DAV: status"`
+	Prop   davProp `xml:"DAV: prop"`
+}
+
+type davProp struct {
+	ETag         string `xml:"DAV: getetag"`
+	SyncToken    string `xml:"DAV: sync-token"`
+	CTag         string `xml:"http://calendarserver.org/ns/ getctag"`
+	ResourceType struct {
+		Collection *struct{} `xml:"DAV: collection"`
+	} `xml:"DAV: resourcetype"`
+}
+
+func (p davPropstat) ok() bool {
+	return p.Status == "" || strings.Contains(p.Status, " 200")
+}
+
+func (c *Client) propfind(ctx context.Context, target, depth, body string) ([]davResponse, error) {
+	headers := map[string]string{
+		"Depth":        depth,
+		"Content-Type": "application/xml; charset=utf-8",
+	}
+
+	resp, err := c.do(ctx, "PROPFIND", target, strings.NewReader(body), headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result multistatus
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&result); err != nil {
+		return nil, i18n.Errorf("resposta PROPFIND inválida: %w", err)
+	}
+
+	return result.Responses, nil
+}
+
+func (c *Client) do(ctx context.Context, method, target string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	return c.http.Do(req)
+}
+
+func checkStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return i18n.Errorf("servidor CalDAV recusou as credenciais (HTTP %d)", resp.StatusCode)
+	default:
+		return i18n.Errorf("servidor CalDAV respondeu %s", resp.Status)
+	}
+}
@@ -0,0 +1,393 @@
+// Package caldav fala com servidores CalDAV (Radicale, Nextcloud, iCloud...):
+// lê e escreve iCalendar (RFC 5545), conversa WebDAV (PROPFIND, GET, PUT,
+// DELETE com ETag) e converte VEVENT/VTODO para eventos e tarefas. A
+// sincronização em si, com o banco, fica em services.CalDAVService.
+package caldav
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/timezone"
+)
+
+// Component é um bloco BEGIN/END do iCalendar (VCALENDAR, VEVENT, VALARM...)
+type Component struct {
+	Name       string
+	Properties []Property
+	Children   []*Component
+}
+
+// Property é uma linha "NOME;PARAM=valor:conteúdo". Value fica como veio,
+// com os escapes de texto; use Text para lê-lo como texto.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Parse lê um objeto iCalendar e retorna o VCALENDAR
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []*Component
+	var root *Component
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, i18n.Errorf("linha %d: %w", i+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			component := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, component)
+			} else if root == nil {
+				root = component
+			}
+			stack = append(stack, component)
+
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, i18n.Errorf("linha %d: END:%s sem BEGIN", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+
+		default:
+			if len(stack) == 0 {
+				return nil, i18n.Errorf("linha %d: propriedade fora de um componente", i+1)
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, i18n.Errorf("iCalendar vazio")
+	}
+	if len(stack) > 0 {
+		return nil, i18n.Errorf("BEGIN:%s sem END", stack[len(stack)-1].Name)
+	}
+
+	return root, nil
+}
+
+// unfold junta as linhas dobradas (continuação começa com espaço ou tab)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseLine separa nome, parâmetros e valor. Os parâmetros podem vir entre
+// aspas e conter ";" ou ":".
+func parseLine(line string) (Property, error) {
+	prop := Property{Params: map[string]string{}}
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, i18n.Errorf("propriedade inválida: %q", line)
+	}
+	prop.Name = strings.ToUpper(line[:i])
+	rest := line[i:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			return prop, i18n.Errorf("parâmetro inválido: %q", line)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				return prop, i18n.Errorf("aspas sem fechar: %q", line)
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return prop, i18n.Errorf("propriedade sem valor: %q", line)
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+		prop.Params[name] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return prop, i18n.Errorf("propriedade sem valor: %q", line)
+	}
+	prop.Value = rest[1:]
+
+	return prop, nil
+}
+
+// Encode escreve o componente com CRLF e linhas dobradas em 75 bytes
+func (c *Component) Encode() string {
+	var b strings.Builder
+	c.encode(&b)
+	return b.String()
+}
+
+func (c *Component) encode(b *strings.Builder) {
+	writeLine(b, "BEGIN:"+c.Name)
+	for _, prop := range c.Properties {
+		var line strings.Builder
+		line.WriteString(prop.Name)
+		for _, name := range sortedParams(prop.Params) {
+			value := prop.Params[name]
+			if strings.ContainsAny(value, ";:,") {
+				value = `"` + value + `"`
+			}
+			line.WriteString(";" + name + "=" + value)
+		}
+		line.WriteString(":" + prop.Value)
+		writeLine(b, line.String())
+	}
+	for _, child := range c.Children {
+		child.encode(b)
+	}
+	writeLine(b, "END:"+c.Name)
+}
+
+// writeLine dobra a linha sem cortar caracteres UTF-8 ao meio
+func writeLine(b *strings.Builder, line string) {
+	const limit = 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+func sortedParams(params map[string]string) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	for i := 1; i < len(names); i++ {
+		for j := i; j > 0 && names[j] < names[j-1]; j-- {
+			names[j], names[j-1] = names[j-1], names[j]
+		}
+	}
+	return names
+}
+
+// ═══════════════════════════════════════════════════════════
+// ACESSO ÀS PROPRIEDADES
+// ═══════════════════════════════════════════════════════════
+
+// Get retorna a primeira propriedade com o nome, ou nil
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// Text retorna o valor da propriedade como texto, sem os escapes
+func (c *Component) Text(name string) string {
+	if prop := c.Get(name); prop != nil {
+		return unescapeText(prop.Value)
+	}
+	return ""
+}
+
+// Add acrescenta uma propriedade com valor já formatado
+func (c *Component) Add(name, value string, params map[string]string) {
+	if params == nil {
+		params = map[string]string{}
+	}
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText acrescenta uma propriedade de texto, escapando o valor
+func (c *Component) AddText(name, value string) {
+	c.Add(name, escapeText(value), nil)
+}
+
+// Child retorna o primeiro componente filho com o nome, ou nil
+func (c *Component) Child(name string) *Component {
+	for _, child := range c.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+func unescapeText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(value[i])
+			}
+			continue
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+// ═══════════════════════════════════════════════════════════
+// DATAS E DURAÇÕES
+// ═══════════════════════════════════════════════════════════
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+// DateTime lê DTSTART, DTEND, DUE... Retorna também se o valor é só a data
+// (VALUE=DATE), que vira a meia-noite do dia no fuso do usuário. Horários
+// com TZID usam aquele fuso; sem TZID nem "Z" (horário "flutuante"), o do
+// usuário.
+func (p *Property) DateTime() (time.Time, bool, error) {
+	value := strings.TrimSpace(p.Value)
+
+	if p.Params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		t, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return time.Time{}, false, i18n.Errorf("data inválida em %s: %q", p.Name, value)
+		}
+		return timezone.Floating(t), true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeLayout+"Z", value)
+		if err != nil {
+			return time.Time{}, false, i18n.Errorf("data inválida em %s: %q", p.Name, value)
+		}
+		return t, false, nil
+	}
+
+	location := timezone.Location()
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			location = loaded
+		}
+	}
+
+	t, err := time.ParseInLocation(dateTimeLayout, value, location)
+	if err != nil {
+		return time.Time{}, false, i18n.Errorf("data inválida em %s: %q", p.Name, value)
+	}
+	return t, false, nil
+}
+
+// FormatDateTime formata o instante em UTC ("20261019T120000Z")
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout) + "Z"
+}
+
+// FormatDate formata só o dia ("20261019"), para VALUE=DATE
+func FormatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// ParseDuration lê uma duração iCalendar ("-PT15M", "P1D", "PT1H30M")
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+
+	if !strings.HasPrefix(value, "P") {
+		return 0, i18n.Errorf("duração inválida: %q", value)
+	}
+	value = value[1:]
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == 'T':
+			inTime = true
+		case c >= '0' && c <= '9':
+			number += string(c)
+		default:
+			n, err := strconv.Atoi(number)
+			unit, ok := units[c]
+			if err != nil || !ok || (c == 'M' && !inTime) {
+				return 0, i18n.Errorf("duração inválida: %q", value)
+			}
+			total += time.Duration(n) * unit
+			number = ""
+		}
+	}
+	if number != "" {
+		return 0, i18n.Errorf("duração inválida: %q", value)
+	}
+
+	return sign * total, nil
+}
+
+// FormatDuration escreve a duração em minutos ("-PT15M", "PT0M")
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	return fmt.Sprintf("%sPT%dM", sign, int(d/time.Minute))
+}
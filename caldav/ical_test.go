@@ -0,0 +1,262 @@
+package caldav
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ics monta um objeto iCalendar com as linhas separadas por CRLF
+func ics(lines ...string) string {
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		fails bool
+	}{
+		{value: "-PT15M", want: -15 * time.Minute},
+		{value: "PT1H30M", want: 90 * time.Minute},
+		{value: "P1D", want: 24 * time.Hour},
+		{value: "P1W", want: 7 * 24 * time.Hour},
+		{value: "+P1DT2H", want: 26 * time.Hour},
+		{value: "PT0S", want: 0},
+		{value: "P1M", fails: true}, // mês não tem duração fixa
+		{value: "PT15", fails: true},
+		{value: "15M", fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDuration(tt.value)
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("ParseDuration(%q) = %v, quero erro", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDuration(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, quero %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"vazio":              "",
+		"END sem BEGIN":      ics("BEGIN:VCALENDAR", "END:VEVENT"),
+		"BEGIN sem END":      ics("BEGIN:VCALENDAR", "BEGIN:VEVENT", "END:VEVENT"),
+		"fora de componente": ics("SUMMARY:solto"),
+		"aspas sem fechar":   ics("BEGIN:VCALENDAR", `X-A;P="abc:1`, "END:VCALENDAR"),
+		"sem valor":          ics("BEGIN:VCALENDAR", "SUMMARY", "END:VCALENDAR"),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(data)); err == nil {
+				t.Errorf("Parse deveria falhar")
+			}
+		})
+	}
+}
+
+func TestParseUnfoldsAndReadsParams(t *testing.T) {
+	data := ics(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		`SUMMARY:Reunião de planejamento\, sala 3\; pauta`,
+		" : orçamento",
+		`ATTENDEE;CN="Silva, Ana";ROLE=REQ-PARTICIPANT:mailto:ana@example.com`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+
+	root, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	vevent := root.Child("VEVENT")
+	if vevent == nil {
+		t.Fatal("VEVENT não encontrado")
+	}
+
+	if got, want := vevent.Text("SUMMARY"), "Reunião de planejamento, sala 3; pauta: orçamento"; got != want {
+		t.Errorf("SUMMARY = %q, quero %q", got, want)
+	}
+
+	attendee := vevent.Get("ATTENDEE")
+	if attendee == nil || attendee.Params["CN"] != "Silva, Ana" || attendee.Params["ROLE"] != "REQ-PARTICIPANT" || attendee.Value != "mailto:ana@example.com" {
+		t.Errorf("ATTENDEE = %+v", attendee)
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	const layout = "2006-01-02 15:04"
+
+	tests := []struct {
+		name     string
+		lines    []string
+		start    string // em UTC, ou só a data nos de dia inteiro
+		end      string
+		allDay   bool
+		reminder int // -1 sem lembrete
+		rrule    string
+	}{
+		{
+			name:     "UTC com alarme",
+			lines:    []string{"DTSTART:20261019T120000Z", "DTEND:20261019T133000Z", "BEGIN:VALARM", "ACTION:DISPLAY", "TRIGGER:-PT15M", "END:VALARM"},
+			start:    "2026-10-19 12:00",
+			end:      "2026-10-19 13:30",
+			reminder: 15,
+		},
+		{
+			name:     "TZID e DURATION",
+			lines:    []string{"DTSTART;TZID=America/Sao_Paulo:20261019T090000", "DURATION:PT45M"},
+			start:    "2026-10-19 12:00",
+			end:      "2026-10-19 12:45",
+			reminder: -1,
+		},
+		{
+			name:     "dia inteiro sem DTEND",
+			lines:    []string{"DTSTART;VALUE=DATE:20261102"},
+			start:    "2026-11-02",
+			end:      "2026-11-03",
+			allDay:   true,
+			reminder: -1,
+		},
+		{
+			name:     "repetição e alarme depois do fim",
+			lines:    []string{"DTSTART:20261019T120000Z", "DTEND:20261019T130000Z", "RRULE:FREQ=WEEKLY;BYDAY=MO", "BEGIN:VALARM", "TRIGGER;RELATED=END:-PT5M", "END:VALARM"},
+			start:    "2026-10-19 12:00",
+			end:      "2026-10-19 13:00",
+			reminder: -1,
+			rrule:    "FREQ=WEEKLY;BYDAY=MO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:abc", "SUMMARY:Dentista"}, tt.lines...)
+			object, err := Decode(ics(append(lines, "END:VEVENT", "END:VCALENDAR")...))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if object.Kind != "event" || object.UID != "abc" || object.Event.Title != "Dentista" {
+				t.Fatalf("objeto = %+v", object)
+			}
+
+			event := object.Event
+			format := func(t time.Time) string { return t.UTC().Format(layout) }
+			if tt.allDay {
+				format = func(t time.Time) string { return t.Format("2006-01-02") }
+			}
+			if got := format(event.StartDate); got != tt.start {
+				t.Errorf("início = %q, quero %q", got, tt.start)
+			}
+			if got := format(event.EndDate); got != tt.end {
+				t.Errorf("fim = %q, quero %q", got, tt.end)
+			}
+			if event.AllDay != tt.allDay {
+				t.Errorf("dia inteiro = %v, quero %v", event.AllDay, tt.allDay)
+			}
+
+			reminder := -1
+			if event.ReminderMinutes != nil {
+				reminder = *event.ReminderMinutes
+			}
+			if reminder != tt.reminder {
+				t.Errorf("lembrete = %d, quero %d", reminder, tt.reminder)
+			}
+			if event.RecurrenceRule != tt.rrule {
+				t.Errorf("RRULE = %q, quero %q", event.RecurrenceRule, tt.rrule)
+			}
+		})
+	}
+}
+
+func TestDecodeTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		status   string
+		priority string
+		due      string // em UTC
+	}{
+		{
+			name:     "alta com prazo",
+			lines:    []string{"PRIORITY:1", "DUE:20261020T150000Z"},
+			status:   "pending",
+			priority: "high",
+			due:      "2026-10-20 15:00",
+		},
+		{
+			name:     "baixa concluída",
+			lines:    []string{"PRIORITY:9", "STATUS:COMPLETED"},
+			status:   "completed",
+			priority: "low",
+		},
+		{
+			name:     "sem prioridade",
+			lines:    []string{"PRIORITY:0", "STATUS:CANCELLED"},
+			status:   "cancelled",
+			priority: "medium",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VCALENDAR", "BEGIN:VTODO", "UID:t1", "SUMMARY:Pagar conta"}, tt.lines...)
+			object, err := Decode(ics(append(lines, "END:VTODO", "END:VCALENDAR")...))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if object.Kind != "task" || object.Task.Title != "Pagar conta" {
+				t.Fatalf("objeto = %+v", object)
+			}
+
+			task := object.Task
+			if task.Status != tt.status {
+				t.Errorf("status = %q, quero %q", task.Status, tt.status)
+			}
+			if task.Priority != tt.priority {
+				t.Errorf("prioridade = %q, quero %q", task.Priority, tt.priority)
+			}
+			due := ""
+			if task.DueDate != nil {
+				due = task.DueDate.UTC().Format("2006-01-02 15:04")
+			}
+			if due != tt.due {
+				t.Errorf("prazo = %q, quero %q", due, tt.due)
+			}
+		})
+	}
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	root := &Component{Name: "VCALENDAR"}
+	vevent := &Component{Name: "VEVENT"}
+	summary := strings.Repeat("Reunião longa, ", 10)
+	vevent.AddText("SUMMARY", summary)
+	root.Children = append(root.Children, vevent)
+
+	encoded := root.Encode()
+	for _, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("linha com %d bytes: %q", len(line), line)
+		}
+	}
+
+	parsed, err := Parse(strings.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := parsed.Child("VEVENT").Text("SUMMARY"); got != summary {
+		t.Errorf("SUMMARY = %q, quero %q", got, summary)
+	}
+}
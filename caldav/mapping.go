@@ -0,0 +1,278 @@
+package caldav
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+const productID = "-//Personal Cockpit//CalDAV//PT"
+
+// estimateProperty guarda a duração prevista da tarefa. O VTODO não aceita
+// DURATION junto com DUE, então ela vai numa propriedade própria, que os
+// outros clientes preservam sem mostrar.
+const estimateProperty = "X-COCKPIT-ESTIMATED-MINUTES"
+
+// untitled é o título de itens que chegam sem SUMMARY, no idioma do app
+func untitled() string {
+	return i18n.T("(sem título)")
+}
+
+// Object é um item lido do servidor: o VEVENT ou VTODO já convertido, com o
+// UID e a data da última alteração (LAST-MODIFIED, ou DTSTAMP) para a
+// política "o mais recente vence"
+type Object struct {
+	UID          string
+	Kind         string // event ou task
+	Event        models.Event
+	Task         models.Task
+	LastModified time.Time
+}
+
+// EncodeEvent escreve o evento como um VCALENDAR com um VEVENT. Eventos de
+// dia inteiro vão com VALUE=DATE e DTEND exclusivo; o lembrete vira um
+// VALARM e a regra de repetição, um RRULE.
+func EncodeEvent(uid string, event models.Event) string {
+	vevent := &Component{Name: "VEVENT"}
+	vevent.AddText("UID", uid)
+	vevent.Add("DTSTAMP", FormatDateTime(time.Now()), nil)
+	if !event.UpdatedAt.IsZero() {
+		vevent.Add("LAST-MODIFIED", FormatDateTime(event.UpdatedAt), nil)
+	}
+	vevent.AddText("SUMMARY", event.Title)
+
+	if event.AllDay {
+		start := timezone.Floating(event.StartDate)
+		end := timezone.Floating(event.EndDate)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		vevent.Add("DTSTART", FormatDate(start), map[string]string{"VALUE": "DATE"})
+		vevent.Add("DTEND", FormatDate(end), map[string]string{"VALUE": "DATE"})
+	} else {
+		vevent.Add("DTSTART", FormatDateTime(event.StartDate), nil)
+		end := event.EndDate
+		if end.Before(event.StartDate) {
+			end = event.StartDate
+		}
+		vevent.Add("DTEND", FormatDateTime(end), nil)
+	}
+
+	if event.Description != "" {
+		vevent.AddText("DESCRIPTION", event.Description)
+	}
+	if event.Location != "" {
+		vevent.AddText("LOCATION", event.Location)
+	}
+	if event.Color != "" {
+		vevent.AddText("COLOR", event.Color)
+	}
+	if event.RecurrenceRule != "" {
+		vevent.Add("RRULE", strings.TrimPrefix(event.RecurrenceRule, "RRULE:"), nil)
+	}
+	if event.ReminderMinutes != nil {
+		alarm := &Component{Name: "VALARM"}
+		alarm.Add("ACTION", "DISPLAY", nil)
+		alarm.AddText("DESCRIPTION", event.Title)
+		alarm.Add("TRIGGER", FormatDuration(-time.Duration(*event.ReminderMinutes)*time.Minute), nil)
+		vevent.Children = append(vevent.Children, alarm)
+	}
+
+	return calendar(vevent).Encode()
+}
+
+// EncodeTask escreve a tarefa como um VCALENDAR com um VTODO. A prioridade
+// segue a escala do iCalendar (1 = alta, 5 = média, 9 = baixa); um prazo à
+// meia-noite vai só com a data.
+func EncodeTask(uid string, task models.Task) string {
+	vtodo := &Component{Name: "VTODO"}
+	vtodo.AddText("UID", uid)
+	vtodo.Add("DTSTAMP", FormatDateTime(time.Now()), nil)
+	if !task.UpdatedAt.IsZero() {
+		vtodo.Add("LAST-MODIFIED", FormatDateTime(task.UpdatedAt), nil)
+	}
+	vtodo.AddText("SUMMARY", task.Title)
+	if task.Description != "" {
+		vtodo.AddText("DESCRIPTION", task.Description)
+	}
+
+	vtodo.Add("STATUS", taskStatusToICal[task.Status], nil)
+	vtodo.Add("PRIORITY", strconv.Itoa(taskPriorityToICal[task.Priority]), nil)
+
+	if task.DueDate != nil {
+		due := timezone.In(*task.DueDate)
+		if due.Equal(timezone.StartOfDay(due)) {
+			vtodo.Add("DUE", FormatDate(due), map[string]string{"VALUE": "DATE"})
+		} else {
+			vtodo.Add("DUE", FormatDateTime(due), nil)
+		}
+	}
+	if task.CompletedAt != nil {
+		vtodo.Add("COMPLETED", FormatDateTime(*task.CompletedAt), nil)
+	}
+	if task.EstimatedMinutes != nil {
+		vtodo.Add(estimateProperty, strconv.Itoa(*task.EstimatedMinutes), nil)
+	}
+
+	return calendar(vtodo).Encode()
+}
+
+func calendar(child *Component) *Component {
+	root := &Component{Name: "VCALENDAR"}
+	root.Add("VERSION", "2.0", nil)
+	root.Add("PRODID", productID, nil)
+	root.Children = append(root.Children, child)
+	return root
+}
+
+var (
+	taskStatusToICal   = map[string]string{"pending": "NEEDS-ACTION", "completed": "COMPLETED", "cancelled": "CANCELLED"}
+	taskPriorityToICal = map[string]int{"high": 1, "medium": 5, "low": 9}
+)
+
+// Decode lê um objeto do servidor. Só o primeiro VEVENT ou VTODO conta: as
+// exceções de eventos repetidos (RECURRENCE-ID) são ignoradas.
+func Decode(data string) (*Object, error) {
+	root, err := Parse(strings.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range root.Children {
+		if child.Get("RECURRENCE-ID") != nil {
+			continue
+		}
+		switch child.Name {
+		case "VEVENT":
+			return decodeEvent(child)
+		case "VTODO":
+			return decodeTask(child)
+		}
+	}
+
+	return nil, i18n.Errorf("objeto sem VEVENT nem VTODO")
+}
+
+func decodeEvent(c *Component) (*Object, error) {
+	object := &Object{UID: c.Text("UID"), Kind: "event", LastModified: lastModified(c)}
+	event := models.Event{
+		Title:       c.Text("SUMMARY"),
+		Description: c.Text("DESCRIPTION"),
+		Location:    c.Text("LOCATION"),
+	}
+	if color := c.Text("COLOR"); len(color) == 7 && strings.HasPrefix(color, "#") {
+		event.Color = color
+	}
+	if strings.TrimSpace(event.Title) == "" {
+		event.Title = untitled()
+	}
+
+	dtstart := c.Get("DTSTART")
+	if dtstart == nil {
+		return nil, i18n.Errorf("VEVENT %s sem DTSTART", object.UID)
+	}
+	start, allDay, err := dtstart.DateTime()
+	if err != nil {
+		return nil, err
+	}
+	event.StartDate, event.AllDay = start, allDay
+
+	switch {
+	case c.Get("DTEND") != nil:
+		end, _, err := c.Get("DTEND").DateTime()
+		if err != nil {
+			return nil, err
+		}
+		event.EndDate = end
+	case c.Get("DURATION") != nil:
+		duration, err := ParseDuration(c.Get("DURATION").Value)
+		if err != nil {
+			return nil, err
+		}
+		event.EndDate = start.Add(duration)
+		if allDay {
+			event.EndDate = start.AddDate(0, 0, int(duration/(24*time.Hour)))
+		}
+	case allDay:
+		event.EndDate = start.AddDate(0, 0, 1)
+	default:
+		event.EndDate = start
+	}
+
+	if rrule := c.Get("RRULE"); rrule != nil {
+		event.RecurrenceRule = rrule.Value
+	}
+
+	if alarm := c.Child("VALARM"); alarm != nil && alarm.Get("TRIGGER") != nil {
+		trigger := alarm.Get("TRIGGER")
+		if trigger.Params["VALUE"] != "DATE-TIME" && trigger.Params["RELATED"] != "END" {
+			if before, err := ParseDuration(trigger.Value); err == nil && before <= 0 {
+				minutes := int(-before / time.Minute)
+				event.ReminderMinutes = &minutes
+			}
+		}
+	}
+
+	object.Event = event
+	return object, nil
+}
+
+func decodeTask(c *Component) (*Object, error) {
+	object := &Object{UID: c.Text("UID"), Kind: "task", LastModified: lastModified(c)}
+	task := models.Task{
+		Title:       c.Text("SUMMARY"),
+		Description: c.Text("DESCRIPTION"),
+		Status:      "pending",
+		Priority:    "medium",
+	}
+	if strings.TrimSpace(task.Title) == "" {
+		task.Title = untitled()
+	}
+
+	switch strings.ToUpper(c.Text("STATUS")) {
+	case "COMPLETED":
+		task.Status = "completed"
+	case "CANCELLED":
+		task.Status = "cancelled"
+	}
+
+	if priority, err := strconv.Atoi(c.Text("PRIORITY")); err == nil {
+		switch {
+		case priority >= 1 && priority <= 4:
+			task.Priority = "high"
+		case priority >= 6 && priority <= 9:
+			task.Priority = "low"
+		}
+	}
+
+	if due := c.Get("DUE"); due != nil {
+		t, _, err := due.DateTime()
+		if err != nil {
+			return nil, err
+		}
+		task.DueDate = &t
+	}
+
+	if minutes, err := strconv.Atoi(c.Text(estimateProperty)); err == nil && minutes > 0 {
+		task.EstimatedMinutes = &minutes
+	}
+
+	object.Task = task
+	return object, nil
+}
+
+// lastModified é a última alteração declarada pelo objeto (zero se não houver)
+func lastModified(c *Component) time.Time {
+	for _, name := range []string{"LAST-MODIFIED", "DTSTAMP"} {
+		if prop := c.Get(name); prop != nil {
+			if t, _, err := prop.DateTime(); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
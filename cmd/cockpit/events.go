@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+	"personal-cockpit/services"
+	"personal-cockpit/timezone"
+)
+
+func runEvent(ctx context.Context, db *sql.DB, args []string) error {
+	sub, args, err := subcommand(args, "event")
+	if err != nil {
+		return err
+	}
+
+	eventService := services.NewEventService(db, nil)
+
+	switch sub {
+	case "add":
+		return eventAdd(ctx, eventService, args)
+	case "today":
+		return eventToday(ctx, eventService, args)
+	default:
+		return unknownSubcommand("event", sub)
+	}
+}
+
+func eventAdd(ctx context.Context, eventService *services.EventService, args []string) error {
+	fs := flag.NewFlagSet("event add", flag.ContinueOnError)
+	start := fs.String("start", "", "início (AAAA-MM-DD HH:MM, ou AAAA-MM-DD com --all-day)")
+	end := fs.String("end", "", "término (mesmo formato de --start)")
+	duration := fs.Duration("duration", time.Hour, "duração, usada quando --end não é informado")
+	location := fs.String("location", "", "local")
+	description := fs.String("d", "", "descrição")
+	allDay := fs.Bool("all-day", false, "evento de dia inteiro")
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		return errors.New("informe o título do evento")
+	}
+
+	if *start == "" {
+		return errors.New("informe o início com --start")
+	}
+
+	startDate, err := parseEventTime(*start)
+	if err != nil {
+		return err
+	}
+
+	var endDate time.Time
+	switch {
+	case *end != "":
+		if endDate, err = parseEventTime(*end); err != nil {
+			return err
+		}
+	case *allDay:
+		endDate = startDate.AddDate(0, 0, 1)
+	default:
+		endDate = startDate.Add(*duration)
+	}
+
+	event := models.Event{
+		Title:       strings.Join(positional, " "),
+		Description: *description,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		AllDay:      *allDay,
+		Location:    *location,
+	}
+
+	saved, err := eventService.CreateEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	created, err := eventService.GetEventByID(ctx, saved.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, conflict := range saved.Conflicts {
+		fmt.Fprintf(os.Stderr, "aviso: no mesmo horário de #%d %s (%s)\n", conflict.ID, conflict.Title, formatDateTime(conflict.StartDate))
+	}
+
+	if *asJSON {
+		return printJSON(created)
+	}
+
+	fmt.Printf("Evento #%d criado: %s em %s\n", created.ID, created.Title, formatDateTime(created.StartDate))
+	return nil
+}
+
+// parseEventTime aceita data com hora ou só a data, no horário local
+func parseEventTime(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, timezone.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida: %q, use AAAA-MM-DD HH:MM", value)
+}
+
+func eventToday(ctx context.Context, eventService *services.EventService, args []string) error {
+	fs := flag.NewFlagSet("event today", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+
+	events, err := eventService.GetTodayEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if events == nil {
+			events = []models.Event{}
+		}
+		return printJSON(events)
+	}
+
+	rows := make([][]string, 0, len(events))
+	for _, event := range events {
+		when := i18n.FormatTime(timezone.In(event.StartDate)) + "–" + i18n.FormatTime(timezone.In(event.EndDate))
+		if event.AllDay {
+			when = i18n.T("dia inteiro")
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(event.ID),
+			when,
+			truncate(event.Title, 50),
+			truncate(event.Location, 30),
+		})
+	}
+
+	return printTable([]string{"ID", "HORÁRIO", "TÍTULO", "LOCAL"}, rows)
+}
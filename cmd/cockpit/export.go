@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"personal-cockpit/services"
+)
+
+func runExport(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "json", "json ou markdown")
+	output := fs.String("o", "", "arquivo de saída (padrão: saída padrão)")
+	asJSON := fs.Bool("json", false, "equivale a --format json")
+
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		*format = "json"
+	}
+
+	exportService := services.NewExportService(db, version)
+
+	var write func(context.Context, io.Writer) error
+	switch *format {
+	case "json":
+		write = exportService.ExportJSON
+	case "markdown", "md":
+		write = exportService.ExportMarkdown
+	default:
+		return fmt.Errorf("formato desconhecido: %s", *format)
+	}
+
+	if *output == "" {
+		return write(ctx, os.Stdout)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo: %w", err)
+	}
+
+	if err := write(ctx, file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exportado para %s\n", *output)
+	return nil
+}
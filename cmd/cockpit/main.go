@@ -0,0 +1,160 @@
+// Command cockpit é a interface de linha de comando do Personal Cockpit.
+// Usa o mesmo banco e os mesmos services do app desktop, então pode rodar
+// em scripts e atalhos sem abrir a janela.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"personal-cockpit/database"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/services"
+	"personal-cockpit/timezone"
+)
+
+// version é sobrescrito no build com -ldflags "-X main.version=..."
+var version = "1.0.0"
+
+const usage = `Uso: cockpit <comando> [argumentos]
+
+Comandos:
+  task add "título" [-p prioridade] [-d descrição] [--due AAAA-MM-DD] [-c categoria]
+  task list [--status pending|completed|all] [--priority low|medium|high]
+  task done <id>
+  note new "título" [-f arquivo]      (sem -f, lê o conteúdo da entrada padrão)
+  note search <texto>
+  note cat <id>
+  event add "título" --start "AAAA-MM-DD HH:MM" [--end ... | --duration 1h] [--location local] [--all-day]
+  event today
+  export [--format json|markdown] [-o arquivo]
+  version
+
+Todos os comandos aceitam --json para saída em JSON.
+`
+
+// errUsage indica argumentos inválidos; a mensagem de uso já foi exibida
+var errUsage = errors.New("uso incorreto")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Ctrl+C cancela a consulta ou exportação em andamento
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1], os.Args[2:])
+	stop()
+
+	if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "erro:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "version", "--version", "-v":
+		fmt.Println("cockpit", version)
+		return nil
+	case "help", "--help", "-h":
+		fmt.Print(usage)
+		return nil
+	}
+
+	handlers := map[string]func(context.Context, *sql.DB, []string) error{
+		"task":   runTask,
+		"note":   runNote,
+		"event":  runEvent,
+		"export": runExport,
+	}
+
+	handler, ok := handlers[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "comando desconhecido: %s\n\n%s", command, usage)
+		return errUsage
+	}
+
+	db, err := openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return handler(ctx, db.GetConnection(), args)
+}
+
+// openDatabase abre o banco do app. O log (migrations, duração das operações)
+// vai só para o arquivo, para não misturar com a saída do comando (ex.: --json
+// em pipes); ele fica aberto até o processo terminar.
+func openDatabase(ctx context.Context) (*database.DB, error) {
+	if dataDir, err := database.GetAppDataDir(); err == nil {
+		logging.Setup(dataDir, nil)
+	}
+
+	db, err := database.NewDB()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir banco: %w", err)
+	}
+
+	// Mesmo idioma e fuso do app; sem escolha salva, os do sistema
+	settings := services.NewSettingsService(db.GetConnection())
+	locale, _ := settings.Get(ctx, i18n.SettingLocale, "")
+	if locale == "" {
+		locale = i18n.FromEnvironment()
+	}
+	i18n.SetLocale(locale)
+
+	if name, _ := settings.Get(ctx, timezone.SettingTimezone, ""); name != "" {
+		timezone.Set(name)
+	}
+
+	return db, nil
+}
+
+// parseInterspersed permite flags antes ou depois dos argumentos posicionais
+// (ex.: `task add "Comprar pão" -p high`), o que o pacote flag não faz sozinho
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func subcommand(args []string, name string) (string, []string, error) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: informe o subcomando\n\n%s", name, usage)
+		return "", nil, errUsage
+	}
+	return args[0], args[1:], nil
+}
+
+func unknownSubcommand(name, sub string) error {
+	fmt.Fprintf(os.Stderr, "%s: subcomando desconhecido: %s\n\n%s", name, sub, usage)
+	return errUsage
+}
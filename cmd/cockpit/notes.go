@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"personal-cockpit/models"
+	"personal-cockpit/services"
+)
+
+func runNote(ctx context.Context, db *sql.DB, args []string) error {
+	sub, args, err := subcommand(args, "note")
+	if err != nil {
+		return err
+	}
+
+	noteService := services.NewNoteService(db, nil, services.NewTaskService(db, nil))
+
+	switch sub {
+	case "new", "add":
+		return noteNew(ctx, noteService, args)
+	case "search":
+		return noteSearch(ctx, noteService, args)
+	case "cat", "show":
+		return noteCat(ctx, noteService, args)
+	default:
+		return unknownSubcommand("note", sub)
+	}
+}
+
+func noteNew(ctx context.Context, noteService *services.NoteService, args []string) error {
+	fs := flag.NewFlagSet("note new", flag.ContinueOnError)
+	file := fs.String("f", "", "arquivo com o conteúdo da nota")
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		return errors.New("informe o título da nota")
+	}
+
+	content, err := readNoteContent(*file)
+	if err != nil {
+		return err
+	}
+
+	id, err := noteService.CreateNote(ctx, models.Note{
+		Title:   strings.Join(positional, " "),
+		Content: content,
+	})
+	if err != nil {
+		return err
+	}
+
+	note, err := noteService.GetNoteByID(ctx, int(id))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(note)
+	}
+
+	fmt.Printf("Nota #%d criada: %s\n", note.ID, note.Title)
+	return nil
+}
+
+// readNoteContent lê o arquivo informado ou, se a entrada padrão não for um
+// terminal (ex.: `echo texto | cockpit note new "Título"`), o que vier por ela
+func readNoteContent(path string) (string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler arquivo: %w", err)
+		}
+		return string(data), nil
+	}
+
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler entrada padrão: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func noteSearch(ctx context.Context, noteService *services.NoteService, args []string) error {
+	fs := flag.NewFlagSet("note search", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	notes, err := noteService.SearchNotes(ctx, strings.Join(positional, " "))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if notes == nil {
+			notes = []models.Note{}
+		}
+		return printJSON(notes)
+	}
+
+	rows := make([][]string, 0, len(notes))
+	for _, note := range notes {
+		favorite := ""
+		if note.IsFavorite {
+			favorite = "★"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(note.ID),
+			favorite,
+			formatDateTime(note.UpdatedAt),
+			truncate(note.Title, 40),
+			truncate(note.Content, 50),
+		})
+	}
+
+	return printTable([]string{"ID", "", "ATUALIZADA", "TÍTULO", "CONTEÚDO"}, rows)
+}
+
+func noteCat(ctx context.Context, noteService *services.NoteService, args []string) error {
+	fs := flag.NewFlagSet("note cat", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("informe o ID da nota")
+	}
+
+	id, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("ID inválido: %s", positional[0])
+	}
+
+	note, err := noteService.GetNoteByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(note)
+	}
+
+	fmt.Print(note.Content)
+	if note.Content != "" && !strings.HasSuffix(note.Content, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
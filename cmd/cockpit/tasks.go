@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"personal-cockpit/models"
+	"personal-cockpit/services"
+	"personal-cockpit/timezone"
+)
+
+func runTask(ctx context.Context, db *sql.DB, args []string) error {
+	sub, args, err := subcommand(args, "task")
+	if err != nil {
+		return err
+	}
+
+	taskService := services.NewTaskService(db, nil)
+
+	switch sub {
+	case "add":
+		return taskAdd(ctx, db, taskService, args)
+	case "list", "ls":
+		return taskList(ctx, taskService, args)
+	case "done":
+		return taskDone(ctx, taskService, args)
+	default:
+		return unknownSubcommand("task", sub)
+	}
+}
+
+func taskAdd(ctx context.Context, db *sql.DB, taskService *services.TaskService, args []string) error {
+	fs := flag.NewFlagSet("task add", flag.ContinueOnError)
+	priority := fs.String("p", "medium", "prioridade (low, medium, high)")
+	description := fs.String("d", "", "descrição")
+	due := fs.String("due", "", "data de vencimento (AAAA-MM-DD)")
+	category := fs.String("c", "", "nome da categoria")
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		return errors.New("informe o título da tarefa")
+	}
+
+	task := models.Task{
+		Title:       strings.Join(positional, " "),
+		Description: *description,
+		Status:      "pending",
+		Priority:    *priority,
+	}
+
+	if *due != "" {
+		dueDate, err := timezone.ParseDate(*due)
+		if err != nil {
+			return fmt.Errorf("data de vencimento inválida, use o formato AAAA-MM-DD")
+		}
+		task.DueDate = &dueDate
+	}
+
+	if *category != "" {
+		categoryID, err := findCategory(ctx, db, "task", *category)
+		if err != nil {
+			return err
+		}
+		task.CategoryID = &categoryID
+	}
+
+	id, err := taskService.CreateTask(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	created, err := taskService.GetTaskByID(ctx, int(id))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(created)
+	}
+
+	fmt.Printf("Tarefa #%d criada: %s\n", created.ID, created.Title)
+	return nil
+}
+
+func taskList(ctx context.Context, taskService *services.TaskService, args []string) error {
+	fs := flag.NewFlagSet("task list", flag.ContinueOnError)
+	status := fs.String("status", "pending", "pending, completed ou all")
+	priority := fs.String("priority", "", "filtrar por prioridade")
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+
+	filter := models.TaskFilter{Status: *status, Priority: *priority}
+	if filter.Status == "all" {
+		filter.Status = ""
+	}
+
+	tasks, err := taskService.GetTasksByFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if tasks == nil {
+			tasks = []models.Task{}
+		}
+		return printJSON(tasks)
+	}
+
+	rows := make([][]string, 0, len(tasks))
+	for _, task := range tasks {
+		mark := "[ ]"
+		if task.Status == "completed" {
+			mark = "[x]"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(task.ID),
+			mark,
+			task.Priority,
+			formatDate(task.DueDate),
+			truncate(task.Title, 60),
+		})
+	}
+
+	return printTable([]string{"ID", "", "PRIORIDADE", "VENCE", "TÍTULO"}, rows)
+}
+
+func taskDone(ctx context.Context, taskService *services.TaskService, args []string) error {
+	fs := flag.NewFlagSet("task done", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "saída em JSON")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		return errors.New("informe o ID da tarefa")
+	}
+
+	var done []models.Task
+
+	for _, arg := range positional {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("ID inválido: %s", arg)
+		}
+
+		task, err := taskService.GetTaskByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// UpdateTask mantém o checklist da nota vinculada sincronizado
+		if task.Status != "completed" {
+			task.Status = "completed"
+			if err := taskService.UpdateTask(ctx, *task); err != nil {
+				return err
+			}
+			if task, err = taskService.GetTaskByID(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		done = append(done, *task)
+	}
+
+	if *asJSON {
+		return printJSON(done)
+	}
+
+	for _, task := range done {
+		fmt.Printf("Tarefa #%d concluída: %s\n", task.ID, task.Title)
+	}
+	return nil
+}
+
+// findCategory busca uma categoria do tipo pelo nome, sem diferenciar maiúsculas
+func findCategory(ctx context.Context, db *sql.DB, categoryType, name string) (int, error) {
+	categories, err := services.NewCategoryService(db, nil).GetCategoriesByType(ctx, categoryType)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, category := range categories {
+		if strings.EqualFold(category.Name, name) {
+			return category.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("categoria não encontrada: %s", name)
+}
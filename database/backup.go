@@ -0,0 +1,186 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"personal-cockpit/models"
+)
+
+const (
+	// BackupDirName é a pasta das cópias, ao lado do arquivo do banco
+	BackupDirName = "backups"
+
+	// MaxBackups é quantas cópias são mantidas; as mais antigas são removidas
+	MaxBackups = 10
+)
+
+// BackupDir retorna a pasta de cópias do banco informado
+func BackupDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), BackupDirName)
+}
+
+// Backup grava uma cópia consistente do banco (VACUUM INTO) em backups/ e
+// retorna o caminho. reason entra no nome do arquivo ("daily", "pre-v9").
+func (db *DB) Backup(reason string) (string, error) {
+	dir := BackupDir(db.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("erro ao criar diretório de cópias: %w", err)
+	}
+
+	name := "cockpit-" + time.Now().Format("20060102-150405") + "-" + reason + ".db"
+	path := filepath.Join(dir, name)
+
+	if _, err := db.conn.Exec("VACUUM INTO ?", path); err != nil {
+		return "", fmt.Errorf("erro ao copiar banco: %w", err)
+	}
+
+	pruneBackups(dir)
+	slog.Info("cópia do banco criada", "path", path)
+
+	return path, nil
+}
+
+// BackupIfDue faz a cópia diária: só copia se a mais recente tiver mais de
+// um dia
+func (db *DB) BackupIfDue() error {
+	backups, err := ListBackups(db.path)
+	if err != nil {
+		return err
+	}
+
+	if len(backups) > 0 && time.Since(backups[0].CreatedAt) < 24*time.Hour {
+		return nil
+	}
+
+	_, err = db.Backup("daily")
+	return err
+}
+
+// ListBackups lista as cópias do banco informado, da mais recente para a mais
+// antiga
+func ListBackups(dbPath string) ([]models.Backup, error) {
+	return listBackupsIn(BackupDir(dbPath))
+}
+
+func listBackupsIn(dir string) ([]models.Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar cópias: %w", err)
+	}
+
+	var backups []models.Backup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".db") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, models.Backup{
+			Name:      entry.Name(),
+			Path:      filepath.Join(dir, entry.Name()),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// RestoreBackup troca o banco pela cópia name (de ListBackups). O banco
+// precisa estar fechado; o arquivo atual é preservado com sufixo .broken-*.
+func RestoreBackup(dbPath, name string) error {
+	if name != filepath.Base(name) || !strings.HasSuffix(name, ".db") {
+		return fmt.Errorf("cópia inválida: %s", name)
+	}
+
+	source, err := os.Open(filepath.Join(BackupDir(dbPath), name))
+	if err != nil {
+		return fmt.Errorf("erro ao abrir cópia: %w", err)
+	}
+	defer source.Close()
+
+	if _, err := MoveAside(dbPath); err != nil {
+		return err
+	}
+
+	target, err := os.Create(dbPath)
+	if err != nil {
+		return fmt.Errorf("erro ao restaurar cópia: %w", err)
+	}
+
+	if _, err := io.Copy(target, source); err != nil {
+		target.Close()
+		return fmt.Errorf("erro ao restaurar cópia: %w", err)
+	}
+
+	if err := target.Close(); err != nil {
+		return fmt.Errorf("erro ao restaurar cópia: %w", err)
+	}
+
+	slog.Info("cópia do banco restaurada", "backup", name)
+	return nil
+}
+
+// MoveAside renomeia o banco (e os arquivos -wal/-shm) para
+// <nome>.broken-AAAAMMDD-HHMMSS, liberando o caminho para um banco novo sem
+// apagar nada. Retorna o novo caminho, ou "" se o banco não existia.
+func MoveAside(dbPath string) (string, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	// Nunca sobrescreve um .broken-* anterior do mesmo segundo
+	suffix := ".broken-" + time.Now().Format("20060102-150405")
+	for i := 2; fileExists(dbPath + suffix); i++ {
+		suffix = fmt.Sprintf(".broken-%s-%d", time.Now().Format("20060102-150405"), i)
+	}
+	for _, extra := range []string{"-wal", "-shm"} {
+		if _, err := os.Stat(dbPath + extra); err == nil {
+			if err := os.Rename(dbPath+extra, dbPath+suffix+extra); err != nil {
+				return "", fmt.Errorf("erro ao mover banco: %w", err)
+			}
+		}
+	}
+
+	if err := os.Rename(dbPath, dbPath+suffix); err != nil {
+		return "", fmt.Errorf("erro ao mover banco: %w", err)
+	}
+
+	slog.Warn("banco movido", "from", dbPath, "to", dbPath+suffix)
+	return dbPath + suffix, nil
+}
+
+// pruneBackups remove as cópias além de MaxBackups
+func pruneBackups(dir string) {
+	backups, err := listBackupsIn(dir)
+	if err != nil {
+		return
+	}
+
+	for i := MaxBackups; i < len(backups); i++ {
+		os.Remove(backups[i].Path)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -11,37 +12,55 @@ import (
 
 type DB struct {
 	conn *sql.DB
+	path string
 }
 
+// NewDB abre o banco padrão, no diretório de dados do app
 func NewDB() (*DB, error) {
-	dbPath, err := getDatabasePath()
+	dbPath, err := DefaultPath()
 	if err != nil {
 		return nil, fmt.Errorf("erro ao obter caminho do banco: %w", err)
 	}
 
-	conn, err := sql.Open("sqlite", dbPath)
+	return Open(dbPath)
+}
+
+// Open abre (ou cria) o banco no caminho informado e aplica as migrations.
+// Em caso de erro a conexão é fechada, sem deixar o arquivo preso.
+func Open(dbPath string) (*DB, error) {
+	conn, err := sql.Open("sqlite", dataSourceName(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao abrir banco: %w", err)
 	}
 
+	db := &DB{conn: conn, path: dbPath}
+
 	if err := conn.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("erro ao conectar ao banco: %w", err)
 	}
 
-	db := &DB{conn: conn}
-
-	if err := db.configurePragmas(); err != nil {
-		return nil, err
-	}
-
 	if err := db.RunMigrations(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("erro ao executar migrations: %w", err)
 	}
 
 	return db, nil
 }
 
-func getDatabasePath() (string, error) {
+// DefaultPath é o caminho do banco padrão (<dados do app>/cockpit.db)
+func DefaultPath() (string, error) {
+	appDir, err := GetAppDataDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Retornar caminho completo do banco
+	return filepath.Join(appDir, "cockpit.db"), nil
+}
+
+// GetAppDataDir retorna o diretório de dados do app, criando-o se necessário
+func GetAppDataDir() (string, error) {
 	// Obter diretório de configuração do usuário
 	// Windows: C:\Users\{user}\AppData\Roaming
 	// macOS: ~/Library/Application Support
@@ -57,27 +76,28 @@ func getDatabasePath() (string, error) {
 		return "", err
 	}
 
-	// Retornar caminho completo do banco
-	return filepath.Join(appDir, "cockpit.db"), nil
+	return appDir, nil
 }
 
-func (db *DB) configurePragmas() error {
-	pragmas := []string{
-		"PRAGMA journal_mode = WAL",
-		"PRAGMA foreign_keys = ON",
-		"PRAGMA synchronous = NORMAL",
-		"PRAGMA cache_size = -2000",
-		"PRAGMA temp_store = MEMORY",
-		"PRAGMA busy_timeout = 5000",
-	}
+// connectionPragmas configuram cada conexão. O database/sql mantém um pool,
+// e um PRAGMA executado pelo *sql.DB vale só para a conexão que o rodou; no
+// DSN, o driver os aplica a toda conexão que abre (busy_timeout primeiro).
+var connectionPragmas = []string{
+	"busy_timeout(5000)",
+	"foreign_keys(1)",
+	"journal_mode(WAL)",
+	"synchronous(NORMAL)",
+	"cache_size(-2000)",
+	"temp_store(MEMORY)",
+}
 
-	for _, pragma := range pragmas {
-		if _, err := db.conn.Exec(pragma); err != nil {
-			return fmt.Errorf("erro ao configurar pragma: %w", err)
-		}
+// dataSourceName monta o DSN do driver com os PRAGMAs de cada conexão
+func dataSourceName(dbPath string) string {
+	query := url.Values{}
+	for _, pragma := range connectionPragmas {
+		query.Add("_pragma", pragma)
 	}
-
-	return nil
+	return dbPath + "?" + query.Encode()
 }
 
 func (db *DB) Close() error {
@@ -90,3 +110,8 @@ func (db *DB) Close() error {
 func (db *DB) GetConnection() *sql.DB {
 	return db.conn
 }
+
+// Path é o caminho do arquivo do banco
+func (db *DB) Path() string {
+	return db.path
+}
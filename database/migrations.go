@@ -1,10 +1,13 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
 )
 
-const CurrentSchemaVersion = 1
+const CurrentSchemaVersion = 12
 
 func (db *DB) RunMigrations() error {
 
@@ -17,22 +20,25 @@ func (db *DB) RunMigrations() error {
 		return err
 	}
 
-	fmt.Printf("📊 Schema atual: v%d | Schema necessário: v%d\n", currentVersion, CurrentSchemaVersion)
+	slog.Info("verificando schema", "atual", currentVersion, "necessario", CurrentSchemaVersion)
 
 	if currentVersion >= CurrentSchemaVersion {
-		fmt.Println("✅ Schema já está atualizado!")
+		slog.Info("schema já está atualizado")
 		return nil
 	}
 
-	if err := db.executeMigrations(currentVersion); err != nil {
-		return err
+	// Cópia antes de mexer no schema de um banco que já tem dados
+	if currentVersion > 0 {
+		if _, err := db.Backup(fmt.Sprintf("pre-v%d", CurrentSchemaVersion)); err != nil {
+			return err
+		}
 	}
 
-	if err := db.setSchemaVersion(CurrentSchemaVersion); err != nil {
+	if err := db.executeMigrations(currentVersion); err != nil {
 		return err
 	}
 
-	fmt.Printf("✅ Schema atualizado para v%d\n", CurrentSchemaVersion)
+	slog.Info("schema atualizado", "versao", CurrentSchemaVersion)
 	return nil
 }
 
@@ -58,9 +64,9 @@ func (db *DB) getSchemaVersion() (int, error) {
 }
 
 // setSchemaVersion registra nova versão
-func (db *DB) setSchemaVersion(version int) error {
+func setSchemaVersion(tx *sql.Tx, migration Migration) error {
 	query := "INSERT INTO schema_version (version, description) VALUES (?, ?)"
-	_, err := db.conn.Exec(query, version, fmt.Sprintf("Schema v%d", version))
+	_, err := tx.Exec(query, migration.Version, migration.Description)
 	return err
 }
 
@@ -83,16 +89,149 @@ func (db *DB) executeMigrations(currentVersion int) error {
 				createTriggers,
 			},
 		},
+		// ═══════════════════════════════════════
+		// VERSÃO 2 - Tarefas extraídas de notas
+		// ═══════════════════════════════════════
+		{
+			Version:     2,
+			Description: "Vincular tarefas às notas de origem",
+			SQL: []string{
+				addTaskNoteColumn,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 3 - Gerenciador de Arquivos
+		// ═══════════════════════════════════════
+		{
+			Version:     3,
+			Description: "Criar tabela de anexos",
+			SQL: []string{
+				createAttachmentsTable,
+				createAttachmentTriggers,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 4 - Cadernos de notas
+		// ═══════════════════════════════════════
+		{
+			Version:     4,
+			Description: "Criar hierarquia de cadernos",
+			SQL: []string{
+				createNotebooksTable,
+				addNoteNotebookColumn,
+				createNotebookTriggers,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 5 - Modelos
+		// ═══════════════════════════════════════
+		{
+			Version:     5,
+			Description: "Criar modelos de notas e tarefas",
+			SQL: []string{
+				createTemplatesTable,
+				seedTemplates,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 6 - Diário
+		// ═══════════════════════════════════════
+		{
+			Version:     6,
+			Description: "Criar entradas do diário",
+			SQL: []string{
+				createJournalTable,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 7 - Controle Financeiro
+		// ═══════════════════════════════════════
+		{
+			Version:     7,
+			Description: "Criar contas, transações e orçamentos",
+			SQL: []string{
+				rebuildCategoriesWithFinance,
+				createAccountsTable,
+				createRecurringTransactionsTable,
+				createTransactionsTable,
+				createBudgetsTable,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 8 - Webhooks
+		// ═══════════════════════════════════════
+		{
+			Version:     8,
+			Description: "Criar webhooks e log de entregas",
+			SQL: []string{
+				createWebhooksTable,
+				createWebhookDeliveriesTable,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 9 - Datas em UTC
+		// ═══════════════════════════════════════
+		{
+			Version:     9,
+			Description: "Normalizar datas para UTC",
+			Func:        normalizeTimestamps,
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 10 - Blocos de tempo
+		// ═══════════════════════════════════════
+		{
+			Version:     10,
+			Description: "Vincular eventos a tarefas (blocos de tempo)",
+			SQL: []string{
+				addTimeBlockColumns,
+				createTimeBlockTriggers,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 11 - CalDAV
+		// ═══════════════════════════════════════
+		{
+			Version:     11,
+			Description: "Sincronização CalDAV de eventos e tarefas",
+			SQL: []string{
+				addRecurrenceRuleColumn,
+				createCalDAVItemsTable,
+				createCalDAVSyncLogTable,
+			},
+		},
+		// ═══════════════════════════════════════
+		// VERSÃO 12 - Pasta de notas
+		// ═══════════════════════════════════════
+		{
+			Version:     12,
+			Description: "Sincronização das notas com uma pasta de arquivos Markdown",
+			SQL: []string{
+				createNoteFilesTable,
+			},
+		},
 	}
 
+	// Todas as migrations rodam na mesma conexão, com as foreign keys desligadas:
+	// o PRAGMA vale por conexão e é obrigatório para recriar tabelas referenciadas
+	// (ex.: categories na v7) sem disparar ON DELETE nas tabelas filhas.
+	ctx := context.Background()
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao obter conexão: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("erro ao configurar pragma: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
 	for _, migration := range migrations {
 		if migration.Version > currentVersion {
-			fmt.Printf("🔄 Executando migration v%d: %s\n", migration.Version, migration.Description)
+			slog.Info("executando migration", "versao", migration.Version, "descricao", migration.Description)
 
-			for i, sql := range migration.SQL {
-				if _, err := db.conn.Exec(sql); err != nil {
-					return fmt.Errorf("erro na migration v%d [passo %d]: %w", migration.Version, i+1, err)
-				}
+			if err := runMigration(ctx, conn, migration); err != nil {
+				return err
 			}
 		}
 	}
@@ -100,10 +239,42 @@ func (db *DB) executeMigrations(currentVersion int) error {
 	return nil
 }
 
+// runMigration aplica uma migration em transação própria e registra a versão,
+// para que uma falha no meio não deixe o schema pela metade
+func runMigration(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar migration v%d: %w", migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	for i, sql := range migration.SQL {
+		if _, err := tx.Exec(sql); err != nil {
+			return fmt.Errorf("erro na migration v%d [passo %d]: %w", migration.Version, i+1, err)
+		}
+	}
+
+	if migration.Func != nil {
+		if err := migration.Func(ctx, tx); err != nil {
+			return fmt.Errorf("erro na migration v%d: %w", migration.Version, err)
+		}
+	}
+
+	if err := setSchemaVersion(tx, migration); err != nil {
+		return fmt.Errorf("erro ao registrar migration v%d: %w", migration.Version, err)
+	}
+
+	return tx.Commit()
+}
+
 type Migration struct {
 	Version     int
 	Description string
 	SQL         []string
+
+	// Func roda depois do SQL, na mesma transação, para conversões de dados
+	// que não cabem em SQL puro
+	Func func(ctx context.Context, tx *sql.Tx) error
 }
 
 // ═══════════════════════════════════════════════════════════
@@ -212,3 +383,419 @@ BEGIN
     UPDATE events SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
 END;
 `
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 2
+// ═══════════════════════════════════════════════════════════
+
+const addTaskNoteColumn = `
+ALTER TABLE tasks ADD COLUMN note_id INTEGER REFERENCES notes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_note ON tasks(note_id);
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 3
+// ═══════════════════════════════════════════════════════════
+
+// O conteúdo dos arquivos fica no disco, endereçado pelo SHA-256.
+// Várias linhas podem apontar para o mesmo blob.
+const createAttachmentsTable = `
+CREATE TABLE IF NOT EXISTS attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sha256 TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    mime_type TEXT,
+    file_size INTEGER NOT NULL,
+    tags TEXT DEFAULT '',
+    entity_type TEXT CHECK(entity_type IN ('note', 'task', 'event')),
+    entity_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_sha256 ON attachments(sha256);
+CREATE INDEX IF NOT EXISTS idx_attachments_entity ON attachments(entity_type, entity_id);
+`
+
+// Ao deletar o item, o anexo continua no gerenciador, apenas sem vínculo
+const createAttachmentTriggers = `
+CREATE TRIGGER IF NOT EXISTS unlink_note_attachments
+AFTER DELETE ON notes
+FOR EACH ROW
+BEGIN
+    UPDATE attachments SET entity_type = NULL, entity_id = NULL
+    WHERE entity_type = 'note' AND entity_id = OLD.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS unlink_task_attachments
+AFTER DELETE ON tasks
+FOR EACH ROW
+BEGIN
+    UPDATE attachments SET entity_type = NULL, entity_id = NULL
+    WHERE entity_type = 'task' AND entity_id = OLD.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS unlink_event_attachments
+AFTER DELETE ON events
+FOR EACH ROW
+BEGIN
+    UPDATE attachments SET entity_type = NULL, entity_id = NULL
+    WHERE entity_type = 'event' AND entity_id = OLD.id;
+END;
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 4
+// ═══════════════════════════════════════════════════════════
+
+const createNotebooksTable = `
+CREATE TABLE IF NOT EXISTS notebooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    parent_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (parent_id) REFERENCES notebooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notebooks_parent ON notebooks(parent_id);
+`
+
+const addNoteNotebookColumn = `
+ALTER TABLE notes ADD COLUMN notebook_id INTEGER REFERENCES notebooks(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_notebook ON notes(notebook_id);
+`
+
+const createNotebookTriggers = `
+CREATE TRIGGER IF NOT EXISTS update_notebook_timestamp
+AFTER UPDATE ON notebooks
+FOR EACH ROW
+BEGIN
+    UPDATE notebooks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 5
+// ═══════════════════════════════════════════════════════════
+
+const createTemplatesTable = `
+CREATE TABLE IF NOT EXISTS templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    kind TEXT CHECK(kind IN ('note', 'tasks')) DEFAULT 'note',
+    title TEXT,
+    content TEXT,
+    category_id INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL
+);
+
+CREATE TRIGGER IF NOT EXISTS update_template_timestamp
+AFTER UPDATE ON templates
+FOR EACH ROW
+BEGIN
+    UPDATE templates SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`
+
+const seedTemplates = `
+INSERT OR IGNORE INTO templates (name, kind, title, content) VALUES
+('Ata de reunião', 'note', 'Reunião {{prompt:Assunto}} - {{date}}',
+'# {{prompt:Assunto}}
+
+**Data:** {{weekday}}, {{date}} às {{time}}
+**Participantes:** {{prompt:Participantes}}
+
+## Pauta
+
+- {{cursor}}
+
+## Decisões
+
+## Próximos passos
+
+- [ ] '),
+('Revisão semanal', 'note', 'Revisão semanal {{year}}-S{{week}}',
+'# Revisão da semana {{week}}
+
+## O que deu certo
+
+- {{cursor}}
+
+## O que pode melhorar
+
+## Prioridades da próxima semana
+
+- [ ] '),
+('Diário do dia', 'note', 'Diário {{date}}',
+'# {{weekday}}, {{date}}
+
+## Foco do dia
+
+{{cursor}}
+
+## Anotações
+
+## Gratidão
+'),
+('Onboarding', 'tasks', 'Onboarding {{prompt:Nome}}',
+'- Enviar boas-vindas para {{prompt:Nome}} !high due:{{date}}
+- Criar conta de e-mail !high due:{{date}}
+- Liberar acessos aos sistemas !high due:{{date+1}}
+- Entregar equipamento due:{{date+1}}
+- Apresentar a equipe due:{{date+1}}
+- Agendar reunião com o gestor due:{{date+2}}
+- Revisar documentação interna due:{{date+3}}
+- Definir primeiras metas due:{{date+5}}
+- Conversa de acompanhamento de 30 dias !low due:{{date+30}}
+- Coletar feedback do onboarding !low due:{{date+30}}');
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 6
+// ═══════════════════════════════════════════════════════════
+
+// Uma entrada por dia; entry_date no formato AAAA-MM-DD
+const createJournalTable = `
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entry_date TEXT NOT NULL UNIQUE,
+    mood INTEGER CHECK(mood BETWEEN 1 AND 5),
+    energy INTEGER CHECK(energy BETWEEN 1 AND 5),
+    tags TEXT DEFAULT '',
+    content TEXT DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS update_journal_timestamp
+AFTER UPDATE ON journal_entries
+FOR EACH ROW
+BEGIN
+    UPDATE journal_entries SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 7
+// ═══════════════════════════════════════════════════════════
+
+// O SQLite não altera CHECK de tabelas existentes: a tabela é recriada
+// com o novo tipo 'finance' (as foreign keys estão desligadas na migration)
+const rebuildCategoriesWithFinance = `
+CREATE TABLE categories_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    color TEXT DEFAULT '#3b82f6',
+    type TEXT CHECK(type IN ('task', 'note', 'general', 'finance')) DEFAULT 'general',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO categories_new (id, name, color, type, created_at)
+SELECT id, name, color, type, created_at FROM categories;
+
+DROP TABLE categories;
+
+ALTER TABLE categories_new RENAME TO categories;
+`
+
+// Valores monetários são inteiros em unidades mínimas (centavos) com o código ISO da moeda
+const createAccountsTable = `
+CREATE TABLE IF NOT EXISTS accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    type TEXT CHECK(type IN ('checking', 'savings', 'credit', 'cash', 'investment')) DEFAULT 'checking',
+    currency TEXT NOT NULL DEFAULT 'BRL',
+    initial_balance INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const createRecurringTransactionsTable = `
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    category_id INTEGER,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    description TEXT NOT NULL,
+    frequency TEXT CHECK(frequency IN ('weekly', 'monthly', 'yearly')) DEFAULT 'monthly',
+    next_date TEXT NOT NULL,
+    end_date TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL
+);
+`
+
+// amount negativo = despesa, positivo = receita.
+// import_id identifica lançamentos importados de extratos e evita duplicatas.
+const createTransactionsTable = `
+CREATE TABLE IF NOT EXISTS transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    category_id INTEGER,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    description TEXT,
+    date TEXT NOT NULL,
+    recurring_id INTEGER,
+    import_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL,
+    FOREIGN KEY (recurring_id) REFERENCES recurring_transactions(id) ON DELETE SET NULL,
+    UNIQUE(account_id, import_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_date ON transactions(date);
+CREATE INDEX IF NOT EXISTS idx_transactions_category ON transactions(category_id);
+
+CREATE TRIGGER IF NOT EXISTS update_transaction_timestamp
+AFTER UPDATE ON transactions
+FOR EACH ROW
+BEGIN
+    UPDATE transactions SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`
+
+// Limite mensal de gastos por categoria
+const createBudgetsTable = `
+CREATE TABLE IF NOT EXISTS budgets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL CHECK(amount > 0),
+    currency TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE,
+    UNIQUE(category_id, currency)
+);
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 8
+// ═══════════════════════════════════════════════════════════
+
+// events guarda os filtros separados por vírgula (ex.: "task.completed,note.*")
+const createWebhooksTable = `
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '*',
+    secret TEXT NOT NULL,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS update_webhook_timestamp
+AFTER UPDATE ON webhooks
+FOR EACH ROW
+BEGIN
+    UPDATE webhooks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+`
+
+// Uma linha por tentativa de entrega
+const createWebhookDeliveriesTable = `
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    success BOOLEAN NOT NULL DEFAULT 0,
+    error TEXT,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at);
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 10
+// ═══════════════════════════════════════════════════════════
+
+// Um bloco de tempo é um evento com task_id: no máximo um por tarefa
+const addTimeBlockColumns = `
+ALTER TABLE events ADD COLUMN task_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL;
+ALTER TABLE events ADD COLUMN is_done BOOLEAN DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN estimated_minutes INTEGER;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_task ON events(task_id) WHERE task_id IS NOT NULL;
+`
+
+// O bloco fica concluído junto com a tarefa, venha a mudança do app, da API
+// ou do checklist da nota
+const createTimeBlockTriggers = `
+CREATE TRIGGER IF NOT EXISTS sync_time_block_done
+AFTER UPDATE OF status ON tasks
+FOR EACH ROW
+WHEN NEW.status IS NOT OLD.status
+BEGIN
+    UPDATE events SET is_done = (NEW.status = 'completed') WHERE task_id = NEW.id;
+END;
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 11
+// ═══════════════════════════════════════════════════════════
+
+// A regra de repetição só é guardada e devolvida ao servidor: o calendário
+// do app mostra a primeira ocorrência
+const addRecurrenceRuleColumn = `
+ALTER TABLE events ADD COLUMN recurrence_rule TEXT NOT NULL DEFAULT '';
+`
+
+// Cada evento ou tarefa sincronizado tem um item com o endereço e o ETag do
+// servidor. synced_version é o updated_at da entidade na última
+// sincronização: se mudou, houve alteração local desde então.
+const createCalDAVItemsTable = `
+CREATE TABLE IF NOT EXISTS caldav_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL CHECK(entity_type IN ('event', 'task')),
+    entity_id INTEGER NOT NULL,
+    uid TEXT NOT NULL,
+    href TEXT NOT NULL UNIQUE,
+    etag TEXT NOT NULL DEFAULT '',
+    synced_version DATETIME,
+    synced_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(entity_type, entity_id)
+);
+`
+
+const createCalDAVSyncLogTable = `
+CREATE TABLE IF NOT EXISTS caldav_sync_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    pulled INTEGER NOT NULL DEFAULT 0,
+    pushed INTEGER NOT NULL DEFAULT 0,
+    deleted INTEGER NOT NULL DEFAULT 0,
+    conflicts INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_caldav_sync_log_started ON caldav_sync_log(started_at);
+`
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATIONS - VERSÃO 12
+// ═══════════════════════════════════════════════════════════
+
+// Cada nota sincronizada tem o caminho do arquivo (relativo à pasta, com "/")
+// e o hash do conteúdo na última sincronização: hash diferente é edição
+// externa; synced_version diferente do updated_at é edição no app.
+const createNoteFilesTable = `
+CREATE TABLE IF NOT EXISTS note_files (
+    note_id INTEGER PRIMARY KEY,
+    path TEXT NOT NULL UNIQUE,
+    hash TEXT NOT NULL,
+    synced_version DATETIME
+);
+`
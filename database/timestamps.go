@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Formato das datas no banco. Instantes ficam em UTC, no mesmo formato do
+// CURRENT_TIMESTAMP do SQLite, para que a comparação de texto siga a ordem
+// cronológica e date()/datetime() funcionem. Datas "flutuantes" (eventos de
+// dia inteiro) ficam só com o dia, sem fuso.
+const (
+	TimestampLayout = "2006-01-02 15:04:05"
+	DateLayout      = "2006-01-02"
+)
+
+// FormatTimestamp converte o instante para o formato do banco, em UTC
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(TimestampLayout)
+}
+
+// FormatDate grava só o dia, como ele aparece no fuso do próprio t
+func FormatDate(t time.Time) string {
+	return t.Format(DateLayout)
+}
+
+// ═══════════════════════════════════════════════════════════
+// MIGRATION - VERSÃO 9
+// ═══════════════════════════════════════════════════════════
+
+// storedLayouts são os formatos encontrados em bancos antigos: o time.Time
+// gravado direto pelo driver (com o fuso de quem gravou) e o RFC 3339
+var storedLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	DateLayout,
+}
+
+// parseStoredTime lê uma data em qualquer formato antigo. Sem fuso, vale
+// UTC, como no SQLite.
+func parseStoredTime(value string) (time.Time, bool) {
+	// O String() de um time.Time com leitura monotônica termina em " m=+1.23"
+	value, _, _ = strings.Cut(strings.TrimSpace(value), " m=")
+
+	for _, layout := range storedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// normalizeTimestamps reescreve todas as colunas DATETIME em UTC no formato
+// único. O início e o fim dos eventos de dia inteiro viram datas flutuantes,
+// com o dia que o usuário via quando gravou. Os gatilhos de updated_at ficam
+// desligados durante a conversão, para não marcar todas as linhas como
+// alteradas agora.
+func normalizeTimestamps(ctx context.Context, tx *sql.Tx) error {
+	columns, err := datetimeColumns(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for table, names := range columns {
+		restore, err := suspendTriggers(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+
+		if err := normalizeTable(ctx, tx, table, names); err != nil {
+			return err
+		}
+
+		if err := restore(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// datetimeColumns lista as colunas declaradas como DATETIME, por tabela
+func datetimeColumns(ctx context.Context, tx *sql.Tx) (map[string][]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tabelas: %w", err)
+	}
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("erro ao listar tabelas: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	columns := make(map[string][]string)
+	for _, table := range tables {
+		rows, err := tx.QueryContext(ctx, "SELECT name, type FROM pragma_table_info(?)", table)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler colunas de %s: %w", table, err)
+		}
+
+		for rows.Next() {
+			var name, declared string
+			if err := rows.Scan(&name, &declared); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("erro ao ler colunas de %s: %w", table, err)
+			}
+			if strings.EqualFold(declared, "DATETIME") {
+				columns[table] = append(columns[table], name)
+			}
+		}
+		rows.Close()
+	}
+
+	return columns, nil
+}
+
+// suspendTriggers remove os gatilhos da tabela e retorna a função que os
+// recria com o mesmo SQL
+func suspendTriggers(ctx context.Context, tx *sql.Tx, table string) (func() error, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name, sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ?", table)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar gatilhos de %s: %w", table, err)
+	}
+
+	type trigger struct{ name, sql string }
+	var triggers []trigger
+	for rows.Next() {
+		var t trigger
+		if err := rows.Scan(&t.name, &t.sql); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("erro ao listar gatilhos de %s: %w", table, err)
+		}
+		triggers = append(triggers, t)
+	}
+	rows.Close()
+
+	for _, t := range triggers {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DROP TRIGGER %q", t.name)); err != nil {
+			return nil, fmt.Errorf("erro ao remover gatilho %s: %w", t.name, err)
+		}
+	}
+
+	restore := func() error {
+		for _, t := range triggers {
+			if _, err := tx.ExecContext(ctx, t.sql); err != nil {
+				return fmt.Errorf("erro ao recriar gatilho %s: %w", t.name, err)
+			}
+		}
+		return nil
+	}
+
+	return restore, nil
+}
+
+// normalizeTable converte as colunas de cada linha num único UPDATE, para
+// que CHECKs entre colunas (end_date >= start_date) comparem valores já no
+// mesmo formato
+func normalizeTable(ctx context.Context, tx *sql.Tx, table string, columns []string) error {
+	floating := table == "events"
+
+	selected := make([]string, len(columns))
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		selected[i] = fmt.Sprintf("CAST(%q AS TEXT)", column)
+		assignments[i] = fmt.Sprintf("%q = ?", column)
+	}
+	if floating {
+		selected = append(selected, "all_day")
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT rowid, %s FROM %q", strings.Join(selected, ", "), table))
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", table, err)
+	}
+
+	type change struct {
+		rowID  int64
+		values []interface{}
+	}
+	var changes []change
+
+	for rows.Next() {
+		var (
+			rowID  int64
+			allDay bool
+		)
+		stored := make([]sql.NullString, len(columns))
+
+		dest := []interface{}{&rowID}
+		for i := range stored {
+			dest = append(dest, &stored[i])
+		}
+		if floating {
+			dest = append(dest, &allDay)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return fmt.Errorf("erro ao ler %s: %w", table, err)
+		}
+
+		values := make([]interface{}, len(columns))
+		changed := false
+		for i, column := range columns {
+			values[i] = stored[i]
+			if !stored[i].Valid {
+				continue
+			}
+
+			t, ok := parseStoredTime(stored[i].String)
+			if !ok {
+				slog.Warn("data em formato desconhecido mantida", "tabela", table, "coluna", column, "rowid", rowID, "valor", stored[i].String)
+				continue
+			}
+
+			value := FormatTimestamp(t)
+			if allDay && (column == "start_date" || column == "end_date") {
+				value = FormatDate(t)
+			}
+
+			if value != stored[i].String {
+				values[i] = value
+				changed = true
+			}
+		}
+
+		if changed {
+			changes = append(changes, change{rowID, values})
+		}
+	}
+	rows.Close()
+
+	update := fmt.Sprintf("UPDATE %q SET %s WHERE rowid = ?", table, strings.Join(assignments, ", "))
+	for _, c := range changes {
+		if _, err := tx.ExecContext(ctx, update, append(c.values, c.rowID)...); err != nil {
+			return fmt.Errorf("erro ao converter %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
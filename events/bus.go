@@ -0,0 +1,128 @@
+// Package events é o barramento interno de eventos de domínio. Os services
+// publicam aqui cada mudança (tarefa concluída, nota criada, evento começando)
+// e quem precisa reagir (frontend, webhooks) se inscreve.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event é uma mudança já gravada no banco
+type Event struct {
+	ID         string      `json:"id"`
+	Type       Type        `json:"type"`
+	EntityID   int         `json:"entity_id"`
+	Data       interface{} `json:"data,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// Handler recebe os eventos publicados. É chamado de forma síncrona, na
+// goroutine de quem publicou, então não deve bloquear.
+type Handler func(Event)
+
+type subscription struct {
+	handler  Handler
+	patterns []string
+}
+
+// Bus distribui os eventos para os inscritos. Um *Bus nil é válido e
+// simplesmente descarta tudo (usado pela CLI e pela exportação).
+type Bus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]subscription
+}
+
+// NewBus cria um barramento vazio
+func NewBus() *Bus {
+	return &Bus{subs: make(map[int]subscription)}
+}
+
+// Subscribe inscreve o handler nos tipos que casam com os padrões (ex.:
+// "task.completed", "task.*"). Sem padrões, recebe todos os eventos.
+// Retorna a função que cancela a inscrição.
+func (b *Bus) Subscribe(handler Handler, patterns ...string) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subs[id] = subscription{handler: handler, patterns: patterns}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+// HasSubscribers indica se vale a pena montar o evento (evita buscar a
+// entidade no banco quando ninguém está ouvindo)
+func (b *Bus) HasSubscribers() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs) > 0
+}
+
+// Publish envia o evento para todos os inscritos interessados
+func (b *Bus) Publish(eventType Type, entityID int, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	event := Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		EntityID:   entityID,
+		Data:       data,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.subs))
+	for _, sub := range b.subs {
+		if MatchesAny(sub.patterns, eventType) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// MatchesAny indica se o tipo casa com algum dos padrões. Lista vazia e "*"
+// casam com tudo; "task.*" casa com todos os eventos de tarefa.
+func MatchesAny(patterns []string, eventType Type) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+
+		switch {
+		case pattern == "*" || pattern == string(eventType):
+			return true
+		case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(string(eventType), strings.TrimSuffix(pattern, "*")):
+			return true
+		}
+	}
+
+	return false
+}
+
+func newEventID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
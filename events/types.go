@@ -0,0 +1,104 @@
+package events
+
+import "strings"
+
+// Type identifica o tipo do evento, no formato "entidade.ação"
+type Type string
+
+// Tarefas
+const (
+	TaskCreated   Type = "task.created"
+	TaskUpdated   Type = "task.updated"
+	TaskCompleted Type = "task.completed"
+	TaskReopened  Type = "task.reopened"
+	TaskDeleted   Type = "task.deleted"
+)
+
+// Notas e cadernos
+const (
+	NoteCreated     Type = "note.created"
+	NoteUpdated     Type = "note.updated"
+	NoteDeleted     Type = "note.deleted"
+	NotebookCreated Type = "notebook.created"
+	NotebookUpdated Type = "notebook.updated"
+	NotebookDeleted Type = "notebook.deleted"
+)
+
+// Eventos da agenda
+const (
+	EventCreated  Type = "event.created"
+	EventUpdated  Type = "event.updated"
+	EventDeleted  Type = "event.deleted"
+	EventStarting Type = "event.starting"
+)
+
+// Categorias, anexos e modelos
+const (
+	CategoryCreated   Type = "category.created"
+	CategoryUpdated   Type = "category.updated"
+	CategoryDeleted   Type = "category.deleted"
+	AttachmentCreated Type = "attachment.created"
+	AttachmentUpdated Type = "attachment.updated"
+	AttachmentDeleted Type = "attachment.deleted"
+	TemplateCreated   Type = "template.created"
+	TemplateUpdated   Type = "template.updated"
+	TemplateDeleted   Type = "template.deleted"
+)
+
+// Diário
+const (
+	JournalCreated Type = "journal.created"
+	JournalUpdated Type = "journal.updated"
+	JournalDeleted Type = "journal.deleted"
+)
+
+// Finanças
+const (
+	AccountCreated       Type = "account.created"
+	AccountUpdated       Type = "account.updated"
+	AccountDeleted       Type = "account.deleted"
+	TransactionCreated   Type = "transaction.created"
+	TransactionUpdated   Type = "transaction.updated"
+	TransactionDeleted   Type = "transaction.deleted"
+	TransactionsImported Type = "transaction.imported"
+	BudgetUpdated        Type = "budget.updated"
+	BudgetDeleted        Type = "budget.deleted"
+	RecurringCreated     Type = "recurring.created"
+	RecurringDeleted     Type = "recurring.deleted"
+)
+
+// Webhooks
+const (
+	WebhookCreated Type = "webhook.created"
+	WebhookUpdated Type = "webhook.updated"
+	WebhookDeleted Type = "webhook.deleted"
+	WebhookPing    Type = "webhook.ping"
+)
+
+// Sincronização
+const (
+	CalDAVSynced Type = "caldav.synced"
+)
+
+// AllTypes lista os tipos conhecidos, para montar filtros na interface
+var AllTypes = []Type{
+	TaskCreated, TaskUpdated, TaskCompleted, TaskReopened, TaskDeleted,
+	NoteCreated, NoteUpdated, NoteDeleted,
+	NotebookCreated, NotebookUpdated, NotebookDeleted,
+	EventCreated, EventUpdated, EventDeleted, EventStarting,
+	CategoryCreated, CategoryUpdated, CategoryDeleted,
+	AttachmentCreated, AttachmentUpdated, AttachmentDeleted,
+	TemplateCreated, TemplateUpdated, TemplateDeleted,
+	JournalCreated, JournalUpdated, JournalDeleted,
+	AccountCreated, AccountUpdated, AccountDeleted,
+	TransactionCreated, TransactionUpdated, TransactionDeleted, TransactionsImported,
+	BudgetUpdated, BudgetDeleted,
+	RecurringCreated, RecurringDeleted,
+	CalDAVSynced,
+}
+
+// Entity retorna a entidade do tipo ("task" para "task.completed")
+func (t Type) Entity() string {
+	entity, _, _ := strings.Cut(string(t), ".")
+	return entity
+}
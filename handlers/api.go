@@ -0,0 +1,311 @@
+// Package handlers expõe os services por uma API HTTP/JSON local, para
+// integrações como plugins de editor, launchers e scripts agendados.
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/services"
+	"personal-cockpit/timezone"
+)
+
+// Chaves de configuração da API
+const (
+	SettingAPIEnabled = "api_enabled"
+	SettingAPIPort    = "api_port"
+	SettingAPIToken   = "api_token"
+
+	DefaultAPIPort = 8765
+)
+
+// maxBodySize limita o corpo das requisições (1 MB)
+const maxBodySize = 1 << 20
+
+// requestTimeout é o prazo das consultas de cada requisição, abaixo do
+// WriteTimeout para o erro ainda chegar ao cliente
+const requestTimeout = 20 * time.Second
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// APIServer é o servidor HTTP local. Escuta apenas em 127.0.0.1 e exige o
+// token salvo nas configurações em todas as rotas, exceto /openapi.json.
+type APIServer struct {
+	taskService     *services.TaskService
+	noteService     *services.NoteService
+	eventService    *services.EventService
+	categoryService *services.CategoryService
+	settingsService *services.SettingsService
+
+	mu     sync.Mutex
+	server *http.Server
+	addr   string
+}
+
+// NewAPIServer cria o servidor (ainda parado)
+func NewAPIServer(
+	taskService *services.TaskService,
+	noteService *services.NoteService,
+	eventService *services.EventService,
+	categoryService *services.CategoryService,
+	settingsService *services.SettingsService,
+) *APIServer {
+	return &APIServer{
+		taskService:     taskService,
+		noteService:     noteService,
+		eventService:    eventService,
+		categoryService: categoryService,
+		settingsService: settingsService,
+	}
+}
+
+// Start inicia o servidor na porta informada e retorna o endereço em uso.
+// Se já estiver rodando, não faz nada.
+func (s *APIServer) Start(port int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server != nil {
+		return s.addr, nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return "", i18n.Errorf("erro ao iniciar API na porta %d: %w", port, err)
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("erro no servidor da API", "err", err)
+		}
+	}()
+
+	s.server = server
+	s.addr = listener.Addr().String()
+
+	return s.addr, nil
+}
+
+// Stop encerra o servidor, aguardando as requisições em andamento
+func (s *APIServer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server == nil {
+		return nil
+	}
+
+	err := s.server.Shutdown(ctx)
+	s.server = nil
+	s.addr = ""
+
+	if err != nil {
+		return i18n.Errorf("erro ao parar API: %w", err)
+	}
+
+	return nil
+}
+
+// Address retorna o endereço em uso, ou "" se o servidor estiver parado
+func (s *APIServer) Address() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// Handler monta as rotas da API
+func (s *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+
+	api := http.NewServeMux()
+
+	api.HandleFunc("GET /api/tasks", s.listTasks)
+	api.HandleFunc("POST /api/tasks", s.createTask)
+	api.HandleFunc("GET /api/tasks/{id}", s.getTask)
+	api.HandleFunc("PUT /api/tasks/{id}", s.updateTask)
+	api.HandleFunc("DELETE /api/tasks/{id}", s.deleteTask)
+	api.HandleFunc("POST /api/tasks/{id}/toggle", s.toggleTask)
+
+	api.HandleFunc("GET /api/notes", s.listNotes)
+	api.HandleFunc("POST /api/notes", s.createNote)
+	api.HandleFunc("GET /api/notes/{id}", s.getNote)
+	api.HandleFunc("PUT /api/notes/{id}", s.updateNote)
+	api.HandleFunc("DELETE /api/notes/{id}", s.deleteNote)
+	api.HandleFunc("POST /api/notes/{id}/favorite", s.toggleNoteFavorite)
+
+	api.HandleFunc("GET /api/events", s.listEvents)
+	api.HandleFunc("GET /api/events/today", s.todayEvents)
+	api.HandleFunc("GET /api/events/upcoming", s.upcomingEvents)
+	api.HandleFunc("POST /api/events", s.createEvent)
+	api.HandleFunc("GET /api/events/{id}", s.getEvent)
+	api.HandleFunc("PUT /api/events/{id}", s.updateEvent)
+	api.HandleFunc("DELETE /api/events/{id}", s.deleteEvent)
+
+	api.HandleFunc("GET /api/categories", s.listCategories)
+	api.HandleFunc("POST /api/categories", s.createCategory)
+	api.HandleFunc("GET /api/categories/{id}", s.getCategory)
+	api.HandleFunc("PUT /api/categories/{id}", s.updateCategory)
+	api.HandleFunc("DELETE /api/categories/{id}", s.deleteCategory)
+
+	mux.Handle("/api/", s.requireToken(api))
+
+	return mux
+}
+
+// requireToken exige "Authorization: Bearer <token>". O token é lido das
+// configurações a cada requisição, então gerar um novo invalida o antigo na hora.
+func (s *APIServer) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected, err := s.settingsService.Get(r.Context(), SettingAPIToken, "")
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if expected == "" || !found || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="personal-cockpit"`)
+			writeError(w, http.StatusUnauthorized, errors.New(i18n.T("token inválido ou ausente")))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s *APIServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// GenerateToken cria um token aleatório para a API
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", i18n.Errorf("erro ao gerar token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// HELPERS
+// ═══════════════════════════════════════════════════════════
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// errorBody é o corpo das respostas de erro: "error" com a mensagem (formato
+// original) mais os detalhes dos erros tipados dos services
+type errorBody struct {
+	Error string `json:"error"`
+	services.ErrorPayload
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorBody{Error: err.Error(), ErrorPayload: services.NewErrorPayload(err)})
+}
+
+// writeServiceError escolhe o status HTTP pelo tipo do erro do service
+func writeServiceError(w http.ResponseWriter, err error) {
+	var (
+		validation *services.ValidationError
+		notFound   *services.NotFoundError
+		conflict   *services.ConflictError
+	)
+
+	switch {
+	case errors.As(err, &validation):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.As(err, &notFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.As(err, &conflict):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusServiceUnavailable, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		writeError(w, http.StatusBadRequest, i18n.Errorf("JSON inválido: %w", err))
+		return false
+	}
+	return true
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, i18n.Errorf("ID inválido: %s", r.PathValue("id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// queryID lê um ID opcional da query string
+func queryID(w http.ResponseWriter, r *http.Request, name string) (*int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, true
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, i18n.Errorf("%s inválido: %s", name, value))
+		return nil, false
+	}
+
+	return &id, true
+}
+
+// queryTime aceita RFC 3339 ou AAAA-MM-DD (meia-noite no horário local)
+func queryTime(w http.ResponseWriter, r *http.Request, name string) (time.Time, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+
+	if t, err := timezone.ParseDate(value); err == nil {
+		return t, true
+	}
+
+	writeError(w, http.StatusBadRequest, i18n.Errorf("%s inválido: use RFC 3339 ou AAAA-MM-DD", name))
+	return time.Time{}, false
+}
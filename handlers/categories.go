@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"personal-cockpit/models"
+)
+
+func (s *APIServer) listCategories(w http.ResponseWriter, r *http.Request) {
+	var categories []models.Category
+	var err error
+
+	if categoryType := r.URL.Query().Get("type"); categoryType != "" {
+		categories, err = s.categoryService.GetCategoriesByType(r.Context(), categoryType)
+	} else {
+		categories, err = s.categoryService.GetAllCategories(r.Context())
+	}
+
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if categories == nil {
+		categories = []models.Category{}
+	}
+
+	writeJSON(w, http.StatusOK, categories)
+}
+
+func (s *APIServer) createCategory(w http.ResponseWriter, r *http.Request) {
+	var category models.Category
+	if !decodeBody(w, r, &category) {
+		return
+	}
+
+	id, err := s.categoryService.CreateCategory(r.Context(), category)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeCategory(w, r, int(id), http.StatusCreated)
+}
+
+func (s *APIServer) getCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeCategory(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) updateCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var category models.Category
+	if !decodeBody(w, r, &category) {
+		return
+	}
+	category.ID = id
+
+	if err := s.categoryService.UpdateCategory(r.Context(), category); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeCategory(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.categoryService.DeleteCategory(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) writeCategory(w http.ResponseWriter, r *http.Request, id int, status int) {
+	category, err := s.categoryService.GetCategoryByID(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, status, category)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+func (s *APIServer) listEvents(w http.ResponseWriter, r *http.Request) {
+	from, ok := queryTime(w, r, "from")
+	if !ok {
+		return
+	}
+
+	to, ok := queryTime(w, r, "to")
+	if !ok {
+		return
+	}
+
+	var events []models.Event
+	var err error
+
+	switch {
+	case from.IsZero() && to.IsZero():
+		events, err = s.eventService.GetAllEvents(r.Context())
+	case from.IsZero() || to.IsZero():
+		writeError(w, http.StatusBadRequest, errors.New(i18n.T("informe from e to juntos")))
+		return
+	default:
+		events, err = s.eventService.GetEventsByDateRange(r.Context(), from, to)
+	}
+
+	s.writeEvents(w, events, err)
+}
+
+func (s *APIServer) todayEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := s.eventService.GetTodayEvents(r.Context())
+	s.writeEvents(w, events, err)
+}
+
+func (s *APIServer) upcomingEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := s.eventService.GetUpcomingEvents(r.Context())
+	s.writeEvents(w, events, err)
+}
+
+func (s *APIServer) createEvent(w http.ResponseWriter, r *http.Request) {
+	var event models.Event
+	if !decodeBody(w, r, &event) {
+		return
+	}
+
+	saved, err := s.eventService.CreateEvent(r.Context(), event)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeEvent(w, r, saved.ID, http.StatusCreated)
+}
+
+func (s *APIServer) getEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeEvent(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) updateEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var event models.Event
+	if !decodeBody(w, r, &event) {
+		return
+	}
+	event.ID = id
+
+	if _, err := s.eventService.UpdateEvent(r.Context(), event); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeEvent(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) deleteEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.eventService.DeleteEvent(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) writeEvent(w http.ResponseWriter, r *http.Request, id int, status int) {
+	event, err := s.eventService.GetEventByID(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, status, event)
+}
+
+func (s *APIServer) writeEvents(w http.ResponseWriter, events []models.Event, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if events == nil {
+		events = []models.Event{}
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
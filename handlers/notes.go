@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"personal-cockpit/models"
+)
+
+func (s *APIServer) listNotes(w http.ResponseWriter, r *http.Request) {
+	var notes []models.Note
+	var err error
+
+	switch query := r.URL.Query(); {
+	case query.Get("q") != "":
+		notes, err = s.noteService.SearchNotes(r.Context(), query.Get("q"))
+	case query.Get("favorite") == "true":
+		notes, err = s.noteService.GetFavoriteNotes(r.Context())
+	default:
+		notes, err = s.noteService.GetAllNotes(r.Context())
+	}
+
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if notes == nil {
+		notes = []models.Note{}
+	}
+
+	writeJSON(w, http.StatusOK, notes)
+}
+
+func (s *APIServer) createNote(w http.ResponseWriter, r *http.Request) {
+	var note models.Note
+	if !decodeBody(w, r, &note) {
+		return
+	}
+
+	id, err := s.noteService.CreateNote(r.Context(), note)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeNote(w, r, int(id), http.StatusCreated)
+}
+
+func (s *APIServer) getNote(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeNote(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) updateNote(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var note models.Note
+	if !decodeBody(w, r, &note) {
+		return
+	}
+	note.ID = id
+
+	if err := s.noteService.UpdateNote(r.Context(), note); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeNote(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) deleteNote(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.noteService.DeleteNote(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) toggleNoteFavorite(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.noteService.ToggleFavorite(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeNote(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) writeNote(w http.ResponseWriter, r *http.Request, id int, status int) {
+	note, err := s.noteService.GetNoteByID(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, status, note)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"personal-cockpit/models"
+)
+
+func (s *APIServer) listTasks(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := queryID(w, r, "category_id")
+	if !ok {
+		return
+	}
+
+	filter := models.TaskFilter{
+		Status:     r.URL.Query().Get("status"),
+		Priority:   r.URL.Query().Get("priority"),
+		CategoryID: categoryID,
+	}
+
+	tasks, err := s.taskService.GetTasksByFilter(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *APIServer) createTask(w http.ResponseWriter, r *http.Request) {
+	var task models.Task
+	if !decodeBody(w, r, &task) {
+		return
+	}
+
+	if task.Status == "" {
+		task.Status = "pending"
+	}
+
+	id, err := s.taskService.CreateTask(r.Context(), task)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeTask(w, r, int(id), http.StatusCreated)
+}
+
+func (s *APIServer) getTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeTask(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) updateTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var task models.Task
+	if !decodeBody(w, r, &task) {
+		return
+	}
+	task.ID = id
+
+	if err := s.taskService.UpdateTask(r.Context(), task); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeTask(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) deleteTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.taskService.DeleteTask(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *APIServer) toggleTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.taskService.ToggleTaskStatus(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	s.writeTask(w, r, id, http.StatusOK)
+}
+
+func (s *APIServer) writeTask(w http.ResponseWriter, r *http.Request, id int, status int) {
+	task, err := s.taskService.GetTaskByID(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, status, task)
+}
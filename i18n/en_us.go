@@ -0,0 +1,333 @@
+package i18n
+
+// enUS traduz as mensagens do português para o inglês (EUA)
+var enUS = map[string]string{
+	// Validação e erros tipados
+	"título":                 "title",
+	"ID da nota":             "note ID",
+	"data (AAAA-MM-DD)":      "date (YYYY-MM-DD)",
+	"primeiro dia da semana": "first day of the week",
+	"visão":                  "view",
+	"segredo inválido: %w":   "invalid secret: %w",
+	"segredo inválido":       "invalid secret",
+	"não foi possível decifrar o segredo (a chave secret.key mudou?)": "could not decrypt the secret (did the secret.key file change?)",
+	"chave inválida em %s":                    "invalid key in %s",
+	"data inválida: %w":                       "invalid date: %w",
+	"data inválida, use o formato AAAA-MM-DD": "invalid date, use the YYYY-MM-DD format",
+	"humor deve estar entre 1 e 5":            "mood must be between 1 and 5",
+	"energia deve estar entre 1 e 5":          "energy must be between 1 and 5",
+	"ID da entrada":                           "entry ID",
+	"nome":                                    "name",
+	"cor (formato #rrggbb)":                   "color (#rrggbb format)",
+	"tipo":                                    "type",
+	"ID da categoria":                         "category ID",
+	"uma categoria":                           "a category",
+	"Hoje: %s":                                "Today: %s",
+	"%s começa às %s":                         "%s starts at %s",
+	"data de início":                          "start date",
+	"data de término":                         "end date",
+	"data de término deve ser após data de início": "end date must be after start date",
+	"ID do evento": "event ID",
+	"chave da configuração é obrigatória":      "setting key is required",
+	"modelo \"%s\" não é um modelo de nota":    "template \"%s\" is not a note template",
+	"modelo \"%s\" não é um modelo de tarefas": "template \"%s\" is not a task template",
+	"%s (modelo \"%s\")":                       "%s (template \"%s\")",
+	"tipo (note ou tasks)":                     "kind (note or tasks)",
+	"ID do modelo":                             "template ID",
+	"um modelo":                                "a template",
+	"anexo não é uma imagem":                   "attachment is not an image",
+	"formato de imagem não suportado: %w":      "unsupported image format: %w",
+	"nome do arquivo":                          "file name",
+	"ID do item vinculado":                     "linked item ID",
+	"tipo do item vinculado":                   "linked item type",
+	"ID do anexo":                              "attachment ID",
+	"a pasta está vazia, mas %d notas estão vinculadas a ela; nada foi apagado": "the folder is empty but %d notes are linked to it; nothing was deleted",
+	" (conflito %s)":                   " (conflict %s)",
+	"sem título":                       "untitled",
+	"pasta das notas":                  "notes folder",
+	"caminho absoluto da pasta":        "absolute folder path",
+	"data inválida na recorrência: %w": "invalid date in recurring transaction: %w",
+	"conta":                            "account",
+	"valor":                            "amount",
+	"categoria":                        "category",
+	"limite (maior que zero)":          "limit (greater than zero)",
+	"moeda (código ISO de 3 letras)":   "currency (3-letter ISO code)",
+	"frequência":                       "frequency",
+	"ID da conta":                      "account ID",
+	"ID da transação":                  "transaction ID",
+	"uma conta":                        "an account",
+	"não é possível mover um caderno para dentro dele mesmo": "a notebook cannot be moved into itself",
+	"prioridade":                                                    "priority",
+	"duração estimada deve ser positiva":                            "estimated duration must be positive",
+	"status":                                                        "status",
+	"ID da tarefa":                                                  "task ID",
+	"já existe uma tarefa igual":                                    "an identical task already exists",
+	"concluída":                                                     "completed",
+	"repetida no arquivo":                                           "repeated in the file",
+	"Campos obrigatórios:":                                          "Required fields:",
+	"Campos inválidos:":                                             "Invalid fields:",
+	"%s não encontrado":                                             "%s not found",
+	"já existe %s com esse nome":                                    "%s with this name already exists",
+	"banco de dados indisponível: %v":                               "database unavailable: %v",
+	"operação cancelada":                                            "operation cancelled",
+	"tempo limite da operação esgotado":                             "operation timed out",
+	"dias do expediente":                                            "working days",
+	"início do expediente (HH:MM)":                                  "working hours start (HH:MM)",
+	"fim do expediente (HH:MM)":                                     "working hours end (HH:MM)",
+	"fim do expediente deve ser após o início":                      "working hours must end after they start",
+	"dias do expediente (0 = domingo a 6 = sábado)":                 "working days (0 = Sunday to 6 = Saturday)",
+	"folga não pode ser negativa":                                   "buffer cannot be negative",
+	"fim do período deve ser após o início":                         "end of the period must be after the start",
+	"duração deve ser positiva":                                     "duration must be positive",
+	"sincronização já em andamento":                                 "sync already in progress",
+	"endereço da coleção de eventos":                                "events collection URL",
+	"intervalo não pode ser negativo":                               "interval cannot be negative",
+	"política de conflito":                                          "conflict policy",
+	"evento desconhecido: %s":                                       "unknown event: %s",
+	"URL (http:// ou https://)":                                     "URL (http:// or https://)",
+	"ID do webhook":                                                 "webhook ID",
+	"início do bloco":                                               "block start",
+	"tarefa já concluída":                                           "task already completed",
+	"formato de extrato não suportado: %s":                          "unsupported statement format: %s",
+	"extrato CSV vazio":                                             "empty CSV statement",
+	"CSV precisa das colunas de data e valor":                       "CSV needs date and amount columns",
+	"extrato em %s, mas a conta usa %s":                             "statement in %s, but the account uses %s",
+	"data inválida: %q":                                             "invalid date: %q",
+	"valor inválido: %q":                                            "invalid amount: %q",
+	"valor com mais casas decimais do que a moeda permite (%d): %q": "amount has more decimal places than the currency allows (%d): %q",
+	"já existe uma nota igual":                                      "a matching note already exists",
+	"repetida na importação":                                        "repeated in the import",
+	"origem":                                                        "source",
+	"destino das pastas":                                            "folder destination",
+	"no mesmo horário: %s":                                          "at the same time: %s",
+	"informe o título":                                              "enter a title",
+	"tarefas não têm local: @%s ignorado":                           "tasks have no location: @%s ignored",
+	"tarefas não têm duração: horário de término ignorado":          "tasks have no duration: end time ignored",
+	"informe a data do evento":                                      "enter the event date",
+	"eventos não têm prioridade: !%s ignorado":                      "events have no priority: !%s ignored",
+	"eventos não têm categoria: #%s ignorado":                       "events have no category: #%s ignored",
+	"texto":                         "text",
+	"Exportado em %s (v%s)":         "Exported on %s (v%s)",
+	"Tarefas":                       "Tasks",
+	"vence %s":                      "due %s",
+	"Eventos":                       "Events",
+	"dia inteiro":                   "all day",
+	"Notas":                         "Notes",
+	"Diário":                        "Journal",
+	"Humor: %d/5":                   "Mood: %d/5",
+	"Energia: %d/5":                 "Energy: %d/5",
+	"Tags: %s":                      "Tags: %s",
+	"fuso horário desconhecido: %s": "unknown time zone: %s",
+	"token inválido ou ausente":     "missing or invalid token",
+	"JSON inválido: %w":             "invalid JSON: %w",
+	"ID inválido: %s":               "invalid ID: %s",
+	"%s inválido: %s":               "invalid %s: %s",
+	"%s inválido: use RFC 3339 ou AAAA-MM-DD":          "invalid %s: use RFC 3339 or YYYY-MM-DD",
+	"informe from e to juntos":                         "provide from and to together",
+	"arquivo ENEX inválido: %w":                        "invalid ENEX file: %w",
+	"nota %d":                                          "note %d",
+	"arquivo não é uma exportação do Evernote":         "file is not an Evernote export",
+	"anexo inválido: %w":                               "invalid attachment: %w",
+	"anexo-%d":                                         "attachment-%d",
+	"JSON do Todoist inválido: %w":                     "invalid Todoist JSON: %w",
+	"tarefa %s":                                        "task %s",
+	"tarefa %d":                                        "task %d",
+	"linha %d":                                         "line %d",
+	"JSON do Trello inválido: %w":                      "invalid Trello JSON: %w",
+	"cartão %d":                                        "card %d",
+	"cartão arquivado":                                 "archived card",
+	"lista arquivada":                                  "archived list",
+	"escolha a coluna do título":                       "choose the title column",
+	"coluna não encontrada no CSV: %s":                 "column not found in CSV: %s",
+	"CSV sem tarefas":                                  "CSV has no tasks",
+	"não é uma pasta: %s":                              "not a folder: %s",
+	"resposta PROPFIND inválida: %w":                   "invalid PROPFIND response: %w",
+	"servidor CalDAV recusou as credenciais (HTTP %d)": "CalDAV server rejected the credentials (HTTP %d)",
+	"servidor CalDAV respondeu %s":                     "CalDAV server responded %s",
+	"(sem título)":                                     "(untitled)",
+	"objeto sem VEVENT nem VTODO":                      "object has no VEVENT or VTODO",
+	"VEVENT %s sem DTSTART":                            "VEVENT %s has no DTSTART",
+	"linha %d: %w":                                     "line %d: %w",
+	"linha %d: END:%s sem BEGIN":                       "line %d: END:%s without BEGIN",
+	"linha %d: propriedade fora de um componente":      "line %d: property outside a component",
+	"iCalendar vazio":                                  "empty iCalendar",
+	"BEGIN:%s sem END":                                 "BEGIN:%s without END",
+	"propriedade inválida: %q":                         "invalid property: %q",
+	"parâmetro inválido: %q":                           "invalid parameter: %q",
+	"aspas sem fechar: %q":                             "unclosed quotes: %q",
+	"propriedade sem valor: %q":                        "property without value: %q",
+	"data inválida em %s: %q":                          "invalid date in %s: %q",
+	"duração inválida: %q":                             "invalid duration: %q",
+	"Erro ao obter diretório de dados:":                "Error getting data directory:",
+	"App inicializado com sucesso!":                    "App started successfully!",
+	"Erro ao inicializar banco:":                       "Error initializing database:",
+	"Erro ao iniciar sincronização da pasta de notas:": "Error starting notes folder sync:",
+	"Erro ao iniciar observador de mudanças:":          "Error starting change watcher:",
+	"Erro ao iniciar API local:":                       "Error starting local API:",
+	"Erro ao processar recorrências:":                  "Error processing recurring transactions:",
+	"%d lançamentos recorrentes gerados":               "%d recurring transactions generated",
+	"Erro ao copiar banco:":                            "Error backing up database:",
+	"o banco não está aberto":                          "the database is not open",
+	"Erro ao verificar eventos:":                       "Error checking events:",
+	"Abrir banco de dados":                             "Open database",
+	"a operação %s já está em andamento":               "operation %s is already running",
+	"Importar tarefas":                                 "Import tasks",
+	"Listas de tarefas (*.txt, *.json, *.csv)":         "Task lists (*.txt, *.json, *.csv)",
+	"Importar notas do Evernote":                       "Import notes from Evernote",
+	"Exportação do Evernote (*.enex)":                  "Evernote export (*.enex)",
+	"Importar pasta de notas":                          "Import notes folder",
+	"Importar extrato":                                 "Import statement",
+	"Extratos (*.ofx, *.qfx, *.csv)":                   "Statements (*.ofx, *.qfx, *.csv)",
+	"Selecionar arquivos":                              "Select files",
+	"Olá %s! Bem-vindo ao Personal Cockpit v%s":        "Hello %s! Welcome to Personal Cockpit v%s",
+	"tarefa não encontrada":                            "task not found",
+	"nota não encontrada":                              "note not found",
+	"evento não encontrado":                            "event not found",
+	"categoria não encontrada":                         "category not found",
+	"caderno não encontrado":                           "notebook not found",
+	"modelo não encontrado":                            "template not found",
+	"anexo não encontrado":                             "attachment not found",
+	"entrada do diário não encontrada":                 "journal entry not found",
+	"conta não encontrada":                             "account not found",
+	"transação não encontrada":                         "transaction not found",
+	"orçamento não encontrado":                         "budget not found",
+	"recorrência não encontrada":                       "recurring transaction not found",
+	"webhook não encontrado":                           "webhook not found",
+
+	// Falhas de banco, arquivo e rede
+	"erro ao abrir arquivo: %w":                  "error opening file: %w",
+	"erro ao abrir banco: %w":                    "error opening database: %w",
+	"erro ao abrir conexão de observação: %w":    "error opening watcher connection: %w",
+	"erro ao abrir extrato: %w":                  "error opening statement: %w",
+	"erro ao abrir imagem: %w":                   "error opening image: %w",
+	"erro ao abrir pasta: %w":                    "error opening folder: %w",
+	"erro ao apagar item CalDAV: %w":             "error deleting CalDAV item: %w",
+	"erro ao apagar itens CalDAV: %w":            "error deleting CalDAV items: %w",
+	"erro ao apagar vínculo da pasta: %w":        "error deleting folder link: %w",
+	"erro ao apagar vínculos da pasta: %w":       "error deleting folder links: %w",
+	"erro ao atualizar anexo: %w":                "error updating attachment: %w",
+	"erro ao atualizar caderno: %w":              "error updating notebook: %w",
+	"erro ao atualizar categoria: %w":            "error updating category: %w",
+	"erro ao atualizar conta: %w":                "error updating account: %w",
+	"erro ao atualizar entrada do diário: %w":    "error updating journal entry: %w",
+	"erro ao atualizar evento: %w":               "error updating event: %w",
+	"erro ao atualizar modelo: %w":               "error updating template: %w",
+	"erro ao atualizar nota: %w":                 "error updating note: %w",
+	"erro ao atualizar recorrência: %w":          "error updating recurring transaction: %w",
+	"erro ao atualizar tarefa: %w":               "error updating task: %w",
+	"erro ao atualizar transação: %w":            "error updating transaction: %w",
+	"erro ao atualizar webhook: %w":              "error updating webhook: %w",
+	"erro ao buscar anexos: %w":                  "error fetching attachments: %w",
+	"erro ao buscar cadernos: %w":                "error fetching notebooks: %w",
+	"erro ao buscar categorias: %w":              "error fetching categories: %w",
+	"erro ao buscar configuração %s: %w":         "error fetching setting %s: %w",
+	"erro ao buscar configurações: %w":           "error fetching settings: %w",
+	"erro ao buscar contas: %w":                  "error fetching accounts: %w",
+	"erro ao buscar entrada do diário: %w":       "error fetching journal entry: %w",
+	"erro ao buscar entradas do diário: %w":      "error fetching journal entries: %w",
+	"erro ao buscar entregas: %w":                "error fetching deliveries: %w",
+	"erro ao buscar eventos: %w":                 "error fetching events: %w",
+	"erro ao buscar humor: %w":                   "error fetching mood: %w",
+	"erro ao buscar itens CalDAV: %w":            "error fetching CalDAV items: %w",
+	"erro ao buscar log CalDAV: %w":              "error fetching CalDAV log: %w",
+	"erro ao buscar modelos: %w":                 "error fetching templates: %w",
+	"erro ao buscar notas: %w":                   "error fetching notes: %w",
+	"erro ao buscar orçamentos: %w":              "error fetching budgets: %w",
+	"erro ao buscar recorrências: %w":            "error fetching recurring transactions: %w",
+	"erro ao buscar resumo: %w":                  "error fetching summary: %w",
+	"erro ao buscar tarefas: %w":                 "error fetching tasks: %w",
+	"erro ao buscar transações: %w":              "error fetching transactions: %w",
+	"erro ao buscar versão do schema: %w":        "error fetching schema version: %w",
+	"erro ao buscar vínculos da pasta: %w":       "error fetching folder links: %w",
+	"erro ao buscar webhooks: %w":                "error fetching webhooks: %w",
+	"erro ao confirmar transação: %w":            "error committing transaction: %w",
+	"erro ao contar referências: %w":             "error counting references: %w",
+	"erro ao copiar arquivo: %w":                 "error copying file: %w",
+	"erro ao criar anexo: %w":                    "error creating attachment: %w",
+	"erro ao criar arquivo temporário: %w":       "error creating temporary file: %w",
+	"erro ao criar caderno: %w":                  "error creating notebook: %w",
+	"erro ao criar categoria: %w":                "error creating category: %w",
+	"erro ao criar conta: %w":                    "error creating account: %w",
+	"erro ao criar diretório de arquivos: %w":    "error creating files directory: %w",
+	"erro ao criar diretório de miniaturas: %w":  "error creating thumbnails directory: %w",
+	"erro ao criar entrada do diário: %w":        "error creating journal entry: %w",
+	"erro ao criar evento: %w":                   "error creating event: %w",
+	"erro ao criar modelo: %w":                   "error creating template: %w",
+	"erro ao criar nota: %w":                     "error creating note: %w",
+	"erro ao criar pasta da chave: %w":           "error creating key folder: %w",
+	"erro ao criar pasta das notas: %w":          "error creating notes folder: %w",
+	"erro ao criar recorrência: %w":              "error creating recurring transaction: %w",
+	"erro ao criar tarefa: %w":                   "error creating task: %w",
+	"erro ao criar transação: %w":                "error creating transaction: %w",
+	"erro ao criar webhook: %w":                  "error creating webhook: %w",
+	"erro ao deletar anexo: %w":                  "error deleting attachment: %w",
+	"erro ao deletar caderno: %w":                "error deleting notebook: %w",
+	"erro ao deletar categoria: %w":              "error deleting category: %w",
+	"erro ao deletar conta: %w":                  "error deleting account: %w",
+	"erro ao deletar entrada do diário: %w":      "error deleting journal entry: %w",
+	"erro ao deletar entregas: %w":               "error deleting deliveries: %w",
+	"erro ao deletar evento: %w":                 "error deleting event: %w",
+	"erro ao deletar modelo: %w":                 "error deleting template: %w",
+	"erro ao deletar nota: %w":                   "error deleting note: %w",
+	"erro ao deletar orçamento: %w":              "error deleting budget: %w",
+	"erro ao deletar recorrência: %w":            "error deleting recurring transaction: %w",
+	"erro ao deletar tarefa: %w":                 "error deleting task: %w",
+	"erro ao deletar transação: %w":              "error deleting transaction: %w",
+	"erro ao deletar webhook: %w":                "error deleting webhook: %w",
+	"erro ao gerar JSON: %w":                     "error generating JSON: %w",
+	"erro ao gerar diagnóstico: %w":              "error generating diagnostics: %w",
+	"erro ao gerar miniatura: %w":                "error generating thumbnail: %w",
+	"erro ao gerar segredo: %w":                  "error generating secret: %w",
+	"erro ao gerar token: %w":                    "error generating token: %w",
+	"erro ao gravar chave: %w":                   "error writing key: %w",
+	"erro ao gravar exportação: %w":              "error writing export: %w",
+	"erro ao gravar item CalDAV: %w":             "error saving CalDAV item: %w",
+	"erro ao gravar log CalDAV: %w":              "error saving CalDAV log: %w",
+	"erro ao gravar vínculo da pasta: %w":        "error saving folder link: %w",
+	"erro ao iniciar API na porta %d: %w":        "error starting API on port %d: %w",
+	"erro ao iniciar transação: %w":              "error starting transaction: %w",
+	"erro ao ler %s: %w":                         "error reading %s: %w",
+	"erro ao ler CSV: %w":                        "error reading CSV: %w",
+	"erro ao ler anexo: %w":                      "error reading attachment: %w",
+	"erro ao ler caderno: %w":                    "error reading notebook: %w",
+	"erro ao ler categoria: %w":                  "error reading category: %w",
+	"erro ao ler chave: %w":                      "error reading key: %w",
+	"erro ao ler configuração: %w":               "error reading setting: %w",
+	"erro ao ler conta: %w":                      "error reading account: %w",
+	"erro ao ler entrada do diário: %w":          "error reading journal entry: %w",
+	"erro ao ler entrega: %w":                    "error reading delivery: %w",
+	"erro ao ler estatísticas do banco: %w":      "error reading database stats: %w",
+	"erro ao ler evento: %w":                     "error reading event: %w",
+	"erro ao ler extrato: %w":                    "error reading statement: %w",
+	"erro ao ler humor: %w":                      "error reading mood: %w",
+	"erro ao ler item CalDAV: %w":                "error reading CalDAV item: %w",
+	"erro ao ler log CalDAV: %w":                 "error reading CalDAV log: %w",
+	"erro ao ler logs: %w":                       "error reading logs: %w",
+	"erro ao ler modelo: %w":                     "error reading template: %w",
+	"erro ao ler nota: %w":                       "error reading note: %w",
+	"erro ao ler orçamento: %w":                  "error reading budget: %w",
+	"erro ao ler pasta das notas: %w":            "error reading notes folder: %w",
+	"erro ao ler pasta: %w":                      "error reading folder: %w",
+	"erro ao ler recorrência: %w":                "error reading recurring transaction: %w",
+	"erro ao ler resumo: %w":                     "error reading summary: %w",
+	"erro ao ler tarefa: %w":                     "error reading task: %w",
+	"erro ao ler transação: %w":                  "error reading transaction: %w",
+	"erro ao ler vínculo da pasta: %w":           "error reading folder link: %w",
+	"erro ao ler webhook: %w":                    "error reading webhook: %w",
+	"erro ao montar requisição: %w":              "error building request: %w",
+	"erro ao mover caderno: %w":                  "error moving notebook: %w",
+	"erro ao mover nota: %w":                     "error moving note: %w",
+	"erro ao obter ID: %w":                       "error getting ID: %w",
+	"erro ao parar API: %w":                      "error stopping API: %w",
+	"erro ao preparar arquivo: %w":               "error preparing file: %w",
+	"erro ao remover arquivo: %w":                "error removing file: %w",
+	"erro ao remover configuração %s: %w":        "error removing setting %s: %w",
+	"erro ao remover miniatura: %w":              "error removing thumbnail: %w",
+	"erro ao salvar arquivo: %w":                 "error saving file: %w",
+	"erro ao salvar configuração %s: %w":         "error saving setting %s: %w",
+	"erro ao salvar miniatura: %w":               "error saving thumbnail: %w",
+	"erro ao salvar orçamento: %w":               "error saving budget: %w",
+	"erro ao sincronizar tarefa: %w":             "error syncing task: %w",
+	"erro ao verificar hierarquia: %w":           "error checking hierarchy: %w",
+	"erro ao verificar integridade do banco: %w": "error checking database integrity: %w",
+}
@@ -0,0 +1,143 @@
+// Package i18n traduz as mensagens do app. O português é o idioma de origem:
+// as chaves do catálogo são o próprio texto em português (como no gettext),
+// então o código continua legível e uma mensagem sem tradução simplesmente
+// aparece em português.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Idiomas suportados
+const (
+	PtBR = "pt-BR"
+	EnUS = "en-US"
+
+	// DefaultLocale é o idioma de origem das mensagens
+	DefaultLocale = PtBR
+)
+
+// SettingLocale é a chave da configuração com o idioma escolhido
+const SettingLocale = "language"
+
+// catalogs traz as traduções a partir do português. O pt-BR não precisa de
+// catálogo.
+var catalogs = map[string]map[string]string{
+	EnUS: enUS,
+}
+
+// Formatos de data de cada idioma
+type dateLayouts struct {
+	date     string
+	dateTime string
+	time     string
+}
+
+var layouts = map[string]dateLayouts{
+	PtBR: {date: "02/01/2006", dateTime: "02/01/2006 15:04", time: "15:04"},
+	EnUS: {date: "01/02/2006", dateTime: "01/02/2006 3:04 PM", time: "3:04 PM"},
+}
+
+var current atomic.Value
+
+func init() {
+	current.Store(DefaultLocale)
+}
+
+// Supported lista os idiomas com catálogo, o padrão primeiro
+func Supported() []string {
+	return []string{PtBR, EnUS}
+}
+
+// Resolve escolhe o idioma suportado mais próximo do pedido: o próprio
+// ("en-US"), outro da mesma língua ("en-GB" → "en-US", "pt" → "pt-BR") ou,
+// por fim, o padrão. Aceita também o formato do sistema ("en_US.UTF-8").
+func Resolve(tag string) string {
+	tag = normalize(tag)
+	if tag == "" {
+		return DefaultLocale
+	}
+
+	for _, locale := range Supported() {
+		if strings.EqualFold(locale, tag) {
+			return locale
+		}
+	}
+
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range Supported() {
+		if prefix, _, _ := strings.Cut(locale, "-"); strings.EqualFold(prefix, language) {
+			return locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+// FromEnvironment lê o idioma do sistema (LC_ALL, LC_MESSAGES, LANG), usado
+// quando o usuário ainda não escolheu um nas configurações
+func FromEnvironment() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" && value != "C" && value != "POSIX" {
+			return value
+		}
+	}
+	return ""
+}
+
+// SetLocale troca o idioma do app e retorna o que foi de fato aplicado
+func SetLocale(tag string) string {
+	locale := Resolve(tag)
+	current.Store(locale)
+	return locale
+}
+
+// Locale retorna o idioma em uso
+func Locale() string {
+	return current.Load().(string)
+}
+
+// T traduz a mensagem para o idioma em uso
+func T(message string) string {
+	if translated, ok := catalogs[Locale()][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf traduz o formato e aplica os argumentos
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf é o fmt.Errorf traduzido (aceita %w)
+func Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// FormatDate formata a data no padrão do idioma (19/10/2026 ou 10/19/2026)
+func FormatDate(t time.Time) string {
+	return t.Format(layouts[Locale()].date)
+}
+
+// FormatDateTime formata data e hora no padrão do idioma
+func FormatDateTime(t time.Time) string {
+	return t.Format(layouts[Locale()].dateTime)
+}
+
+// FormatTime formata só o horário no padrão do idioma
+func FormatTime(t time.Time) string {
+	return t.Format(layouts[Locale()].time)
+}
+
+// normalize converte "en_US.UTF-8" em "en-US"
+func normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	return strings.ReplaceAll(tag, "_", "-")
+}
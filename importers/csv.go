@@ -0,0 +1,155 @@
+package importers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+// CSV lê uma planilha qualquer com cabeçalho, com as colunas escolhidas em
+// Options.CSVMapping (sugeridas por Columns). Só o título é obrigatório.
+type CSV struct{}
+
+func (CSV) Name() string { return "csv" }
+
+func (CSV) Parse(data []byte, options Options) ([]Task, []Failure, error) {
+	records, err := readCSV(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mapping := options.CSVMapping
+	if mapping.Title == "" {
+		return nil, nil, i18n.Errorf("escolha a coluna do título")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, header := range records[0] {
+		columns[strings.TrimSpace(header)] = i
+	}
+
+	index := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := columns[name]
+		if !ok {
+			return -1, i18n.Errorf("coluna não encontrada no CSV: %s", name)
+		}
+		return i, nil
+	}
+
+	var cols [6]int
+	for i, name := range []string{mapping.Title, mapping.Description, mapping.Priority, mapping.Status, mapping.Category, mapping.DueDate} {
+		if cols[i], err = index(name); err != nil {
+			return nil, nil, err
+		}
+	}
+	titleCol, descCol, priorityCol, statusCol, categoryCol, dueCol := cols[0], cols[1], cols[2], cols[3], cols[4], cols[5]
+
+	var tasks []Task
+	var failures []Failure
+
+	for line, record := range records[1:] {
+		get := func(col int) string {
+			if col < 0 || col >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[col])
+		}
+
+		task := Task{
+			Ref:         i18n.Sprintf("linha %d", line+2),
+			Title:       get(titleCol),
+			Description: get(descCol),
+			Priority:    normalizePriority(get(priorityCol)),
+			Completed:   isDone(get(statusCol)),
+			Category:    get(categoryCol),
+		}
+
+		due, err := parseDate(get(dueCol))
+		if err != nil {
+			failures = append(failures, Failure{Ref: task.Ref, Title: task.Title, Reason: err.Error()})
+			continue
+		}
+		task.DueDate = due
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, failures, nil
+}
+
+// Columns lê o cabeçalho do CSV, sugere as colunas de cada campo pelos nomes
+// mais comuns e devolve algumas linhas de amostra, para a tela de mapeamento
+func Columns(data []byte) (*models.CSVPreview, error) {
+	records, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.CSVPreview{Columns: records[0], Sample: [][]string{}}
+	for _, record := range records[1:min(len(records), 6)] {
+		preview.Sample = append(preview.Sample, record)
+	}
+
+	suggest := func(field *string, names ...string) {
+		for _, header := range records[0] {
+			for _, name := range names {
+				if *field == "" && strings.EqualFold(strings.TrimSpace(header), name) {
+					*field = strings.TrimSpace(header)
+				}
+			}
+		}
+	}
+
+	m := &preview.Mapping
+	suggest(&m.Title, "título", "titulo", "title", "tarefa", "task", "nome", "name", "content", "assunto", "subject")
+	suggest(&m.Description, "descrição", "descricao", "description", "notas", "notes", "detalhes", "details")
+	suggest(&m.Priority, "prioridade", "priority")
+	suggest(&m.Status, "status", "situação", "situacao", "concluída", "concluida", "done", "completed")
+	suggest(&m.Category, "categoria", "category", "projeto", "project", "lista", "list")
+	suggest(&m.DueDate, "prazo", "vencimento", "data", "due", "due date", "due_date", "date")
+
+	return preview, nil
+}
+
+// readCSV lê o CSV com cabeçalho, separado por vírgula, ponto e vírgula ou
+// tabulação
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectSeparator(data)
+	reader.FieldsPerRecord = -1
+	// Com tabulação, ignorar espaços no início juntaria as colunas vazias
+	reader.TrimLeadingSpace = reader.Comma != '\t'
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, i18n.Errorf("CSV sem tarefas")
+	}
+
+	return records, nil
+}
+
+// detectSeparator escolhe o separador mais frequente na primeira linha
+func detectSeparator(data []byte) rune {
+	firstLine, _ := bufio.NewReader(bytes.NewReader(data)).ReadString('\n')
+
+	separator, count := ',', strings.Count(firstLine, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if n := strings.Count(firstLine, string(candidate)); n > count {
+			separator, count = candidate, n
+		}
+	}
+	return separator
+}
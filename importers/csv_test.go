@@ -0,0 +1,106 @@
+package importers
+
+import (
+	"slices"
+	"testing"
+
+	"personal-cockpit/models"
+)
+
+func TestCSVParse(t *testing.T) {
+	mapping := models.CSVColumnMapping{Title: "Título", Description: "Notas", Priority: "Prioridade", Status: "Status", Category: "Projeto", DueDate: "Prazo"}
+
+	tests := []struct {
+		name string
+		data string
+		want []wantTask
+	}{
+		{
+			name: "ponto e vírgula com BOM",
+			data: "\xef\xbb\xbfTítulo;Notas;Prioridade;Status;Projeto;Prazo\n" +
+				"Ligar para o banco;agência centro;Alta;;Finanças;20/10/2026\n" +
+				"\"Pagar aluguel; condomínio\";;baixa;concluída;Casa;2026-10-05\n",
+			want: []wantTask{
+				{ref: "linha 2", title: "Ligar para o banco", description: "agência centro", priority: "high", due: "2026-10-20", category: "Finanças"},
+				{ref: "linha 3", title: "Pagar aluguel; condomínio", priority: "low", completed: true, due: "2026-10-05", category: "Casa"},
+			},
+		},
+		{
+			name: "vírgula com linhas curtas",
+			data: "Título,Notas,Prioridade,Status,Projeto,Prazo\n" +
+				"Revisar contrato,\"cláusula 3, multa\",p1\n",
+			want: []wantTask{
+				{ref: "linha 2", title: "Revisar contrato", description: "cláusula 3, multa", priority: "high"},
+			},
+		},
+		{
+			name: "tabulação",
+			data: "Título\tNotas\tPrioridade\tStatus\tProjeto\tPrazo\n" +
+				"Comprar pão\t\t\tx\t\t\n",
+			want: []wantTask{
+				{ref: "linha 2", title: "Comprar pão", completed: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, failures, err := CSV{}.Parse([]byte(tt.data), Options{CSVMapping: mapping})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(failures) > 0 {
+				t.Errorf("falhas = %+v", failures)
+			}
+			checkTasks(t, tasks, tt.want)
+		})
+	}
+}
+
+func TestCSVParseErrors(t *testing.T) {
+	data := []byte("Título,Prazo\nComprar pão,amanhã\nPagar conta,2026-10-05\n")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		mapping models.CSVColumnMapping
+	}{
+		{name: "sem coluna do título", data: data, mapping: models.CSVColumnMapping{DueDate: "Prazo"}},
+		{name: "coluna inexistente", data: data, mapping: models.CSVColumnMapping{Title: "Título", Category: "Projeto"}},
+		{name: "só o cabeçalho", data: []byte("Título,Prazo\n"), mapping: models.CSVColumnMapping{Title: "Título"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := (CSV{}).Parse(tt.data, Options{CSVMapping: tt.mapping}); err == nil {
+				t.Error("Parse deveria falhar")
+			}
+		})
+	}
+
+	tasks, failures, err := CSV{}.Parse(data, Options{CSVMapping: models.CSVColumnMapping{Title: "Título", DueDate: "Prazo"}})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tasks) != 1 || len(failures) != 1 || failures[0].Ref != "linha 2" {
+		t.Errorf("tarefas = %+v, falhas = %+v; quero uma de cada", tasks, failures)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	data := []byte("Task,Description,Priority,Done,Project,Due Date,Owner\n" +
+		"Ligar para o banco,,high,,Finanças,2026-10-20,Ana\n")
+
+	preview, err := Columns(data)
+	if err != nil {
+		t.Fatalf("Columns: %v", err)
+	}
+
+	want := models.CSVColumnMapping{Title: "Task", Description: "Description", Priority: "Priority", Status: "Done", Category: "Project", DueDate: "Due Date"}
+	if preview.Mapping != want {
+		t.Errorf("mapeamento = %+v, quero %+v", preview.Mapping, want)
+	}
+	if len(preview.Sample) != 1 || !slices.Equal(preview.Sample[0], []string{"Ligar para o banco", "", "high", "", "Finanças", "2026-10-20", "Ana"}) {
+		t.Errorf("amostra = %q", preview.Sample)
+	}
+}
@@ -0,0 +1,146 @@
+package importers
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+)
+
+// enexNote é uma <note> da exportação do Evernote
+type enexNote struct {
+	Title     string   `xml:"title"`
+	Content   string   `xml:"content"`
+	Created   string   `xml:"created"`
+	Updated   string   `xml:"updated"`
+	Tags      []string `xml:"tag"`
+	Resources []struct {
+		Data     string `xml:"data"`
+		Mime     string `xml:"mime"`
+		FileName string `xml:"resource-attributes>file-name"`
+	} `xml:"resource"`
+}
+
+// Datas do ENEX, sempre em UTC
+const enexTimeLayout = "20060102T150405Z"
+
+// ParseENEX lê uma exportação do Evernote (.enex), nota a nota. O conteúdo
+// (ENML) vira Markdown, e os recursos viram anexos com o hash MD5 como
+// chave, que é como o ENML se refere a eles (<en-media hash="...">).
+func ParseENEX(r io.Reader) ([]Note, []Failure, error) {
+	decoder := xml.NewDecoder(r)
+
+	var notes []Note
+	var failures []Failure
+	found := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, i18n.Errorf("arquivo ENEX inválido: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "en-export" {
+			found = true
+			continue
+		}
+		if start.Name.Local != "note" {
+			continue
+		}
+
+		var raw enexNote
+		if err := decoder.DecodeElement(&raw, &start); err != nil {
+			return nil, nil, i18n.Errorf("arquivo ENEX inválido: %w", err)
+		}
+
+		ref := i18n.Sprintf("nota %d", len(notes)+len(failures)+1)
+		note, err := convertENEXNote(raw)
+		if err != nil {
+			failures = append(failures, Failure{Ref: ref, Title: raw.Title, Reason: err.Error()})
+			continue
+		}
+		note.Ref = ref
+		notes = append(notes, note)
+	}
+
+	if !found {
+		return nil, nil, i18n.Errorf("arquivo não é uma exportação do Evernote")
+	}
+	return notes, failures, nil
+}
+
+func convertENEXNote(raw enexNote) (Note, error) {
+	note := Note{Title: strings.TrimSpace(raw.Title)}
+
+	for _, tag := range raw.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			note.Tags = append(note.Tags, tag)
+		}
+	}
+
+	for _, field := range []struct {
+		value  string
+		target *time.Time
+	}{{raw.Created, &note.CreatedAt}, {raw.Updated, &note.UpdatedAt}} {
+		if field.value == "" {
+			continue
+		}
+		t, err := time.Parse(enexTimeLayout, strings.TrimSpace(field.value))
+		if err != nil {
+			return note, i18n.Errorf("data inválida: %q", field.value)
+		}
+		*field.target = t
+	}
+
+	// Recursos pelo hash; o mesmo arquivo pode aparecer mais de uma vez
+	names := make(map[string]string)
+	for i, resource := range raw.Resources {
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(resource.Data), ""))
+		if err != nil {
+			return note, i18n.Errorf("anexo inválido: %w", err)
+		}
+
+		sum := md5.Sum(data)
+		hash := hex.EncodeToString(sum[:])
+		if _, ok := names[hash]; ok {
+			continue
+		}
+
+		name := strings.TrimSpace(resource.FileName)
+		if name == "" {
+			name = i18n.Sprintf("anexo-%d", i+1)
+			if extensions, _ := mime.ExtensionsByType(resource.Mime); len(extensions) > 0 {
+				name += extensions[0]
+			}
+		}
+		names[hash] = name
+		note.Attachments = append(note.Attachments, Attachment{Key: hash, FileName: name, Data: data})
+	}
+
+	note.Content = HTMLToMarkdown(raw.Content, func(hash, mimeType string) string {
+		name, ok := names[hash]
+		if !ok {
+			return ""
+		}
+		link := "[" + name + AttachmentTarget(hash)
+		if strings.HasPrefix(mimeType, "image/") {
+			link = "!" + link
+		}
+		return link
+	})
+
+	return note, nil
+}
@@ -0,0 +1,111 @@
+package importers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseENEX(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export4.dtd">
+<en-export export-date="20261019T120000Z" application="Evernote">
+  <note>
+    <title>Receita de pão</title>
+    <content><![CDATA[<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd">
+<en-note><div>Ingredientes:</div><div><en-todo checked="true"/>farinha</div><div><en-media hash="2fe04e524ba40505a82e03a2819429cc" type="image/png"/></div><div><en-media hash="bfa4b10a76324b166cfdad5e02a63730" type="application/pdf"/></div></en-note>]]></content>
+    <created>20261001T093000Z</created>
+    <updated>20261002T100000Z</updated>
+    <tag>cozinha</tag>
+    <tag> </tag>
+    <resource>
+      <data encoding="base64">
+b2xh
+      </data>
+      <mime>image/png</mime>
+      <resource-attributes><file-name>massa.png</file-name></resource-attributes>
+    </resource>
+    <resource>
+      <data encoding="base64">JVBERg==</data>
+      <mime>application/pdf</mime>
+    </resource>
+    <resource>
+      <data encoding="base64">b2xh</data>
+      <mime>image/png</mime>
+    </resource>
+  </note>
+  <note>
+    <title>Data quebrada</title>
+    <content><![CDATA[<en-note>oi</en-note>]]></content>
+    <created>ontem</created>
+  </note>
+  <note>
+    <title>Sem datas</title>
+    <content><![CDATA[<en-note><p>Só texto</p></en-note>]]></content>
+  </note>
+</en-export>`
+
+	notes, failures, err := ParseENEX(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseENEX: %v", err)
+	}
+
+	if len(failures) != 1 || failures[0].Ref != "nota 2" || failures[0].Title != "Data quebrada" {
+		t.Errorf("falhas = %+v, quero só a nota 2", failures)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("%d notas, quero 2", len(notes))
+	}
+
+	note := notes[0]
+	if note.Ref != "nota 1" || note.Title != "Receita de pão" {
+		t.Errorf("nota = %q %q", note.Ref, note.Title)
+	}
+	wantContent := "Ingredientes:\n- [x] farinha\n![massa.png](<2fe04e524ba40505a82e03a2819429cc>)\n[anexo-2.pdf](<bfa4b10a76324b166cfdad5e02a63730>)\n"
+	if note.Content != wantContent {
+		t.Errorf("conteúdo = %q, quero %q", note.Content, wantContent)
+	}
+	if !slices.Equal(note.Tags, []string{"cozinha"}) {
+		t.Errorf("tags = %q, quero [cozinha]", note.Tags)
+	}
+	if want := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC); !note.CreatedAt.Equal(want) {
+		t.Errorf("criada em %v, quero %v", note.CreatedAt, want)
+	}
+	if want := time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC); !note.UpdatedAt.Equal(want) {
+		t.Errorf("alterada em %v, quero %v", note.UpdatedAt, want)
+	}
+
+	// O terceiro recurso repete o primeiro e não vira outro anexo
+	if len(note.Attachments) != 2 {
+		t.Fatalf("%d anexos, quero 2", len(note.Attachments))
+	}
+	if a := note.Attachments[0]; a.Key != "2fe04e524ba40505a82e03a2819429cc" || a.FileName != "massa.png" || string(a.Data) != "ola" {
+		t.Errorf("anexo 1 = %q %q %q", a.Key, a.FileName, a.Data)
+	}
+	if a := note.Attachments[1]; a.FileName != "anexo-2.pdf" || string(a.Data) != "%PDF" {
+		t.Errorf("anexo 2 = %q %q", a.FileName, a.Data)
+	}
+
+	if notes[1].Ref != "nota 3" || notes[1].Content != "Só texto\n" || !notes[1].CreatedAt.IsZero() {
+		t.Errorf("nota 3 = %+v", notes[1])
+	}
+}
+
+func TestParseENEXErrors(t *testing.T) {
+	tests := map[string]string{
+		"não é ENEX":      `<?xml version="1.0"?><notes><note><title>x</title></note></notes>`,
+		"XML quebrado":    `<en-export><note><title>x</note>`,
+		"base64 inválido": `<en-export><note><title>x</title><resource><data>%%%</data></resource></note></en-export>`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			notes, failures, err := ParseENEX(strings.NewReader(data))
+			if err == nil && len(failures) == 0 {
+				t.Errorf("ParseENEX = %+v, quero erro ou falha", notes)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package importers
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// HTMLToMarkdown converte HTML (ou o ENML do Evernote) em Markdown:
+// parágrafos, títulos, negrito e itálico, links, imagens, listas (inclusive
+// as caixas de seleção do Evernote, como "- [ ]"), citações, código e
+// tabelas simples. media recebe cada <en-media> (hash e tipo) e devolve o
+// Markdown que o substitui; pode ser nil.
+func HTMLToMarkdown(html string, media func(hash, mimeType string) string) string {
+	decoder := xml.NewDecoder(strings.NewReader(html))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	w := &markdownWriter{media: media}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// Fim do texto ou HTML quebrado: fica o que já foi convertido
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			w.start(t)
+		case xml.EndElement:
+			w.end(strings.ToLower(t.Name.Local))
+		case xml.CharData:
+			w.text(string(t))
+		}
+	}
+
+	return strings.TrimSpace(w.out.String()) + "\n"
+}
+
+type markdownList struct {
+	ordered bool
+	count   int
+}
+
+type markdownWriter struct {
+	out   strings.Builder
+	media func(hash, mimeType string) string
+
+	newlines int    // quebras de linha pendentes antes do próximo texto
+	space    bool   // espaço pendente entre palavras
+	marker   string // marcador do item de lista ainda não escrito
+	lists    []markdownList
+	quote    int
+	pre      int
+	skip     int // dentro de <script>, <style>, <head>
+	links    []string
+	table    struct {
+		row   int
+		cells int
+	}
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (w *markdownWriter) start(t xml.StartElement) {
+	name := strings.ToLower(t.Name.Local)
+	if w.skip > 0 {
+		if name == "script" || name == "style" || name == "head" {
+			w.skip++
+		}
+		return
+	}
+
+	switch name {
+	case "script", "style", "head":
+		w.skip++
+	case "p", "section", "article", "header", "footer":
+		w.block(w.paragraph())
+	case "div", "tr":
+		w.block(1)
+		if name == "tr" {
+			w.table.cells = 0
+		}
+	case "table":
+		w.block(2)
+	case "br":
+		w.newlines = max(w.newlines, 1)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block(2)
+		level, _ := strconv.Atoi(name[1:])
+		w.write(strings.Repeat("#", level) + " ")
+	case "b", "strong":
+		w.write("**")
+	case "i", "em":
+		w.write("*")
+	case "s", "strike", "del":
+		w.write("~~")
+	case "code":
+		if w.pre == 0 {
+			w.write("`")
+		}
+	case "a":
+		w.links = append(w.links, attr(t, "href"))
+		w.write("[")
+	case "img":
+		w.write("![" + attr(t, "alt") + "](" + attr(t, "src") + ")")
+	case "en-media":
+		if w.media != nil {
+			w.write(w.media(attr(t, "hash"), attr(t, "type")))
+		}
+	case "en-todo":
+		mark := "[ ] "
+		if attr(t, "checked") == "true" {
+			mark = "[x] "
+		}
+		if len(w.lists) == 0 {
+			mark = "- " + mark
+		}
+		w.write(mark)
+	case "ul", "ol":
+		w.block(w.paragraph())
+		w.lists = append(w.lists, markdownList{ordered: name == "ol"})
+	case "li":
+		w.block(1)
+		if len(w.lists) > 0 {
+			list := &w.lists[len(w.lists)-1]
+			list.count++
+			w.marker = "- "
+			if list.ordered {
+				w.marker = strconv.Itoa(list.count) + ". "
+			}
+		}
+	case "blockquote":
+		w.block(2)
+		w.quote++
+	case "pre":
+		w.block(2)
+		w.write("```")
+		w.newlines = 1
+		w.pre++
+	case "hr":
+		w.block(2)
+		w.write("---")
+		w.block(2)
+	case "td", "th":
+		w.write("| ")
+		w.table.cells++
+	}
+}
+
+func (w *markdownWriter) end(name string) {
+	if w.skip > 0 {
+		if name == "script" || name == "style" || name == "head" {
+			w.skip--
+		}
+		return
+	}
+
+	switch name {
+	case "p", "section", "article", "header", "footer":
+		w.block(w.paragraph())
+	case "div", "li":
+		w.block(1)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		w.block(2)
+	case "b", "strong":
+		w.close("**")
+	case "i", "em":
+		w.close("*")
+	case "s", "strike", "del":
+		w.close("~~")
+	case "code":
+		if w.pre == 0 {
+			w.close("`")
+		}
+	case "a":
+		href := ""
+		if n := len(w.links); n > 0 {
+			href, w.links = w.links[n-1], w.links[:n-1]
+		}
+		w.close("](" + href + ")")
+	case "ul", "ol":
+		if len(w.lists) > 0 {
+			w.lists = w.lists[:len(w.lists)-1]
+		}
+		w.block(w.paragraph())
+	case "blockquote":
+		if w.quote > 0 {
+			w.quote--
+		}
+		w.block(2)
+	case "pre":
+		if w.pre > 0 {
+			w.pre--
+		}
+		w.newlines = max(w.newlines, 1)
+		w.write("```")
+		w.block(2)
+	case "td", "th":
+		w.write(" ")
+	case "tr":
+		w.write("|")
+		w.table.row++
+		if w.table.row == 1 {
+			w.newlines = 1
+			w.write("|" + strings.Repeat(" --- |", w.table.cells))
+		}
+		w.block(1)
+	case "table":
+		w.table.row = 0
+		w.block(2)
+	}
+}
+
+// paragraph é o espaço entre blocos: linha em branco, ou só a quebra
+// dentro de listas
+func (w *markdownWriter) paragraph() int {
+	if len(w.lists) > 0 {
+		return 1
+	}
+	return 2
+}
+
+func (w *markdownWriter) block(newlines int) {
+	if w.out.Len() > 0 {
+		w.newlines = max(w.newlines, newlines)
+	}
+}
+
+func (w *markdownWriter) text(s string) {
+	if w.skip > 0 {
+		return
+	}
+
+	if w.pre > 0 {
+		for i, line := range strings.Split(s, "\n") {
+			if i > 0 {
+				w.newlines++
+			}
+			if line != "" {
+				w.write(line)
+			}
+		}
+		return
+	}
+
+	// Espaços nas pontas separam palavras de elementos diferentes
+	// ("<b>a</b> b"), mas só entram antes da próxima palavra
+	words := strings.Join(strings.Fields(s), " ")
+	if words == "" {
+		w.space = w.space || s != ""
+		return
+	}
+	if strings.TrimLeft(s, " \t\r\n") != s {
+		w.space = true
+	}
+	w.write(words)
+	if strings.TrimRight(s, " \t\r\n") != s {
+		w.space = true
+	}
+}
+
+// close escreve o fim de uma marcação (**, ], ...) colado ao texto, deixando
+// o espaço pendente para depois dela
+func (w *markdownWriter) close(s string) {
+	space := w.space
+	w.space = false
+	w.write(s)
+	w.space = space
+}
+
+// write escreve no fim, abrindo as linhas pendentes com o prefixo das
+// citações e a indentação das listas
+func (w *markdownWriter) write(s string) {
+	if w.space && w.newlines == 0 && w.out.Len() > 0 {
+		w.out.WriteString(" ")
+	}
+	w.space = false
+
+	if w.newlines > 0 || w.out.Len() == 0 {
+		if w.out.Len() > 0 {
+			w.out.WriteString(strings.Repeat("\n", w.newlines))
+		}
+		w.newlines = 0
+
+		w.out.WriteString(strings.Repeat("> ", w.quote))
+		if depth := len(w.lists); depth > 0 {
+			if w.marker != "" {
+				w.out.WriteString(strings.Repeat("  ", depth-1) + w.marker)
+				w.marker = ""
+			} else {
+				w.out.WriteString(strings.Repeat("  ", depth))
+			}
+		}
+		if w.pre == 0 {
+			s = strings.TrimLeft(s, " ")
+		}
+	} else if w.marker != "" && len(w.lists) > 0 {
+		w.out.WriteString(strings.Repeat("  ", len(w.lists)-1) + w.marker)
+		w.marker = ""
+	}
+
+	w.out.WriteString(s)
+}
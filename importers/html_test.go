@@ -0,0 +1,66 @@
+package importers
+
+import "testing"
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "parágrafos e ênfase",
+			html: "<p>Olá <b>mundo</b>, tudo <em>bem</em>?</p><p>Segunda linha<br/>quebrada</p>",
+			want: "Olá **mundo**, tudo *bem*?\n\nSegunda linha\nquebrada\n",
+		},
+		{
+			name: "títulos e links",
+			html: `<h1>Pauta</h1><p>Veja o <a href="https://example.com/doc">documento</a> antes.</p><h3>Itens</h3>`,
+			want: "# Pauta\n\nVeja o [documento](https://example.com/doc) antes.\n\n### Itens\n",
+		},
+		{
+			name: "listas aninhadas",
+			html: "<ul><li>um</li><li>dois<ol><li>a</li><li>b</li></ol></li><li>três</li></ul>",
+			want: "- um\n- dois\n  1. a\n  2. b\n- três\n",
+		},
+		{
+			name: "checklist do Evernote",
+			html: `<en-note><div><en-todo checked="true"/>comprar pão</div><div><en-todo/>pagar conta</div></en-note>`,
+			want: "- [x] comprar pão\n- [ ] pagar conta\n",
+		},
+		{
+			name: "citação e código",
+			html: "<blockquote>Frase famosa</blockquote><p>Use <code>go test</code>:</p><pre>go test ./...\ngo vet ./...</pre>",
+			want: "> Frase famosa\n\nUse `go test`:\n\n```\ngo test ./...\ngo vet ./...\n```\n",
+		},
+		{
+			name: "tabela",
+			html: "<table><tr><th>Item</th><th>Valor</th></tr><tr><td>Aluguel</td><td>1500</td></tr></table>",
+			want: "| Item | Valor |\n| --- | --- |\n| Aluguel | 1500 |\n",
+		},
+		{
+			name: "entidades, script e HTML quebrado",
+			html: "<head><style>p { color: red }</style></head><p>caf&eacute; &amp; p&atilde;o<script>alert(1)</script></p><p>sem fechar",
+			want: "café & pão\n\nsem fechar\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToMarkdown(tt.html, nil); got != tt.want {
+				t.Errorf("HTMLToMarkdown(%q)\n= %q\nquero %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToMarkdownMedia(t *testing.T) {
+	html := `<en-note><p>Foto: <en-media hash="abc" type="image/png"/></p><en-media hash="def" type="application/pdf"/></en-note>`
+
+	got := HTMLToMarkdown(html, func(hash, mimeType string) string {
+		return "{" + hash + " " + mimeType + "}"
+	})
+	if want := "Foto: {abc image/png}\n\n{def application/pdf}\n"; got != want {
+		t.Errorf("HTMLToMarkdown = %q, quero %q", got, want)
+	}
+}
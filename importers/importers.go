@@ -0,0 +1,166 @@
+// Package importers lê listas de tarefas (todo.txt, Todoist, Trello, CSV) e
+// notas (Evernote, Obsidian, pastas de Markdown) exportadas por outros
+// programas. Os formatos não conhecem o banco: convertem o arquivo em Task
+// ou Note, e o TaskImportService e o NoteImportService decidem o que criar.
+package importers
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// ErrUnknownFormat indica um formato que não foi registrado
+var ErrUnknownFormat = errors.New("formato de importação desconhecido")
+
+// Task é uma tarefa lida do arquivo, com a categoria ainda pelo nome
+type Task struct {
+	Ref         string // onde ela está no arquivo, para o relatório ("linha 3")
+	Title       string
+	Description string
+	Priority    string // high, medium, low ou vazio
+	Completed   bool
+	DueDate     *time.Time
+	Category    string
+	Skip        string // motivo para não importar (ex.: cartão arquivado)
+}
+
+// Failure é um item do arquivo que não pôde ser lido
+type Failure struct {
+	Ref    string
+	Title  string // o texto do item, como estava no arquivo
+	Reason string
+}
+
+// Options são os ajustes da leitura
+type Options struct {
+	CSVMapping models.CSVColumnMapping // colunas do CSV genérico
+}
+
+// Format lê um tipo de arquivo. Parse só falha quando o arquivo inteiro é
+// ilegível; itens com problema vão para as falhas e os outros seguem.
+type Format interface {
+	Name() string
+	Parse(data []byte, options Options) ([]Task, []Failure, error)
+}
+
+var registry = map[string]Format{}
+
+func init() {
+	Register(TodoTxt{})
+	Register(Todoist{})
+	Register(Trello{})
+	Register(CSV{})
+}
+
+// Register adiciona um formato (substitui um de mesmo nome)
+func Register(format Format) {
+	registry[format.Name()] = format
+}
+
+// Get retorna o formato pelo nome
+func Get(name string) (Format, error) {
+	format, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+	return format, nil
+}
+
+// Names lista os formatos registrados
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect escolhe o formato pela extensão e, nos JSON, pelo conteúdo
+func Detect(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".tsv":
+		return "csv"
+	case ".json":
+		// A exportação de um quadro do Trello tem listas e cartões
+		text := string(data)
+		if strings.Contains(text, `"cards"`) && strings.Contains(text, `"lists"`) {
+			return "trello"
+		}
+		return "todoist"
+	default:
+		return "todotxt"
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// AUXILIARES
+// ═══════════════════════════════════════════════════════════
+
+// parseDate aceita data (AAAA-MM-DD, DD/MM/AAAA), data e hora sem fuso
+// (horário local) e RFC 3339
+func parseDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	layouts := []string{"2006-01-02", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", "02/01/2006", "02/01/2006 15:04"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, timezone.Location()); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, i18n.Errorf("data inválida: %q", value)
+}
+
+// normalizePriority entende os nomes usados pelos outros programas (em
+// português e inglês, letras do todo.txt); o que não reconhece fica vazio
+func normalizePriority(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "high", "alta", "urgent", "urgente", "a", "1", "p1":
+		return "high"
+	case "medium", "média", "media", "normal", "b", "2", "p2":
+		return "medium"
+	case "low", "baixa", "c", "3", "p3", "4", "p4":
+		return "low"
+	default:
+		return ""
+	}
+}
+
+// isDone reconhece valores de "concluída" em colunas de status e nomes de
+// listas
+func isDone(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "x", "done", "complete", "completed", "closed", "true", "yes", "sim", "1",
+		"concluída", "concluida", "concluído", "concluido", "feita", "feito", "finalizada":
+		return true
+	default:
+		return false
+	}
+}
+
+// appendLine junta uma linha à descrição, separada por linha em branco
+func appendLine(description, line string) string {
+	if line == "" {
+		return description
+	}
+	if description == "" {
+		return line
+	}
+	return description + "\n\n" + line
+}
@@ -0,0 +1,131 @@
+package importers
+
+import (
+	"testing"
+	"time"
+)
+
+// formatDue devolve o prazo como AAAA-MM-DD, ou "" sem prazo
+func formatDue(task Task) string {
+	if task.DueDate == nil {
+		return ""
+	}
+	return task.DueDate.Format("2006-01-02")
+}
+
+// wantTask é o que se espera de uma tarefa lida, com o prazo como AAAA-MM-DD
+type wantTask struct {
+	ref         string
+	title       string
+	description string
+	priority    string
+	completed   bool
+	due         string
+	category    string
+	skip        string
+}
+
+// checkTasks compara as tarefas lidas com as esperadas, na ordem
+func checkTasks(t *testing.T, got []Task, want []wantTask) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%d tarefas, quero %d: %+v", len(got), len(want), got)
+	}
+
+	for i, task := range got {
+		w := want[i]
+		if task.Ref != w.ref {
+			t.Errorf("[%d] ref = %q, quero %q", i, task.Ref, w.ref)
+		}
+		if task.Title != w.title {
+			t.Errorf("[%d] título = %q, quero %q", i, task.Title, w.title)
+		}
+		if task.Description != w.description {
+			t.Errorf("[%d] descrição = %q, quero %q", i, task.Description, w.description)
+		}
+		if task.Priority != w.priority {
+			t.Errorf("[%d] prioridade = %q, quero %q", i, task.Priority, w.priority)
+		}
+		if task.Completed != w.completed {
+			t.Errorf("[%d] concluída = %v, quero %v", i, task.Completed, w.completed)
+		}
+		if due := formatDue(task); due != w.due {
+			t.Errorf("[%d] prazo = %q, quero %q", i, due, w.due)
+		}
+		if task.Category != w.category {
+			t.Errorf("[%d] categoria = %q, quero %q", i, task.Category, w.category)
+		}
+		if task.Skip != w.skip {
+			t.Errorf("[%d] motivo para pular = %q, quero %q", i, task.Skip, w.skip)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		filename string
+		data     string
+		want     string
+	}{
+		{"todo.txt", "(A) Ligar para o banco", "todotxt"},
+		{"tarefas.CSV", "título;prazo", "csv"},
+		{"tarefas.tsv", "título\tprazo", "csv"},
+		{"quadro.json", `{"name": "Casa", "lists": [], "cards": []}`, "trello"},
+		{"todoist.json", `{"projects": [], "items": []}`, "todoist"},
+		{"sem-extensao", "Comprar pão", "todotxt"},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.filename, []byte(tt.data)); got != tt.want {
+			t.Errorf("Detect(%q) = %q, quero %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // AAAA-MM-DD HH:MM em UTC, ou "" sem data
+		fails bool
+	}{
+		{value: "", want: ""},
+		{value: "2026-10-20T15:00:00Z", want: "2026-10-20 15:00"},
+		{value: "2026-10-20T12:00:00-03:00", want: "2026-10-20 15:00"},
+		{value: "amanhã", fails: true},
+		{value: "2026-13-01", fails: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDate(tt.value)
+		if tt.fails {
+			if err == nil {
+				t.Errorf("parseDate(%q) = %v, quero erro", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", tt.value, err)
+			continue
+		}
+
+		formatted := ""
+		if got != nil {
+			formatted = got.UTC().Format("2006-01-02 15:04")
+		}
+		if formatted != tt.want {
+			t.Errorf("parseDate(%q) = %q, quero %q", tt.value, formatted, tt.want)
+		}
+	}
+
+	// Sem fuso, vale o horário local; DD/MM/AAAA é o formato brasileiro
+	for _, value := range []string{"2026-10-20", "20/10/2026", "2026-10-20 09:30"} {
+		got, err := parseDate(value)
+		if err != nil {
+			t.Fatalf("parseDate(%q): %v", value, err)
+		}
+		if got.Year() != 2026 || got.Month() != time.October || got.Day() != 20 {
+			t.Errorf("parseDate(%q) = %v, quero 20/10/2026", value, got)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package importers
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+)
+
+// Extensões lidas como notas nas pastas
+var noteExtensions = []string{".md", ".markdown", ".txt"}
+
+func isNoteFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, candidate := range noteExtensions {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ![[arquivo.png]], ![[arquivo.png|300]], ![[Nota#Seção]]
+	embedPattern = regexp.MustCompile(`!\[\[([^\]|#]+)(#[^\]|]*)?(\|[^\]]*)?\]\]`)
+	// [texto](destino) e ![alt](destino "título"); o destino pode vir entre <>
+	linkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\((<[^>]+>|[^)\s]+)(\s+"[^"]*")?\)`)
+	// [[Nota]], [[pasta/Nota#Seção|texto]]
+	wikiLinkPattern = regexp.MustCompile(`\[\[([^\]|#]+)(#[^\]|]*)?(\|[^\]]*)?\]\]`)
+)
+
+// ReadNoteFolder lê as notas (.md, .markdown, .txt) de uma pasta e das
+// subpastas, como um cofre do Obsidian. Pastas ocultas (.obsidian, .trash,
+// .git) ficam de fora. Do cabeçalho YAML vêm título, tags, aliases e datas;
+// sem ele, o título é o nome do arquivo e as datas são a da modificação.
+// Arquivos da pasta usados pelas notas (![[imagem.png]], [pdf](docs/a.pdf))
+// viram anexos, com o caminho relativo à pasta como chave, e links para
+// outras notas viram links [[...]] pelo caminho, que fica nos Aliases.
+func ReadNoteFolder(root string) ([]Note, []Failure, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, nil, i18n.Errorf("erro ao abrir pasta: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, nil, i18n.Errorf("não é uma pasta: %s", root)
+	}
+
+	var notePaths []string
+	// Outros arquivos, pelo caminho relativo e pelo nome (em minúsculas),
+	// que é como o Obsidian resolve ![[arquivo]]
+	files := make(map[string]string)
+
+	err = filepath.WalkDir(root, func(p string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		if rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		if isNoteFile(rel) {
+			notePaths = append(notePaths, rel)
+			return nil
+		}
+		files[strings.ToLower(rel)] = rel
+		if _, ok := files[strings.ToLower(path.Base(rel))]; !ok {
+			files[strings.ToLower(path.Base(rel))] = rel
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, i18n.Errorf("erro ao ler pasta: %w", err)
+	}
+
+	// Arquivos mais rasos primeiro: ganham quando dois têm o mesmo nome
+	sort.Slice(notePaths, func(i, j int) bool {
+		di, dj := strings.Count(notePaths[i], "/"), strings.Count(notePaths[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return notePaths[i] < notePaths[j]
+	})
+
+	var notes []Note
+	var failures []Failure
+
+	for _, rel := range notePaths {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		title := strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+
+		data, err := os.ReadFile(full)
+		if err != nil {
+			failures = append(failures, Failure{Ref: rel, Title: title, Reason: err.Error()})
+			continue
+		}
+		stat, err := os.Stat(full)
+		if err != nil {
+			failures = append(failures, Failure{Ref: rel, Title: title, Reason: err.Error()})
+			continue
+		}
+
+		note := Note{
+			Ref:       rel,
+			Title:     title,
+			Content:   strings.TrimPrefix(string(data), "\ufeff"),
+			CreatedAt: stat.ModTime(),
+			UpdatedAt: stat.ModTime(),
+		}
+		if dir := path.Dir(rel); dir != "." {
+			note.Folder = strings.Split(dir, "/")
+		}
+
+		if ext := strings.ToLower(path.Ext(rel)); ext != ".txt" {
+			if err := applyFrontMatter(&note); err != nil {
+				failures = append(failures, Failure{Ref: rel, Title: title, Reason: err.Error()})
+				continue
+			}
+			note.Content = linkFolderFiles(&note, root, path.Dir(rel), files)
+		}
+		note.Content = strings.TrimLeft(note.Content, "\r\n")
+
+		// Links chegam à nota também pelo arquivo ([[pasta/Nota]], ou [[Nota]]
+		// quando o título do cabeçalho é outro)
+		note.Aliases = append(note.Aliases, strings.TrimSuffix(rel, path.Ext(rel)))
+		if title != note.Title {
+			note.Aliases = append(note.Aliases, title)
+		}
+
+		notes = append(notes, note)
+	}
+
+	return notes, failures, nil
+}
+
+// applyFrontMatter tira o cabeçalho YAML do conteúdo e usa o que o app
+// entende dele. Só chaves simples e listas ("- item" ou "[a, b]") são lidas.
+func applyFrontMatter(note *Note) error {
+	text := note.Content
+	if !strings.HasPrefix(text, "---\n") && !strings.HasPrefix(text, "---\r\n") {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r") == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil
+	}
+
+	values := make(map[string][]string)
+	key := ""
+	for _, line := range lines[1:end] {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "- ") && key != "" {
+			values[key] = append(values[key], yamlScalar(strings.TrimPrefix(trimmed, "- ")))
+			continue
+		}
+		if strings.HasPrefix(line, " ") || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			key = ""
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = yamlScalar(item); item != "" {
+					values[key] = append(values[key], item)
+				}
+			}
+		case value != "":
+			values[key] = append(values[key], yamlScalar(value))
+		}
+	}
+	note.Content = strings.Join(lines[end+1:], "\n")
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if v := values[key]; len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+
+	if title := first("title"); title != "" {
+		note.Title = title
+	}
+
+	for _, key := range []string{"tags", "tag"} {
+		for _, value := range values[key] {
+			// "tags: a, b" e "tags: a b" também aparecem em cofres antigos
+			for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+				if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+					note.Tags = append(note.Tags, tag)
+				}
+			}
+		}
+	}
+	note.Aliases = append(note.Aliases, values["aliases"]...)
+	note.Aliases = append(note.Aliases, values["alias"]...)
+
+	for _, field := range []struct {
+		value  string
+		target *time.Time
+	}{{first("created", "date", "created_at"), &note.CreatedAt}, {first("updated", "modified", "updated_at"), &note.UpdatedAt}} {
+		t, err := parseDate(field.value)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			*field.target = *t
+		}
+	}
+	if note.UpdatedAt.Before(note.CreatedAt) {
+		note.UpdatedAt = note.CreatedAt
+	}
+
+	return nil
+}
+
+func yamlScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// linkFolderFiles troca as referências a arquivos da pasta pelo destino do
+// anexo (AttachmentTarget) e os links para outras notas por [[caminho]].
+// Blocos de código ficam como estão.
+func linkFolderFiles(note *Note, root, dir string, files map[string]string) string {
+	added := make(map[string]bool)
+	attach := func(rel string) string {
+		if !added[rel] {
+			added[rel] = true
+			note.Attachments = append(note.Attachments, Attachment{Key: rel, FileName: path.Base(rel), Path: filepath.Join(root, filepath.FromSlash(rel))})
+		}
+		return rel
+	}
+
+	// Caminho relativo à nota ou, como no Obsidian, à raiz do cofre
+	resolve := func(target string) (string, bool) {
+		for _, candidate := range []string{path.Join(dir, target), path.Clean(strings.TrimPrefix(target, "/"))} {
+			if rel, ok := files[strings.ToLower(candidate)]; ok {
+				return rel, true
+			}
+		}
+		return "", false
+	}
+
+	return mapOutsideCode(note.Content, func(line string) string {
+		line = linkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			groups := linkPattern.FindStringSubmatch(match)
+			bang, text, target := groups[1], groups[2], strings.Trim(groups[3], "<>")
+			if strings.Contains(target, ":") || strings.HasPrefix(target, "#") {
+				// URLs, mailto: e âncoras
+				return match
+			}
+			if unescaped, err := url.PathUnescape(target); err == nil {
+				target = unescaped
+			}
+			target, heading, _ := strings.Cut(target, "#")
+
+			if isNoteFile(target) {
+				rel := path.Clean(path.Join(dir, target))
+				link := strings.TrimSuffix(rel, path.Ext(rel))
+				if heading != "" {
+					link += "#" + heading
+				}
+				if text != "" {
+					link += "|" + text
+				}
+				return "[[" + link + "]]"
+			}
+
+			rel, ok := resolve(target)
+			if !ok {
+				return match
+			}
+			return bang + "[" + text + AttachmentTarget(attach(rel))
+		})
+
+		return embedPattern.ReplaceAllStringFunc(line, func(match string) string {
+			groups := embedPattern.FindStringSubmatch(match)
+			target := strings.TrimSpace(groups[1])
+			if isNoteFile(target) || path.Ext(target) == "" {
+				// Nota embutida: fica para RewriteWikiLinks
+				return match
+			}
+
+			rel, ok := resolve(target)
+			if !ok {
+				if rel, ok = files[strings.ToLower(path.Base(target))]; !ok {
+					return match
+				}
+			}
+			link := "[" + path.Base(rel) + AttachmentTarget(attach(rel))
+			if isImage(rel) {
+				link = "!" + link
+			}
+			return link
+		})
+	})
+}
+
+func isImage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".bmp":
+		return true
+	default:
+		return false
+	}
+}
+
+// mapOutsideCode aplica fn às linhas fora dos blocos de código (```)
+func mapOutsideCode(content string, fn func(line string) string) string {
+	lines := strings.Split(content, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if !inCode {
+			lines[i] = fn(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RewriteWikiLinks passa cada link [[alvo]] do conteúdo por resolve, que
+// devolve o título da nota. Seções (#) e textos (|) são mantidos; os alvos
+// que resolve não encontra ficam como estão e são devolvidos.
+func RewriteWikiLinks(content string, resolve func(target string) (string, bool)) (string, []string) {
+	var unresolved []string
+
+	content = mapOutsideCode(content, func(line string) string {
+		return wikiLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			groups := wikiLinkPattern.FindStringSubmatch(match)
+			target := strings.TrimSpace(groups[1])
+
+			title, ok := resolve(target)
+			if !ok {
+				unresolved = append(unresolved, target)
+				return match
+			}
+			return "[[" + title + groups[2] + groups[3] + "]]"
+		})
+	})
+
+	return content, unresolved
+}
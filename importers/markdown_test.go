@@ -0,0 +1,181 @@
+package importers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplyFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		title   string
+		tags    []string
+		aliases []string
+		created string // AAAA-MM-DD, ou "" para não mexer
+		body    string
+	}{
+		{
+			name: "chaves e listas",
+			content: "---\ntitle: \"Reunião: planejamento\"\ntags: [trabalho, '#q4']\naliases:\n  - Planejamento\n  - Q4\n" +
+				"created: 2026-10-01\n# comentário\n---\n# Pauta\n",
+			title:   "Reunião: planejamento",
+			tags:    []string{"trabalho", "q4"},
+			aliases: []string{"Planejamento", "Q4"},
+			created: "2026-10-01",
+			body:    "# Pauta\n",
+		},
+		{
+			name:    "tags separadas por espaço e CRLF",
+			content: "---\r\ntags: casa #compras\r\ndate: 20/10/2026\r\n---\r\nlista",
+			title:   "arquivo",
+			tags:    []string{"casa", "compras"},
+			created: "2026-10-20",
+			body:    "lista",
+		},
+		{
+			name:    "sem cabeçalho",
+			content: "Texto com --- no meio\n",
+			title:   "arquivo",
+			body:    "Texto com --- no meio\n",
+		},
+		{
+			name:    "cabeçalho sem fim",
+			content: "---\ntitle: Outro\nconteúdo",
+			title:   "arquivo",
+			body:    "---\ntitle: Outro\nconteúdo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := Note{Title: "arquivo", Content: tt.content}
+			if err := applyFrontMatter(&note); err != nil {
+				t.Fatalf("applyFrontMatter: %v", err)
+			}
+
+			if note.Title != tt.title {
+				t.Errorf("título = %q, quero %q", note.Title, tt.title)
+			}
+			if !slices.Equal(note.Tags, tt.tags) {
+				t.Errorf("tags = %q, quero %q", note.Tags, tt.tags)
+			}
+			if !slices.Equal(note.Aliases, tt.aliases) {
+				t.Errorf("aliases = %q, quero %q", note.Aliases, tt.aliases)
+			}
+			created := ""
+			if !note.CreatedAt.IsZero() {
+				created = note.CreatedAt.Format("2006-01-02")
+			}
+			if created != tt.created {
+				t.Errorf("criada em %q, quero %q", created, tt.created)
+			}
+			if note.Content != tt.body {
+				t.Errorf("conteúdo = %q, quero %q", note.Content, tt.body)
+			}
+		})
+	}
+
+	note := Note{Content: "---\ncreated: ontem\n---\n"}
+	if err := applyFrontMatter(&note); err == nil {
+		t.Error("data inválida no cabeçalho deveria falhar")
+	}
+}
+
+func TestRewriteWikiLinks(t *testing.T) {
+	titles := map[string]string{"Projetos/Casa": "Reforma da casa", "Mercado": "Lista do mercado"}
+	resolve := func(target string) (string, bool) {
+		title, ok := titles[target]
+		return title, ok
+	}
+
+	content := "Ver [[Projetos/Casa#Orçamento|orçamento]] e [[Mercado]].\n" +
+		"```\n[[Mercado]] em código fica\n```\n" +
+		"Falta [[Inexistente]]."
+
+	got, unresolved := RewriteWikiLinks(content, resolve)
+
+	want := "Ver [[Reforma da casa#Orçamento|orçamento]] e [[Lista do mercado]].\n" +
+		"```\n[[Mercado]] em código fica\n```\n" +
+		"Falta [[Inexistente]]."
+	if got != want {
+		t.Errorf("RewriteWikiLinks =\n%q\nquero\n%q", got, want)
+	}
+	if !slices.Equal(unresolved, []string{"Inexistente"}) {
+		t.Errorf("não resolvidos = %q, quero [Inexistente]", unresolved)
+	}
+}
+
+func TestReadNoteFolder(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("Início.md", "---\ntitle: Página inicial\ntags: [home]\n---\nVeja ![[foto.png]], o [contrato](docs/contrato.pdf) e a [[Projetos/Casa]].\n")
+	write("Projetos/Casa.md", "Link [relativo](../Início.md#Topo) e [site](https://example.com).\n")
+	write("Projetos/imagens/foto.png", "png")
+	write("docs/contrato.pdf", "pdf")
+	write("lembrete.txt", "![[foto.png]] fica como está")
+	write(".obsidian/app.json", "{}")
+	write(".trash/Velha.md", "apagada")
+
+	notes, failures, err := ReadNoteFolder(root)
+	if err != nil {
+		t.Fatalf("ReadNoteFolder: %v", err)
+	}
+	if len(failures) > 0 {
+		t.Errorf("falhas = %+v", failures)
+	}
+
+	var refs []string
+	for _, note := range notes {
+		refs = append(refs, note.Ref)
+	}
+	if want := []string{"Início.md", "lembrete.txt", "Projetos/Casa.md"}; !slices.Equal(refs, want) {
+		t.Fatalf("notas = %q, quero %q", refs, want)
+	}
+
+	home := notes[0]
+	if home.Title != "Página inicial" || !slices.Equal(home.Tags, []string{"home"}) || home.Folder != nil {
+		t.Errorf("nota inicial = %q %q %q", home.Title, home.Tags, home.Folder)
+	}
+	wantContent := "Veja ![foto.png](<Projetos/imagens/foto.png>), o [contrato](<docs/contrato.pdf>) e a [[Projetos/Casa]].\n"
+	if home.Content != wantContent {
+		t.Errorf("conteúdo = %q, quero %q", home.Content, wantContent)
+	}
+	if !slices.Contains(home.Aliases, "Início") {
+		t.Errorf("aliases = %q, quero o nome do arquivo", home.Aliases)
+	}
+	var keys []string
+	for _, attachment := range home.Attachments {
+		keys = append(keys, attachment.Key)
+	}
+	if want := []string{"docs/contrato.pdf", "Projetos/imagens/foto.png"}; !slices.Equal(keys, want) {
+		t.Errorf("anexos = %q, quero %q", keys, want)
+	}
+
+	if txt := notes[1]; txt.Content != "![[foto.png]] fica como está" || len(txt.Attachments) > 0 {
+		t.Errorf("nota .txt = %q com %d anexos", txt.Content, len(txt.Attachments))
+	}
+
+	house := notes[2]
+	if !slices.Equal(house.Folder, []string{"Projetos"}) {
+		t.Errorf("pastas = %q, quero [Projetos]", house.Folder)
+	}
+	if want := "Link [[Início#Topo|relativo]] e [site](https://example.com).\n"; house.Content != want {
+		t.Errorf("conteúdo = %q, quero %q", house.Content, want)
+	}
+	if house.CreatedAt.IsZero() || time.Since(house.CreatedAt) > time.Hour {
+		t.Errorf("criada em %v, quero a data de modificação do arquivo", house.CreatedAt)
+	}
+}
@@ -0,0 +1,32 @@
+package importers
+
+import "time"
+
+// Note é uma nota lida de outro programa (Evernote, Obsidian, pastas de
+// Markdown), com as pastas pelo nome e os anexos ainda fora do app
+type Note struct {
+	Ref         string // arquivo ou posição na exportação, para o relatório
+	Title       string
+	Content     string   // Markdown; os anexos aparecem como ](<Key>)
+	Folder      []string // pastas, da raiz para dentro
+	Tags        []string
+	Aliases     []string // outros nomes pelos quais os links [[...]] a encontram
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Attachments []Attachment
+}
+
+// Attachment é um arquivo usado pela nota: o conteúdo em Data ou o caminho
+// em Path
+type Attachment struct {
+	Key      string // identifica o anexo no conteúdo da nota
+	FileName string
+	Data     []byte
+	Path     string
+}
+
+// AttachmentTarget é como o conteúdo da nota aponta para o anexo com a chave,
+// para ser trocado pelo endereço do anexo gravado
+func AttachmentTarget(key string) string {
+	return "](<" + key + ">)"
+}
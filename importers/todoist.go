@@ -0,0 +1,132 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"personal-cockpit/i18n"
+)
+
+// Todoist lê as tarefas exportadas do Todoist em JSON: a resposta da Sync
+// API ({"projects": [...], "items": [...]}) ou a lista de tarefas da REST
+// API. O projeto vira a categoria; as etiquetas vão para a descrição.
+type Todoist struct{}
+
+func (Todoist) Name() string { return "todoist" }
+
+// todoistID aceita os IDs numéricos das versões antigas da API e os textos
+// das atuais
+type todoistID string
+
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*id = todoistID(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*id = todoistID(number.String())
+	return nil
+}
+
+type todoistItem struct {
+	ID          todoistID `json:"id"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	Priority    int       `json:"priority"` // 4 = p1 (urgente) ... 1 = sem prioridade
+	ProjectID   todoistID `json:"project_id"`
+	Checked     bool      `json:"checked"`
+	IsCompleted bool      `json:"is_completed"`
+	IsDeleted   bool      `json:"is_deleted"`
+	Labels      []string  `json:"labels"`
+	Due         *struct {
+		Date     string `json:"date"`
+		Datetime string `json:"datetime"`
+	} `json:"due"`
+}
+
+type todoistExport struct {
+	Projects []struct {
+		ID   todoistID `json:"id"`
+		Name string    `json:"name"`
+	} `json:"projects"`
+	Items []todoistItem `json:"items"`
+	Tasks []todoistItem `json:"tasks"`
+}
+
+func (Todoist) Parse(data []byte, options Options) ([]Task, []Failure, error) {
+	var export todoistExport
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &export.Items); err != nil {
+			return nil, nil, i18n.Errorf("JSON do Todoist inválido: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &export); err != nil {
+		return nil, nil, i18n.Errorf("JSON do Todoist inválido: %w", err)
+	}
+
+	projects := make(map[todoistID]string, len(export.Projects))
+	for _, project := range export.Projects {
+		projects[project.ID] = project.Name
+	}
+
+	var tasks []Task
+	var failures []Failure
+
+	for i, item := range append(export.Items, export.Tasks...) {
+		ref := i18n.Sprintf("tarefa %s", item.ID)
+		if item.ID == "" {
+			ref = i18n.Sprintf("tarefa %d", i+1)
+		}
+		if item.IsDeleted {
+			continue
+		}
+
+		task := Task{
+			Ref:         ref,
+			Title:       strings.TrimSpace(item.Content),
+			Description: strings.TrimSpace(item.Description),
+			Priority:    todoistPriority(item.Priority),
+			Completed:   item.Checked || item.IsCompleted,
+			Category:    projects[item.ProjectID],
+		}
+
+		if item.Due != nil {
+			value := item.Due.Datetime
+			if value == "" {
+				value = item.Due.Date
+			}
+			due, err := parseDate(value)
+			if err != nil {
+				failures = append(failures, Failure{Ref: ref, Title: item.Content, Reason: err.Error()})
+				continue
+			}
+			task.DueDate = due
+		}
+
+		if len(item.Labels) > 0 {
+			task.Description = appendLine(task.Description, "@"+strings.Join(item.Labels, " @"))
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, failures, nil
+}
+
+func todoistPriority(priority int) string {
+	switch priority {
+	case 4:
+		return "high"
+	case 3:
+		return "medium"
+	case 2:
+		return "low"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,67 @@
+package importers
+
+import "testing"
+
+func TestTodoistParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []wantTask
+	}{
+		{
+			name: "Sync API",
+			data: `{
+				"projects": [{"id": "220474322", "name": "Casa"}],
+				"items": [
+					{"id": "2995104339", "content": "Ligar para o banco", "priority": 4, "project_id": "220474322",
+					 "labels": ["telefone", "manhã"], "due": {"date": "2026-10-20"}},
+					{"id": "2995104340", "content": "Pagar aluguel", "description": "até dia 5", "priority": 1,
+					 "checked": true, "due": {"date": "2026-10-05", "datetime": "2026-10-05T12:00:00Z"}},
+					{"id": "2995104341", "content": "Apagada", "is_deleted": true}
+				]
+			}`,
+			want: []wantTask{
+				{ref: "tarefa 2995104339", title: "Ligar para o banco", description: "@telefone @manhã", priority: "high", due: "2026-10-20", category: "Casa"},
+				{ref: "tarefa 2995104340", title: "Pagar aluguel", description: "até dia 5", completed: true, due: "2026-10-05"},
+			},
+		},
+		{
+			name: "REST API com IDs numéricos",
+			data: `[
+				{"id": 7, "content": "Revisar contrato", "priority": 2, "is_completed": false},
+				{"content": "Sem ID", "priority": 3, "is_completed": true}
+			]`,
+			want: []wantTask{
+				{ref: "tarefa 7", title: "Revisar contrato", priority: "low"},
+				{ref: "tarefa 2", title: "Sem ID", priority: "medium", completed: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, failures, err := Todoist{}.Parse([]byte(tt.data), Options{})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(failures) > 0 {
+				t.Errorf("falhas = %+v", failures)
+			}
+			checkTasks(t, tasks, tt.want)
+		})
+	}
+}
+
+func TestTodoistParseErrors(t *testing.T) {
+	if _, _, err := (Todoist{}).Parse([]byte(`{"items": `), Options{}); err == nil {
+		t.Error("JSON inválido deveria falhar")
+	}
+
+	tasks, failures, err := Todoist{}.Parse([]byte(`[{"id": "1", "content": "Prazo ruim", "due": {"date": "amanhã"}}]`), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tasks) != 0 || len(failures) != 1 || failures[0].Ref != "tarefa 1" {
+		t.Errorf("tarefas = %+v, falhas = %+v; quero só a falha da tarefa 1", tasks, failures)
+	}
+}
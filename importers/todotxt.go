@@ -0,0 +1,141 @@
+package importers
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"personal-cockpit/i18n"
+)
+
+// TodoTxt lê o formato todo.txt (http://todotxt.org), uma tarefa por linha:
+//
+//	x 2026-10-18 2026-10-01 (A) Ligar para o banco +Casa @telefone due:2026-10-20
+//
+// A prioridade (A) é alta, (B) média e as demais baixa; o primeiro +projeto
+// vira a categoria e due: o prazo. Contextos e outras chaves vão para a
+// descrição.
+type TodoTxt struct{}
+
+func (TodoTxt) Name() string { return "todotxt" }
+
+var todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\) `)
+
+var todoTxtDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} `)
+
+func (TodoTxt) Parse(data []byte, options Options) ([]Task, []Failure, error) {
+	var tasks []Task
+	var failures []Failure
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+lines:
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		task := Task{Ref: i18n.Sprintf("linha %d", line)}
+
+		// "x " no início marca concluída, seguida da data de conclusão
+		if strings.HasPrefix(text, "x ") {
+			task.Completed = true
+			text = strings.TrimSpace(text[2:])
+			if todoTxtDate.MatchString(text) {
+				text = text[11:]
+			}
+		}
+
+		// Prioridade e data de criação, que alguns programas trocam de ordem
+		// nas concluídas; a data de criação não tem onde ser guardada
+		for i := 0; i < 2; i++ {
+			if match := todoTxtPriority.FindStringSubmatch(text); match != nil {
+				task.Priority = todoTxtLetterPriority(match[1])
+				text = text[len(match[0]):]
+			}
+			if todoTxtDate.MatchString(text) {
+				text = text[11:]
+			}
+		}
+
+		var words, contexts, extras []string
+		for _, word := range strings.Fields(text) {
+			switch {
+			case len(word) > 1 && word[0] == '+':
+				if task.Category == "" {
+					task.Category = word[1:]
+				} else {
+					extras = append(extras, word)
+				}
+			case len(word) > 1 && word[0] == '@':
+				contexts = append(contexts, word)
+			case strings.HasPrefix(word, "due:") && len(word) > 4:
+				due, err := parseDate(word[4:])
+				if err != nil {
+					failures = append(failures, Failure{Ref: task.Ref, Title: text, Reason: err.Error()})
+					continue lines
+				}
+				task.DueDate = due
+			case strings.HasPrefix(word, "pri:") && len(word) == 5:
+				task.Priority = todoTxtLetterPriority(strings.ToUpper(word[4:]))
+			case todoTxtKeyValue(word):
+				extras = append(extras, word)
+			default:
+				words = append(words, word)
+			}
+		}
+
+		task.Title = strings.Join(words, " ")
+		if task.Title == "" {
+			// Só projetos e contextos: o texto original é o título
+			task.Title = text
+		}
+		if len(contexts) > 0 {
+			task.Description = appendLine(task.Description, strings.Join(contexts, " "))
+		}
+		if len(extras) > 0 {
+			task.Description = appendLine(task.Description, strings.Join(extras, " "))
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, failures, nil
+}
+
+func todoTxtLetterPriority(letter string) string {
+	switch letter {
+	case "A":
+		return "high"
+	case "B":
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// todoTxtKeyValue reconhece extensões "chave:valor" (t:, rec:...). A chave
+// só tem letras e o valor não tem "/", para não pegar horários ("10:30") nem
+// URLs.
+func todoTxtKeyValue(word string) bool {
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" || strings.Contains(value, "/") {
+		return false
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
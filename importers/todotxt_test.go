@@ -0,0 +1,97 @@
+package importers
+
+import "testing"
+
+func TestTodoTxtParse(t *testing.T) {
+	tests := []struct {
+		line        string
+		title       string
+		priority    string
+		category    string
+		completed   bool
+		due         string
+		description string
+	}{
+		{
+			line:        "x 2026-10-18 2026-10-01 (A) Ligar para o banco +Casa @telefone due:2026-10-20",
+			title:       "Ligar para o banco",
+			priority:    "high",
+			category:    "Casa",
+			completed:   true,
+			due:         "2026-10-20",
+			description: "@telefone",
+		},
+		{
+			line:  "Call Bob at 10:30 due:2024-05-01",
+			title: "Call Bob at 10:30",
+			due:   "2024-05-01",
+		},
+		{
+			line:  "Ler https://example.com/artigo",
+			title: "Ler https://example.com/artigo",
+		},
+		{
+			line:        "(B) Pagar aluguel +Casa +Contas t:2026-10-01 rec:1m",
+			title:       "Pagar aluguel",
+			priority:    "medium",
+			category:    "Casa",
+			description: "+Contas t:2026-10-01 rec:1m",
+		},
+		{
+			line:     "Revisar contrato pri:c",
+			title:    "Revisar contrato",
+			priority: "low",
+		},
+		{
+			line:        "+Casa @rua",
+			title:       "+Casa @rua",
+			category:    "Casa",
+			description: "@rua",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			tasks, failures, err := TodoTxt{}.Parse([]byte(tt.line), Options{})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(failures) > 0 || len(tasks) != 1 {
+				t.Fatalf("Parse = %d tarefas, falhas %+v; quero 1 tarefa", len(tasks), failures)
+			}
+
+			task := tasks[0]
+			if task.Title != tt.title {
+				t.Errorf("título = %q, quero %q", task.Title, tt.title)
+			}
+			if task.Priority != tt.priority {
+				t.Errorf("prioridade = %q, quero %q", task.Priority, tt.priority)
+			}
+			if task.Category != tt.category {
+				t.Errorf("categoria = %q, quero %q", task.Category, tt.category)
+			}
+			if task.Completed != tt.completed {
+				t.Errorf("concluída = %v, quero %v", task.Completed, tt.completed)
+			}
+			if got := formatDue(task); got != tt.due {
+				t.Errorf("prazo = %q, quero %q", got, tt.due)
+			}
+			if task.Description != tt.description {
+				t.Errorf("descrição = %q, quero %q", task.Description, tt.description)
+			}
+		})
+	}
+}
+
+func TestTodoTxtParseInvalidDue(t *testing.T) {
+	tasks, failures, err := TodoTxt{}.Parse([]byte("Comprar pão\nPagar conta due:amanhã\n"), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "Comprar pão" {
+		t.Errorf("tarefas = %+v, quero só \"Comprar pão\"", tasks)
+	}
+	if len(failures) != 1 || failures[0].Ref != "linha 2" {
+		t.Errorf("falhas = %+v, quero a linha 2", failures)
+	}
+}
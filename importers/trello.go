@@ -0,0 +1,144 @@
+package importers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"personal-cockpit/i18n"
+)
+
+// Trello lê a exportação JSON de um quadro do Trello (Menu > Imprimir,
+// exportar e compartilhar > Exportar como JSON). Cada cartão vira uma
+// tarefa na categoria com o nome do quadro; as checklists e as etiquetas
+// vão para a descrição. Cartões numa lista de concluídos ("Done",
+// "Concluído"...) ou com o prazo marcado como cumprido são concluídos.
+type Trello struct{}
+
+func (Trello) Name() string { return "trello" }
+
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string `json:"id"`
+		IDShort     int    `json:"idShort"`
+		Name        string `json:"name"`
+		Desc        string `json:"desc"`
+		IDList      string `json:"idList"`
+		Closed      bool   `json:"closed"`
+		Due         string `json:"due"`
+		DueComplete bool   `json:"dueComplete"`
+		Labels      []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string `json:"idCard"`
+		Name       string `json:"name"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+func (Trello) Parse(data []byte, options Options) ([]Task, []Failure, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, nil, i18n.Errorf("JSON do Trello inválido: %w", err)
+	}
+
+	type list struct {
+		name   string
+		closed bool
+	}
+	lists := make(map[string]list, len(board.Lists))
+	for _, l := range board.Lists {
+		lists[l.ID] = list{name: l.Name, closed: l.Closed}
+	}
+
+	// Checklists em Markdown, no formato das notas ("- [x] item")
+	checklists := make(map[string]string)
+	for _, checklist := range board.Checklists {
+		var b strings.Builder
+		b.WriteString(checklist.Name + ":\n")
+		for _, item := range checklist.CheckItems {
+			mark := " "
+			if item.State == "complete" {
+				mark = "x"
+			}
+			b.WriteString("- [" + mark + "] " + item.Name + "\n")
+		}
+		checklists[checklist.IDCard] = appendLine(checklists[checklist.IDCard], strings.TrimRight(b.String(), "\n"))
+	}
+
+	var tasks []Task
+	var failures []Failure
+
+	for _, card := range board.Cards {
+		l := lists[card.IDList]
+		task := Task{
+			Ref:         i18n.Sprintf("cartão %d", card.IDShort),
+			Title:       strings.TrimSpace(card.Name),
+			Description: strings.TrimSpace(card.Desc),
+			Completed:   card.DueComplete || isDoneName(l.name),
+			Category:    strings.TrimSpace(board.Name),
+		}
+
+		switch {
+		case card.Closed:
+			task.Skip = i18n.T("cartão arquivado")
+		case l.closed:
+			task.Skip = i18n.T("lista arquivada")
+		}
+
+		due, err := parseDate(card.Due)
+		if err != nil {
+			failures = append(failures, Failure{Ref: task.Ref, Title: task.Title, Reason: err.Error()})
+			continue
+		}
+		task.DueDate = due
+
+		var labels []string
+		for _, label := range card.Labels {
+			// Etiquetas sem nome são só uma cor
+			name := strings.TrimSpace(label.Name)
+			if name == "" {
+				continue
+			}
+			if priority := normalizePriority(name); priority != "" && task.Priority == "" && len(name) > 2 {
+				// Etiquetas "Alta", "Urgente"... são a prioridade
+				task.Priority = priority
+				continue
+			}
+			labels = append(labels, "@"+strings.ReplaceAll(name, " ", "-"))
+		}
+		if len(labels) > 0 {
+			task.Description = appendLine(task.Description, strings.Join(labels, " "))
+		}
+		task.Description = appendLine(task.Description, checklists[card.ID])
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, failures, nil
+}
+
+// isDoneName reconhece as listas de concluídos mais comuns
+func isDoneName(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if isDone(name) {
+		return true
+	}
+	for _, prefix := range []string{"done", "concluíd", "concluid", "feito", "finalizad", "complete"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
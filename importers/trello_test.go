@@ -0,0 +1,67 @@
+package importers
+
+import "testing"
+
+func TestTrelloParse(t *testing.T) {
+	data := `{
+		"name": "Casa",
+		"lists": [
+			{"id": "l1", "name": "A fazer"},
+			{"id": "l2", "name": "Concluídos"},
+			{"id": "l3", "name": "Antigas", "closed": true}
+		],
+		"cards": [
+			{"id": "c1", "idShort": 1, "name": "Consertar pia", "desc": "vazamento", "idList": "l1",
+			 "due": "2026-10-20T15:00:00.000Z", "labels": [{"name": "Urgente"}, {"name": "encanador externo"}, {"name": ""}]},
+			{"id": "c2", "idShort": 2, "name": "Trocar lâmpada", "idList": "l2"},
+			{"id": "c3", "idShort": 3, "name": "Pintar muro", "idList": "l1", "closed": true},
+			{"id": "c4", "idShort": 4, "name": "Podar árvore", "idList": "l3", "dueComplete": true}
+		],
+		"checklists": [
+			{"idCard": "c1", "name": "Material", "checkItems": [
+				{"name": "vedante", "state": "complete"},
+				{"name": "chave inglesa", "state": "incomplete"}
+			]}
+		]
+	}`
+
+	tasks, failures, err := Trello{}.Parse([]byte(data), Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(failures) > 0 {
+		t.Errorf("falhas = %+v", failures)
+	}
+
+	checkTasks(t, tasks, []wantTask{
+		{
+			ref:         "cartão 1",
+			title:       "Consertar pia",
+			description: "vazamento\n\n@encanador-externo\n\nMaterial:\n- [x] vedante\n- [ ] chave inglesa",
+			priority:    "high",
+			due:         "2026-10-20",
+			category:    "Casa",
+		},
+		{ref: "cartão 2", title: "Trocar lâmpada", completed: true, category: "Casa"},
+		{ref: "cartão 3", title: "Pintar muro", category: "Casa", skip: "cartão arquivado"},
+		{ref: "cartão 4", title: "Podar árvore", completed: true, category: "Casa", skip: "lista arquivada"},
+	})
+}
+
+func TestIsDoneName(t *testing.T) {
+	tests := map[string]bool{
+		"Done":            true,
+		"Concluído":       true,
+		"concluidas":      true,
+		"Feito ✔":         true,
+		"Completed tasks": true,
+		"A fazer":         false,
+		"Doing":           false,
+	}
+
+	for name, want := range tests {
+		if got := isDoneName(name); got != want {
+			t.Errorf("isDoneName(%q) = %v, quero %v", name, got, want)
+		}
+	}
+}
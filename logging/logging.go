@@ -0,0 +1,105 @@
+// Package logging configura o log/slog do app: níveis, arquivo com rotação no
+// diretório de dados e a medição de duração das operações dos services.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DirName é a pasta dos logs dentro do diretório de dados do app
+	DirName = "logs"
+
+	// FileName é o arquivo atual; os antigos ganham sufixo (.1, .2, ...)
+	FileName = "cockpit.log"
+
+	// Rotação: até 5 MB por arquivo, guardando os 5 últimos
+	MaxFileSize = 5 << 20
+	MaxFiles    = 5
+
+	// SlowOperation é a duração a partir da qual uma operação vira aviso
+	SlowOperation = 500 * time.Millisecond
+
+	// SettingLevel é a chave da configuração com o nível do log
+	SettingLevel = "log_level"
+)
+
+var level = new(slog.LevelVar)
+
+// Dir retorna a pasta dos logs dentro do diretório de dados
+func Dir(appDir string) string {
+	return filepath.Join(appDir, DirName)
+}
+
+// Setup passa o slog padrão a gravar em <appDir>/logs/cockpit.log e, se
+// console não for nil, também nele (o app usa a saída de erro; a CLI, só o
+// arquivo, para não misturar com a saída dos comandos). O nível inicial vem
+// de COCKPIT_LOG_LEVEL, ou info.
+func Setup(appDir string, console io.Writer) (*RotatingFile, error) {
+	file, err := OpenRotatingFile(filepath.Join(Dir(appDir), FileName), MaxFileSize, MaxFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if value := os.Getenv("COCKPIT_LOG_LEVEL"); value != "" {
+		SetLevel(value)
+	}
+
+	var out io.Writer = file
+	if console != nil {
+		out = io.MultiWriter(console, file)
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{Level: level})))
+	return file, nil
+}
+
+// SetLevel troca o nível (debug, info, warn, error) e retorna o aplicado;
+// valores desconhecidos voltam para info
+func SetLevel(name string) string {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		parsed = slog.LevelInfo
+	}
+	level.Set(parsed)
+	return strings.ToLower(parsed.String())
+}
+
+// Level retorna o nível em uso ("info", "debug", ...)
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
+// Track mede uma operação e registra o resultado. Uso, com o erro como
+// retorno nomeado:
+//
+//	func (s *TaskService) CreateTask(...) (_ int64, err error) {
+//		defer logging.Track("TaskService.CreateTask", &err)()
+//
+// As operações saem em debug; as lentas, em warn. Com erro, o registro ganha
+// o atributo "err" e sai pelo menos em info, para ser achado no nível padrão.
+func Track(operation string, err *error) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+
+		level, msg := slog.LevelDebug, "operação"
+		attrs := []any{"op", operation, "duration", elapsed}
+
+		if err != nil && *err != nil {
+			level, msg = slog.LevelInfo, "operação falhou"
+			attrs = append(attrs, "err", *err)
+		}
+		if elapsed >= SlowOperation {
+			level, msg = slog.LevelWarn, "operação lenta"
+		}
+
+		slog.Log(context.Background(), level, msg, attrs...)
+	}
+}
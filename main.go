@@ -2,30 +2,38 @@ package main
 
 import (
 	"embed"
-	"fmt"
-	"log"
+	"log/slog"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 
 	"personal-cockpit/database"
+	"personal-cockpit/logging"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
-	// Criar e testar banco de dados
-	fmt.Println("🔄 Inicializando banco de dados...")
-	db, err := database.NewDB()
+	// Log no terminal e em <dados do app>/logs/cockpit.log, que continua
+	// disponível no app empacotado
+	dataDir, err := database.GetAppDataDir()
 	if err != nil {
-		log.Fatal("❌ Erro ao criar banco:", err)
+		slog.Error("erro ao obter diretório de dados", "err", err)
+		os.Exit(1)
 	}
-	defer db.Close()
-	fmt.Println("✅ Banco de dados pronto!\n")
 
-	// Criar instância do App
+	logFile, err := logging.Setup(dataDir, os.Stderr)
+	if err != nil {
+		slog.Error("erro ao abrir log", "err", err)
+		os.Exit(1)
+	}
+	defer logFile.Close()
+
+	// Criar instância do App. O banco é aberto (só uma vez) no startup, e o
+	// beforeClose fecha; se falhar, o app abre em modo de recuperação.
 	app := NewApp()
 
 	// Criar aplicação Wails
@@ -38,12 +46,14 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnBeforeClose:    app.beforeClose,
+		ErrorFormatter:   formatError,
 		Bind: []interface{}{
 			app,
 		},
 	})
 
 	if err != nil {
-		println("Error:", err.Error())
+		slog.Error("erro ao executar o app", "err", err)
 	}
 }
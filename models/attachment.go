@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	ID         int       `json:"id"`
+	SHA256     string    `json:"sha256"`
+	FileName   string    `json:"file_name"`
+	MimeType   string    `json:"mime_type"`
+	FileSize   int64     `json:"file_size"`
+	Tags       []string  `json:"tags"`
+	EntityType string    `json:"entity_type"`
+	EntityID   *int      `json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AttachmentFilter struct {
+	Name       string
+	MimeType   string
+	Tag        string
+	EntityType string
+	EntityID   *int
+}
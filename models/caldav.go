@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// CalDAVConfig é a conta CalDAV usada na sincronização de eventos e tarefas
+type CalDAVConfig struct {
+	Enabled         bool   `json:"enabled"`
+	EventsURL       string `json:"events_url"` // coleção dos eventos (ex.: https://host/user/calendar/)
+	TasksURL        string `json:"tasks_url"`  // coleção das tarefas; vazia usa a dos eventos
+	Username        string `json:"username"`
+	Password        string `json:"password,omitempty"` // só na gravação: vazia mantém a senha salva
+	HasPassword     bool   `json:"has_password"`
+	ConflictPolicy  string `json:"conflict_policy"`  // server, local ou newest
+	IntervalMinutes int    `json:"interval_minutes"` // 0 = só sincronização manual
+}
+
+// CalDAVSyncLog é o resumo de uma sincronização
+type CalDAVSyncLog struct {
+	ID         int        `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Pulled     int        `json:"pulled"`    // criados ou alterados aqui a partir do servidor
+	Pushed     int        `json:"pushed"`    // enviados ao servidor
+	Deleted    int        `json:"deleted"`   // apagados de um lado por terem sido apagados do outro
+	Conflicts  int        `json:"conflicts"` // alterados dos dois lados, resolvidos pela política
+	Failed     int        `json:"failed"`    // itens que não puderam ser sincronizados
+	Error      string     `json:"error"`     // erros dos itens, ou o que interrompeu a sincronização
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// CalendarView é a grade de um mês, semana ou dia, com os eventos já
+// distribuídos pelos dias no fuso do usuário
+type CalendarView struct {
+	Kind      string        `json:"kind"`       // month, week ou day
+	Anchor    string        `json:"anchor"`     // dia pedido (AAAA-MM-DD)
+	Start     string        `json:"start"`      // primeiro dia da grade
+	End       string        `json:"end"`        // último dia da grade
+	WeekStart string        `json:"week_start"` // sunday, monday...
+	Timezone  string        `json:"timezone"`
+	Days      []CalendarDay `json:"days"`
+	Lanes     int           `json:"lanes"` // maior número de faixas de dia inteiro numa semana
+}
+
+// CalendarDay é uma célula da grade
+type CalendarDay struct {
+	Date    string            `json:"date"`     // AAAA-MM-DD
+	InRange bool              `json:"in_range"` // false nos dias de outro mês que completam a grade
+	IsToday bool              `json:"is_today"`
+	AllDay  []CalendarAllDay  `json:"all_day"` // eventos de dia inteiro ou de vários dias
+	Events  []CalendarSegment `json:"events"`  // eventos com hora, recortados no dia
+}
+
+// CalendarAllDay é um evento na faixa de dia inteiro. O evento fica na mesma
+// faixa (Lane) em todos os dias da semana que ocupa, para a barra não quebrar.
+type CalendarAllDay struct {
+	Event           Event `json:"event"`
+	Lane            int   `json:"lane"`
+	ContinuesBefore bool  `json:"continues_before"` // começou num dia anterior
+	ContinuesAfter  bool  `json:"continues_after"`  // segue no dia seguinte
+}
+
+// CalendarSegment é o trecho de um evento com hora dentro de um dia
+type CalendarSegment struct {
+	Event           Event     `json:"event"`
+	Start           time.Time `json:"start"` // início do trecho, no fuso do usuário
+	End             time.Time `json:"end"`
+	ContinuesBefore bool      `json:"continues_before"`
+	ContinuesAfter  bool      `json:"continues_after"`
+}
+
+// WorkingHours é o expediente considerado na busca por horários livres
+type WorkingHours struct {
+	Start         string `json:"start"`          // "09:00"
+	End           string `json:"end"`            // "18:00"
+	Days          []int  `json:"days"`           // dias da semana, 0 = domingo
+	BufferMinutes int    `json:"buffer_minutes"` // folga antes e depois de cada evento
+}
+
+// TimeSlot é um intervalo livre na agenda, no fuso do usuário
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ChangeNotice avisa o frontend que uma lista precisa ser atualizada. É
+// emitido no evento "<entidade>:changed" (ex.: "tasks:changed").
+type ChangeNotice struct {
+	Entity string `json:"entity"` // tasks, notes, events, ...
+	IDs    []int  `json:"ids"`    // vazio = recarregar a lista inteira
+	Source string `json:"source"` // "app" (este processo) ou "external" (CLI, outro programa)
+}
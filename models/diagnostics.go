@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Diagnostics resume o estado do app para anexar em relatos de bug
+type Diagnostics struct {
+	App            string        `json:"app"`
+	Version        string        `json:"version"`
+	SchemaVersion  int           `json:"schema_version"`
+	GeneratedAt    time.Time     `json:"generated_at"`
+	GoVersion      string        `json:"go_version"`
+	OS             string        `json:"os"`
+	Arch           string        `json:"arch"`
+	Locale         string        `json:"locale"`
+	Timezone       string        `json:"timezone"`
+	LogLevel       string        `json:"log_level"`
+	Database       DatabaseStats `json:"database"`
+	IntegrityCheck []string      `json:"integrity_check"` // ["ok"] quando está tudo certo
+}
+
+// DatabaseStats traz o tamanho do banco e a contagem de linhas por tabela
+type DatabaseStats struct {
+	PageSize      int64          `json:"page_size"`
+	PageCount     int64          `json:"page_count"`
+	FreelistCount int64          `json:"freelist_count"`
+	SizeBytes     int64          `json:"size_bytes"`
+	JournalMode   string         `json:"journal_mode"`
+	Tables        map[string]int `json:"tables"`
+}
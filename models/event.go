@@ -12,6 +12,24 @@ type Event struct {
 	Color           string    `json:"color"`
 	Location        string    `json:"location"`
 	ReminderMinutes *int      `json:"reminder_minutes"`
+	TaskID          *int      `json:"task_id"`         // bloco de tempo da tarefa
+	IsDone          bool      `json:"is_done"`         // a tarefa do bloco foi concluída
+	RecurrenceRule  string    `json:"recurrence_rule"` // RRULE do iCalendar, vinda da sincronização CalDAV
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
+
+// Reminder é o aviso de que um evento está começando
+type Reminder struct {
+	EventID   int       `json:"event_id"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"` // já no idioma do app
+	StartDate time.Time `json:"start_date"`
+}
+
+// EventSaveResult é o evento criado ou alterado e os eventos que ocupam o
+// mesmo horário. Conflitos não impedem a gravação: são só um aviso.
+type EventSaveResult struct {
+	ID        int     `json:"id"`
+	Conflicts []Event `json:"conflicts"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ExportData é o conteúdo completo exportado em JSON
+type ExportData struct {
+	App            string         `json:"app"`
+	Version        string         `json:"version"`
+	SchemaVersion  int            `json:"schema_version"`
+	ExportedAt     time.Time      `json:"exported_at"`
+	Categories     []Category     `json:"categories"`
+	Tasks          []Task         `json:"tasks"`
+	Notebooks      []Notebook     `json:"notebooks"`
+	Notes          []Note         `json:"notes"`
+	Events         []Event        `json:"events"`
+	Templates      []Template     `json:"templates"`
+	JournalEntries []JournalEntry `json:"journal_entries"`
+	Accounts       []Account      `json:"accounts"`
+	Transactions   []Transaction  `json:"transactions"`
+}
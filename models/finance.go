@@ -0,0 +1,105 @@
+package models
+
+import "time"
+
+// Valores monetários são inteiros em unidades mínimas da moeda
+// (ex.: centavos para BRL/USD) acompanhados do código ISO 4217.
+
+type Account struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	Currency       string    `json:"currency"`
+	InitialBalance int64     `json:"initial_balance"`
+	Balance        int64     `json:"balance"` // Calculado: saldo inicial + transações
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type Transaction struct {
+	ID          int       `json:"id"`
+	AccountID   int       `json:"account_id"`
+	CategoryID  *int      `json:"category_id"`
+	Amount      int64     `json:"amount"` // Negativo = despesa
+	Currency    string    `json:"currency"`
+	Description string    `json:"description"`
+	Date        string    `json:"date"` // AAAA-MM-DD
+	RecurringID *int      `json:"recurring_id"`
+	ImportID    string    `json:"import_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TransactionFilter struct {
+	AccountID  *int
+	CategoryID *int
+	StartDate  string // AAAA-MM-DD, inclusivo
+	EndDate    string // AAAA-MM-DD, inclusivo
+	Query      string
+}
+
+type RecurringTransaction struct {
+	ID          int       `json:"id"`
+	AccountID   int       `json:"account_id"`
+	CategoryID  *int      `json:"category_id"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	Description string    `json:"description"`
+	Frequency   string    `json:"frequency"` // weekly, monthly, yearly
+	NextDate    string    `json:"next_date"` // AAAA-MM-DD
+	EndDate     *string   `json:"end_date"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Budget struct {
+	ID         int       `json:"id"`
+	CategoryID int       `json:"category_id"`
+	Amount     int64     `json:"amount"` // Limite mensal (positivo)
+	Currency   string    `json:"currency"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BudgetStatus compara o orçamento com o gasto no mês
+type BudgetStatus struct {
+	Budget       Budget  `json:"budget"`
+	CategoryName string  `json:"category_name"`
+	Spent        int64   `json:"spent"`
+	Remaining    int64   `json:"remaining"`
+	Percent      float64 `json:"percent"`
+	Alert        string  `json:"alert"` // "", "warning" (>= 80%) ou "exceeded"
+}
+
+type CategorySummary struct {
+	CategoryID   *int   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Color        string `json:"color"`
+	Currency     string `json:"currency"`
+	Income       int64  `json:"income"`
+	Expense      int64  `json:"expense"` // Positivo
+	Count        int    `json:"count"`
+}
+
+type CurrencyTotal struct {
+	Currency string `json:"currency"`
+	Income   int64  `json:"income"`
+	Expense  int64  `json:"expense"`
+	Net      int64  `json:"net"`
+}
+
+type MonthlySummary struct {
+	Year       int               `json:"year"`
+	Month      int               `json:"month"`
+	Categories []CategorySummary `json:"categories"`
+	Totals     []CurrencyTotal   `json:"totals"`
+}
+
+// TransactionResult é retornado ao criar uma transação pelo frontend
+type TransactionResult struct {
+	ID     int64          `json:"id"`
+	Alerts []BudgetStatus `json:"alerts"` // Orçamentos do mês em alerta
+}
+
+type ImportResult struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"` // Já importados anteriormente
+	Errors   []string `json:"errors"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type JournalEntry struct {
+	ID      int      `json:"id"`
+	Date    string   `json:"date"`   // AAAA-MM-DD
+	Mood    *int     `json:"mood"`   // 1 a 5
+	Energy  *int     `json:"energy"` // 1 a 5
+	Tags    []string `json:"tags"`
+	Content string   `json:"content"`
+
+	// Preenchidos automaticamente a partir das tarefas e eventos do dia
+	CompletedTasks []Task  `json:"completed_tasks"`
+	Events         []Event `json:"events"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type JournalFilter struct {
+	StartDate string // AAAA-MM-DD, inclusivo
+	EndDate   string // AAAA-MM-DD, inclusivo
+	Query     string
+	Tag       string
+}
+
+// MoodPoint é um ponto da série de humor/energia
+type MoodPoint struct {
+	Date   string `json:"date"`
+	Mood   *int   `json:"mood"`
+	Energy *int   `json:"energy"`
+	// Média móvel do humor nos últimos 7 pontos com humor registrado
+	MoodAverage float64 `json:"mood_average"`
+}
@@ -7,7 +7,48 @@ type Note struct {
 	Title      string    `json:"title"`
 	Content    string    `json:"content"`
 	CategoryID *int      `json:"category_id"`
+	NotebookID *int      `json:"notebook_id"`
 	IsFavorite bool      `json:"is_favorite"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
+
+// NoteFolderSyncResult é o resumo de uma sincronização com a pasta de notas
+type NoteFolderSyncResult struct {
+	Imported  int      `json:"imported"`  // notas criadas ou alteradas a partir de arquivos
+	Exported  int      `json:"exported"`  // arquivos escritos a partir das notas
+	Renamed   int      `json:"renamed"`   // arquivos renomeados ou movidos fora do app
+	Deleted   int      `json:"deleted"`   // apagados de um lado por terem sido apagados do outro
+	Conflicts int      `json:"conflicts"` // alterados dos dois lados (as duas versões ficam)
+	Errors    []string `json:"errors"`    // arquivos que não puderam ser sincronizados
+}
+
+// NoteImportOptions ajusta uma importação de notas
+type NoteImportOptions struct {
+	Source    string `json:"source"`     // enex ou folder (pasta de .md/.txt, inclusive cofres do Obsidian); vazio detecta pelo caminho
+	FoldersAs string `json:"folders_as"` // notebooks (padrão), categories ou none
+	DryRun    bool   `json:"dry_run"`    // só mostra o que seria feito
+}
+
+// NoteImportReport é o resultado (ou, no dry run, a prévia) de uma importação
+// de notas
+type NoteImportReport struct {
+	Source            string           `json:"source"`
+	DryRun            bool             `json:"dry_run"`
+	Created           []NoteImportItem `json:"created"` // no dry run, as que seriam criadas
+	Skipped           []NoteImportItem `json:"skipped"`
+	Failed            []NoteImportItem `json:"failed"`
+	NotebooksCreated  []string         `json:"notebooks_created"` // caminhos, como "Trabalho/Reuniões"
+	CategoriesCreated []string         `json:"categories_created"`
+	Attachments       int              `json:"attachments"` // arquivos anexados às notas
+}
+
+// NoteImportItem é uma nota do relatório de importação
+type NoteImportItem struct {
+	Ref             string   `json:"ref"` // arquivo ou posição na exportação
+	Title           string   `json:"title"`
+	NoteID          int      `json:"note_id,omitempty"`
+	Folder          string   `json:"folder,omitempty"`           // caderno ou categoria de destino
+	UnresolvedLinks []string `json:"unresolved_links,omitempty"` // [[links]] para notas que não existem
+	Reason          string   `json:"reason,omitempty"`           // por que foi pulada ou falhou
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type Notebook struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	ParentID   *int       `json:"parent_id"`
+	NoteCount  int        `json:"note_count"`  // Notas diretamente no caderno
+	TotalCount int        `json:"total_count"` // Notas no caderno e em todos os subcadernos
+	Children   []Notebook `json:"children"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
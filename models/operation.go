@@ -0,0 +1,9 @@
+package models
+
+// OperationProgress é o andamento de uma operação longa (importação,
+// exportação, busca), enviado ao frontend no evento "operation:progress"
+type OperationProgress struct {
+	ID    string `json:"id"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// QuickAddResult é a interpretação de um texto da criação rápida. Serve de
+// prévia enquanto o usuário digita e, depois de confirmado, traz o ID criado.
+type QuickAddResult struct {
+	Kind         string   `json:"kind"` // "task" ou "event"
+	Task         *Task    `json:"task,omitempty"`
+	Event        *Event   `json:"event,omitempty"`
+	CategoryName string   `json:"category_name,omitempty"` // do #marcador
+	NewCategory  bool     `json:"new_category"`            // a categoria não existe e será criada
+	Warnings     []string `json:"warnings"`                // partes ignoradas ou faltando
+	ID           int      `json:"id"`                      // preenchido após criar
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Setting é uma configuração chave/valor do app
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// APIStatus descreve o servidor HTTP local de integrações
+type APIStatus struct {
+	Enabled    bool   `json:"enabled"`
+	Running    bool   `json:"running"`
+	Address    string `json:"address"`
+	Token      string `json:"token"`
+	OpenAPIURL string `json:"openapi_url"`
+}
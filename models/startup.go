@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Ações oferecidas quando o app inicia em modo de recuperação
+const (
+	StartupActionRetry         = "retry"          // tentar abrir o banco de novo
+	StartupActionRestoreBackup = "restore_backup" // voltar para uma cópia de segurança
+	StartupActionOpenDatabase  = "open_database"  // abrir outro arquivo de banco
+	StartupActionReset         = "reset"          // começar com um banco vazio
+)
+
+// StartupStatus informa se o app iniciou normalmente ou em modo de
+// recuperação, com o erro e as ações possíveis
+type StartupStatus struct {
+	Ready        bool     `json:"ready"`
+	Error        string   `json:"error,omitempty"`
+	DatabasePath string   `json:"database_path"`
+	Actions      []string `json:"actions,omitempty"`
+	Backups      []Backup `json:"backups,omitempty"`
+}
+
+// Backup é uma cópia de segurança do banco
+type Backup struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -3,16 +3,18 @@ package models
 import "time"
 
 type Task struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      string     `json:"status"`
-	Priority    string     `json:"priority"`
-	CategoryID  *int       `json:"category_id"`
-	DueDate     *time.Time `json:"due_date"`
-	CompletedAt *time.Time `json:"completed_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID               int        `json:"id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	Priority         string     `json:"priority"`
+	CategoryID       *int       `json:"category_id"`
+	NoteID           *int       `json:"note_id"`
+	DueDate          *time.Time `json:"due_date"`
+	EstimatedMinutes *int       `json:"estimated_minutes"` // duração prevista, para encaixar na agenda
+	CompletedAt      *time.Time `json:"completed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 type TaskFilter struct {
@@ -20,3 +22,54 @@ type TaskFilter struct {
 	Priority   string
 	CategoryID *int
 }
+
+// AutoScheduleResult diz onde cada tarefa foi encaixada na agenda
+type AutoScheduleResult struct {
+	Scheduled   []Event `json:"scheduled"`   // blocos de tempo (os que já existiam primeiro)
+	Unscheduled []Task  `json:"unscheduled"` // tarefas que não couberam no período
+}
+
+// CSVColumnMapping diz de qual coluna do CSV sai cada campo da tarefa (pelo
+// nome no cabeçalho; vazio = não importar)
+type CSVColumnMapping struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"`
+	Status      string `json:"status"`
+	Category    string `json:"category"`
+	DueDate     string `json:"due_date"`
+}
+
+// CSVPreview é o cabeçalho de um CSV, com as colunas sugeridas e amostras
+type CSVPreview struct {
+	Columns []string         `json:"columns"`
+	Mapping CSVColumnMapping `json:"mapping"`
+	Sample  [][]string       `json:"sample"` // primeiras linhas, sem o cabeçalho
+}
+
+// TaskImportOptions ajusta uma importação de tarefas
+type TaskImportOptions struct {
+	Format           string           `json:"format"`            // todotxt, todoist, trello ou csv; vazio detecta pelo arquivo
+	DryRun           bool             `json:"dry_run"`           // só mostra o que seria feito
+	IncludeCompleted bool             `json:"include_completed"` // importa também as concluídas
+	CSVMapping       CSVColumnMapping `json:"csv_mapping"`
+}
+
+// TaskImportReport é o resultado (ou, no dry run, a prévia) de uma importação
+type TaskImportReport struct {
+	Format            string           `json:"format"`
+	DryRun            bool             `json:"dry_run"`
+	Created           []TaskImportItem `json:"created"` // no dry run, as que seriam criadas
+	Skipped           []TaskImportItem `json:"skipped"`
+	Failed            []TaskImportItem `json:"failed"`
+	CategoriesCreated []string         `json:"categories_created"`
+}
+
+// TaskImportItem é uma linha do relatório de importação
+type TaskImportItem struct {
+	Ref      string `json:"ref"` // onde o item está no arquivo ("linha 3", "cartão 12")
+	Title    string `json:"title"`
+	Task     *Task  `json:"task,omitempty"`     // como a tarefa foi (ou seria) criada
+	Category string `json:"category,omitempty"` // nome da categoria da tarefa
+	Reason   string `json:"reason,omitempty"`   // por que foi pulada ou falhou
+}
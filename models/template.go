@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Template struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"` // "note" ou "tasks"
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	CategoryID *int      `json:"category_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RenderedTemplate é o resultado de um modelo com as variáveis preenchidas
+type RenderedTemplate struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// Posição do {{cursor}} no conteúdo, em unidades UTF-16 (como no JavaScript).
+	// -1 quando o modelo não define cursor.
+	CursorOffset int `json:"cursor_offset"`
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+const attachmentColumns = `id, sha256, file_name, mime_type, file_size, tags, entity_type, entity_id, created_at`
+
+type sqliteAttachments struct {
+	db DBTX
+}
+
+func (r *sqliteAttachments) Create(ctx context.Context, attachment models.Attachment) (int64, error) {
+	query := `
+		INSERT INTO attachments (sha256, file_name, mime_type, file_size, tags, entity_type, entity_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		attachment.SHA256,
+		attachment.FileName,
+		attachment.MimeType,
+		attachment.FileSize,
+		joinTags(attachment.Tags),
+		nullableString(attachment.EntityType),
+		attachment.EntityID,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar anexo: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteAttachments) GetByID(ctx context.Context, id int) (*models.Attachment, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+attachmentColumns+" FROM attachments WHERE id = ?", id)
+	return scanOne(row, scanAttachment)
+}
+
+func (r *sqliteAttachments) Search(ctx context.Context, filter models.AttachmentFilter) ([]models.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.Name != "" {
+		query += " AND file_name LIKE ?"
+		args = append(args, "%"+filter.Name+"%")
+	}
+
+	// "image/" encontra todas as imagens, "image/png" apenas PNG
+	if filter.MimeType != "" {
+		query += " AND mime_type LIKE ?"
+		args = append(args, filter.MimeType+"%")
+	}
+
+	if filter.Tag != "" {
+		query += " AND " + tagCondition("tags")
+		args = append(args, tagPattern(filter.Tag))
+	}
+
+	if filter.EntityType != "" {
+		query += " AND entity_type = ?"
+		args = append(args, filter.EntityType)
+	}
+
+	if filter.EntityID != nil {
+		query += " AND entity_id = ?"
+		args = append(args, *filter.EntityID)
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar anexos: %w", err)
+	}
+
+	return scanAll(rows, scanAttachment)
+}
+
+func (r *sqliteAttachments) Update(ctx context.Context, attachment models.Attachment) error {
+	query := `
+		UPDATE attachments
+		SET file_name = ?, tags = ?, entity_type = ?, entity_id = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		attachment.FileName,
+		joinTags(attachment.Tags),
+		nullableString(attachment.EntityType),
+		attachment.EntityID,
+		attachment.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar anexo: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteAttachments) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM attachments WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar anexo: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteAttachments) CountBySHA256(ctx context.Context, hash string) (int, error) {
+	var refs int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM attachments WHERE sha256 = ?", hash).Scan(&refs); err != nil {
+		return 0, i18n.Errorf("erro ao contar referências: %w", err)
+	}
+	return refs, nil
+}
+
+func scanAttachment(row rowScanner) (models.Attachment, error) {
+	var attachment models.Attachment
+	var mimeType, tags, entityType sql.NullString
+
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.SHA256,
+		&attachment.FileName,
+		&mimeType,
+		&attachment.FileSize,
+		&tags,
+		&entityType,
+		&attachment.EntityID,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return attachment, i18n.Errorf("erro ao ler anexo: %w", err)
+	}
+
+	attachment.MimeType = mimeType.String
+	attachment.Tags = splitTags(tags.String)
+	attachment.EntityType = entityType.String
+
+	return attachment, nil
+}
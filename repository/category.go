@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+type sqliteCategories struct {
+	db DBTX
+}
+
+// Create e Update deixam passar o erro de UNIQUE do SQLite (nome repetido)
+// embrulhado, para o service responder com ConflictError
+func (r *sqliteCategories) Create(ctx context.Context, category models.Category) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		"INSERT INTO categories (name, color, type) VALUES (?, ?, ?)",
+		category.Name,
+		category.Color,
+		category.Type,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar categoria: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteCategories) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories WHERE id = ?", id)
+	return scanOne(row, scanCategory)
+}
+
+func (r *sqliteCategories) List(ctx context.Context, categoryType string) ([]models.Category, error) {
+	query := "SELECT " + categoryColumns + " FROM categories"
+	args := []interface{}{}
+
+	if categoryType != "" {
+		query += " WHERE type = ?"
+		args = append(args, categoryType)
+	}
+
+	query += " ORDER BY name ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar categorias: %w", err)
+	}
+
+	return scanAll(rows, scanCategory)
+}
+
+func (r *sqliteCategories) Update(ctx context.Context, category models.Category) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE categories SET name = ?, color = ?, type = ? WHERE id = ?",
+		category.Name,
+		category.Color,
+		category.Type,
+		category.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar categoria: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteCategories) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM categories WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar categoria: %w", err)
+	}
+
+	return affected(result)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"personal-cockpit/database"
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+type sqliteEvents struct {
+	db DBTX
+}
+
+func (r *sqliteEvents) Create(ctx context.Context, event models.Event) (int64, error) {
+	query := `
+		INSERT INTO events (title, description, start_date, end_date, all_day, color, location, reminder_minutes, task_id, recurrence_rule)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	start, end := eventBounds(event)
+
+	result, err := r.db.ExecContext(ctx, query,
+		event.Title,
+		event.Description,
+		start,
+		end,
+		event.AllDay,
+		event.Color,
+		event.Location,
+		event.ReminderMinutes,
+		event.TaskID,
+		event.RecurrenceRule,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar evento: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteEvents) GetByID(ctx context.Context, id int) (*models.Event, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+eventColumns+" FROM events WHERE id = ?", id)
+	return scanOne(row, scanEvent)
+}
+
+// GetByTask traz o bloco de tempo da tarefa, ou ErrNotFound
+func (r *sqliteEvents) GetByTask(ctx context.Context, taskID int) (*models.Event, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+eventColumns+" FROM events WHERE task_id = ?", taskID)
+	return scanOne(row, scanEvent)
+}
+
+func (r *sqliteEvents) List(ctx context.Context) ([]models.Event, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+eventColumns+" FROM events ORDER BY start_date ASC")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar eventos: %w", err)
+	}
+
+	return scanAll(rows, scanEvent)
+}
+
+// ListStartingBetween traz os eventos que começam de from a to, inclusive. Um
+// evento de dia inteiro começa à meia-noite do seu dia no fuso do usuário.
+func (r *sqliteEvents) ListStartingBetween(ctx context.Context, from, to time.Time) ([]models.Event, error) {
+	query := "SELECT " + eventColumns + ` FROM events
+		WHERE (all_day = 0 AND start_date >= ? AND start_date <= ?)
+		   OR (all_day = 1 AND start_date >= ? AND start_date <= ?)
+		ORDER BY start_date ASC`
+
+	firstDay, lastDay := daysStartingBetween(from, to)
+
+	rows, err := r.db.QueryContext(ctx, query, timestamp(from), timestamp(to), firstDay, lastDay)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar eventos: %w", err)
+	}
+
+	return scanAll(rows, scanEvent)
+}
+
+// daysStartingBetween retorna o primeiro e o último dia cuja meia-noite, no
+// fuso do usuário, cai entre from e to
+func daysStartingBetween(from, to time.Time) (string, string) {
+	first := timezone.StartOfDay(from)
+	if first.Before(from) {
+		first = first.AddDate(0, 0, 1)
+	}
+
+	return database.FormatDate(first), database.FormatDate(timezone.In(to))
+}
+
+// ListOverlapping traz os eventos que ocupam algum momento de [from, to).
+// Um evento com hora ocupa [início, fim); um de dia inteiro ocupa os dias de
+// start_date até o dia anterior a end_date (ou só start_date, se forem
+// iguais). Eventos de duração zero contam se começam dentro do intervalo.
+func (r *sqliteEvents) ListOverlapping(ctx context.Context, from, to time.Time) ([]models.Event, error) {
+	query := "SELECT " + eventColumns + ` FROM events
+		WHERE (all_day = 0 AND start_date < ? AND (end_date > ? OR start_date >= ?))
+		   OR (all_day = 1 AND start_date <= ? AND (end_date > ? OR start_date >= ?))
+		ORDER BY start_date ASC`
+
+	start, end := timestamp(from), timestamp(to)
+	firstDay, lastDay := daysOverlapping(from, to)
+
+	rows, err := r.db.QueryContext(ctx, query, end, start, start, lastDay, firstDay, firstDay)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar eventos: %w", err)
+	}
+
+	return scanAll(rows, scanEvent)
+}
+
+// daysOverlapping retorna o primeiro e o último dia, no fuso do usuário, que
+// têm algum momento em [from, to)
+func daysOverlapping(from, to time.Time) (string, string) {
+	last := to.Add(-time.Nanosecond)
+	if last.Before(from) {
+		last = from
+	}
+
+	return database.FormatDate(timezone.In(from)), database.FormatDate(timezone.In(last))
+}
+
+func (r *sqliteEvents) Update(ctx context.Context, event models.Event) error {
+	query := `
+		UPDATE events
+		SET title = ?, description = ?, start_date = ?, end_date = ?, all_day = ?,
+		    color = ?, location = ?, reminder_minutes = ?, recurrence_rule = ?
+		WHERE id = ?
+	`
+
+	start, end := eventBounds(event)
+
+	result, err := r.db.ExecContext(ctx, query,
+		event.Title,
+		event.Description,
+		start,
+		end,
+		event.AllDay,
+		event.Color,
+		event.Location,
+		event.ReminderMinutes,
+		event.RecurrenceRule,
+		event.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar evento: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteEvents) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM events WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar evento: %w", err)
+	}
+
+	return affected(result)
+}
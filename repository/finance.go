@@ -0,0 +1,472 @@
+package repository
+
+import (
+	"context"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+// Colunas das leituras de contas (com o saldo atual), transações e recorrências
+const (
+	accountColumns = `a.id, a.name, a.type, a.currency, a.initial_balance,
+		a.initial_balance + COALESCE((SELECT SUM(amount) FROM transactions WHERE account_id = a.id), 0),
+		a.created_at`
+
+	transactionColumns = `id, account_id, category_id, amount, currency, COALESCE(description, ''), date,
+		recurring_id, COALESCE(import_id, ''), created_at, updated_at`
+
+	recurringColumns = `id, account_id, category_id, amount, currency, description, frequency,
+		next_date, end_date, created_at`
+)
+
+// ═══════════════════════════════════════════════════════════
+// CONTAS
+// ═══════════════════════════════════════════════════════════
+
+type sqliteAccounts struct {
+	db DBTX
+}
+
+// Create e Update deixam passar o erro de UNIQUE do SQLite (nome repetido)
+// embrulhado, para o service responder com ConflictError
+func (r *sqliteAccounts) Create(ctx context.Context, account models.Account) (int64, error) {
+	query := `
+		INSERT INTO accounts (name, type, currency, initial_balance)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		account.Name,
+		account.Type,
+		account.Currency,
+		account.InitialBalance,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar conta: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteAccounts) GetByID(ctx context.Context, id int) (*models.Account, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts a WHERE a.id = ?", id)
+	return scanOne(row, scanAccount)
+}
+
+func (r *sqliteAccounts) List(ctx context.Context) ([]models.Account, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts a ORDER BY a.name ASC")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar contas: %w", err)
+	}
+
+	return scanAll(rows, scanAccount)
+}
+
+func (r *sqliteAccounts) Update(ctx context.Context, account models.Account) error {
+	query := `
+		UPDATE accounts
+		SET name = ?, type = ?, initial_balance = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, account.Name, account.Type, account.InitialBalance, account.ID)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar conta: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteAccounts) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM accounts WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar conta: %w", err)
+	}
+
+	return affected(result)
+}
+
+func scanAccount(row rowScanner) (models.Account, error) {
+	var account models.Account
+	err := row.Scan(
+		&account.ID,
+		&account.Name,
+		&account.Type,
+		&account.Currency,
+		&account.InitialBalance,
+		&account.Balance,
+		&account.CreatedAt,
+	)
+	if err != nil {
+		return account, i18n.Errorf("erro ao ler conta: %w", err)
+	}
+	return account, nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// TRANSAÇÕES
+// ═══════════════════════════════════════════════════════════
+
+type sqliteTransactions struct {
+	db DBTX
+}
+
+func (r *sqliteTransactions) Create(ctx context.Context, transaction models.Transaction) (int64, error) {
+	query := `
+		INSERT INTO transactions (account_id, category_id, amount, currency, description, date, recurring_id, import_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		transaction.AccountID,
+		transaction.CategoryID,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Description,
+		transaction.Date,
+		transaction.RecurringID,
+		nullableString(transaction.ImportID),
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar transação: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteTransactions) Import(ctx context.Context, transaction models.Transaction) (bool, error) {
+	query := `
+		INSERT OR IGNORE INTO transactions (account_id, amount, currency, description, date, import_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		transaction.AccountID,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Description,
+		transaction.Date,
+		transaction.ImportID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+func (r *sqliteTransactions) List(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM transactions WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.AccountID != nil {
+		query += " AND account_id = ?"
+		args = append(args, *filter.AccountID)
+	}
+
+	if filter.CategoryID != nil {
+		query += " AND category_id = ?"
+		args = append(args, *filter.CategoryID)
+	}
+
+	if filter.StartDate != "" {
+		query += " AND date >= ?"
+		args = append(args, filter.StartDate)
+	}
+
+	if filter.EndDate != "" {
+		query += " AND date <= ?"
+		args = append(args, filter.EndDate)
+	}
+
+	if filter.Query != "" {
+		query += " AND description LIKE ?"
+		args = append(args, "%"+filter.Query+"%")
+	}
+
+	query += " ORDER BY date DESC, id DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar transações: %w", err)
+	}
+
+	return scanAll(rows, scanTransaction)
+}
+
+func (r *sqliteTransactions) Update(ctx context.Context, transaction models.Transaction) error {
+	query := `
+		UPDATE transactions
+		SET account_id = ?, category_id = ?, amount = ?, currency = ?, description = ?, date = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		transaction.AccountID,
+		transaction.CategoryID,
+		transaction.Amount,
+		transaction.Currency,
+		transaction.Description,
+		transaction.Date,
+		transaction.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar transação: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteTransactions) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM transactions WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar transação: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteTransactions) DeleteByAccount(ctx context.Context, accountID int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM transactions WHERE account_id = ?", accountID); err != nil {
+		return i18n.Errorf("erro ao deletar conta: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteTransactions) Summarize(ctx context.Context, start, end string) ([]models.CategorySummary, error) {
+	query := `
+		SELECT t.category_id, COALESCE(c.name, 'Sem categoria'), COALESCE(c.color, '#9ca3af'), t.currency,
+		       COALESCE(SUM(CASE WHEN t.amount > 0 THEN t.amount END), 0),
+		       COALESCE(SUM(CASE WHEN t.amount < 0 THEN -t.amount END), 0),
+		       COUNT(*)
+		FROM transactions t
+		LEFT JOIN categories c ON c.id = t.category_id
+		WHERE t.date >= ? AND t.date < ?
+		GROUP BY t.category_id, t.currency
+		ORDER BY 6 DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar resumo: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.CategorySummary, error) {
+		var category models.CategorySummary
+		err := row.Scan(
+			&category.CategoryID,
+			&category.CategoryName,
+			&category.Color,
+			&category.Currency,
+			&category.Income,
+			&category.Expense,
+			&category.Count,
+		)
+		if err != nil {
+			return category, i18n.Errorf("erro ao ler resumo: %w", err)
+		}
+		return category, nil
+	})
+}
+
+func scanTransaction(row rowScanner) (models.Transaction, error) {
+	var transaction models.Transaction
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.AccountID,
+		&transaction.CategoryID,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.Description,
+		&transaction.Date,
+		&transaction.RecurringID,
+		&transaction.ImportID,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+	if err != nil {
+		return transaction, i18n.Errorf("erro ao ler transação: %w", err)
+	}
+	return transaction, nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// ORÇAMENTOS
+// ═══════════════════════════════════════════════════════════
+
+type sqliteBudgets struct {
+	db DBTX
+}
+
+func (r *sqliteBudgets) Save(ctx context.Context, budget models.Budget) (int64, error) {
+	query := `
+		INSERT INTO budgets (category_id, amount, currency)
+		VALUES (?, ?, ?)
+		ON CONFLICT(category_id, currency) DO UPDATE SET amount = excluded.amount
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, budget.CategoryID, budget.Amount, budget.Currency); err != nil {
+		return 0, i18n.Errorf("erro ao salvar orçamento: %w", err)
+	}
+
+	var id int64
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id FROM budgets WHERE category_id = ? AND currency = ?",
+		budget.CategoryID, budget.Currency,
+	).Scan(&id)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteBudgets) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM budgets WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar orçamento: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteBudgets) ListSpent(ctx context.Context, start, end string) ([]models.BudgetStatus, error) {
+	query := `
+		SELECT b.id, b.category_id, b.amount, b.currency, b.created_at, c.name,
+		       COALESCE((
+		           SELECT -SUM(t.amount) FROM transactions t
+		           WHERE t.category_id = b.category_id AND t.currency = b.currency
+		             AND t.amount < 0 AND t.date >= ? AND t.date < ?
+		       ), 0)
+		FROM budgets b
+		JOIN categories c ON c.id = b.category_id
+		ORDER BY c.name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar orçamentos: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.BudgetStatus, error) {
+		var status models.BudgetStatus
+		err := row.Scan(
+			&status.Budget.ID,
+			&status.Budget.CategoryID,
+			&status.Budget.Amount,
+			&status.Budget.Currency,
+			&status.Budget.CreatedAt,
+			&status.CategoryName,
+			&status.Spent,
+		)
+		if err != nil {
+			return status, i18n.Errorf("erro ao ler orçamento: %w", err)
+		}
+		return status, nil
+	})
+}
+
+// ═══════════════════════════════════════════════════════════
+// RECORRÊNCIAS
+// ═══════════════════════════════════════════════════════════
+
+type sqliteRecurring struct {
+	db DBTX
+}
+
+func (r *sqliteRecurring) Create(ctx context.Context, recurring models.RecurringTransaction) (int64, error) {
+	query := `
+		INSERT INTO recurring_transactions (account_id, category_id, amount, currency, description, frequency, next_date, end_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		recurring.AccountID,
+		recurring.CategoryID,
+		recurring.Amount,
+		recurring.Currency,
+		recurring.Description,
+		recurring.Frequency,
+		recurring.NextDate,
+		recurring.EndDate,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar recorrência: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteRecurring) List(ctx context.Context) ([]models.RecurringTransaction, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+recurringColumns+" FROM recurring_transactions ORDER BY next_date ASC")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar recorrências: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.RecurringTransaction, error) {
+		var recurring models.RecurringTransaction
+		err := row.Scan(
+			&recurring.ID,
+			&recurring.AccountID,
+			&recurring.CategoryID,
+			&recurring.Amount,
+			&recurring.Currency,
+			&recurring.Description,
+			&recurring.Frequency,
+			&recurring.NextDate,
+			&recurring.EndDate,
+			&recurring.CreatedAt,
+		)
+		if err != nil {
+			return recurring, i18n.Errorf("erro ao ler recorrência: %w", err)
+		}
+		return recurring, nil
+	})
+}
+
+func (r *sqliteRecurring) SetNextDate(ctx context.Context, id int, nextDate string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE recurring_transactions SET next_date = ? WHERE id = ?", nextDate, id)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar recorrência: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteRecurring) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM recurring_transactions WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar recorrência: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteRecurring) DeleteByAccount(ctx context.Context, accountID int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM recurring_transactions WHERE account_id = ?", accountID); err != nil {
+		return i18n.Errorf("erro ao deletar conta: %w", err)
+	}
+	return nil
+}
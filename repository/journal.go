@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+const journalColumns = `id, entry_date, mood, energy, tags, content, created_at, updated_at`
+
+type sqliteJournal struct {
+	db DBTX
+}
+
+func (r *sqliteJournal) Ensure(ctx context.Context, date string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "INSERT OR IGNORE INTO journal_entries (entry_date) VALUES (?)", date)
+	if err != nil {
+		return false, i18n.Errorf("erro ao criar entrada do diário: %w", err)
+	}
+
+	created, _ := result.RowsAffected()
+	return created > 0, nil
+}
+
+func (r *sqliteJournal) GetByDate(ctx context.Context, date string) (*models.JournalEntry, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+journalColumns+" FROM journal_entries WHERE entry_date = ?", date)
+	return scanOne(row, scanJournalEntry)
+}
+
+func (r *sqliteJournal) GetByID(ctx context.Context, id int) (*models.JournalEntry, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+journalColumns+" FROM journal_entries WHERE id = ?", id)
+	return scanOne(row, scanJournalEntry)
+}
+
+func (r *sqliteJournal) Adjacent(ctx context.Context, date string, after bool) (string, error) {
+	query := "SELECT entry_date FROM journal_entries WHERE entry_date < ? ORDER BY entry_date DESC LIMIT 1"
+	if after {
+		query = "SELECT entry_date FROM journal_entries WHERE entry_date > ? ORDER BY entry_date ASC LIMIT 1"
+	}
+
+	var found string
+	err := r.db.QueryRowContext(ctx, query, date).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", i18n.Errorf("erro ao buscar entrada do diário: %w", err)
+	}
+
+	return found, nil
+}
+
+func (r *sqliteJournal) Search(ctx context.Context, filter models.JournalFilter) ([]models.JournalEntry, error) {
+	query := "SELECT " + journalColumns + " FROM journal_entries WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.StartDate != "" {
+		query += " AND entry_date >= ?"
+		args = append(args, filter.StartDate)
+	}
+
+	if filter.EndDate != "" {
+		query += " AND entry_date <= ?"
+		args = append(args, filter.EndDate)
+	}
+
+	if filter.Query != "" {
+		query += " AND content LIKE ?"
+		args = append(args, "%"+filter.Query+"%")
+	}
+
+	if filter.Tag != "" {
+		query += " AND " + tagCondition("tags")
+		args = append(args, tagPattern(filter.Tag))
+	}
+
+	query += " ORDER BY entry_date DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar entradas do diário: %w", err)
+	}
+
+	return scanAll(rows, scanJournalEntry)
+}
+
+func (r *sqliteJournal) MoodSeries(ctx context.Context, startDate, endDate string) ([]models.MoodPoint, error) {
+	query := `
+		SELECT entry_date, mood, energy
+		FROM journal_entries
+		WHERE entry_date >= ? AND entry_date <= ?
+		  AND (mood IS NOT NULL OR energy IS NOT NULL)
+		ORDER BY entry_date ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar humor: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.MoodPoint, error) {
+		var point models.MoodPoint
+		if err := row.Scan(&point.Date, &point.Mood, &point.Energy); err != nil {
+			return point, i18n.Errorf("erro ao ler humor: %w", err)
+		}
+		return point, nil
+	})
+}
+
+func (r *sqliteJournal) Update(ctx context.Context, entry models.JournalEntry) error {
+	query := `
+		UPDATE journal_entries
+		SET mood = ?, energy = ?, tags = ?, content = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		entry.Mood,
+		entry.Energy,
+		joinTags(entry.Tags),
+		entry.Content,
+		entry.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar entrada do diário: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteJournal) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM journal_entries WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar entrada do diário: %w", err)
+	}
+
+	return affected(result)
+}
+
+func scanJournalEntry(row rowScanner) (models.JournalEntry, error) {
+	var entry models.JournalEntry
+	var tags, content sql.NullString
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.Date,
+		&entry.Mood,
+		&entry.Energy,
+		&tags,
+		&content,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return entry, i18n.Errorf("erro ao ler entrada do diário: %w", err)
+	}
+
+	entry.Tags = splitTags(tags.String)
+	entry.Content = content.String
+
+	return entry, nil
+}
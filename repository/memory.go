@@ -0,0 +1,1283 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"personal-cockpit/models"
+)
+
+// Memory implementa UnitOfWork em memória, sem banco, para testar os services
+// sem SQLite. Guarda tarefas, notas, cadernos, eventos, categorias, finanças e
+// configurações, imitando o que o esquema faz por conta própria: updated_at e
+// completed_at, nomes de categoria e conta únicos, import_id único por conta,
+// is_done do bloco de tempo seguindo a tarefa e os ON DELETE das chaves
+// estrangeiras.
+//
+// Os demais repositórios não existem aqui: os acessores retornam nil. Uma
+// transação trabalha numa cópia dos dados, que substitui a original no
+// commit; o que for gravado fora dela enquanto isso se perde.
+type Memory struct {
+	txMu sync.Mutex
+
+	mu   sync.Mutex
+	data *memoryData
+}
+
+// NewMemory cria um Store/UnitOfWork vazio
+func NewMemory() *Memory {
+	return &Memory{data: newMemoryData()}
+}
+
+// WithTx executa fn sobre uma cópia dos dados; sem erro, a cópia passa a
+// valer. Uma transação por vez.
+func (m *Memory) WithTx(ctx context.Context, fn func(tx Store) error) error {
+	m.txMu.Lock()
+	defer m.txMu.Unlock()
+
+	m.mu.Lock()
+	snapshot := m.data.clone()
+	m.mu.Unlock()
+
+	if err := fn(memoryStore{data: snapshot}); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.data = snapshot
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Memory) store() memoryStore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return memoryStore{data: m.data}
+}
+
+func (m *Memory) Tasks() TaskRepository               { return m.store().Tasks() }
+func (m *Memory) Notes() NoteRepository               { return m.store().Notes() }
+func (m *Memory) Notebooks() NotebookRepository       { return m.store().Notebooks() }
+func (m *Memory) Events() EventRepository             { return m.store().Events() }
+func (m *Memory) Categories() CategoryRepository      { return m.store().Categories() }
+func (m *Memory) Journal() JournalRepository          { return nil }
+func (m *Memory) Templates() TemplateRepository       { return nil }
+func (m *Memory) Attachments() AttachmentRepository   { return nil }
+func (m *Memory) Accounts() AccountRepository         { return m.store().Accounts() }
+func (m *Memory) Transactions() TransactionRepository { return m.store().Transactions() }
+func (m *Memory) Budgets() BudgetRepository           { return m.store().Budgets() }
+func (m *Memory) Recurring() RecurringRepository      { return m.store().Recurring() }
+func (m *Memory) Settings() SettingRepository         { return m.store().Settings() }
+func (m *Memory) Webhooks() WebhookRepository         { return nil }
+func (m *Memory) CalDAV() CalDAVRepository            { return nil }
+func (m *Memory) NoteFiles() NoteFileRepository       { return nil }
+
+// memoryData são as "tabelas"; mu protege todas elas
+type memoryData struct {
+	mu           sync.Mutex
+	lastID       map[string]int
+	tasks        map[int]models.Task
+	notes        map[int]models.Note
+	notebooks    map[int]models.Notebook
+	events       map[int]models.Event
+	categories   map[int]models.Category
+	accounts     map[int]models.Account
+	transactions map[int]models.Transaction
+	budgets      map[int]models.Budget
+	recurring    map[int]models.RecurringTransaction
+	settings     map[string]models.Setting
+}
+
+func newMemoryData() *memoryData {
+	return &memoryData{
+		lastID:       make(map[string]int),
+		tasks:        make(map[int]models.Task),
+		notes:        make(map[int]models.Note),
+		notebooks:    make(map[int]models.Notebook),
+		events:       make(map[int]models.Event),
+		categories:   make(map[int]models.Category),
+		accounts:     make(map[int]models.Account),
+		transactions: make(map[int]models.Transaction),
+		budgets:      make(map[int]models.Budget),
+		recurring:    make(map[int]models.RecurringTransaction),
+		settings:     make(map[string]models.Setting),
+	}
+}
+
+func (d *memoryData) clone() *memoryData {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &memoryData{
+		lastID:       maps.Clone(d.lastID),
+		tasks:        maps.Clone(d.tasks),
+		notes:        maps.Clone(d.notes),
+		notebooks:    maps.Clone(d.notebooks),
+		events:       maps.Clone(d.events),
+		categories:   maps.Clone(d.categories),
+		accounts:     maps.Clone(d.accounts),
+		transactions: maps.Clone(d.transactions),
+		budgets:      maps.Clone(d.budgets),
+		recurring:    maps.Clone(d.recurring),
+		settings:     maps.Clone(d.settings),
+	}
+}
+
+// nextID segue o AUTOINCREMENT: cada tabela conta os seus IDs
+func (d *memoryData) nextID(table string) int {
+	d.lastID[table]++
+	return d.lastID[table]
+}
+
+// memoryNow tem a precisão do CURRENT_TIMESTAMP do SQLite
+func memoryNow() time.Time {
+	return time.Now().Truncate(time.Second)
+}
+
+// sorted retorna os valores do mapa na ordem de less
+func sorted[T any](items map[int]T, less func(a, b T) int) []T {
+	list := slices.Collect(maps.Values(items))
+	slices.SortFunc(list, less)
+	return list
+}
+
+// errUnique imita o erro de UNIQUE do SQLite, que os services reconhecem
+func errUnique(column string) error {
+	return errors.New("UNIQUE constraint failed: " + column)
+}
+
+type memoryStore struct {
+	data *memoryData
+}
+
+func (s memoryStore) Tasks() TaskRepository               { return memoryTasks{s.data} }
+func (s memoryStore) Notes() NoteRepository               { return memoryNotes{s.data} }
+func (s memoryStore) Notebooks() NotebookRepository       { return memoryNotebooks{s.data} }
+func (s memoryStore) Events() EventRepository             { return memoryEvents{s.data} }
+func (s memoryStore) Categories() CategoryRepository      { return memoryCategories{s.data} }
+func (s memoryStore) Journal() JournalRepository          { return nil }
+func (s memoryStore) Templates() TemplateRepository       { return nil }
+func (s memoryStore) Attachments() AttachmentRepository   { return nil }
+func (s memoryStore) Accounts() AccountRepository         { return memoryAccounts{s.data} }
+func (s memoryStore) Transactions() TransactionRepository { return memoryTransactions{s.data} }
+func (s memoryStore) Budgets() BudgetRepository           { return memoryBudgets{s.data} }
+func (s memoryStore) Recurring() RecurringRepository      { return memoryRecurring{s.data} }
+func (s memoryStore) Settings() SettingRepository         { return memorySettings{s.data} }
+func (s memoryStore) Webhooks() WebhookRepository         { return nil }
+func (s memoryStore) CalDAV() CalDAVRepository            { return nil }
+func (s memoryStore) NoteFiles() NoteFileRepository       { return nil }
+
+// ═══════════════════════════════════════════════════════════
+// TAREFAS
+// ═══════════════════════════════════════════════════════════
+
+type memoryTasks struct{ d *memoryData }
+
+func (r memoryTasks) Create(ctx context.Context, task models.Task) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	task.ID = r.d.nextID("tasks")
+	task.CompletedAt = nil
+	task.CreatedAt, task.UpdatedAt = memoryNow(), memoryNow()
+	r.d.tasks[task.ID] = task
+
+	return int64(task.ID), nil
+}
+
+func (r memoryTasks) GetByID(ctx context.Context, id int) (*models.Task, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	task, ok := r.d.tasks[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &task, nil
+}
+
+func (r memoryTasks) List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var tasks []models.Task
+	for _, task := range sorted(r.d.tasks, newestTask) {
+		if filter.Status != "" && task.Status != filter.Status {
+			continue
+		}
+		if filter.Priority != "" && task.Priority != filter.Priority {
+			continue
+		}
+		if filter.CategoryID != nil && !sameID(task.CategoryID, *filter.CategoryID) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (r memoryTasks) ListCompletedBetween(ctx context.Context, from, to time.Time) ([]models.Task, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var tasks []models.Task
+	for _, task := range r.d.tasks {
+		if task.Status != "completed" || task.CompletedAt == nil {
+			continue
+		}
+		if task.CompletedAt.Before(from) || !task.CompletedAt.Before(to) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	slices.SortFunc(tasks, func(a, b models.Task) int { return a.CompletedAt.Compare(*b.CompletedAt) })
+	return tasks, nil
+}
+
+func (r memoryTasks) Update(ctx context.Context, task models.Task) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	old, ok := r.d.tasks[task.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	old.Title = task.Title
+	old.Description = task.Description
+	old.Priority = task.Priority
+	old.CategoryID = task.CategoryID
+	old.DueDate = task.DueDate
+	old.EstimatedMinutes = task.EstimatedMinutes
+	r.d.saveTask(old, task.Status)
+
+	return nil
+}
+
+func (r memoryTasks) SetStatus(ctx context.Context, id int, status string) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	task, ok := r.d.tasks[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	r.d.saveTask(task, status)
+	return nil
+}
+
+func (r memoryTasks) SetStatusFromNote(ctx context.Context, id, noteID int, completed bool) (bool, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	task, ok := r.d.tasks[id]
+	if !ok || !sameID(task.NoteID, noteID) || (task.Status == "completed") == completed {
+		return false, nil
+	}
+
+	status := "pending"
+	if completed {
+		status = "completed"
+	}
+	r.d.saveTask(task, status)
+
+	return true, nil
+}
+
+func (r memoryTasks) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.tasks[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.tasks, id)
+
+	// events.task_id: ON DELETE SET NULL
+	for eventID, event := range r.d.events {
+		if sameID(event.TaskID, id) {
+			event.TaskID = nil
+			r.d.events[eventID] = event
+		}
+	}
+
+	return nil
+}
+
+// saveTask grava a tarefa com o novo status, como os gatilhos do banco:
+// updated_at sempre, completed_at ao concluir e is_done do bloco de tempo
+func (d *memoryData) saveTask(task models.Task, status string) {
+	if status != task.Status {
+		if status == "completed" {
+			completed := memoryNow()
+			task.CompletedAt = &completed
+		}
+		for eventID, event := range d.events {
+			if sameID(event.TaskID, task.ID) {
+				event.IsDone = status == "completed"
+				d.events[eventID] = event
+			}
+		}
+	}
+
+	task.Status = status
+	task.UpdatedAt = memoryNow()
+	d.tasks[task.ID] = task
+}
+
+func newestTask(a, b models.Task) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return b.ID - a.ID
+}
+
+func sameID(id *int, value int) bool {
+	return id != nil && *id == value
+}
+
+// ═══════════════════════════════════════════════════════════
+// NOTAS E CADERNOS
+// ═══════════════════════════════════════════════════════════
+
+type memoryNotes struct{ d *memoryData }
+
+func (r memoryNotes) Create(ctx context.Context, note models.Note) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	note.ID = r.d.nextID("notes")
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = memoryNow()
+	}
+	if note.UpdatedAt.IsZero() {
+		note.UpdatedAt = memoryNow()
+	}
+	r.d.notes[note.ID] = note
+
+	return int64(note.ID), nil
+}
+
+func (r memoryNotes) GetByID(ctx context.Context, id int) (*models.Note, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	note, ok := r.d.notes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &note, nil
+}
+
+func (r memoryNotes) List(ctx context.Context, query NoteQuery) ([]models.Note, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var subtree []int
+	if query.NotebookID != nil {
+		subtree = r.d.subtree(*query.NotebookID)
+	}
+	search := strings.ToLower(query.Search)
+
+	var notes []models.Note
+	for _, note := range sorted(r.d.notes, func(a, b models.Note) int { return b.UpdatedAt.Compare(a.UpdatedAt) }) {
+		if query.NotebookID != nil && (note.NotebookID == nil || !slices.Contains(subtree, *note.NotebookID)) {
+			continue
+		}
+		if query.FavoritesOnly && !note.IsFavorite {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(note.Title), search) && !strings.Contains(strings.ToLower(note.Content), search) {
+			continue
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
+
+func (r memoryNotes) Update(ctx context.Context, note models.Note) error {
+	return r.change(note.ID, func(old *models.Note) {
+		old.Title = note.Title
+		old.Content = note.Content
+		old.CategoryID = note.CategoryID
+		old.IsFavorite = note.IsFavorite
+	})
+}
+
+func (r memoryNotes) SetFavorite(ctx context.Context, id int, favorite bool) error {
+	return r.change(id, func(note *models.Note) { note.IsFavorite = favorite })
+}
+
+func (r memoryNotes) SetContent(ctx context.Context, id int, content string) error {
+	return r.change(id, func(note *models.Note) { note.Content = content })
+}
+
+func (r memoryNotes) SetNotebook(ctx context.Context, id int, notebookID *int) error {
+	return r.change(id, func(note *models.Note) { note.NotebookID = notebookID })
+}
+
+// change altera a nota e atualiza updated_at, como o gatilho do banco
+func (r memoryNotes) change(id int, fn func(note *models.Note)) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	note, ok := r.d.notes[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	fn(&note)
+	note.UpdatedAt = memoryNow()
+	r.d.notes[id] = note
+
+	return nil
+}
+
+func (r memoryNotes) Versions(ctx context.Context) (map[int]time.Time, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	versions := make(map[int]time.Time, len(r.d.notes))
+	for id, note := range r.d.notes {
+		versions[id] = note.UpdatedAt
+	}
+	return versions, nil
+}
+
+func (r memoryNotes) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.notes[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.notes, id)
+
+	// tasks.note_id: ON DELETE SET NULL
+	for taskID, task := range r.d.tasks {
+		if sameID(task.NoteID, id) {
+			task.NoteID = nil
+			r.d.tasks[taskID] = task
+		}
+	}
+
+	return nil
+}
+
+type memoryNotebooks struct{ d *memoryData }
+
+func (r memoryNotebooks) Create(ctx context.Context, notebook models.Notebook) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	notebook.ID = r.d.nextID("notebooks")
+	notebook.NoteCount, notebook.TotalCount, notebook.Children = 0, 0, nil
+	notebook.CreatedAt, notebook.UpdatedAt = memoryNow(), memoryNow()
+	r.d.notebooks[notebook.ID] = notebook
+
+	return int64(notebook.ID), nil
+}
+
+func (r memoryNotebooks) GetByID(ctx context.Context, id int) (*models.Notebook, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	notebook, ok := r.d.notebooks[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &notebook, nil
+}
+
+func (r memoryNotebooks) List(ctx context.Context) ([]models.Notebook, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	notebooks := sorted(r.d.notebooks, func(a, b models.Notebook) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	for i := range notebooks {
+		for _, note := range r.d.notes {
+			if sameID(note.NotebookID, notebooks[i].ID) {
+				notebooks[i].NoteCount++
+			}
+		}
+	}
+	return notebooks, nil
+}
+
+func (r memoryNotebooks) Rename(ctx context.Context, id int, name string) error {
+	return r.change(id, func(notebook *models.Notebook) { notebook.Name = name })
+}
+
+func (r memoryNotebooks) SetParent(ctx context.Context, id int, parentID *int) error {
+	return r.change(id, func(notebook *models.Notebook) { notebook.ParentID = parentID })
+}
+
+func (r memoryNotebooks) change(id int, fn func(notebook *models.Notebook)) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	notebook, ok := r.d.notebooks[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	fn(&notebook)
+	notebook.UpdatedAt = memoryNow()
+	r.d.notebooks[id] = notebook
+
+	return nil
+}
+
+func (r memoryNotebooks) Subtree(ctx context.Context, id int) ([]int, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	return r.d.subtree(id), nil
+}
+
+// subtree segue a CTE notebookSubtree: o caderno e, nível a nível, os descendentes
+func (d *memoryData) subtree(id int) []int {
+	if _, ok := d.notebooks[id]; !ok {
+		return nil
+	}
+
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		for _, notebook := range sorted(d.notebooks, func(a, b models.Notebook) int { return a.ID - b.ID }) {
+			if sameID(notebook.ParentID, ids[i]) {
+				ids = append(ids, notebook.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func (r memoryNotebooks) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	subtree := r.d.subtree(id)
+	if subtree == nil {
+		return ErrNotFound
+	}
+
+	// notebooks.parent_id: ON DELETE CASCADE; notes.notebook_id: ON DELETE SET NULL
+	for _, notebookID := range subtree {
+		delete(r.d.notebooks, notebookID)
+	}
+	for noteID, note := range r.d.notes {
+		if note.NotebookID != nil && slices.Contains(subtree, *note.NotebookID) {
+			note.NotebookID = nil
+			r.d.notes[noteID] = note
+		}
+	}
+
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// EVENTOS
+// ═══════════════════════════════════════════════════════════
+
+type memoryEvents struct{ d *memoryData }
+
+func (r memoryEvents) Create(ctx context.Context, event models.Event) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	event.ID = r.d.nextID("events")
+	event.IsDone = false
+	event.CreatedAt, event.UpdatedAt = memoryNow(), memoryNow()
+	r.d.events[event.ID] = event
+
+	return int64(event.ID), nil
+}
+
+func (r memoryEvents) GetByID(ctx context.Context, id int) (*models.Event, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	event, ok := r.d.events[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &event, nil
+}
+
+func (r memoryEvents) GetByTask(ctx context.Context, taskID int) (*models.Event, error) {
+	events, _ := r.filter(func(event models.Event) bool { return sameID(event.TaskID, taskID) })
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+	return &events[0], nil
+}
+
+func (r memoryEvents) List(ctx context.Context) ([]models.Event, error) {
+	return r.filter(func(models.Event) bool { return true })
+}
+
+// ListStartingBetween segue a consulta do SQLite: com hora, o início entre
+// from e to; de dia inteiro, a meia-noite do dia entre os dois
+func (r memoryEvents) ListStartingBetween(ctx context.Context, from, to time.Time) ([]models.Event, error) {
+	firstDay, lastDay := daysStartingBetween(from, to)
+
+	return r.filter(func(event models.Event) bool {
+		if event.AllDay {
+			day := floatingDate(event.StartDate)
+			return day >= firstDay && day <= lastDay
+		}
+		return !event.StartDate.Before(from) && !event.StartDate.After(to)
+	})
+}
+
+// ListOverlapping segue a consulta do SQLite (veja sqliteEvents.ListOverlapping)
+func (r memoryEvents) ListOverlapping(ctx context.Context, from, to time.Time) ([]models.Event, error) {
+	firstDay, lastDay := daysOverlapping(from, to)
+
+	return r.filter(func(event models.Event) bool {
+		if event.AllDay {
+			start, end := eventBounds(event)
+			return start <= lastDay && (end > firstDay || start >= firstDay)
+		}
+		return event.StartDate.Before(to) && (event.EndDate.After(from) || !event.StartDate.Before(from))
+	})
+}
+
+func (r memoryEvents) filter(keep func(models.Event) bool) ([]models.Event, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var events []models.Event
+	for _, event := range sorted(r.d.events, func(a, b models.Event) int { return a.StartDate.Compare(b.StartDate) }) {
+		if keep(event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r memoryEvents) Update(ctx context.Context, event models.Event) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	old, ok := r.d.events[event.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	// task_id e is_done não mudam no Update (veja EventRepository)
+	event.TaskID, event.IsDone = old.TaskID, old.IsDone
+	event.CreatedAt, event.UpdatedAt = old.CreatedAt, memoryNow()
+	r.d.events[event.ID] = event
+
+	return nil
+}
+
+func (r memoryEvents) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.events[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.events, id)
+
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// CATEGORIAS
+// ═══════════════════════════════════════════════════════════
+
+type memoryCategories struct{ d *memoryData }
+
+func (r memoryCategories) Create(ctx context.Context, category models.Category) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if r.d.categoryNameTaken(category.Name, 0) {
+		return 0, errUnique("categories.name")
+	}
+
+	category.ID = r.d.nextID("categories")
+	category.CreatedAt = memoryNow()
+	r.d.categories[category.ID] = category
+
+	return int64(category.ID), nil
+}
+
+func (r memoryCategories) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	category, ok := r.d.categories[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r memoryCategories) List(ctx context.Context, categoryType string) ([]models.Category, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var categories []models.Category
+	for _, category := range sorted(r.d.categories, func(a, b models.Category) int { return strings.Compare(a.Name, b.Name) }) {
+		if categoryType == "" || category.Type == categoryType {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (r memoryCategories) Update(ctx context.Context, category models.Category) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	old, ok := r.d.categories[category.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.d.categoryNameTaken(category.Name, category.ID) {
+		return errUnique("categories.name")
+	}
+
+	category.CreatedAt = old.CreatedAt
+	r.d.categories[category.ID] = category
+
+	return nil
+}
+
+func (r memoryCategories) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.categories[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.categories, id)
+
+	// tasks.category_id e notes.category_id: ON DELETE SET NULL
+	for taskID, task := range r.d.tasks {
+		if sameID(task.CategoryID, id) {
+			task.CategoryID = nil
+			r.d.tasks[taskID] = task
+		}
+	}
+	for noteID, note := range r.d.notes {
+		if sameID(note.CategoryID, id) {
+			note.CategoryID = nil
+			r.d.notes[noteID] = note
+		}
+	}
+
+	// transactions e recurring_transactions: SET NULL; budgets: CASCADE
+	for transactionID, transaction := range r.d.transactions {
+		if sameID(transaction.CategoryID, id) {
+			transaction.CategoryID = nil
+			r.d.transactions[transactionID] = transaction
+		}
+	}
+	for recurringID, recurring := range r.d.recurring {
+		if sameID(recurring.CategoryID, id) {
+			recurring.CategoryID = nil
+			r.d.recurring[recurringID] = recurring
+		}
+	}
+	for budgetID, budget := range r.d.budgets {
+		if budget.CategoryID == id {
+			delete(r.d.budgets, budgetID)
+		}
+	}
+
+	return nil
+}
+
+// categoryNameTaken segue o UNIQUE da coluna, que diferencia maiúsculas
+func (d *memoryData) categoryNameTaken(name string, exceptID int) bool {
+	for _, category := range d.categories {
+		if category.Name == name && category.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// ═══════════════════════════════════════════════════════════
+// FINANÇAS
+// ═══════════════════════════════════════════════════════════
+
+type memoryAccounts struct{ d *memoryData }
+
+func (r memoryAccounts) Create(ctx context.Context, account models.Account) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if r.d.accountNameTaken(account.Name, 0) {
+		return 0, errUnique("accounts.name")
+	}
+
+	account.ID = r.d.nextID("accounts")
+	account.Balance = 0
+	account.CreatedAt = memoryNow()
+	r.d.accounts[account.ID] = account
+
+	return int64(account.ID), nil
+}
+
+func (r memoryAccounts) GetByID(ctx context.Context, id int) (*models.Account, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	account, ok := r.d.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	account.Balance = r.d.balance(account)
+	return &account, nil
+}
+
+func (r memoryAccounts) List(ctx context.Context) ([]models.Account, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	accounts := sorted(r.d.accounts, func(a, b models.Account) int { return strings.Compare(a.Name, b.Name) })
+	for i := range accounts {
+		accounts[i].Balance = r.d.balance(accounts[i])
+	}
+	return accounts, nil
+}
+
+func (r memoryAccounts) Update(ctx context.Context, account models.Account) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	old, ok := r.d.accounts[account.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if r.d.accountNameTaken(account.Name, account.ID) {
+		return errUnique("accounts.name")
+	}
+
+	old.Name, old.Type, old.InitialBalance = account.Name, account.Type, account.InitialBalance
+	r.d.accounts[account.ID] = old
+
+	return nil
+}
+
+func (r memoryAccounts) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.accounts[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.accounts, id)
+
+	// transactions e recurring_transactions: ON DELETE CASCADE
+	for transactionID, transaction := range r.d.transactions {
+		if transaction.AccountID == id {
+			delete(r.d.transactions, transactionID)
+		}
+	}
+	for recurringID, recurring := range r.d.recurring {
+		if recurring.AccountID == id {
+			delete(r.d.recurring, recurringID)
+		}
+	}
+
+	return nil
+}
+
+// balance segue accountColumns: saldo inicial mais todas as transações
+func (d *memoryData) balance(account models.Account) int64 {
+	balance := account.InitialBalance
+	for _, transaction := range d.transactions {
+		if transaction.AccountID == account.ID {
+			balance += transaction.Amount
+		}
+	}
+	return balance
+}
+
+func (d *memoryData) accountNameTaken(name string, exceptID int) bool {
+	for _, account := range d.accounts {
+		if account.Name == name && account.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+type memoryTransactions struct{ d *memoryData }
+
+func (r memoryTransactions) Create(ctx context.Context, transaction models.Transaction) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if r.d.importTaken(transaction) {
+		return 0, errUnique("transactions.account_id, transactions.import_id")
+	}
+
+	return int64(r.d.insertTransaction(transaction)), nil
+}
+
+func (r memoryTransactions) Import(ctx context.Context, transaction models.Transaction) (bool, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	// INSERT OR IGNORE: o import_id repetido na conta não grava nada
+	if r.d.importTaken(transaction) {
+		return false, nil
+	}
+
+	transaction.CategoryID, transaction.RecurringID = nil, nil
+	r.d.insertTransaction(transaction)
+	return true, nil
+}
+
+func (d *memoryData) insertTransaction(transaction models.Transaction) int {
+	transaction.ID = d.nextID("transactions")
+	transaction.CreatedAt, transaction.UpdatedAt = memoryNow(), memoryNow()
+	d.transactions[transaction.ID] = transaction
+	return transaction.ID
+}
+
+// importTaken segue o UNIQUE(account_id, import_id); sem import_id (NULL)
+// não há conflito
+func (d *memoryData) importTaken(transaction models.Transaction) bool {
+	if transaction.ImportID == "" {
+		return false
+	}
+	for _, other := range d.transactions {
+		if other.AccountID == transaction.AccountID && other.ImportID == transaction.ImportID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r memoryTransactions) List(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	query := strings.ToLower(filter.Query)
+
+	var transactions []models.Transaction
+	for _, transaction := range sorted(r.d.transactions, newestTransaction) {
+		if filter.AccountID != nil && transaction.AccountID != *filter.AccountID {
+			continue
+		}
+		if filter.CategoryID != nil && !sameID(transaction.CategoryID, *filter.CategoryID) {
+			continue
+		}
+		if filter.StartDate != "" && transaction.Date < filter.StartDate {
+			continue
+		}
+		if filter.EndDate != "" && transaction.Date > filter.EndDate {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(transaction.Description), query) {
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+func (r memoryTransactions) Update(ctx context.Context, transaction models.Transaction) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	old, ok := r.d.transactions[transaction.ID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	old.AccountID = transaction.AccountID
+	old.CategoryID = transaction.CategoryID
+	old.Amount = transaction.Amount
+	old.Currency = transaction.Currency
+	old.Description = transaction.Description
+	old.Date = transaction.Date
+	old.UpdatedAt = memoryNow()
+	r.d.transactions[transaction.ID] = old
+
+	return nil
+}
+
+func (r memoryTransactions) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.transactions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.transactions, id)
+
+	return nil
+}
+
+func (r memoryTransactions) DeleteByAccount(ctx context.Context, accountID int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	for id, transaction := range r.d.transactions {
+		if transaction.AccountID == accountID {
+			delete(r.d.transactions, id)
+		}
+	}
+	return nil
+}
+
+// Summarize segue a consulta do SQLite: agrupa por categoria e moeda, com o
+// nome e a cor padrão para as transações sem categoria
+func (r memoryTransactions) Summarize(ctx context.Context, start, end string) ([]models.CategorySummary, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	type group struct {
+		categoryID int
+		currency   string
+	}
+
+	groups := make(map[group]*models.CategorySummary)
+	var summaries []*models.CategorySummary
+
+	for _, transaction := range sorted(r.d.transactions, func(a, b models.Transaction) int { return a.ID - b.ID }) {
+		if transaction.Date < start || transaction.Date >= end {
+			continue
+		}
+
+		key := group{currency: transaction.Currency}
+		if transaction.CategoryID != nil {
+			key.categoryID = *transaction.CategoryID
+		}
+
+		summary, ok := groups[key]
+		if !ok {
+			summary = &models.CategorySummary{CategoryID: transaction.CategoryID, CategoryName: "Sem categoria", Color: "#9ca3af", Currency: transaction.Currency}
+			if category, found := r.d.categories[key.categoryID]; found {
+				summary.CategoryName, summary.Color = category.Name, category.Color
+			}
+			groups[key] = summary
+			summaries = append(summaries, summary)
+		}
+
+		if transaction.Amount > 0 {
+			summary.Income += transaction.Amount
+		} else {
+			summary.Expense -= transaction.Amount
+		}
+		summary.Count++
+	}
+
+	slices.SortStableFunc(summaries, func(a, b *models.CategorySummary) int { return cmp.Compare(b.Expense, a.Expense) })
+
+	var result []models.CategorySummary
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	return result, nil
+}
+
+func newestTransaction(a, b models.Transaction) int {
+	if c := strings.Compare(b.Date, a.Date); c != 0 {
+		return c
+	}
+	return b.ID - a.ID
+}
+
+type memoryBudgets struct{ d *memoryData }
+
+func (r memoryBudgets) Save(ctx context.Context, budget models.Budget) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	// ON CONFLICT(category_id, currency): só troca o limite
+	for id, old := range r.d.budgets {
+		if old.CategoryID == budget.CategoryID && old.Currency == budget.Currency {
+			old.Amount = budget.Amount
+			r.d.budgets[id] = old
+			return int64(id), nil
+		}
+	}
+
+	budget.ID = r.d.nextID("budgets")
+	budget.CreatedAt = memoryNow()
+	r.d.budgets[budget.ID] = budget
+
+	return int64(budget.ID), nil
+}
+
+func (r memoryBudgets) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.budgets[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.budgets, id)
+
+	return nil
+}
+
+func (r memoryBudgets) ListSpent(ctx context.Context, start, end string) ([]models.BudgetStatus, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var statuses []models.BudgetStatus
+	for _, budget := range r.d.budgets {
+		category, ok := r.d.categories[budget.CategoryID]
+		if !ok {
+			continue
+		}
+
+		status := models.BudgetStatus{Budget: budget, CategoryName: category.Name}
+		for _, transaction := range r.d.transactions {
+			if sameID(transaction.CategoryID, budget.CategoryID) && transaction.Currency == budget.Currency &&
+				transaction.Amount < 0 && transaction.Date >= start && transaction.Date < end {
+				status.Spent -= transaction.Amount
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	slices.SortFunc(statuses, func(a, b models.BudgetStatus) int {
+		if c := strings.Compare(a.CategoryName, b.CategoryName); c != 0 {
+			return c
+		}
+		return a.Budget.ID - b.Budget.ID
+	})
+	return statuses, nil
+}
+
+type memoryRecurring struct{ d *memoryData }
+
+func (r memoryRecurring) Create(ctx context.Context, recurring models.RecurringTransaction) (int64, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	recurring.ID = r.d.nextID("recurring_transactions")
+	recurring.CreatedAt = memoryNow()
+	r.d.recurring[recurring.ID] = recurring
+
+	return int64(recurring.ID), nil
+}
+
+func (r memoryRecurring) List(ctx context.Context) ([]models.RecurringTransaction, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	return sorted(r.d.recurring, func(a, b models.RecurringTransaction) int {
+		if c := strings.Compare(a.NextDate, b.NextDate); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	}), nil
+}
+
+func (r memoryRecurring) SetNextDate(ctx context.Context, id int, nextDate string) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	recurring, ok := r.d.recurring[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	recurring.NextDate = nextDate
+	r.d.recurring[id] = recurring
+
+	return nil
+}
+
+func (r memoryRecurring) Delete(ctx context.Context, id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.recurring[id]; !ok {
+		return ErrNotFound
+	}
+	r.d.deleteRecurring(id)
+
+	return nil
+}
+
+func (r memoryRecurring) DeleteByAccount(ctx context.Context, accountID int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	for id, recurring := range r.d.recurring {
+		if recurring.AccountID == accountID {
+			r.d.deleteRecurring(id)
+		}
+	}
+	return nil
+}
+
+// deleteRecurring apaga a recorrência; transactions.recurring_id: ON DELETE SET NULL
+func (d *memoryData) deleteRecurring(id int) {
+	delete(d.recurring, id)
+
+	for transactionID, transaction := range d.transactions {
+		if sameID(transaction.RecurringID, id) {
+			transaction.RecurringID = nil
+			d.transactions[transactionID] = transaction
+		}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// CONFIGURAÇÕES
+// ═══════════════════════════════════════════════════════════
+
+type memorySettings struct{ d *memoryData }
+
+func (r memorySettings) Get(ctx context.Context, key string) (string, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	setting, ok := r.d.settings[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return setting.Value, nil
+}
+
+func (r memorySettings) Set(ctx context.Context, key, value string) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	r.d.settings[key] = models.Setting{Key: key, Value: value, UpdatedAt: memoryNow()}
+	return nil
+}
+
+func (r memorySettings) List(ctx context.Context) ([]models.Setting, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	settings := slices.Collect(maps.Values(r.d.settings))
+	slices.SortFunc(settings, func(a, b models.Setting) int { return strings.Compare(a.Key, b.Key) })
+	return settings, nil
+}
+
+func (r memorySettings) Delete(ctx context.Context, key string) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	delete(r.d.settings, key)
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+// notebookSubtree seleciona o caderno informado e todos os seus descendentes
+// (CTE "subtree"; o ID do caderno é o primeiro argumento da query)
+const notebookSubtree = `
+	WITH RECURSIVE subtree(id) AS (
+		SELECT id FROM notebooks WHERE id = ?
+		UNION ALL
+		SELECT n.id FROM notebooks n JOIN subtree s ON n.parent_id = s.id
+	)
+`
+
+type sqliteNotes struct {
+	db DBTX
+}
+
+// Create usa CreatedAt e UpdatedAt quando preenchidos (notas importadas
+// mantêm as datas originais); vazios, valem o momento da criação
+func (r *sqliteNotes) Create(ctx context.Context, note models.Note) (int64, error) {
+	query := `
+		INSERT INTO notes (title, content, category_id, notebook_id, is_favorite, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		note.Title,
+		note.Content,
+		note.CategoryID,
+		note.NotebookID,
+		note.IsFavorite,
+		timestampOrNil(note.CreatedAt),
+		timestampOrNil(note.UpdatedAt),
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar nota: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteNotes) GetByID(ctx context.Context, id int) (*models.Note, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+noteColumns+" FROM notes WHERE id = ?", id)
+	return scanOne(row, scanNote)
+}
+
+func (r *sqliteNotes) List(ctx context.Context, filter NoteQuery) ([]models.Note, error) {
+	query := "SELECT " + noteColumns + " FROM notes WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.NotebookID != nil {
+		query = notebookSubtree + query + " AND notebook_id IN (SELECT id FROM subtree)"
+		args = append(args, *filter.NotebookID)
+	}
+
+	if filter.FavoritesOnly {
+		query += " AND is_favorite = 1"
+	}
+
+	if filter.Search != "" {
+		searchTerm := "%" + filter.Search + "%"
+		query += " AND (title LIKE ? OR content LIKE ?)"
+		args = append(args, searchTerm, searchTerm)
+	}
+
+	query += " ORDER BY updated_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar notas: %w", err)
+	}
+
+	return scanAll(rows, scanNote)
+}
+
+func (r *sqliteNotes) Update(ctx context.Context, note models.Note) error {
+	query := `
+		UPDATE notes
+		SET title = ?, content = ?, category_id = ?, is_favorite = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		note.Title,
+		note.Content,
+		note.CategoryID,
+		note.IsFavorite,
+		note.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar nota: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteNotes) SetFavorite(ctx context.Context, id int, favorite bool) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE notes SET is_favorite = ? WHERE id = ?", favorite, id)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar nota: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteNotes) SetContent(ctx context.Context, id int, content string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE notes SET content = ? WHERE id = ?", content, id)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar nota: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteNotes) SetNotebook(ctx context.Context, id int, notebookID *int) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE notes SET notebook_id = ? WHERE id = ?", notebookID, id)
+	if err != nil {
+		return i18n.Errorf("erro ao mover nota: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteNotes) Versions(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, updated_at FROM notes")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar notas: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int]time.Time)
+	for rows.Next() {
+		var id int
+		var updated time.Time
+		if err := rows.Scan(&id, &updated); err != nil {
+			return nil, i18n.Errorf("erro ao ler nota: %w", err)
+		}
+		versions[id] = updated
+	}
+
+	return versions, rows.Err()
+}
+
+func (r *sqliteNotes) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM notes WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar nota: %w", err)
+	}
+
+	return affected(result)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+type sqliteNotebooks struct {
+	db DBTX
+}
+
+func (r *sqliteNotebooks) Create(ctx context.Context, notebook models.Notebook) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		"INSERT INTO notebooks (name, parent_id) VALUES (?, ?)",
+		notebook.Name,
+		notebook.ParentID,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar caderno: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteNotebooks) GetByID(ctx context.Context, id int) (*models.Notebook, error) {
+	query := `
+		SELECT id, name, parent_id, created_at, updated_at
+		FROM notebooks
+		WHERE id = ?
+	`
+
+	return scanOne(r.db.QueryRowContext(ctx, query, id), func(row rowScanner) (models.Notebook, error) {
+		var notebook models.Notebook
+		err := row.Scan(
+			&notebook.ID,
+			&notebook.Name,
+			&notebook.ParentID,
+			&notebook.CreatedAt,
+			&notebook.UpdatedAt,
+		)
+		if err != nil {
+			return notebook, i18n.Errorf("erro ao ler caderno: %w", err)
+		}
+		return notebook, nil
+	})
+}
+
+func (r *sqliteNotebooks) List(ctx context.Context) ([]models.Notebook, error) {
+	query := `
+		SELECT nb.id, nb.name, nb.parent_id, nb.created_at, nb.updated_at,
+		       (SELECT COUNT(*) FROM notes WHERE notebook_id = nb.id) AS note_count
+		FROM notebooks nb
+		ORDER BY nb.name COLLATE NOCASE ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar cadernos: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.Notebook, error) {
+		var notebook models.Notebook
+		err := row.Scan(
+			&notebook.ID,
+			&notebook.Name,
+			&notebook.ParentID,
+			&notebook.CreatedAt,
+			&notebook.UpdatedAt,
+			&notebook.NoteCount,
+		)
+		if err != nil {
+			return notebook, i18n.Errorf("erro ao ler caderno: %w", err)
+		}
+		return notebook, nil
+	})
+}
+
+func (r *sqliteNotebooks) Rename(ctx context.Context, id int, name string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE notebooks SET name = ? WHERE id = ?", name, id)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar caderno: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteNotebooks) SetParent(ctx context.Context, id int, parentID *int) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE notebooks SET parent_id = ? WHERE id = ?", parentID, id)
+	if err != nil {
+		return i18n.Errorf("erro ao mover caderno: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteNotebooks) Subtree(ctx context.Context, id int) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, notebookSubtree+"SELECT id FROM subtree", id)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao verificar hierarquia: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (int, error) {
+		var id int
+		if err := row.Scan(&id); err != nil {
+			return 0, i18n.Errorf("erro ao ler caderno: %w", err)
+		}
+		return id, nil
+	})
+}
+
+func (r *sqliteNotebooks) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM notebooks WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar caderno: %w", err)
+	}
+
+	return affected(result)
+}
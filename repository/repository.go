@@ -0,0 +1,280 @@
+// Package repository isola o SQL dos dados do app (tarefas, notas, agenda,
+// finanças, diário, anexos, integrações) atrás de interfaces. Os services
+// dependem só das interfaces, o que permite testá-los com implementações
+// falsas (veja NewMemory) e juntar operações de várias entidades numa mesma
+// transação (UnitOfWork.WithTx).
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"personal-cockpit/models"
+)
+
+// ErrNotFound é retornado quando o registro pedido não existe; os services o
+// convertem em NotFoundError
+var ErrNotFound = errors.New("registro não encontrado")
+
+// DBTX é o que *sql.DB e *sql.Tx têm em comum, para os repositórios
+// funcionarem dentro e fora de uma transação
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Store dá acesso aos repositórios
+type Store interface {
+	Tasks() TaskRepository
+	Notes() NoteRepository
+	Notebooks() NotebookRepository
+	Events() EventRepository
+	Categories() CategoryRepository
+	Journal() JournalRepository
+	Templates() TemplateRepository
+	Attachments() AttachmentRepository
+	Accounts() AccountRepository
+	Transactions() TransactionRepository
+	Budgets() BudgetRepository
+	Recurring() RecurringRepository
+	Settings() SettingRepository
+	Webhooks() WebhookRepository
+	CalDAV() CalDAVRepository
+	NoteFiles() NoteFileRepository
+}
+
+// UnitOfWork é um Store que também abre transações: tudo o que fn grava pelo
+// Store recebido é confirmado junto, ou desfeito se fn retornar erro
+type UnitOfWork interface {
+	Store
+	WithTx(ctx context.Context, fn func(tx Store) error) error
+}
+
+// TaskRepository guarda as tarefas
+type TaskRepository interface {
+	Create(ctx context.Context, task models.Task) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Task, error)
+	List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error)
+	ListCompletedBetween(ctx context.Context, from, to time.Time) ([]models.Task, error)
+	Update(ctx context.Context, task models.Task) error
+	SetStatus(ctx context.Context, id int, status string) error
+	// SetStatusFromNote marca ou reabre a tarefa vinculada à nota, retornando
+	// se o status mudou
+	SetStatusFromNote(ctx context.Context, id, noteID int, completed bool) (bool, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// NoteQuery filtra a listagem de notas; os campos vazios não filtram
+type NoteQuery struct {
+	FavoritesOnly bool
+	Search        string // trecho do título ou do conteúdo
+	NotebookID    *int   // o caderno e todos os subcadernos
+}
+
+// NoteRepository guarda as notas
+type NoteRepository interface {
+	Create(ctx context.Context, note models.Note) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Note, error)
+	List(ctx context.Context, query NoteQuery) ([]models.Note, error)
+	Update(ctx context.Context, note models.Note) error
+	SetFavorite(ctx context.Context, id int, favorite bool) error
+	SetContent(ctx context.Context, id int, content string) error
+	// SetNotebook move a nota para o caderno (nil tira do caderno)
+	SetNotebook(ctx context.Context, id int, notebookID *int) error
+	// Versions traz o updated_at de todas as notas, sem o conteúdo
+	Versions(ctx context.Context) (map[int]time.Time, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// NotebookRepository guarda os cadernos de notas
+type NotebookRepository interface {
+	Create(ctx context.Context, notebook models.Notebook) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Notebook, error)
+	// List lista todos os cadernos, sem hierarquia, com NoteCount preenchido
+	List(ctx context.Context) ([]models.Notebook, error)
+	Rename(ctx context.Context, id int, name string) error
+	SetParent(ctx context.Context, id int, parentID *int) error
+	// Subtree traz o ID do caderno e os de todos os seus descendentes
+	Subtree(ctx context.Context, id int) ([]int, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// EventRepository guarda os eventos da agenda
+type EventRepository interface {
+	Create(ctx context.Context, event models.Event) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Event, error)
+	// GetByTask traz o bloco de tempo da tarefa (ErrNotFound se não houver)
+	GetByTask(ctx context.Context, taskID int) (*models.Event, error)
+	List(ctx context.Context) ([]models.Event, error)
+	// ListStartingBetween lista os eventos com início entre from e to (inclusive)
+	ListStartingBetween(ctx context.Context, from, to time.Time) ([]models.Event, error)
+	// ListOverlapping lista os eventos que ocupam algum momento de [from, to)
+	ListOverlapping(ctx context.Context, from, to time.Time) ([]models.Event, error)
+	// Update não mexe em task_id nem is_done: o vínculo é criado com o bloco
+	// e is_done segue o status da tarefa
+	Update(ctx context.Context, event models.Event) error
+	Delete(ctx context.Context, id int) error
+}
+
+// CategoryRepository guarda as categorias
+type CategoryRepository interface {
+	Create(ctx context.Context, category models.Category) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	// List lista as categorias do tipo informado, ou todas com ""
+	List(ctx context.Context, categoryType string) ([]models.Category, error)
+	Update(ctx context.Context, category models.Category) error
+	Delete(ctx context.Context, id int) error
+}
+
+// JournalRepository guarda as entradas do diário, uma por data (AAAA-MM-DD)
+type JournalRepository interface {
+	// Ensure cria a entrada da data se ainda não existir, retornando se criou
+	Ensure(ctx context.Context, date string) (bool, error)
+	GetByDate(ctx context.Context, date string) (*models.JournalEntry, error)
+	GetByID(ctx context.Context, id int) (*models.JournalEntry, error)
+	// Adjacent traz a data da entrada mais próxima depois (after) ou antes da
+	// data informada
+	Adjacent(ctx context.Context, date string, after bool) (string, error)
+	Search(ctx context.Context, filter models.JournalFilter) ([]models.JournalEntry, error)
+	// MoodSeries lista humor e energia das entradas do período (datas
+	// inclusivas) que têm algum dos dois, sem a média móvel
+	MoodSeries(ctx context.Context, startDate, endDate string) ([]models.MoodPoint, error)
+	Update(ctx context.Context, entry models.JournalEntry) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TemplateRepository guarda os modelos de notas e de listas de tarefas
+type TemplateRepository interface {
+	Create(ctx context.Context, template models.Template) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Template, error)
+	List(ctx context.Context) ([]models.Template, error)
+	Update(ctx context.Context, template models.Template) error
+	Delete(ctx context.Context, id int) error
+}
+
+// AttachmentRepository guarda os dados dos anexos; o conteúdo fica no disco,
+// com o AttachmentService
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment models.Attachment) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Attachment, error)
+	Search(ctx context.Context, filter models.AttachmentFilter) ([]models.Attachment, error)
+	// Update altera nome, tags e vínculo
+	Update(ctx context.Context, attachment models.Attachment) error
+	Delete(ctx context.Context, id int) error
+	// CountBySHA256 conta os anexos que usam o conteúdo com o hash
+	CountBySHA256(ctx context.Context, hash string) (int, error)
+}
+
+// AccountRepository guarda as contas; a leitura traz o saldo atual
+type AccountRepository interface {
+	Create(ctx context.Context, account models.Account) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Account, error)
+	List(ctx context.Context) ([]models.Account, error)
+	// Update altera nome, tipo e saldo inicial (a moeda não muda)
+	Update(ctx context.Context, account models.Account) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TransactionRepository guarda as transações financeiras
+type TransactionRepository interface {
+	Create(ctx context.Context, transaction models.Transaction) (int64, error)
+	// Import grava a transação de um extrato, retornando false se o ImportID
+	// já tinha sido importado
+	Import(ctx context.Context, transaction models.Transaction) (bool, error)
+	List(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error)
+	Update(ctx context.Context, transaction models.Transaction) error
+	Delete(ctx context.Context, id int) error
+	DeleteByAccount(ctx context.Context, accountID int) error
+	// Summarize soma receitas e despesas de [start, end) por categoria e
+	// moeda, das maiores despesas para as menores
+	Summarize(ctx context.Context, start, end string) ([]models.CategorySummary, error)
+}
+
+// BudgetRepository guarda os orçamentos mensais por categoria e moeda
+type BudgetRepository interface {
+	// Save cria o orçamento ou substitui o limite do que já existe para a
+	// mesma categoria e moeda, retornando o ID
+	Save(ctx context.Context, budget models.Budget) (int64, error)
+	Delete(ctx context.Context, id int) error
+	// ListSpent lista os orçamentos com o nome da categoria e o gasto em
+	// [start, end); os demais campos do status ficam com o service
+	ListSpent(ctx context.Context, start, end string) ([]models.BudgetStatus, error)
+}
+
+// RecurringRepository guarda os lançamentos recorrentes
+type RecurringRepository interface {
+	Create(ctx context.Context, recurring models.RecurringTransaction) (int64, error)
+	List(ctx context.Context) ([]models.RecurringTransaction, error)
+	SetNextDate(ctx context.Context, id int, nextDate string) error
+	Delete(ctx context.Context, id int) error
+	DeleteByAccount(ctx context.Context, accountID int) error
+}
+
+// SettingRepository guarda as configurações chave/valor
+type SettingRepository interface {
+	Get(ctx context.Context, key string) (string, error)
+	// Set cria a configuração ou troca o valor
+	Set(ctx context.Context, key, value string) error
+	List(ctx context.Context) ([]models.Setting, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// WebhookRepository guarda os webhooks e o log de entregas
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook models.Webhook) (int64, error)
+	GetByID(ctx context.Context, id int) (*models.Webhook, error)
+	List(ctx context.Context, activeOnly bool) ([]models.Webhook, error)
+	// Update mantém o segredo atual quando Secret vem vazio
+	Update(ctx context.Context, webhook models.Webhook) error
+	// Delete apaga o webhook e o log de entregas dele
+	Delete(ctx context.Context, id int) error
+	// LogDelivery registra a tentativa (preenchendo o ID) e mantém só as
+	// últimas keep do webhook
+	LogDelivery(ctx context.Context, delivery *models.WebhookDelivery, keep int) error
+	ListDeliveries(ctx context.Context, webhookID, limit int) ([]models.WebhookDelivery, error)
+}
+
+// CalDAVItem vincula um evento ou tarefa ao item do servidor CalDAV
+type CalDAVItem struct {
+	ID            int
+	Kind          string // "event" ou "task"
+	EntityID      int
+	UID           string
+	Href          string
+	ETag          string
+	SyncedVersion time.Time // updated_at da entidade na última sincronização
+}
+
+// CalDAVRepository guarda os vínculos e o log da sincronização CalDAV
+type CalDAVRepository interface {
+	ListItems(ctx context.Context, kinds []string) ([]CalDAVItem, error)
+	// SaveItem cria ou atualiza o vínculo pelo Href
+	SaveItem(ctx context.Context, item CalDAVItem) error
+	DeleteItem(ctx context.Context, href string) error
+	DeleteAllItems(ctx context.Context) error
+	// SaveLog registra a sincronização (preenchendo o ID) e mantém só as
+	// últimas keep
+	SaveLog(ctx context.Context, log *models.CalDAVSyncLog, keep int) error
+	ListLogs(ctx context.Context, limit int) ([]models.CalDAVSyncLog, error)
+}
+
+// NoteFile vincula uma nota ao seu arquivo na pasta de notas
+type NoteFile struct {
+	NoteID        int
+	Path          string // relativo à pasta, com "/"
+	Hash          string
+	SyncedVersion time.Time // updated_at da nota na última sincronização
+}
+
+// NoteFileRepository guarda os vínculos da pasta de notas
+type NoteFileRepository interface {
+	List(ctx context.Context) ([]NoteFile, error)
+	// Save cria ou atualiza o vínculo da nota; o caminho deixa de ser de
+	// qualquer outra
+	Save(ctx context.Context, file NoteFile) error
+	DeleteByNote(ctx context.Context, noteID int) error
+	DeleteAll(ctx context.Context) error
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// Colunas lidas por scanTask, scanNote, scanEvent e scanCategory, na ordem
+const (
+	taskColumns = `id, title, description, status, priority, category_id, note_id,
+		due_date, estimated_minutes, completed_at, created_at, updated_at`
+
+	noteColumns = `id, title, content, category_id, notebook_id, is_favorite, created_at, updated_at`
+
+	eventColumns = `id, title, description, start_date, end_date, all_day, color, location,
+		reminder_minutes, task_id, is_done, recurrence_rule, created_at, updated_at`
+
+	categoryColumns = `id, name, color, type, created_at`
+)
+
+// rowScanner é *sql.Row ou *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(row rowScanner) (models.Task, error) {
+	var task models.Task
+	err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.CategoryID,
+		&task.NoteID,
+		&task.DueDate,
+		&task.EstimatedMinutes,
+		&task.CompletedAt,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+	)
+	if err != nil {
+		return task, i18n.Errorf("erro ao ler tarefa: %w", err)
+	}
+	localizeOptional(task.DueDate, task.CompletedAt)
+	localize(&task.CreatedAt, &task.UpdatedAt)
+	return task, nil
+}
+
+func scanNote(row rowScanner) (models.Note, error) {
+	var note models.Note
+	err := row.Scan(
+		&note.ID,
+		&note.Title,
+		&note.Content,
+		&note.CategoryID,
+		&note.NotebookID,
+		&note.IsFavorite,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return note, i18n.Errorf("erro ao ler nota: %w", err)
+	}
+	localize(&note.CreatedAt, &note.UpdatedAt)
+	return note, nil
+}
+
+func scanEvent(row rowScanner) (models.Event, error) {
+	var event models.Event
+	err := row.Scan(
+		&event.ID,
+		&event.Title,
+		&event.Description,
+		&event.StartDate,
+		&event.EndDate,
+		&event.AllDay,
+		&event.Color,
+		&event.Location,
+		&event.ReminderMinutes,
+		&event.TaskID,
+		&event.IsDone,
+		&event.RecurrenceRule,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+	if err != nil {
+		return event, i18n.Errorf("erro ao ler evento: %w", err)
+	}
+	if event.AllDay {
+		event.StartDate = timezone.Floating(event.StartDate)
+		event.EndDate = timezone.Floating(event.EndDate)
+	} else {
+		localize(&event.StartDate, &event.EndDate)
+	}
+	localize(&event.CreatedAt, &event.UpdatedAt)
+	return event, nil
+}
+
+func scanCategory(row rowScanner) (models.Category, error) {
+	var category models.Category
+	err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.Color,
+		&category.Type,
+		&category.CreatedAt,
+	)
+	if err != nil {
+		return category, i18n.Errorf("erro ao ler categoria: %w", err)
+	}
+	return category, nil
+}
+
+// scanOne lê uma linha de QueryRow; sem linha, retorna ErrNotFound
+func scanOne[T any](row *sql.Row, scan func(rowScanner) (T, error)) (*T, error) {
+	item, err := scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// scanAll lê todas as linhas e fecha rows. Sem linhas, retorna nil.
+func scanAll[T any](rows *sql.Rows, scan func(rowScanner) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+type sqliteSettings struct {
+	db DBTX
+}
+
+func (r *sqliteSettings) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	err := r.db.QueryRowContext(ctx, "SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", i18n.Errorf("erro ao buscar configuração %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+func (r *sqliteSettings) Set(ctx context.Context, key, value string) error {
+	query := `
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, key, value); err != nil {
+		return i18n.Errorf("erro ao salvar configuração %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (r *sqliteSettings) List(ctx context.Context) ([]models.Setting, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT key, value, updated_at FROM settings ORDER BY key ASC")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar configurações: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.Setting, error) {
+		var setting models.Setting
+		if err := row.Scan(&setting.Key, &setting.Value, &setting.UpdatedAt); err != nil {
+			return setting, i18n.Errorf("erro ao ler configuração: %w", err)
+		}
+		return setting, nil
+	})
+}
+
+func (r *sqliteSettings) Delete(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM settings WHERE key = ?", key); err != nil {
+		return i18n.Errorf("erro ao remover configuração %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"personal-cockpit/i18n"
+)
+
+// SQLite implementa UnitOfWork sobre o banco do app
+type SQLite struct {
+	db *sql.DB
+	sqliteStore
+}
+
+// NewSQLite cria o Store/UnitOfWork do SQLite
+func NewSQLite(db *sql.DB) *SQLite {
+	return &SQLite{db: db, sqliteStore: sqliteStore{db: db}}
+}
+
+// WithTx executa fn numa transação. Se fn retornar erro (ou entrar em pânico),
+// nada do que ela gravou fica no banco.
+func (s *SQLite) WithTx(ctx context.Context, fn func(tx Store) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return i18n.Errorf("erro ao iniciar transação: %w", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			tx.Rollback()
+			panic(recovered)
+		}
+	}()
+
+	if err := fn(sqliteStore{db: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return i18n.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// sqliteStore monta os repositórios sobre o banco ou sobre uma transação
+type sqliteStore struct {
+	db DBTX
+}
+
+func (s sqliteStore) Tasks() TaskRepository {
+	return &sqliteTasks{db: s.db}
+}
+
+func (s sqliteStore) Notes() NoteRepository {
+	return &sqliteNotes{db: s.db}
+}
+
+func (s sqliteStore) Events() EventRepository {
+	return &sqliteEvents{db: s.db}
+}
+
+func (s sqliteStore) Notebooks() NotebookRepository {
+	return &sqliteNotebooks{db: s.db}
+}
+
+func (s sqliteStore) Categories() CategoryRepository {
+	return &sqliteCategories{db: s.db}
+}
+
+func (s sqliteStore) Journal() JournalRepository {
+	return &sqliteJournal{db: s.db}
+}
+
+func (s sqliteStore) Templates() TemplateRepository {
+	return &sqliteTemplates{db: s.db}
+}
+
+func (s sqliteStore) Attachments() AttachmentRepository {
+	return &sqliteAttachments{db: s.db}
+}
+
+func (s sqliteStore) Accounts() AccountRepository {
+	return &sqliteAccounts{db: s.db}
+}
+
+func (s sqliteStore) Transactions() TransactionRepository {
+	return &sqliteTransactions{db: s.db}
+}
+
+func (s sqliteStore) Budgets() BudgetRepository {
+	return &sqliteBudgets{db: s.db}
+}
+
+func (s sqliteStore) Recurring() RecurringRepository {
+	return &sqliteRecurring{db: s.db}
+}
+
+func (s sqliteStore) Settings() SettingRepository {
+	return &sqliteSettings{db: s.db}
+}
+
+func (s sqliteStore) Webhooks() WebhookRepository {
+	return &sqliteWebhooks{db: s.db}
+}
+
+func (s sqliteStore) CalDAV() CalDAVRepository {
+	return &sqliteCalDAV{db: s.db}
+}
+
+func (s sqliteStore) NoteFiles() NoteFileRepository {
+	return &sqliteNoteFiles{db: s.db}
+}
+
+// nullableString grava NULL para strings vazias
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// affected converte "nenhuma linha alterada" em ErrNotFound
+func affected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
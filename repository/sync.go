@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+// ═══════════════════════════════════════════════════════════
+// CALDAV
+// ═══════════════════════════════════════════════════════════
+
+type sqliteCalDAV struct {
+	db DBTX
+}
+
+func (r *sqliteCalDAV) ListItems(ctx context.Context, kinds []string) ([]CalDAVItem, error) {
+	query := `
+		SELECT id, entity_type, entity_id, uid, href, etag, synced_version
+		FROM caldav_items
+		WHERE entity_type IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(kinds)), ", ") + `)
+	`
+
+	args := make([]interface{}, len(kinds))
+	for i, kind := range kinds {
+		args[i] = kind
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar itens CalDAV: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (CalDAVItem, error) {
+		var item CalDAVItem
+		var synced sql.NullTime
+		if err := row.Scan(&item.ID, &item.Kind, &item.EntityID, &item.UID, &item.Href, &item.ETag, &synced); err != nil {
+			return item, i18n.Errorf("erro ao ler item CalDAV: %w", err)
+		}
+		item.SyncedVersion = synced.Time
+		return item, nil
+	})
+}
+
+func (r *sqliteCalDAV) SaveItem(ctx context.Context, item CalDAVItem) error {
+	query := `
+		INSERT INTO caldav_items (entity_type, entity_id, uid, href, etag, synced_version, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(href) DO UPDATE SET
+			entity_type = excluded.entity_type,
+			entity_id = excluded.entity_id,
+			uid = excluded.uid,
+			etag = excluded.etag,
+			synced_version = excluded.synced_version,
+			synced_at = excluded.synced_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, item.Kind, item.EntityID, item.UID, item.Href, item.ETag, timestamp(item.SyncedVersion))
+	if err != nil {
+		return i18n.Errorf("erro ao gravar item CalDAV: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteCalDAV) DeleteItem(ctx context.Context, href string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM caldav_items WHERE href = ?", href); err != nil {
+		return i18n.Errorf("erro ao apagar item CalDAV: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteCalDAV) DeleteAllItems(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM caldav_items"); err != nil {
+		return i18n.Errorf("erro ao apagar itens CalDAV: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteCalDAV) SaveLog(ctx context.Context, log *models.CalDAVSyncLog, keep int) error {
+	query := `
+		INSERT INTO caldav_sync_log (started_at, finished_at, pulled, pushed, deleted, conflicts, failed, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		timestamp(log.StartedAt),
+		nullableTimestamp(log.FinishedAt),
+		log.Pulled, log.Pushed, log.Deleted, log.Conflicts, log.Failed, log.Error,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao gravar log CalDAV: %w", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		log.ID = int(id)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		DELETE FROM caldav_sync_log
+		WHERE id NOT IN (SELECT id FROM caldav_sync_log ORDER BY id DESC LIMIT ?)
+	`, keep)
+	return err
+}
+
+func (r *sqliteCalDAV) ListLogs(ctx context.Context, limit int) ([]models.CalDAVSyncLog, error) {
+	query := `
+		SELECT id, started_at, finished_at, pulled, pushed, deleted, conflicts, failed, error
+		FROM caldav_sync_log
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar log CalDAV: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.CalDAVSyncLog, error) {
+		var entry models.CalDAVSyncLog
+		var finished sql.NullTime
+		err := row.Scan(
+			&entry.ID,
+			&entry.StartedAt,
+			&finished,
+			&entry.Pulled,
+			&entry.Pushed,
+			&entry.Deleted,
+			&entry.Conflicts,
+			&entry.Failed,
+			&entry.Error,
+		)
+		if err != nil {
+			return entry, i18n.Errorf("erro ao ler log CalDAV: %w", err)
+		}
+		if finished.Valid {
+			entry.FinishedAt = &finished.Time
+		}
+		return entry, nil
+	})
+}
+
+// ═══════════════════════════════════════════════════════════
+// PASTA DE NOTAS
+// ═══════════════════════════════════════════════════════════
+
+type sqliteNoteFiles struct {
+	db DBTX
+}
+
+func (r *sqliteNoteFiles) List(ctx context.Context) ([]NoteFile, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT note_id, path, hash, synced_version FROM note_files")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar vínculos da pasta: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (NoteFile, error) {
+		var file NoteFile
+		var synced sql.NullTime
+		if err := row.Scan(&file.NoteID, &file.Path, &file.Hash, &synced); err != nil {
+			return file, i18n.Errorf("erro ao ler vínculo da pasta: %w", err)
+		}
+		file.SyncedVersion = synced.Time
+		return file, nil
+	})
+}
+
+func (r *sqliteNoteFiles) Save(ctx context.Context, file NoteFile) error {
+	// O caminho pode ter sido de outra nota (apagada nesta mesma varredura)
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM note_files WHERE path = ? AND note_id != ?", file.Path, file.NoteID); err != nil {
+		return i18n.Errorf("erro ao gravar vínculo da pasta: %w", err)
+	}
+
+	query := `
+		INSERT INTO note_files (note_id, path, hash, synced_version)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(note_id) DO UPDATE SET
+			path = excluded.path,
+			hash = excluded.hash,
+			synced_version = excluded.synced_version
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, file.NoteID, file.Path, file.Hash, timestampOrNil(file.SyncedVersion)); err != nil {
+		return i18n.Errorf("erro ao gravar vínculo da pasta: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteNoteFiles) DeleteByNote(ctx context.Context, noteID int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM note_files WHERE note_id = ?", noteID); err != nil {
+		return i18n.Errorf("erro ao apagar vínculo da pasta: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteNoteFiles) DeleteAll(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM note_files"); err != nil {
+		return i18n.Errorf("erro ao apagar vínculos da pasta: %w", err)
+	}
+	return nil
+}
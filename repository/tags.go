@@ -0,0 +1,44 @@
+package repository
+
+import "strings"
+
+// normalizeTags remove espaços, duplicatas e diferenças de caixa
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(tag, "#")))
+		tag = strings.ReplaceAll(tag, ",", "")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+
+	return result
+}
+
+// joinTags serializa as tags para a coluna TEXT ("a,b,c")
+func joinTags(tags []string) string {
+	return strings.Join(normalizeTags(tags), ",")
+}
+
+// splitTags converte a coluna TEXT de volta em lista
+func splitTags(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return normalizeTags(strings.Split(value, ","))
+}
+
+// tagCondition monta o filtro SQL que encontra uma tag exata na coluna informada
+func tagCondition(column string) string {
+	return "(',' || COALESCE(" + column + ", '') || ',') LIKE ?"
+}
+
+// tagPattern é o argumento correspondente a tagCondition
+func tagPattern(tag string) string {
+	return "%," + strings.ToLower(strings.TrimSpace(strings.TrimPrefix(tag, "#"))) + ",%"
+}
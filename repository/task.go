@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+type sqliteTasks struct {
+	db DBTX
+}
+
+func (r *sqliteTasks) Create(ctx context.Context, task models.Task) (int64, error) {
+	query := `
+		INSERT INTO tasks (title, description, status, priority, category_id, note_id, due_date, estimated_minutes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.Priority,
+		task.CategoryID,
+		task.NoteID,
+		nullableTimestamp(task.DueDate),
+		task.EstimatedMinutes,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar tarefa: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteTasks) GetByID(ctx context.Context, id int) (*models.Task, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = ?", id)
+	return scanOne(row, scanTask)
+}
+
+func (r *sqliteTasks) List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE 1=1"
+	args := []interface{}{}
+
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+
+	if filter.Priority != "" {
+		query += " AND priority = ?"
+		args = append(args, filter.Priority)
+	}
+
+	if filter.CategoryID != nil {
+		query += " AND category_id = ?"
+		args = append(args, *filter.CategoryID)
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar tarefas: %w", err)
+	}
+
+	return scanAll(rows, scanTask)
+}
+
+func (r *sqliteTasks) ListCompletedBetween(ctx context.Context, from, to time.Time) ([]models.Task, error) {
+	query := "SELECT " + taskColumns + ` FROM tasks
+		WHERE status = 'completed' AND completed_at >= ? AND completed_at < ?
+		ORDER BY completed_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, timestamp(from), timestamp(to))
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar tarefas: %w", err)
+	}
+
+	return scanAll(rows, scanTask)
+}
+
+func (r *sqliteTasks) Update(ctx context.Context, task models.Task) error {
+	query := `
+		UPDATE tasks
+		SET title = ?, description = ?, status = ?, priority = ?,
+		    category_id = ?, due_date = ?, estimated_minutes = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.Priority,
+		task.CategoryID,
+		nullableTimestamp(task.DueDate),
+		task.EstimatedMinutes,
+		task.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar tarefa: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteTasks) SetStatus(ctx context.Context, id int, status string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE tasks SET status = ? WHERE id = ?", status, id)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar tarefa: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteTasks) SetStatusFromNote(ctx context.Context, id, noteID int, completed bool) (bool, error) {
+	query := "UPDATE tasks SET status = 'pending' WHERE id = ? AND note_id = ? AND status = 'completed'"
+	if completed {
+		query = "UPDATE tasks SET status = 'completed' WHERE id = ? AND note_id = ? AND status != 'completed'"
+	}
+
+	result, err := r.db.ExecContext(ctx, query, id, noteID)
+	if err != nil {
+		return false, i18n.Errorf("erro ao sincronizar tarefa: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (r *sqliteTasks) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar tarefa: %w", err)
+	}
+
+	return affected(result)
+}
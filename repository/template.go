@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+const templateColumns = `id, name, kind, title, content, category_id, created_at, updated_at`
+
+type sqliteTemplates struct {
+	db DBTX
+}
+
+// Create e Update deixam passar o erro de UNIQUE do SQLite (nome repetido)
+// embrulhado, para o service responder com ConflictError
+func (r *sqliteTemplates) Create(ctx context.Context, template models.Template) (int64, error) {
+	query := `
+		INSERT INTO templates (name, kind, title, content, category_id)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		template.Name,
+		template.Kind,
+		template.Title,
+		template.Content,
+		template.CategoryID,
+	)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar modelo: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteTemplates) GetByID(ctx context.Context, id int) (*models.Template, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+templateColumns+" FROM templates WHERE id = ?", id)
+	return scanOne(row, scanTemplate)
+}
+
+func (r *sqliteTemplates) List(ctx context.Context) ([]models.Template, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+templateColumns+" FROM templates ORDER BY name ASC")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar modelos: %w", err)
+	}
+
+	return scanAll(rows, scanTemplate)
+}
+
+func (r *sqliteTemplates) Update(ctx context.Context, template models.Template) error {
+	query := `
+		UPDATE templates
+		SET name = ?, kind = ?, title = ?, content = ?, category_id = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		template.Name,
+		template.Kind,
+		template.Title,
+		template.Content,
+		template.CategoryID,
+		template.ID,
+	)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar modelo: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteTemplates) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM templates WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar modelo: %w", err)
+	}
+
+	return affected(result)
+}
+
+func scanTemplate(row rowScanner) (models.Template, error) {
+	var template models.Template
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Kind,
+		&template.Title,
+		&template.Content,
+		&template.CategoryID,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return template, i18n.Errorf("erro ao ler modelo: %w", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"personal-cockpit/database"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// timestamp grava o instante em UTC, no formato único do banco
+func timestamp(t time.Time) string {
+	return database.FormatTimestamp(t)
+}
+
+// nullableTimestamp grava NULL para datas opcionais vazias
+func nullableTimestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// timestampOrNil grava NULL para datas não informadas (zero), que no INSERT
+// ficam com o padrão da coluna
+func timestampOrNil(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamp(t)
+}
+
+// eventBounds grava início e fim do evento: instantes em UTC ou, nos eventos
+// de dia inteiro, datas flutuantes (só o dia, como o usuário o vê)
+func eventBounds(event models.Event) (string, string) {
+	if event.AllDay {
+		return floatingDate(event.StartDate), floatingDate(event.EndDate)
+	}
+	return timestamp(event.StartDate), timestamp(event.EndDate)
+}
+
+// floatingDate grava o dia de um evento de dia inteiro (veja timezone.DateOf)
+func floatingDate(t time.Time) string {
+	return database.FormatDate(timezone.DateOf(t))
+}
+
+// localize leva para o fuso do usuário as datas lidas do banco (em UTC)
+func localize(times ...*time.Time) {
+	for _, t := range times {
+		if !t.IsZero() {
+			*t = timezone.In(*t)
+		}
+	}
+}
+
+// localizeOptional faz o mesmo com datas que podem ser NULL
+func localizeOptional(times ...*time.Time) {
+	for _, t := range times {
+		if t != nil {
+			*t = timezone.In(*t)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+const webhookColumns = `id, url, events, secret, is_active, created_at, updated_at`
+
+type sqliteWebhooks struct {
+	db DBTX
+}
+
+func (r *sqliteWebhooks) Create(ctx context.Context, webhook models.Webhook) (int64, error) {
+	query := `
+		INSERT INTO webhooks (url, events, secret, is_active)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, webhook.URL, joinEventFilters(webhook.Events), webhook.Secret, webhook.IsActive)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao criar webhook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, i18n.Errorf("erro ao obter ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *sqliteWebhooks) GetByID(ctx context.Context, id int) (*models.Webhook, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = ?", id)
+	return scanOne(row, scanWebhook)
+}
+
+func (r *sqliteWebhooks) List(ctx context.Context, activeOnly bool) ([]models.Webhook, error) {
+	query := "SELECT " + webhookColumns + " FROM webhooks"
+	if activeOnly {
+		query += " WHERE is_active = 1"
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar webhooks: %w", err)
+	}
+
+	return scanAll(rows, scanWebhook)
+}
+
+func (r *sqliteWebhooks) Update(ctx context.Context, webhook models.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = ?, events = ?, secret = COALESCE(NULLIF(?, ''), secret), is_active = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, webhook.URL, joinEventFilters(webhook.Events), webhook.Secret, webhook.IsActive, webhook.ID)
+	if err != nil {
+		return i18n.Errorf("erro ao atualizar webhook: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteWebhooks) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+		return i18n.Errorf("erro ao deletar entregas: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return i18n.Errorf("erro ao deletar webhook: %w", err)
+	}
+
+	return affected(result)
+}
+
+func (r *sqliteWebhooks) LogDelivery(ctx context.Context, delivery *models.WebhookDelivery, keep int) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, attempt, status_code, success, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx,
+		query,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Success,
+		nullableString(delivery.Error),
+		delivery.DurationMs,
+	)
+	if err != nil {
+		return err
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		delivery.ID = int(id)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE webhook_id = ? AND id <= (
+			SELECT id FROM webhook_deliveries WHERE webhook_id = ?
+			ORDER BY id DESC LIMIT 1 OFFSET ?
+		)
+	`, delivery.WebhookID, delivery.WebhookID, keep)
+
+	return err
+}
+
+func (r *sqliteWebhooks) ListDeliveries(ctx context.Context, webhookID, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event_id, event_type, attempt, status_code, success,
+		       COALESCE(error, ''), duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar entregas: %w", err)
+	}
+
+	return scanAll(rows, func(row rowScanner) (models.WebhookDelivery, error) {
+		var delivery models.WebhookDelivery
+		err := row.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Success,
+			&delivery.Error,
+			&delivery.DurationMs,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return delivery, i18n.Errorf("erro ao ler entrega: %w", err)
+		}
+		return delivery, nil
+	})
+}
+
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var webhook models.Webhook
+	var filters string
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&filters,
+		&webhook.Secret,
+		&webhook.IsActive,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return webhook, i18n.Errorf("erro ao ler webhook: %w", err)
+	}
+
+	webhook.Events = splitTags(filters)
+	if len(webhook.Events) == 1 && webhook.Events[0] == "*" {
+		webhook.Events = []string{}
+	}
+
+	return webhook, nil
+}
+
+// joinEventFilters serializa os filtros como as tags; sem filtros = "*"
+func joinEventFilters(filters []string) string {
+	if joined := joinTags(filters); joined != "" {
+		return joined
+	}
+	return "*"
+}
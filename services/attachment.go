@@ -0,0 +1,491 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// Tamanho máximo (em pixels) do maior lado das miniaturas
+const thumbnailSize = 256
+
+// AttachmentService gerencia os arquivos anexados a notas, tarefas e eventos.
+// O conteúdo fica em um armazenamento endereçado pelo SHA-256 dentro do
+// diretório de dados do app, então arquivos iguais são gravados uma única vez.
+type AttachmentService struct {
+	store    repository.UnitOfWork
+	bus      *events.Bus
+	blobDir  string
+	thumbDir string
+
+	// blobMu serializa a gravação e a remoção dos blobs, para um anexo novo
+	// não reaproveitar um arquivo que um DeleteAttachment está apagando
+	blobMu sync.Mutex
+}
+
+// NewAttachmentService cria novo serviço de anexos
+func NewAttachmentService(db *sql.DB, bus *events.Bus, dataDir string) *AttachmentService {
+	return NewAttachmentServiceWithStore(repository.NewSQLite(db), bus, dataDir)
+}
+
+// NewAttachmentServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewAttachmentServiceWithStore(store repository.UnitOfWork, bus *events.Bus, dataDir string) *AttachmentService {
+	return &AttachmentService{
+		store:    store,
+		bus:      bus,
+		blobDir:  filepath.Join(dataDir, "files"),
+		thumbDir: filepath.Join(dataDir, "thumbnails"),
+	}
+}
+
+// AddFromPath copia o arquivo para o armazenamento e registra o anexo
+func (s *AttachmentService) AddFromPath(ctx context.Context, path string, entityType string, entityID *int, tags []string) (_ *models.Attachment, err error) {
+	defer logging.Track("AttachmentService.AddFromPath", &err)()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer file.Close()
+
+	return s.add(ctx, file, filepath.Base(path), entityType, entityID, tags)
+}
+
+// AddFromBytes registra um anexo a partir de conteúdo em memória
+func (s *AttachmentService) AddFromBytes(ctx context.Context, data []byte, fileName string, entityType string, entityID *int, tags []string) (_ *models.Attachment, err error) {
+	defer logging.Track("AttachmentService.AddFromBytes", &err)()
+
+	return s.add(ctx, bytes.NewReader(data), fileName, entityType, entityID, tags)
+}
+
+func (s *AttachmentService) add(ctx context.Context, r io.Reader, fileName string, entityType string, entityID *int, tags []string) (*models.Attachment, error) {
+	erros := &ValidationError{}
+
+	if fileName == "" {
+		erros.Required("file_name", "nome do arquivo")
+	}
+
+	if entityType != "" && !oneOf(entityType, attachmentKinds) {
+		erros.Invalid("entity_type", CodeInvalidChoice, choiceMessage("tipo do item vinculado", attachmentKinds))
+	}
+
+	if entityType != "" && entityID == nil {
+		erros.Required("entity_id", "ID do item vinculado")
+	}
+
+	if erros.HasErrors() {
+		return nil, erros
+	}
+
+	// Do blob até o registro do anexo, para a contagem de referências de
+	// removeBlobIfUnused já enxergar o anexo novo
+	s.blobMu.Lock()
+	hash, size, sniff, err := s.storeBlob(r)
+	if err != nil {
+		s.blobMu.Unlock()
+		return nil, err
+	}
+
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName)))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(sniff)
+	}
+
+	id, err := s.store.Attachments().Create(ctx, models.Attachment{
+		SHA256:     hash,
+		FileName:   fileName,
+		MimeType:   mimeType,
+		FileSize:   size,
+		Tags:       tags,
+		EntityType: entityType,
+		EntityID:   entityID,
+	})
+	if err != nil {
+		s.removeBlobIfUnused(ctx, hash)
+		s.blobMu.Unlock()
+		return nil, err
+	}
+	s.blobMu.Unlock()
+
+	attachment, err := s.GetAttachmentByID(ctx, int(id))
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(events.AttachmentCreated, attachment.ID, attachment)
+
+	return attachment, nil
+}
+
+// storeBlob grava o conteúdo no armazenamento e retorna o hash, o tamanho
+// e os primeiros bytes (usados para detectar o tipo MIME). Deve ser chamado
+// com blobMu travado.
+func (s *AttachmentService) storeBlob(r io.Reader) (string, int64, []byte, error) {
+	if err := os.MkdirAll(s.blobDir, 0755); err != nil {
+		return "", 0, nil, i18n.Errorf("erro ao criar diretório de arquivos: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.blobDir, "upload-*")
+	if err != nil {
+		return "", 0, nil, i18n.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	sniff := &limitedBuffer{limit: 512}
+
+	size, err := io.Copy(io.MultiWriter(tmp, hasher, sniff), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, nil, i18n.Errorf("erro ao copiar arquivo: %w", err)
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	target := s.blobPath(hash)
+
+	// Conteúdo já armazenado: apenas reaproveita o blob existente
+	if _, err := os.Stat(target); err == nil {
+		return hash, size, sniff.Bytes(), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", 0, nil, i18n.Errorf("erro ao criar diretório de arquivos: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", 0, nil, i18n.Errorf("erro ao salvar arquivo: %w", err)
+	}
+
+	return hash, size, sniff.Bytes(), nil
+}
+
+// GetAttachmentByID busca anexo por ID
+func (s *AttachmentService) GetAttachmentByID(ctx context.Context, id int) (_ *models.Attachment, err error) {
+	defer logging.Track("AttachmentService.GetAttachmentByID", &err)()
+
+	attachment, err := s.store.Attachments().GetByID(ctx, id)
+	if err != nil {
+		return nil, fromRepository(err, "attachment", id)
+	}
+
+	return attachment, nil
+}
+
+// GetAllAttachments retorna todos os anexos
+func (s *AttachmentService) GetAllAttachments(ctx context.Context) (_ []models.Attachment, err error) {
+	defer logging.Track("AttachmentService.GetAllAttachments", &err)()
+
+	return s.SearchAttachments(ctx, models.AttachmentFilter{})
+}
+
+// GetAttachmentsFor retorna os anexos de uma nota, tarefa ou evento
+func (s *AttachmentService) GetAttachmentsFor(ctx context.Context, entityType string, entityID int) (_ []models.Attachment, err error) {
+	defer logging.Track("AttachmentService.GetAttachmentsFor", &err)()
+
+	return s.SearchAttachments(ctx, models.AttachmentFilter{EntityType: entityType, EntityID: &entityID})
+}
+
+// SearchAttachments busca anexos por nome, tipo MIME, tag ou item vinculado
+func (s *AttachmentService) SearchAttachments(ctx context.Context, filter models.AttachmentFilter) (_ []models.Attachment, err error) {
+	defer logging.Track("AttachmentService.SearchAttachments", &err)()
+
+	return s.store.Attachments().Search(ctx, filter)
+}
+
+// UpdateAttachment atualiza nome, tags e vínculo do anexo
+func (s *AttachmentService) UpdateAttachment(ctx context.Context, attachment models.Attachment) (err error) {
+	defer logging.Track("AttachmentService.UpdateAttachment", &err)()
+
+	if attachment.ID == 0 {
+		return requiredID("ID do anexo")
+	}
+
+	if err := s.store.Attachments().Update(ctx, attachment); err != nil {
+		return fromRepository(err, "attachment", attachment.ID)
+	}
+
+	publishEntity(ctx, s.bus, events.AttachmentUpdated, attachment.ID, s.GetAttachmentByID)
+
+	return nil
+}
+
+// DeleteAttachment remove o anexo. O arquivo em disco só é apagado quando
+// nenhum outro anexo referencia o mesmo conteúdo.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, id int) (err error) {
+	defer logging.Track("AttachmentService.DeleteAttachment", &err)()
+
+	attachment, err := s.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Attachments().Delete(ctx, id); err != nil {
+		return fromRepository(err, "attachment", id)
+	}
+
+	s.bus.Publish(events.AttachmentDeleted, id, nil)
+
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+
+	return s.removeBlobIfUnused(ctx, attachment.SHA256)
+}
+
+// removeBlobIfUnused apaga o blob e suas miniaturas se a contagem de referências chegou a zero.
+// Deve ser chamado com blobMu travado.
+func (s *AttachmentService) removeBlobIfUnused(ctx context.Context, hash string) error {
+	refs, err := s.store.Attachments().CountBySHA256(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if refs > 0 {
+		return nil
+	}
+
+	if err := os.Remove(s.blobPath(hash)); err != nil && !os.IsNotExist(err) {
+		return i18n.Errorf("erro ao remover arquivo: %w", err)
+	}
+
+	if err := os.Remove(s.thumbnailPath(hash)); err != nil && !os.IsNotExist(err) {
+		return i18n.Errorf("erro ao remover miniatura: %w", err)
+	}
+
+	return nil
+}
+
+// GetFilePath retorna o caminho do conteúdo do anexo no disco
+func (s *AttachmentService) GetFilePath(ctx context.Context, id int) (_ string, err error) {
+	defer logging.Track("AttachmentService.GetFilePath", &err)()
+
+	attachment, err := s.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return s.blobPath(attachment.SHA256), nil
+}
+
+// OpenAttachment abre o anexo no programa padrão do sistema operacional.
+// Como o blob não tem extensão, uma cópia com o nome original é criada em
+// um diretório temporário.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, id int) (err error) {
+	defer logging.Track("AttachmentService.OpenAttachment", &err)()
+
+	attachment, err := s.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(os.TempDir(), "personal-cockpit", attachment.SHA256[:12])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return i18n.Errorf("erro ao preparar arquivo: %w", err)
+	}
+
+	target := filepath.Join(dir, filepath.Base(attachment.FileName))
+	if err := copyFile(s.blobPath(attachment.SHA256), target); err != nil {
+		return i18n.Errorf("erro ao preparar arquivo: %w", err)
+	}
+
+	return openWithDefaultApp(target)
+}
+
+// GetThumbnail retorna a miniatura PNG de um anexo de imagem como data URL,
+// pronta para ser usada em <img src>. As miniaturas ficam em cache no disco.
+func (s *AttachmentService) GetThumbnail(ctx context.Context, id int) (_ string, err error) {
+	defer logging.Track("AttachmentService.GetThumbnail", &err)()
+
+	attachment, err := s.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(attachment.MimeType, "image/") {
+		return "", i18n.Errorf("anexo não é uma imagem")
+	}
+
+	path := s.thumbnailPath(attachment.SHA256)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = s.generateThumbnail(attachment.SHA256, path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+func (s *AttachmentService) generateThumbnail(hash string, path string) ([]byte, error) {
+	file, err := os.Open(s.blobPath(hash))
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir imagem: %w", err)
+	}
+	defer file.Close()
+
+	src, _, err := image.Decode(file)
+	if err != nil {
+		return nil, i18n.Errorf("formato de imagem não suportado: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, resizeToFit(src, thumbnailSize)); err != nil {
+		return nil, i18n.Errorf("erro ao gerar miniatura: %w", err)
+	}
+
+	if err := os.MkdirAll(s.thumbDir, 0755); err != nil {
+		return nil, i18n.Errorf("erro ao criar diretório de miniaturas: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return nil, i18n.Errorf("erro ao salvar miniatura: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// blobPath distribui os arquivos em subpastas pelos dois primeiros caracteres do hash
+func (s *AttachmentService) blobPath(hash string) string {
+	return filepath.Join(s.blobDir, hash[:2], hash)
+}
+
+func (s *AttachmentService) thumbnailPath(hash string) string {
+	return filepath.Join(s.thumbDir, hash+".png")
+}
+
+// resizeToFit reduz a imagem para caber em max x max, fazendo a média
+// dos pixels de origem que caem em cada pixel de destino
+func resizeToFit(src image.Image, max int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	if w <= max && h <= max {
+		return src
+	}
+
+	dw, dh := max, h*max/w
+	if h > w {
+		dw, dh = w*max/h, max
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		y0 := bounds.Min.Y + y*h/dh
+		y1 := bounds.Min.Y + (y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0 := bounds.Min.X + x*w/dw
+			x1 := bounds.Min.X + (x+1)*w/dw
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+// openWithDefaultApp abre o arquivo com o programa associado no sistema
+func openWithDefaultApp(path string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	case "darwin":
+		cmd = exec.Command("open", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return i18n.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	// Não esperamos o programa fechar, apenas liberamos o processo
+	go cmd.Wait()
+
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// limitedBuffer guarda apenas os primeiros bytes escritos
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// Chaves do expediente usado na busca por horários livres
+const (
+	SettingWorkStart   = "work_start"           // "09:00"
+	SettingWorkEnd     = "work_end"             // "18:00"
+	SettingWorkDays    = "work_days"            // "1,2,3,4,5" (0 = domingo)
+	SettingEventBuffer = "event_buffer_minutes" // folga em torno dos eventos
+)
+
+// DefaultWorkingHours é o expediente quando nada foi configurado: de
+// segunda a sexta, das 9h às 18h, sem folga
+func DefaultWorkingHours() models.WorkingHours {
+	return models.WorkingHours{
+		Start: "09:00",
+		End:   "18:00",
+		Days:  []int{1, 2, 3, 4, 5},
+	}
+}
+
+const clockLayout = "15:04"
+
+// GetWorkingHours lê o expediente das configurações. Valores inválidos
+// gravados por fora do app voltam ao padrão.
+func (s *SettingsService) GetWorkingHours(ctx context.Context) (_ models.WorkingHours, err error) {
+	defer logging.Track("SettingsService.GetWorkingHours", &err)()
+
+	hours := DefaultWorkingHours()
+
+	start, err := s.Get(ctx, SettingWorkStart, hours.Start)
+	if err != nil {
+		return hours, err
+	}
+	end, err := s.Get(ctx, SettingWorkEnd, hours.End)
+	if err != nil {
+		return hours, err
+	}
+	days, err := s.Get(ctx, SettingWorkDays, formatWorkDays(hours.Days))
+	if err != nil {
+		return hours, err
+	}
+	buffer, err := s.GetInt(ctx, SettingEventBuffer, hours.BufferMinutes)
+	if err != nil {
+		return hours, err
+	}
+
+	saved := models.WorkingHours{Start: start, End: end, Days: parseWorkDays(days), BufferMinutes: buffer}
+	if err := validateWorkingHours(saved); err != nil {
+		slog.Warn("expediente inválido nas configurações, usando o padrão", "err", err)
+		return hours, nil
+	}
+
+	return saved, nil
+}
+
+// SetWorkingHours valida e grava o expediente
+func (s *SettingsService) SetWorkingHours(ctx context.Context, hours models.WorkingHours) (err error) {
+	defer logging.Track("SettingsService.SetWorkingHours", &err)()
+
+	if err := validateWorkingHours(hours); err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		SettingWorkStart:   hours.Start,
+		SettingWorkEnd:     hours.End,
+		SettingWorkDays:    formatWorkDays(hours.Days),
+		SettingEventBuffer: strconv.Itoa(hours.BufferMinutes),
+	}
+	for key, value := range values {
+		if err := s.Set(ctx, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateWorkingHours(hours models.WorkingHours) error {
+	erros := &ValidationError{}
+
+	start, startErr := time.Parse(clockLayout, hours.Start)
+	if startErr != nil {
+		erros.Invalid("start", CodeInvalid, "início do expediente (HH:MM)")
+	}
+	end, endErr := time.Parse(clockLayout, hours.End)
+	if endErr != nil {
+		erros.Invalid("end", CodeInvalid, "fim do expediente (HH:MM)")
+	}
+	if startErr == nil && endErr == nil && !end.After(start) {
+		erros.Invalid("end", CodeOutOfRange, "fim do expediente deve ser após o início")
+	}
+
+	if len(hours.Days) == 0 {
+		erros.Required("days", "dias do expediente")
+	}
+	for _, day := range hours.Days {
+		if day < 0 || day > 6 {
+			erros.Invalid("days", CodeOutOfRange, "dias do expediente (0 = domingo a 6 = sábado)")
+			break
+		}
+	}
+
+	if hours.BufferMinutes < 0 {
+		erros.Invalid("buffer_minutes", CodeOutOfRange, "folga não pode ser negativa")
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+	return nil
+}
+
+// workClock lê o "HH:MM" do expediente, já validado
+func workClock(value string) clock {
+	t, _ := time.Parse(clockLayout, value)
+	return clock{hour: t.Hour(), minute: t.Minute()}
+}
+
+func formatWorkDays(days []int) string {
+	parts := make([]string, len(days))
+	for i, day := range days {
+		parts[i] = strconv.Itoa(day)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseWorkDays(value string) []int {
+	var days []int
+	for _, part := range strings.Split(value, ",") {
+		if day, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// eventSpan é o intervalo que o evento ocupa na agenda. Um evento de dia
+// inteiro ocupa os dias inteiros, da meia-noite de start_date à do dia de
+// end_date (ou o próprio dia, se forem iguais). Um evento sem duração ocupa
+// o segundo em que começa, a precisão do banco.
+func eventSpan(event models.Event) (time.Time, time.Time) {
+	if event.AllDay {
+		start, end := timezone.DateOf(event.StartDate), timezone.DateOf(event.EndDate)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		return start, end
+	}
+
+	start, end := timezone.In(event.StartDate), timezone.In(event.EndDate)
+	if !end.After(start) {
+		end = start.Add(time.Second)
+	}
+	return start, end
+}
+
+// FindConflicts lista os outros eventos que ocupam algum momento do horário
+// do evento. Eventos de dia inteiro também contam: uma reunião durante as
+// férias é um conflito.
+func (s *EventService) FindConflicts(ctx context.Context, event models.Event) (_ []models.Event, err error) {
+	defer logging.Track("EventService.FindConflicts", &err)()
+
+	conflicts := []models.Event{}
+	if event.StartDate.IsZero() {
+		return conflicts, nil
+	}
+
+	start, end := eventSpan(event)
+	overlapping, err := s.store.Events().ListOverlapping(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range overlapping {
+		if event.ID == 0 || other.ID != event.ID {
+			conflicts = append(conflicts, other)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// saveResult monta o resultado da gravação com os conflitos. A gravação já
+// aconteceu: se a busca falhar, o evento fica salvo e o aviso some.
+func (s *EventService) saveResult(ctx context.Context, event models.Event) *models.EventSaveResult {
+	result := &models.EventSaveResult{ID: event.ID, Conflicts: []models.Event{}}
+
+	conflicts, err := s.FindConflicts(ctx, event)
+	if err != nil {
+		slog.Warn("erro ao buscar conflitos do evento", "event", event.ID, "err", err)
+		return result
+	}
+
+	result.Conflicts = conflicts
+	return result
+}
+
+// FindFreeSlots retorna os intervalos livres de pelo menos duration entre
+// from e to, dentro do expediente. Eventos com hora ocupam a agenda com a
+// folga do expediente antes e depois; eventos de dia inteiro ocupam o dia
+// todo. Os intervalos vêm inteiros, em ordem: cabe ao chamador escolher
+// onde, dentro deles, encaixar o compromisso.
+func (s *EventService) FindFreeSlots(ctx context.Context, from, to time.Time, duration time.Duration, hours models.WorkingHours) (_ []models.TimeSlot, err error) {
+	defer logging.Track("EventService.FindFreeSlots", &err)()
+
+	erros := &ValidationError{}
+	if !to.After(from) {
+		erros.Invalid("to", CodeOutOfRange, "fim do período deve ser após o início")
+	}
+	if duration <= 0 {
+		erros.Invalid("duration", CodeOutOfRange, "duração deve ser positiva")
+	}
+	if err := validateWorkingHours(hours); err != nil {
+		erros.Fields = append(erros.Fields, err.(*ValidationError).Fields...)
+	}
+	if erros.HasErrors() {
+		return nil, erros
+	}
+
+	from, to = timezone.In(from), timezone.In(to)
+	buffer := time.Duration(hours.BufferMinutes) * time.Minute
+
+	events, err := s.store.Events().ListOverlapping(ctx, from.Add(-buffer), to.Add(buffer))
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]models.TimeSlot, 0, len(events))
+	for _, event := range events {
+		start, end := eventSpan(event)
+		if !event.AllDay {
+			start, end = start.Add(-buffer), end.Add(buffer)
+		}
+		busy = append(busy, models.TimeSlot{Start: start, End: end})
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	workDays := make(map[time.Weekday]bool)
+	for _, day := range hours.Days {
+		workDays[time.Weekday(day)] = true
+	}
+	workStart, workEnd := workClock(hours.Start), workClock(hours.End)
+
+	slots := []models.TimeSlot{}
+	for day := timezone.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !workDays[day.Weekday()] {
+			continue
+		}
+
+		window := models.TimeSlot{
+			Start: atClock(day, workStart),
+			End:   atClock(day, workEnd),
+		}
+		if window.Start.Before(from) {
+			window.Start = from
+		}
+		if window.End.After(to) {
+			window.End = to
+		}
+		if !window.End.After(window.Start) {
+			continue
+		}
+
+		for _, free := range subtractBusy(window, busy) {
+			if free.End.Sub(free.Start) >= duration {
+				slots = append(slots, free)
+			}
+		}
+	}
+
+	return slots, nil
+}
+
+// subtractBusy retorna os trechos da janela que sobram fora dos intervalos
+// ocupados (em ordem de início)
+func subtractBusy(window models.TimeSlot, busy []models.TimeSlot) []models.TimeSlot {
+	var free []models.TimeSlot
+
+	cursor := window.Start
+	for _, b := range busy {
+		if !b.End.After(cursor) {
+			continue
+		}
+		if !b.Start.Before(window.End) {
+			break
+		}
+		if b.Start.After(cursor) {
+			free = append(free, models.TimeSlot{Start: cursor, End: b.Start})
+		}
+		cursor = b.End
+		if !cursor.Before(window.End) {
+			return free
+		}
+	}
+
+	if window.End.After(cursor) {
+		free = append(free, models.TimeSlot{Start: cursor, End: window.End})
+	}
+	return free
+}
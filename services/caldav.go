@@ -0,0 +1,945 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"personal-cockpit/caldav"
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// Chaves da conta CalDAV nas configurações. A senha fica cifrada (SecretBox).
+const (
+	SettingCalDAVEnabled   = "caldav_enabled"
+	SettingCalDAVEventsURL = "caldav_events_url"
+	SettingCalDAVTasksURL  = "caldav_tasks_url"
+	SettingCalDAVUsername  = "caldav_username"
+	SettingCalDAVPassword  = "caldav_password"
+	SettingCalDAVConflicts = "caldav_conflict_policy"
+	SettingCalDAVInterval  = "caldav_interval_minutes"
+
+	// sync-token (ou ctag) de cada coleção na última sincronização
+	settingCalDAVEventsToken = "caldav_events_token"
+	settingCalDAVTasksToken  = "caldav_tasks_token"
+)
+
+// Políticas para um item alterado dos dois lados desde a última sincronização
+const (
+	ConflictServerWins = "server" // a versão do servidor substitui a local
+	ConflictLocalWins  = "local"  // a versão local é regravada no servidor
+	ConflictNewestWins = "newest" // vence a alteração mais recente (LAST-MODIFIED × updated_at)
+)
+
+var conflictPolicies = []string{ConflictServerWins, ConflictLocalWins, ConflictNewestWins}
+
+// Intervalo padrão da sincronização automática, em minutos
+const DefaultCalDAVInterval = 15
+
+// Quantas sincronizações ficam no log
+const caldavSyncLogSize = 100
+
+// ErrSyncInProgress indica que já há uma sincronização rodando
+var ErrSyncInProgress error = syncInProgressError{}
+
+// syncInProgressError traduz a mensagem na hora de exibir, já que o idioma
+// pode mudar depois de o erro ser criado
+type syncInProgressError struct{}
+
+func (syncInProgressError) Error() string {
+	return i18n.T("sincronização já em andamento")
+}
+
+// CalDAVService sincroniza eventos e tarefas com um servidor CalDAV (VEVENT e
+// VTODO). Cada item sincronizado fica em caldav_items com o endereço e o
+// ETag do servidor e o updated_at local da última sincronização: um ETag
+// diferente é alteração remota; um updated_at diferente, alteração local.
+// O sync-token da coleção evita listar o servidor quando nada mudou lá.
+type CalDAVService struct {
+	store        repository.UnitOfWork
+	bus          *events.Bus
+	settings     *SettingsService
+	secrets      *SecretBox
+	eventService *EventService
+	taskService  *TaskService
+
+	syncing sync.Mutex
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCalDAVService cria novo serviço de sincronização CalDAV (a sincronização
+// automática só começa com Start)
+func NewCalDAVService(db *sql.DB, bus *events.Bus, settings *SettingsService, secrets *SecretBox, eventService *EventService, taskService *TaskService) *CalDAVService {
+	return NewCalDAVServiceWithStore(repository.NewSQLite(db), bus, settings, secrets, eventService, taskService)
+}
+
+// NewCalDAVServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewCalDAVServiceWithStore(store repository.UnitOfWork, bus *events.Bus, settings *SettingsService, secrets *SecretBox, eventService *EventService, taskService *TaskService) *CalDAVService {
+	return &CalDAVService{
+		store:        store,
+		bus:          bus,
+		settings:     settings,
+		secrets:      secrets,
+		eventService: eventService,
+		taskService:  taskService,
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// CONFIGURAÇÃO
+// ═══════════════════════════════════════════════════════════
+
+// GetConfig lê a conta das configurações. A senha não volta: HasPassword diz
+// se há uma salva.
+func (s *CalDAVService) GetConfig(ctx context.Context) (_ *models.CalDAVConfig, err error) {
+	defer logging.Track("CalDAVService.GetConfig", &err)()
+
+	config, err := s.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config.HasPassword = config.Password != ""
+	config.Password = ""
+	return config, nil
+}
+
+// SetConfig valida e grava a conta. Senha vazia mantém a salva. Trocar o
+// endereço de uma coleção esquece os vínculos com a anterior: na próxima
+// sincronização tudo é enviado e baixado de novo.
+func (s *CalDAVService) SetConfig(ctx context.Context, config models.CalDAVConfig) (err error) {
+	defer logging.Track("CalDAVService.SetConfig", &err)()
+
+	config.EventsURL = collectionURL(config.EventsURL)
+	config.TasksURL = collectionURL(config.TasksURL)
+	if config.ConflictPolicy == "" {
+		config.ConflictPolicy = ConflictServerWins
+	}
+
+	if err := validateCalDAVConfig(config); err != nil {
+		return err
+	}
+
+	old, err := s.loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		SettingCalDAVEnabled:   strconv.FormatBool(config.Enabled),
+		SettingCalDAVEventsURL: config.EventsURL,
+		SettingCalDAVTasksURL:  config.TasksURL,
+		SettingCalDAVUsername:  config.Username,
+		SettingCalDAVConflicts: config.ConflictPolicy,
+		SettingCalDAVInterval:  strconv.Itoa(config.IntervalMinutes),
+	}
+	if config.Password != "" {
+		sealed, err := s.secrets.Seal(config.Password)
+		if err != nil {
+			return err
+		}
+		values[SettingCalDAVPassword] = sealed
+	}
+
+	for key, value := range values {
+		if err := s.settings.Set(ctx, key, value); err != nil {
+			return err
+		}
+	}
+
+	if old.EventsURL != config.EventsURL || old.TasksURL != config.TasksURL {
+		if err := s.forgetItems(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadConfig lê a conta com a senha decifrada
+func (s *CalDAVService) loadConfig(ctx context.Context) (*models.CalDAVConfig, error) {
+	config := &models.CalDAVConfig{}
+	var err error
+
+	if config.Enabled, err = s.settings.GetBool(ctx, SettingCalDAVEnabled, false); err != nil {
+		return nil, err
+	}
+	if config.EventsURL, err = s.settings.Get(ctx, SettingCalDAVEventsURL, ""); err != nil {
+		return nil, err
+	}
+	if config.TasksURL, err = s.settings.Get(ctx, SettingCalDAVTasksURL, ""); err != nil {
+		return nil, err
+	}
+	if config.Username, err = s.settings.Get(ctx, SettingCalDAVUsername, ""); err != nil {
+		return nil, err
+	}
+	if config.ConflictPolicy, err = s.settings.Get(ctx, SettingCalDAVConflicts, ConflictServerWins); err != nil {
+		return nil, err
+	}
+	if config.IntervalMinutes, err = s.settings.GetInt(ctx, SettingCalDAVInterval, DefaultCalDAVInterval); err != nil {
+		return nil, err
+	}
+
+	sealed, err := s.settings.Get(ctx, SettingCalDAVPassword, "")
+	if err != nil {
+		return nil, err
+	}
+	if config.Password, err = s.secrets.Open(sealed); err != nil {
+		return nil, err
+	}
+
+	if !oneOf(config.ConflictPolicy, conflictPolicies) {
+		config.ConflictPolicy = ConflictServerWins
+	}
+
+	return config, nil
+}
+
+func validateCalDAVConfig(config models.CalDAVConfig) error {
+	erros := &ValidationError{}
+
+	if config.Enabled && config.EventsURL == "" {
+		erros.Required("events_url", "endereço da coleção de eventos")
+	}
+	for field, value := range map[string]string{"events_url": config.EventsURL, "tasks_url": config.TasksURL} {
+		if value == "" {
+			continue
+		}
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			erros.Invalid(field, CodeInvalid, "URL (http:// ou https://)")
+		}
+	}
+
+	if !oneOf(config.ConflictPolicy, conflictPolicies) {
+		erros.Invalid("conflict_policy", CodeInvalidChoice, choiceMessage("política de conflito", conflictPolicies))
+	}
+
+	if config.IntervalMinutes < 0 {
+		erros.Invalid("interval_minutes", CodeOutOfRange, "intervalo não pode ser negativo")
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+	return nil
+}
+
+// collectionURL normaliza o endereço da coleção com a barra no fim, para os
+// itens serem resolvidos dentro dela
+func collectionURL(value string) string {
+	value = strings.TrimSpace(value)
+	if value != "" && !strings.HasSuffix(value, "/") {
+		value += "/"
+	}
+	return value
+}
+
+// forgetItems apaga os vínculos e os sync-tokens (as entidades ficam)
+func (s *CalDAVService) forgetItems(ctx context.Context) error {
+	if err := s.store.CalDAV().DeleteAllItems(ctx); err != nil {
+		return err
+	}
+	for _, key := range []string{settingCalDAVEventsToken, settingCalDAVTasksToken} {
+		if err := s.settings.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// SINCRONIZAÇÃO AUTOMÁTICA
+// ═══════════════════════════════════════════════════════════
+
+// Start inicia a sincronização periódica. O intervalo é relido a cada
+// minuto, então mudanças na configuração valem sem reiniciar.
+func (s *CalDAVService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(ctx)
+	}()
+}
+
+// Stop para a sincronização periódica e espera a que estiver rodando
+func (s *CalDAVService) Stop() {
+	s.mu.Lock()
+	if s.cancel == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.cancel()
+	s.cancel = nil
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *CalDAVService) loop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			config, err := s.loadConfig(ctx)
+			if err != nil || !config.Enabled || config.IntervalMinutes == 0 {
+				continue
+			}
+			if now.Sub(last) < time.Duration(config.IntervalMinutes)*time.Minute {
+				continue
+			}
+			last = now
+
+			if _, err := s.SyncNow(ctx); err != nil && !errors.Is(err, ErrSyncInProgress) && ctx.Err() == nil {
+				slog.Warn("sincronização CalDAV falhou", "err", err)
+			}
+		}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// SINCRONIZAÇÃO
+// ═══════════════════════════════════════════════════════════
+
+// caldavItem é o vínculo entre uma entidade local e um item do servidor
+type caldavItem struct {
+	id            int
+	kind          string
+	entityID      int
+	uid           string
+	href          string
+	etag          string
+	syncedVersion time.Time
+}
+
+// caldavLocal é um evento ou tarefa local, pronto para ser enviado
+type caldavLocal struct {
+	updatedAt time.Time
+	encode    func(uid string) string
+}
+
+type entityKey struct {
+	kind string
+	id   int
+}
+
+// caldavRun guarda o estado de uma sincronização
+type caldavRun struct {
+	client *caldav.Client
+	policy string
+	log    models.CalDAVSyncLog
+	errors []string
+}
+
+func (r *caldavRun) fail(what string, err error) {
+	r.log.Failed++
+	r.errors = append(r.errors, what+": "+err.Error())
+}
+
+// SyncNow sincroniza agora e grava o resultado no log. Erros de um item não
+// interrompem os outros: vão para Failed e Error no log.
+func (s *CalDAVService) SyncNow(ctx context.Context) (_ *models.CalDAVSyncLog, err error) {
+	defer logging.Track("CalDAVService.SyncNow", &err)()
+
+	if !s.syncing.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer s.syncing.Unlock()
+
+	config, err := s.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if config.EventsURL == "" {
+		erros := &ValidationError{}
+		erros.Required("events_url", "endereço da coleção de eventos")
+		return nil, erros
+	}
+
+	run := &caldavRun{
+		client: caldav.NewClient(config.Username, config.Password),
+		policy: config.ConflictPolicy,
+		log:    models.CalDAVSyncLog{StartedAt: time.Now().UTC().Truncate(time.Second)},
+	}
+
+	// Com a mesma coleção para os dois, eventos e tarefas vão numa passada só
+	type collection struct {
+		url      string
+		kinds    []string
+		tokenKey string
+	}
+	collections := []collection{{config.EventsURL, []string{"event", "task"}, settingCalDAVEventsToken}}
+	if config.TasksURL != "" && config.TasksURL != config.EventsURL {
+		collections = []collection{
+			{config.EventsURL, []string{"event"}, settingCalDAVEventsToken},
+			{config.TasksURL, []string{"task"}, settingCalDAVTasksToken},
+		}
+	}
+
+	var syncErr error
+	for _, c := range collections {
+		if syncErr = s.syncCollection(ctx, run, c.url, c.kinds, c.tokenKey); syncErr != nil {
+			break
+		}
+	}
+
+	finished := time.Now().UTC().Truncate(time.Second)
+	run.log.FinishedAt = &finished
+	if syncErr != nil {
+		run.errors = append([]string{syncErr.Error()}, run.errors...)
+	}
+	run.log.Error = strings.Join(run.errors, "\n")
+
+	if err := s.saveLog(ctx, &run.log); err != nil {
+		slog.Warn("erro ao gravar log da sincronização CalDAV", "err", err)
+	}
+
+	if syncErr != nil {
+		return &run.log, syncErr
+	}
+
+	s.bus.Publish(events.CalDAVSynced, run.log.ID, run.log)
+	return &run.log, nil
+}
+
+// syncCollection sincroniza uma coleção em duas passadas: primeiro o que
+// mudou no servidor (só se o sync-token mudou), depois o que mudou aqui
+func (s *CalDAVService) syncCollection(ctx context.Context, run *caldavRun, collection string, kinds []string, tokenKey string) error {
+	items, err := s.loadItems(ctx, kinds)
+	if err != nil {
+		return err
+	}
+	locals, err := s.loadLocals(ctx, kinds)
+	if err != nil {
+		return err
+	}
+
+	byHref := make(map[string]*caldavItem, len(items))
+	byEntity := make(map[entityKey]*caldavItem, len(items))
+	for _, item := range items {
+		byHref[item.href] = item
+		byEntity[entityKey{item.kind, item.entityID}] = item
+	}
+
+	state, err := run.client.CollectionState(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("%s: %w", collection, err)
+	}
+	saved, err := s.settings.Get(ctx, tokenKey, "")
+	if err != nil {
+		return err
+	}
+
+	// Entidades já resolvidas na passada do servidor
+	handled := make(map[entityKey]bool)
+
+	if state == "" || state != saved {
+		remote, err := run.client.List(ctx, collection)
+		if err != nil {
+			return fmt.Errorf("%s: %w", collection, err)
+		}
+
+		seen := make(map[string]bool, len(remote))
+		for _, resource := range remote {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			seen[resource.Href] = true
+
+			item, known := byHref[resource.Href]
+			if !known {
+				s.pullNew(ctx, run, resource, kinds)
+				continue
+			}
+			if resource.ETag != "" && resource.ETag == item.etag {
+				continue
+			}
+
+			key := entityKey{item.kind, item.entityID}
+			handled[key] = true
+			s.pullChanged(ctx, run, item, locals[key])
+		}
+
+		for href, item := range byHref {
+			if seen[href] {
+				continue
+			}
+			key := entityKey{item.kind, item.entityID}
+			handled[key] = true
+			s.remoteDeleted(ctx, run, item, locals[key])
+		}
+	}
+
+	for key, local := range locals {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if handled[key] {
+			continue
+		}
+
+		item, ok := byEntity[key]
+		switch {
+		case !ok:
+			s.pushNew(ctx, run, collection, key, local)
+		case !local.updatedAt.Equal(item.syncedVersion):
+			s.pushChanged(ctx, run, item, local)
+		}
+	}
+
+	for key, item := range byEntity {
+		if _, exists := locals[key]; exists || handled[key] {
+			continue
+		}
+		s.localDeleted(ctx, run, item)
+	}
+
+	if state != "" {
+		return s.settings.Set(ctx, tokenKey, state)
+	}
+	return nil
+}
+
+// pullNew cria aqui um item que só existe no servidor
+func (s *CalDAVService) pullNew(ctx context.Context, run *caldavRun, resource caldav.Resource, kinds []string) {
+	object, etag, err := s.fetch(ctx, run, resource.Href)
+	if err != nil {
+		run.fail(resource.Href, err)
+		return
+	}
+	if !oneOf(object.Kind, kinds) {
+		return
+	}
+	if etag == "" {
+		etag = resource.ETag
+	}
+
+	id, err := s.createLocal(ctx, object)
+	if err != nil {
+		run.fail(resource.Href, err)
+		return
+	}
+
+	item := &caldavItem{kind: object.Kind, entityID: id, uid: object.UID, href: resource.Href, etag: etag}
+	if err := s.markSynced(ctx, item); err != nil {
+		run.fail(resource.Href, err)
+		return
+	}
+	run.log.Pulled++
+}
+
+// pullChanged trata um item alterado no servidor. Se também mudou (ou foi
+// apagado) aqui, é um conflito e a política decide.
+func (s *CalDAVService) pullChanged(ctx context.Context, run *caldavRun, item *caldavItem, local *caldavLocal) {
+	object, etag, err := s.fetch(ctx, run, item.href)
+	if errors.Is(err, caldav.ErrNotFound) {
+		s.remoteDeleted(ctx, run, item, local)
+		return
+	}
+	if err != nil {
+		run.fail(item.href, err)
+		return
+	}
+
+	if local == nil {
+		// Apagado aqui, alterado lá: a alteração vence a exclusão, salvo
+		// com "local vence"
+		run.log.Conflicts++
+		if run.policy == ConflictLocalWins {
+			if err := run.client.Delete(ctx, item.href, ""); err != nil {
+				run.fail(item.href, err)
+				return
+			}
+			s.dropItem(ctx, run, item)
+			run.log.Deleted++
+			return
+		}
+
+		id, err := s.createLocal(ctx, object)
+		if err != nil {
+			run.fail(item.href, err)
+			return
+		}
+		item.entityID, item.etag = id, etag
+		if err := s.markSynced(ctx, item); err != nil {
+			run.fail(item.href, err)
+			return
+		}
+		run.log.Pulled++
+		return
+	}
+
+	if !local.updatedAt.Equal(item.syncedVersion) {
+		run.log.Conflicts++
+		if !s.remoteWins(run.policy, object.LastModified, local.updatedAt) {
+			s.push(ctx, run, item, local, true)
+			return
+		}
+	}
+
+	if err := s.updateLocal(ctx, item.entityID, object); err != nil {
+		run.fail(item.href, err)
+		return
+	}
+	item.etag = etag
+	if err := s.markSynced(ctx, item); err != nil {
+		run.fail(item.href, err)
+		return
+	}
+	run.log.Pulled++
+}
+
+// remoteDeleted trata um item que sumiu do servidor: a entidade local é
+// apagada, a não ser que tenha mudado aqui e a política não seja "servidor
+// vence" (aí ela é enviada de novo)
+func (s *CalDAVService) remoteDeleted(ctx context.Context, run *caldavRun, item *caldavItem, local *caldavLocal) {
+	if local == nil {
+		s.dropItem(ctx, run, item)
+		return
+	}
+
+	if !local.updatedAt.Equal(item.syncedVersion) {
+		run.log.Conflicts++
+		if run.policy != ConflictServerWins {
+			item.etag = ""
+			s.push(ctx, run, item, local, false)
+			return
+		}
+	}
+
+	if err := s.deleteLocal(ctx, item); err != nil {
+		run.fail(item.href, err)
+		return
+	}
+	s.dropItem(ctx, run, item)
+	run.log.Deleted++
+}
+
+// pushNew envia uma entidade que ainda não está no servidor
+func (s *CalDAVService) pushNew(ctx context.Context, run *caldavRun, collection string, key entityKey, local *caldavLocal) {
+	uid, err := newCalDAVUID()
+	if err != nil {
+		run.fail(key.kind, err)
+		return
+	}
+
+	base, err := url.Parse(collection)
+	if err != nil {
+		run.fail(key.kind, err)
+		return
+	}
+	href := base.ResolveReference(&url.URL{Path: uid + ".ics"}).String()
+
+	item := &caldavItem{kind: key.kind, entityID: key.id, uid: uid, href: href}
+	s.push(ctx, run, item, local, false)
+}
+
+// pushChanged envia uma alteração local. Se o servidor recusar porque o item
+// mudou lá depois da listagem, é um conflito.
+func (s *CalDAVService) pushChanged(ctx context.Context, run *caldavRun, item *caldavItem, local *caldavLocal) {
+	etag, err := run.client.Put(ctx, item.href, local.encode(item.uid), item.etag, false)
+	if errors.Is(err, caldav.ErrPreconditionFailed) {
+		s.pullChanged(ctx, run, item, local)
+		return
+	}
+	if errors.Is(err, caldav.ErrNotFound) {
+		s.remoteDeleted(ctx, run, item, local)
+		return
+	}
+	if err != nil {
+		run.fail(item.href, err)
+		return
+	}
+
+	s.pushed(ctx, run, item, local, etag)
+}
+
+// push grava a versão local no servidor. force ignora o ETag (a versão do
+// servidor é descartada).
+func (s *CalDAVService) push(ctx context.Context, run *caldavRun, item *caldavItem, local *caldavLocal, force bool) {
+	etag, err := run.client.Put(ctx, item.href, local.encode(item.uid), item.etag, force)
+	if err != nil {
+		run.fail(item.href, err)
+		return
+	}
+	s.pushed(ctx, run, item, local, etag)
+}
+
+func (s *CalDAVService) pushed(ctx context.Context, run *caldavRun, item *caldavItem, local *caldavLocal, etag string) {
+	item.etag = etag
+	item.syncedVersion = local.updatedAt
+	if err := s.saveItem(ctx, item); err != nil {
+		run.fail(item.href, err)
+		return
+	}
+	run.log.Pushed++
+}
+
+// localDeleted apaga no servidor um item apagado aqui. Se ele mudou lá
+// desde a última sincronização, é um conflito: a alteração vence a
+// exclusão, salvo com "local vence".
+func (s *CalDAVService) localDeleted(ctx context.Context, run *caldavRun, item *caldavItem) {
+	err := run.client.Delete(ctx, item.href, item.etag)
+	if errors.Is(err, caldav.ErrPreconditionFailed) {
+		s.pullChanged(ctx, run, item, nil)
+		return
+	}
+	if err != nil {
+		run.fail(item.href, err)
+		return
+	}
+
+	s.dropItem(ctx, run, item)
+	run.log.Deleted++
+}
+
+// remoteWins aplica a política a um item alterado dos dois lados
+func (s *CalDAVService) remoteWins(policy string, remoteModified, localModified time.Time) bool {
+	switch policy {
+	case ConflictLocalWins:
+		return false
+	case ConflictNewestWins:
+		return !remoteModified.IsZero() && remoteModified.After(localModified)
+	default:
+		return true
+	}
+}
+
+// fetch baixa e converte um item do servidor
+func (s *CalDAVService) fetch(ctx context.Context, run *caldavRun, href string) (*caldav.Object, string, error) {
+	data, etag, err := run.client.Get(ctx, href)
+	if err != nil {
+		return nil, "", err
+	}
+
+	object, err := caldav.Decode(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return object, etag, nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// ENTIDADES LOCAIS
+// ═══════════════════════════════════════════════════════════
+
+// loadLocals lista os eventos e tarefas que entram na sincronização
+func (s *CalDAVService) loadLocals(ctx context.Context, kinds []string) (map[entityKey]*caldavLocal, error) {
+	locals := make(map[entityKey]*caldavLocal)
+
+	if oneOf("event", kinds) {
+		list, err := s.store.Events().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range list {
+			event := event
+			locals[entityKey{"event", event.ID}] = &caldavLocal{
+				updatedAt: event.UpdatedAt,
+				encode:    func(uid string) string { return caldav.EncodeEvent(uid, event) },
+			}
+		}
+	}
+
+	if oneOf("task", kinds) {
+		list, err := s.store.Tasks().List(ctx, models.TaskFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range list {
+			task := task
+			locals[entityKey{"task", task.ID}] = &caldavLocal{
+				updatedAt: task.UpdatedAt,
+				encode:    func(uid string) string { return caldav.EncodeTask(uid, task) },
+			}
+		}
+	}
+
+	return locals, nil
+}
+
+// createLocal cria o evento ou a tarefa pelos serviços, para os avisos e as
+// validações valerem como numa criação pelo app
+func (s *CalDAVService) createLocal(ctx context.Context, object *caldav.Object) (int, error) {
+	if object.Kind == "event" {
+		saved, err := s.eventService.CreateEvent(ctx, object.Event)
+		if err != nil {
+			return 0, err
+		}
+		return saved.ID, nil
+	}
+
+	id, err := s.taskService.CreateTask(ctx, object.Task)
+	return int(id), err
+}
+
+// updateLocal aplica a versão do servidor, mantendo o que o iCalendar não
+// carrega (categoria e nota da tarefa)
+func (s *CalDAVService) updateLocal(ctx context.Context, id int, object *caldav.Object) error {
+	if object.Kind == "event" {
+		event := object.Event
+		event.ID = id
+		_, err := s.eventService.UpdateEvent(ctx, event)
+		return err
+	}
+
+	current, err := s.taskService.GetTaskByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	task := object.Task
+	task.ID = id
+	task.CategoryID = current.CategoryID
+	task.NoteID = current.NoteID
+	return s.taskService.UpdateTask(ctx, task)
+}
+
+func (s *CalDAVService) deleteLocal(ctx context.Context, item *caldavItem) error {
+	var err error
+	if item.kind == "event" {
+		err = s.eventService.DeleteEvent(ctx, item.entityID)
+	} else {
+		err = s.taskService.DeleteTask(ctx, item.entityID)
+	}
+
+	var notFoundErr *NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil
+	}
+	return err
+}
+
+// localVersion lê o updated_at atual da entidade
+func (s *CalDAVService) localVersion(ctx context.Context, item *caldavItem) (time.Time, error) {
+	if item.kind == "event" {
+		event, err := s.store.Events().GetByID(ctx, item.entityID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return event.UpdatedAt, nil
+	}
+
+	task, err := s.store.Tasks().GetByID(ctx, item.entityID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return task.UpdatedAt, nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// VÍNCULOS E LOG
+// ═══════════════════════════════════════════════════════════
+
+func (s *CalDAVService) loadItems(ctx context.Context, kinds []string) ([]*caldavItem, error) {
+	list, err := s.store.CalDAV().ListItems(ctx, kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*caldavItem, len(list))
+	for i, item := range list {
+		items[i] = &caldavItem{
+			id:            item.ID,
+			kind:          item.Kind,
+			entityID:      item.EntityID,
+			uid:           item.UID,
+			href:          item.Href,
+			etag:          item.ETag,
+			syncedVersion: item.SyncedVersion,
+		}
+	}
+
+	return items, nil
+}
+
+// markSynced grava o vínculo com o updated_at atual da entidade
+func (s *CalDAVService) markSynced(ctx context.Context, item *caldavItem) error {
+	version, err := s.localVersion(ctx, item)
+	if err != nil {
+		return err
+	}
+	item.syncedVersion = version
+	return s.saveItem(ctx, item)
+}
+
+func (s *CalDAVService) saveItem(ctx context.Context, item *caldavItem) error {
+	return s.store.CalDAV().SaveItem(ctx, repository.CalDAVItem{
+		Kind:          item.kind,
+		EntityID:      item.entityID,
+		UID:           item.uid,
+		Href:          item.href,
+		ETag:          item.etag,
+		SyncedVersion: item.syncedVersion,
+	})
+}
+
+func (s *CalDAVService) dropItem(ctx context.Context, run *caldavRun, item *caldavItem) {
+	if err := s.store.CalDAV().DeleteItem(ctx, item.href); err != nil {
+		run.fail(item.href, err)
+	}
+}
+
+func (s *CalDAVService) saveLog(ctx context.Context, log *models.CalDAVSyncLog) error {
+	return s.store.CalDAV().SaveLog(context.WithoutCancel(ctx), log, caldavSyncLogSize)
+}
+
+// GetSyncLog lista as últimas sincronizações, da mais recente para a mais antiga
+func (s *CalDAVService) GetSyncLog(ctx context.Context, limit int) (_ []models.CalDAVSyncLog, err error) {
+	defer logging.Track("CalDAVService.GetSyncLog", &err)()
+
+	if limit <= 0 || limit > caldavSyncLogSize {
+		limit = caldavSyncLogSize
+	}
+
+	logs, err := s.store.CalDAV().ListLogs(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if logs == nil {
+		logs = []models.CalDAVSyncLog{}
+	}
+
+	return logs, nil
+}
+
+// newCalDAVUID gera o UID de um item criado aqui
+func newCalDAVUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b) + "@personal-cockpit", nil
+}
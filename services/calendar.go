@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"personal-cockpit/database"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// SettingWeekStart é a chave da configuração com o primeiro dia da semana
+// nas grades do calendário ("sunday", "monday"...)
+const SettingWeekStart = "week_start"
+
+// DefaultWeekStart é o primeiro dia da semana quando nada foi configurado
+const DefaultWeekStart = "sunday"
+
+var (
+	calendarKinds = []string{"month", "week", "day"}
+	weekdayNames  = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+)
+
+// ParseWeekStart converte o nome do dia ("monday") no time.Weekday
+func ParseWeekStart(name string) (time.Weekday, bool) {
+	for i, weekday := range weekdayNames {
+		if name == weekday {
+			return time.Weekday(i), true
+		}
+	}
+	return time.Sunday, false
+}
+
+// ValidateWeekStart confere o valor da configuração week_start
+func ValidateWeekStart(name string) error {
+	if _, ok := ParseWeekStart(name); !ok {
+		erros := &ValidationError{}
+		erros.Invalid("value", CodeInvalidChoice, choiceMessage("primeiro dia da semana", weekdayNames))
+		return erros
+	}
+	return nil
+}
+
+// GetCalendarView monta a grade do mês, da semana ou do dia que contém
+// anchor (AAAA-MM-DD; vazio é hoje). A grade do mês vai do início da semana
+// do dia 1 ao fim da semana do último dia. Eventos de dia inteiro e eventos
+// de 24 horas ou mais vão para as faixas de dia inteiro; os demais são
+// recortados em cada dia que ocupam.
+func (s *EventService) GetCalendarView(ctx context.Context, kind, anchor, weekStart string) (_ *models.CalendarView, err error) {
+	defer logging.Track("EventService.GetCalendarView", &err)()
+
+	erros := &ValidationError{}
+	if !oneOf(kind, calendarKinds) {
+		erros.Invalid("kind", CodeInvalidChoice, choiceMessage("visão", calendarKinds))
+	}
+
+	day := timezone.StartOfDay(time.Now())
+	if anchor != "" {
+		parsed, err := timezone.ParseDate(anchor)
+		if err != nil {
+			erros.Invalid("anchor", CodeInvalid, "data (AAAA-MM-DD)")
+		}
+		day = parsed
+	}
+
+	if erros.HasErrors() {
+		return nil, erros
+	}
+
+	firstWeekday, ok := ParseWeekStart(weekStart)
+	if !ok {
+		weekStart = DefaultWeekStart
+		firstWeekday, _ = ParseWeekStart(weekStart)
+	}
+
+	first, count, rowSize := calendarRange(kind, day, firstWeekday)
+	end := first.AddDate(0, 0, count)
+
+	events, err := s.GetEventsByDateRange(ctx, first, end)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.CalendarView{
+		Kind:      kind,
+		Anchor:    database.FormatDate(day),
+		Start:     database.FormatDate(first),
+		End:       database.FormatDate(end.AddDate(0, 0, -1)),
+		WeekStart: weekStart,
+		Timezone:  timezone.Name(),
+		Days:      make([]models.CalendarDay, count),
+	}
+
+	today := database.FormatDate(timezone.In(time.Now()))
+	starts := make([]time.Time, count)
+	for i := range view.Days {
+		starts[i] = first.AddDate(0, 0, i)
+		date := database.FormatDate(starts[i])
+		view.Days[i] = models.CalendarDay{
+			Date:    date,
+			InRange: kind != "month" || starts[i].Month() == day.Month(),
+			IsToday: date == today,
+			AllDay:  []models.CalendarAllDay{},
+			Events:  []models.CalendarSegment{},
+		}
+	}
+
+	var spanning []daySpan
+	for _, event := range events {
+		if span, ok := allDaySpan(event); ok {
+			spanning = append(spanning, span)
+			continue
+		}
+		addSegments(view.Days, starts, event)
+	}
+
+	view.Lanes = assignLanes(view.Days, starts, spanning, rowSize)
+
+	return view, nil
+}
+
+// calendarRange retorna o primeiro dia da grade, o número de dias e quantos
+// dias formam uma linha (as faixas de dia inteiro são calculadas por linha)
+func calendarRange(kind string, day time.Time, weekStart time.Weekday) (time.Time, int, int) {
+	startOfWeek := func(t time.Time) time.Time {
+		offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+		return t.AddDate(0, 0, -offset)
+	}
+
+	switch kind {
+	case "day":
+		return day, 1, 1
+	case "week":
+		return startOfWeek(day), 7, 7
+	}
+
+	firstOfMonth := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	lastOfMonth := firstOfMonth.AddDate(0, 1, -1)
+
+	first := startOfWeek(firstOfMonth)
+	last := startOfWeek(lastOfMonth).AddDate(0, 0, 6)
+
+	// Conta pelas datas, não pelas horas: dias com mudança de horário não têm 24h
+	count := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		count++
+	}
+
+	return first, count, 7
+}
+
+// daySpan é um evento da faixa de dia inteiro com os dias que ocupa:
+// de start até o dia anterior a end
+type daySpan struct {
+	event      models.Event
+	start, end time.Time
+}
+
+// allDaySpan diz se o evento vai para a faixa de dia inteiro e quais dias
+// ele ocupa no fuso do usuário
+func allDaySpan(event models.Event) (daySpan, bool) {
+	if event.AllDay {
+		start := timezone.Floating(event.StartDate)
+		end := timezone.Floating(event.EndDate)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		return daySpan{event, start, end}, true
+	}
+
+	if event.EndDate.Sub(event.StartDate) < 24*time.Hour {
+		return daySpan{}, false
+	}
+
+	start := timezone.StartOfDay(event.StartDate)
+	end := timezone.StartOfDay(event.EndDate.Add(-time.Nanosecond)).AddDate(0, 0, 1)
+	return daySpan{event, start, end}, true
+}
+
+// addSegments recorta um evento com hora em cada dia da grade que ele ocupa
+func addSegments(days []models.CalendarDay, starts []time.Time, event models.Event) {
+	start, end := timezone.In(event.StartDate), timezone.In(event.EndDate)
+
+	for i := range days {
+		dayStart := starts[i]
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		if !start.Before(dayEnd) || end.Before(dayStart) {
+			continue
+		}
+		// Terminar exatamente à meia-noite não ocupa o dia seguinte
+		if end.Equal(dayStart) && !start.Equal(end) {
+			continue
+		}
+
+		segment := models.CalendarSegment{
+			Event:           event,
+			Start:           start,
+			End:             end,
+			ContinuesBefore: start.Before(dayStart),
+			ContinuesAfter:  end.After(dayEnd),
+		}
+		if segment.ContinuesBefore {
+			segment.Start = dayStart
+		}
+		if segment.ContinuesAfter {
+			segment.End = dayEnd
+		}
+
+		days[i].Events = append(days[i].Events, segment)
+	}
+}
+
+// assignLanes distribui os eventos de dia inteiro em faixas, linha a linha
+// da grade: cada evento fica na primeira faixa livre em todos os seus dias
+// daquela linha. Eventos que começam antes e os mais longos escolhem
+// primeiro. Retorna o maior número de faixas usado numa linha.
+func assignLanes(days []models.CalendarDay, starts []time.Time, spans []daySpan, rowSize int) int {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if !spans[i].start.Equal(spans[j].start) {
+			return spans[i].start.Before(spans[j].start)
+		}
+		return spans[i].end.After(spans[j].end)
+	})
+
+	maxLanes := 0
+	for row := 0; row < len(days); row += rowSize {
+		used := make([][]bool, rowSize)
+
+		for _, span := range spans {
+			var covered []int
+			for i := row; i < row+rowSize && i < len(days); i++ {
+				if !starts[i].Before(span.start) && starts[i].Before(span.end) {
+					covered = append(covered, i)
+				}
+			}
+			if len(covered) == 0 {
+				continue
+			}
+
+			lane := 0
+			for !laneFree(used, covered, row, lane) {
+				lane++
+			}
+
+			for _, i := range covered {
+				for len(used[i-row]) <= lane {
+					used[i-row] = append(used[i-row], false)
+				}
+				used[i-row][lane] = true
+
+				days[i].AllDay = append(days[i].AllDay, models.CalendarAllDay{
+					Event:           span.event,
+					Lane:            lane,
+					ContinuesBefore: starts[i].After(span.start),
+					ContinuesAfter:  starts[i].AddDate(0, 0, 1).Before(span.end),
+				})
+			}
+
+			if lane+1 > maxLanes {
+				maxLanes = lane + 1
+			}
+		}
+	}
+
+	for i := range days {
+		sort.SliceStable(days[i].AllDay, func(a, b int) bool {
+			return days[i].AllDay[a].Lane < days[i].AllDay[b].Lane
+		})
+	}
+
+	return maxLanes
+}
+
+func laneFree(used [][]bool, covered []int, row, lane int) bool {
+	for _, i := range covered {
+		if lane < len(used[i-row]) && used[i-row][lane] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,223 +1,221 @@
 package services
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"strings"
 
+	"personal-cockpit/events"
+	"personal-cockpit/logging"
 	"personal-cockpit/models"
+	"personal-cockpit/repository"
 )
 
 // CategoryService gerencia operações de categorias
 type CategoryService struct {
-	db *sql.DB
+	store repository.UnitOfWork
+	bus   *events.Bus
 }
 
 // NewCategoryService cria novo serviço de categorias
-func NewCategoryService(db *sql.DB) *CategoryService {
-	return &CategoryService{db: db}
+func NewCategoryService(db *sql.DB, bus *events.Bus) *CategoryService {
+	return NewCategoryServiceWithStore(repository.NewSQLite(db), bus)
+}
+
+// NewCategoryServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewCategoryServiceWithStore(store repository.UnitOfWork, bus *events.Bus) *CategoryService {
+	return &CategoryService{store: store, bus: bus}
 }
 
 // CreateCategory cria uma nova categoria
-func (s *CategoryService) CreateCategory(category models.Category) (int64, error) {
-	// Validações
-	var erros []string
+func (s *CategoryService) CreateCategory(ctx context.Context, category models.Category) (_ int64, err error) {
+	defer logging.Track("CategoryService.CreateCategory", &err)()
 
-	if category.Name == "" {
-		erros = append(erros, "nome")
+	if category.Type == "" {
+		category.Type = "general"
 	}
 
-	if len(erros) > 0 {
-		mensagem := "Campos obrigatórios:\n- " + strings.Join(erros, "\n- ")
-		return 0, fmt.Errorf(mensagem)
+	// Validações
+	if err := validateCategory(category); err != nil {
+		return 0, err
 	}
 
-	// Query SQL
-	query := `
-		INSERT INTO categories (name, color, type)
-		VALUES (?, ?, ?)
-	`
-
-	result, err := s.db.Exec(
-		query,
-		category.Name,
-		category.Color,
-		category.Type,
-	)
-
-	if err != nil {
-		return 0, fmt.Errorf("erro ao criar categoria: %w", err)
+	id, err := s.store.Categories().Create(ctx, category)
+	if isUniqueViolation(err) {
+		return 0, duplicateName("category", "uma categoria")
 	}
-
-	id, err := result.LastInsertId()
 	if err != nil {
-		return 0, fmt.Errorf("erro ao obter ID: %w", err)
+		return 0, err
 	}
 
+	publishEntity(ctx, s.bus, events.CategoryCreated, int(id), s.GetCategoryByID)
+
 	return id, nil
 }
 
 // GetAllCategories retorna todas as categorias
-func (s *CategoryService) GetAllCategories() ([]models.Category, error) {
-	query := `
-		SELECT id, name, color, type, created_at
-		FROM categories
-		ORDER BY name ASC
-	`
-
-	rows, err := s.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar categorias: %w", err)
-	}
-	defer rows.Close()
-
-	var categories []models.Category
-
-	for rows.Next() {
-		var category models.Category
-		err := rows.Scan(
-			&category.ID,
-			&category.Name,
-			&category.Color,
-			&category.Type,
-			&category.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler categoria: %w", err)
-		}
+func (s *CategoryService) GetAllCategories(ctx context.Context) (_ []models.Category, err error) {
+	defer logging.Track("CategoryService.GetAllCategories", &err)()
 
-		categories = append(categories, category)
-	}
-
-	return categories, nil
+	return s.store.Categories().List(ctx, "")
 }
 
 // GetCategoryByID busca categoria por ID
-func (s *CategoryService) GetCategoryByID(id int) (*models.Category, error) {
-	query := `
-		SELECT id, name, color, type, created_at
-		FROM categories
-		WHERE id = ?
-	`
-
-	var category models.Category
-	err := s.db.QueryRow(query, id).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Color,
-		&category.Type,
-		&category.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("categoria não encontrada")
-	}
+func (s *CategoryService) GetCategoryByID(ctx context.Context, id int) (_ *models.Category, err error) {
+	defer logging.Track("CategoryService.GetCategoryByID", &err)()
+
+	category, err := s.store.Categories().GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar categoria: %w", err)
+		return nil, fromRepository(err, "category", id)
 	}
 
-	return &category, nil
+	return category, nil
 }
 
 // UpdateCategory atualiza uma categoria
-func (s *CategoryService) UpdateCategory(category models.Category) error {
+func (s *CategoryService) UpdateCategory(ctx context.Context, category models.Category) (err error) {
+	defer logging.Track("CategoryService.UpdateCategory", &err)()
+
 	if category.ID == 0 {
-		return fmt.Errorf("ID da categoria é obrigatório")
+		return requiredID("ID da categoria")
 	}
 
-	query := `
-		UPDATE categories 
-		SET name = ?, color = ?, type = ?
-		WHERE id = ?
-	`
-
-	result, err := s.db.Exec(
-		query,
-		category.Name,
-		category.Color,
-		category.Type,
-		category.ID,
-	)
-
-	if err != nil {
-		return fmt.Errorf("erro ao atualizar categoria: %w", err)
+	if err := validateCategory(category); err != nil {
+		return err
 	}
 
-	rows, err := result.RowsAffected()
+	err = s.store.Categories().Update(ctx, category)
+	if isUniqueViolation(err) {
+		return duplicateName("category", "uma categoria")
+	}
 	if err != nil {
-		return err
+		return fromRepository(err, "category", category.ID)
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("categoria não encontrada")
-	}
+	publishEntity(ctx, s.bus, events.CategoryUpdated, category.ID, s.GetCategoryByID)
 
 	return nil
 }
 
 // DeleteCategory deleta uma categoria
-func (s *CategoryService) DeleteCategory(id int) error {
-	query := "DELETE FROM categories WHERE id = ?"
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int) (err error) {
+	defer logging.Track("CategoryService.DeleteCategory", &err)()
+
+	if err := s.store.Categories().Delete(ctx, id); err != nil {
+		return fromRepository(err, "category", id)
+	}
+
+	s.bus.Publish(events.CategoryDeleted, id, nil)
+
+	return nil
+}
+
+// GetCategoriesByType busca categorias por tipo
+func (s *CategoryService) GetCategoriesByType(ctx context.Context, categoryType string) (_ []models.Category, err error) {
+	defer logging.Track("CategoryService.GetCategoriesByType", &err)()
+
+	return s.store.Categories().List(ctx, categoryType)
+}
+
+// GetTaskCategories retorna apenas categorias de tarefas
+func (s *CategoryService) GetTaskCategories(ctx context.Context) (_ []models.Category, err error) {
+	defer logging.Track("CategoryService.GetTaskCategories", &err)()
 
-	result, err := s.db.Exec(query, id)
+	return s.GetCategoriesByType(ctx, "task")
+}
+
+// GetNoteCategories retorna apenas categorias de notas
+func (s *CategoryService) GetNoteCategories(ctx context.Context) (_ []models.Category, err error) {
+	defer logging.Track("CategoryService.GetNoteCategories", &err)()
+
+	return s.GetCategoriesByType(ctx, "note")
+}
+
+// GetFinanceCategories retorna apenas categorias financeiras
+func (s *CategoryService) GetFinanceCategories(ctx context.Context) (_ []models.Category, err error) {
+	defer logging.Track("CategoryService.GetFinanceCategories", &err)()
+
+	return s.GetCategoriesByType(ctx, "finance")
+}
+
+// Cor das categorias criadas automaticamente (mesmo padrão do banco)
+const defaultCategoryColor = "#3b82f6"
+
+// FindOrCreateCategory procura a categoria pelo nome, sem diferenciar
+// maiúsculas, e a cria com o tipo se não existir. O nome é único entre todos
+// os tipos, então a de outro tipo com o mesmo nome é usada (a do tipo pedido
+// e as gerais têm preferência). Retorna o ID e se ela foi criada agora.
+func (s *CategoryService) FindOrCreateCategory(ctx context.Context, name, categoryType string) (_ int, _ bool, err error) {
+	defer logging.Track("CategoryService.FindOrCreateCategory", &err)()
+
+	name = strings.TrimSpace(name)
+
+	if id, ok, err := s.findCategoryByName(ctx, name, categoryType); err != nil || ok {
+		return id, false, err
+	}
+
+	id, err := s.CreateCategory(ctx, models.Category{Name: name, Color: defaultCategoryColor, Type: categoryType})
+	var conflict *ConflictError
+	if errors.As(err, &conflict) {
+		// Criada por outro processo (CLI, sincronização) entre a busca e o INSERT
+		if id, ok, findErr := s.findCategoryByName(ctx, name, categoryType); findErr == nil && ok {
+			return id, false, nil
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("erro ao deletar categoria: %w", err)
+		return 0, false, err
 	}
+	return int(id), true, nil
+}
 
-	rows, err := result.RowsAffected()
+// findCategoryByName busca a categoria com o nome em qualquer tipo,
+// preferindo a do tipo informado e depois as gerais
+func (s *CategoryService) findCategoryByName(ctx context.Context, name, categoryType string) (int, bool, error) {
+	categories, err := s.store.Categories().List(ctx, "")
 	if err != nil {
-		return err
+		return 0, false, err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("categoria não encontrada")
+	best, bestRank := 0, 0
+	for _, category := range categories {
+		if !strings.EqualFold(category.Name, name) {
+			continue
+		}
+		rank := 1
+		switch category.Type {
+		case categoryType:
+			rank = 3
+		case "general":
+			rank = 2
+		}
+		if rank > bestRank {
+			best, bestRank = category.ID, rank
+		}
 	}
 
-	return nil
+	return best, bestRank > 0, nil
 }
 
-// GetCategoriesByType busca categorias por tipo
-func (s *CategoryService) GetCategoriesByType(categoryType string) ([]models.Category, error) {
-	query := `
-		SELECT id, name, color, type, created_at
-		FROM categories
-		WHERE type = ?
-		ORDER BY name ASC
-	`
-
-	rows, err := s.db.Query(query, categoryType)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar categorias: %w", err)
-	}
-	defer rows.Close()
-
-	var categories []models.Category
-
-	for rows.Next() {
-		var category models.Category
-		err := rows.Scan(
-			&category.ID,
-			&category.Name,
-			&category.Color,
-			&category.Type,
-			&category.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler categoria: %w", err)
-		}
+// validateCategory confere os campos antes de gravar, com todos os erros de uma vez
+func validateCategory(category models.Category) error {
+	erros := &ValidationError{}
 
-		categories = append(categories, category)
+	if strings.TrimSpace(category.Name) == "" {
+		erros.Required("name", "nome")
 	}
 
-	return categories, nil
-}
+	if !oneOf(category.Type, categoryTypes) {
+		erros.Invalid("type", CodeInvalidChoice, choiceMessage("tipo", categoryTypes))
+	}
 
-// GetTaskCategories retorna apenas categorias de tarefas
-func (s *CategoryService) GetTaskCategories() ([]models.Category, error) {
-	return s.GetCategoriesByType("task")
-}
+	if !validColor(category.Color) {
+		erros.Invalid("color", CodeInvalidColor, "cor (formato #rrggbb)")
+	}
 
-// GetNoteCategories retorna apenas categorias de notas
-func (s *CategoryService) GetNoteCategories() ([]models.Category, error) {
-	return s.GetCategoriesByType("note")
+	if erros.HasErrors() {
+		return erros
+	}
+
+	return nil
 }
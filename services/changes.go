@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/models"
+)
+
+// Tempo de espera para juntar mudanças seguidas (ex.: lote de recorrências)
+// em um único aviso por entidade
+const changeDebounce = 150 * time.Millisecond
+
+// Intervalo do PRAGMA data_version que detecta escritas de outros processos
+const changePollInterval = 2 * time.Second
+
+// Acima disso o aviso externo vai sem IDs e a tela recarrega tudo
+const changeMaxIDs = 100
+
+// changeEntities mapeia a entidade do evento de domínio para o nome usado
+// no aviso ao frontend
+var changeEntities = map[string]string{
+	"task":        "tasks",
+	"note":        "notes",
+	"notebook":    "notebooks",
+	"event":       "events",
+	"category":    "categories",
+	"attachment":  "attachments",
+	"template":    "templates",
+	"journal":     "journal",
+	"account":     "accounts",
+	"transaction": "transactions",
+	"budget":      "budgets",
+	"recurring":   "recurring",
+}
+
+// changeTables são as tabelas observadas para mudanças externas. Nas que têm
+// updated_at dá para descobrir quais linhas mudaram.
+var changeTables = []struct {
+	entity     string
+	table      string
+	hasUpdated bool
+}{
+	{"tasks", "tasks", true},
+	{"notes", "notes", true},
+	{"notebooks", "notebooks", true},
+	{"events", "events", true},
+	{"categories", "categories", false},
+	{"attachments", "attachments", false},
+	{"templates", "templates", true},
+	{"journal", "journal_entries", true},
+	{"accounts", "accounts", false},
+	{"transactions", "transactions", true},
+	{"budgets", "budgets", false},
+	{"recurring", "recurring_transactions", false},
+}
+
+// tableSnapshot resume o estado de uma tabela para comparar entre leituras
+type tableSnapshot struct {
+	count      int
+	maxID      int
+	maxUpdated string
+}
+
+type pendingChange struct {
+	ids map[int]bool
+	all bool
+}
+
+// ChangeNotifier avisa o frontend quando os dados mudam, para as telas se
+// atualizarem sem recarregar. Mudanças feitas por este processo chegam pelo
+// barramento; as de outros processos (CLI, outro programa abrindo o
+// cockpit.db) são detectadas pelo PRAGMA data_version.
+type ChangeNotifier struct {
+	db   *sql.DB
+	bus  *events.Bus
+	emit func(name string, data interface{})
+
+	mu          sync.Mutex
+	pending     map[string]*pendingChange
+	flushTimer  *time.Timer
+	local       map[string]map[int]bool
+	unsubscribe func()
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewChangeNotifier cria o notificador. emit recebe o nome do evento
+// ("tasks:changed") e o models.ChangeNotice.
+func NewChangeNotifier(db *sql.DB, bus *events.Bus, emit func(name string, data interface{})) *ChangeNotifier {
+	return &ChangeNotifier{
+		db:      db,
+		bus:     bus,
+		emit:    emit,
+		pending: make(map[string]*pendingChange),
+		local:   make(map[string]map[int]bool),
+	}
+}
+
+// Start começa a ouvir o barramento e a observar o banco
+func (n *ChangeNotifier) Start() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.unsubscribe != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Conexão dedicada: o data_version só muda quando OUTRA conexão grava
+	conn, err := n.db.Conn(ctx)
+	if err != nil {
+		cancel()
+		return i18n.Errorf("erro ao abrir conexão de observação: %w", err)
+	}
+
+	n.cancel = cancel
+	n.unsubscribe = n.bus.Subscribe(n.handleEvent)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		defer conn.Close()
+		n.watch(ctx, conn)
+	}()
+
+	return nil
+}
+
+// Stop para o observador e descarta avisos ainda não enviados
+func (n *ChangeNotifier) Stop() {
+	n.mu.Lock()
+	if n.unsubscribe == nil {
+		n.mu.Unlock()
+		return
+	}
+	n.unsubscribe()
+	n.unsubscribe = nil
+	n.cancel()
+	if n.flushTimer != nil {
+		n.flushTimer.Stop()
+		n.flushTimer = nil
+	}
+	n.pending = make(map[string]*pendingChange)
+	n.mu.Unlock()
+
+	n.wg.Wait()
+}
+
+// ═══════════════════════════════════════════════════════════
+// MUDANÇAS DESTE PROCESSO
+// ═══════════════════════════════════════════════════════════
+
+func (n *ChangeNotifier) handleEvent(event events.Event) {
+	entity, ok := changeEntities[event.Type.Entity()]
+	if !ok || event.Type == events.EventStarting {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// O observador não deve repetir como externa uma mudança já avisada
+	if n.local[entity] == nil {
+		n.local[entity] = make(map[int]bool)
+	}
+	n.local[entity][event.EntityID] = true
+
+	change := n.pending[entity]
+	if change == nil {
+		change = &pendingChange{ids: make(map[int]bool)}
+		n.pending[entity] = change
+	}
+
+	// A importação publica o ID da conta, não dos lançamentos
+	if event.Type == events.TransactionsImported {
+		change.all = true
+	} else {
+		change.ids[event.EntityID] = true
+	}
+
+	if n.flushTimer == nil {
+		n.flushTimer = time.AfterFunc(changeDebounce, n.flush)
+	}
+}
+
+func (n *ChangeNotifier) flush() {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = make(map[string]*pendingChange)
+	n.flushTimer = nil
+	n.mu.Unlock()
+
+	for entity, change := range pending {
+		var ids []int
+		if !change.all {
+			ids = sortedIDs(change.ids)
+		}
+		n.notify(entity, ids, "app")
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// MUDANÇAS EXTERNAS
+// ═══════════════════════════════════════════════════════════
+
+func (n *ChangeNotifier) watch(ctx context.Context, conn *sql.Conn) {
+	version, err := dataVersion(ctx, conn)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("erro ao ler data_version", "err", err)
+		}
+		return
+	}
+
+	snapshots, err := takeSnapshots(ctx, conn)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("erro ao observar o banco", "err", err)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(changePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := dataVersion(ctx, conn)
+		if err != nil || current == version {
+			continue
+		}
+		version = current
+
+		// Pega as marcas antes de ler as tabelas: uma gravação local que
+		// termine no meio da leitura fica marcada para a próxima volta.
+		// No pior caso a tela recarrega uma vez a mais.
+		n.mu.Lock()
+		local := n.local
+		n.local = make(map[string]map[int]bool)
+		n.mu.Unlock()
+
+		next, err := takeSnapshots(ctx, conn)
+		if err != nil {
+			continue
+		}
+
+		for _, t := range changeTables {
+			before, after := snapshots[t.entity], next[t.entity]
+			if before == after {
+				continue
+			}
+
+			var ids []int
+			if t.hasUpdated && after.count >= before.count {
+				ids, _ = changedIDs(ctx, conn, t.table, before)
+			}
+
+			// Houve gravação local na mesma entidade: só avisa o que sobrar
+			// além dela. Sem como separar (exclusão, tabela sem updated_at),
+			// o aviso local já basta.
+			if seen := local[t.entity]; seen != nil {
+				ids = withoutIDs(ids, seen)
+				if len(ids) == 0 {
+					continue
+				}
+			}
+
+			n.notify(t.entity, ids, "external")
+		}
+
+		snapshots = next
+	}
+}
+
+func (n *ChangeNotifier) notify(entity string, ids []int, source string) {
+	if ids == nil {
+		ids = []int{}
+	}
+
+	n.emit(entity+":changed", models.ChangeNotice{
+		Entity: entity,
+		IDs:    ids,
+		Source: source,
+	})
+}
+
+func dataVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	var version int64
+	err := conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version)
+	return version, err
+}
+
+func takeSnapshots(ctx context.Context, conn *sql.Conn) (map[string]tableSnapshot, error) {
+	snapshots := make(map[string]tableSnapshot, len(changeTables))
+
+	for _, t := range changeTables {
+		updated := "''"
+		if t.hasUpdated {
+			updated = "COALESCE(CAST(MAX(updated_at) AS TEXT), '')"
+		}
+
+		query := "SELECT COUNT(*), COALESCE(MAX(id), 0), " + updated + " FROM " + t.table
+
+		var s tableSnapshot
+		if err := conn.QueryRowContext(ctx, query).Scan(&s.count, &s.maxID, &s.maxUpdated); err != nil {
+			return nil, i18n.Errorf("erro ao ler %s: %w", t.table, err)
+		}
+		snapshots[t.entity] = s
+	}
+
+	return snapshots, nil
+}
+
+// changedIDs busca as linhas alteradas desde a última leitura. Como updated_at
+// tem precisão de segundos, pode incluir linhas vizinhas do mesmo segundo.
+// Retorna nil (recarregar tudo) se forem muitas.
+func changedIDs(ctx context.Context, conn *sql.Conn, table string, before tableSnapshot) ([]int, error) {
+	query := "SELECT id FROM " + table + " WHERE id > ? OR CAST(updated_at AS TEXT) >= ? ORDER BY id LIMIT ?"
+
+	rows, err := conn.QueryContext(ctx, query, before.maxID, before.maxUpdated, changeMaxIDs+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) > changeMaxIDs {
+		return nil, nil
+	}
+
+	return ids, rows.Err()
+}
+
+func withoutIDs(ids []int, exclude map[int]bool) []int {
+	var kept []int
+	for _, id := range ids {
+		if !exclude[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+func sortedIDs(set map[int]bool) []int {
+	ids := make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
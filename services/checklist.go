@@ -0,0 +1,116 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// checklistLine reconhece itens de checklist em Markdown, como "- [ ] fazer X".
+// Itens já convertidos em tarefa carregam o marcador "^task-ID" no final da linha.
+var checklistLine = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.*?)(?:\s+\^task-(\d+))?\s*$`)
+
+// checklistItem representa um item de checklist encontrado no conteúdo de uma nota
+type checklistItem struct {
+	Line    int
+	Text    string
+	Checked bool
+	TaskID  int // 0 quando o item ainda não virou tarefa
+}
+
+// parseChecklist retorna todos os itens de checklist do conteúdo
+func parseChecklist(content string) []checklistItem {
+	var items []checklistItem
+
+	for i, line := range strings.Split(content, "\n") {
+		match := checklistLine.FindStringSubmatch(line)
+		if match == nil || strings.TrimSpace(match[4]) == "" {
+			continue
+		}
+
+		item := checklistItem{
+			Line:    i,
+			Text:    strings.TrimSpace(match[4]),
+			Checked: match[2] != " ",
+		}
+		if match[5] != "" {
+			item.TaskID, _ = strconv.Atoi(match[5])
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// linkChecklistLine anexa o marcador da tarefa à linha indicada, trocando o
+// que ela já tiver
+func linkChecklistLine(content string, line int, taskID int) string {
+	lines := strings.Split(content, "\n")
+	if line < 0 || line >= len(lines) {
+		return content
+	}
+
+	lines[line] = unmarkedLine(lines[line]) + " ^task-" + strconv.Itoa(taskID)
+	return strings.Join(lines, "\n")
+}
+
+// unlinkChecklistLine tira o marcador "^task-ID" da linha indicada
+func unlinkChecklistLine(content string, line int) string {
+	lines := strings.Split(content, "\n")
+	if line < 0 || line >= len(lines) {
+		return content
+	}
+
+	lines[line] = unmarkedLine(lines[line])
+	return strings.Join(lines, "\n")
+}
+
+// unlinkChecklistTask tira o marcador das linhas vinculadas à tarefa.
+// Retorna o conteúdo atualizado e se houve alteração.
+func unlinkChecklistTask(content string, taskID int) (string, bool) {
+	changed := false
+	for _, item := range parseChecklist(content) {
+		if item.TaskID == taskID {
+			content = unlinkChecklistLine(content, item.Line)
+			changed = true
+		}
+	}
+	return content, changed
+}
+
+// unmarkedLine retorna a linha sem o marcador e sem espaços no final
+func unmarkedLine(line string) string {
+	if match := checklistLine.FindStringSubmatchIndex(line); match != nil {
+		return line[:match[9]]
+	}
+	return strings.TrimRight(line, " \t")
+}
+
+// setChecklistItem marca ou desmarca a linha vinculada à tarefa.
+// Retorna o conteúdo atualizado e se houve alteração.
+func setChecklistItem(content string, taskID int, checked bool) (string, bool) {
+	lines := strings.Split(content, "\n")
+	mark := " "
+	if checked {
+		mark = "x"
+	}
+
+	changed := false
+	for i, line := range lines {
+		match := checklistLine.FindStringSubmatchIndex(line)
+		if match == nil || match[10] < 0 {
+			continue
+		}
+
+		id, _ := strconv.Atoi(line[match[10]:match[11]])
+		if id != taskID || (line[match[4]:match[5]] != " ") == checked {
+			continue
+		}
+
+		lines[i] = line[:match[4]] + mark + line[match[5]:]
+		changed = true
+	}
+
+	return strings.Join(lines, "\n"), changed
+}
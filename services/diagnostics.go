@@ -0,0 +1,197 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// DiagnosticsService monta o pacote de diagnóstico (logs + estado do banco)
+type DiagnosticsService struct {
+	db         *sql.DB
+	logDir     string
+	appVersion string
+}
+
+// NewDiagnosticsService cria novo serviço de diagnóstico
+func NewDiagnosticsService(db *sql.DB, logDir, appVersion string) *DiagnosticsService {
+	return &DiagnosticsService{db: db, logDir: logDir, appVersion: appVersion}
+}
+
+// Collect reúne versões, estatísticas do banco e o PRAGMA integrity_check.
+// Sem banco (modo de recuperação), só as versões.
+func (s *DiagnosticsService) Collect(ctx context.Context) (_ *models.Diagnostics, err error) {
+	defer logging.Track("DiagnosticsService.Collect", &err)()
+
+	diagnostics := &models.Diagnostics{
+		App:         "Personal Cockpit",
+		Version:     s.appVersion,
+		GeneratedAt: time.Now(),
+		GoVersion:   runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Locale:      i18n.Locale(),
+		Timezone:    timezone.Name(),
+		LogLevel:    logging.Level(),
+	}
+
+	if s.db == nil {
+		return diagnostics, nil
+	}
+
+	err = s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&diagnostics.SchemaVersion)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar versão do schema: %w", err)
+	}
+
+	if diagnostics.Database, err = s.databaseStats(ctx); err != nil {
+		return nil, err
+	}
+
+	if diagnostics.IntegrityCheck, err = s.integrityCheck(ctx); err != nil {
+		return nil, err
+	}
+
+	return diagnostics, nil
+}
+
+// Export grava o zip com diagnostics.json e os arquivos de log (o atual e os
+// rotacionados)
+func (s *DiagnosticsService) Export(ctx context.Context, w io.Writer) (err error) {
+	defer logging.Track("DiagnosticsService.Export", &err)()
+
+	diagnostics, err := s.Collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	entry, err := archive.Create("diagnostics.json")
+	if err != nil {
+		return i18n.Errorf("erro ao gerar diagnóstico: %w", err)
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diagnostics); err != nil {
+		return i18n.Errorf("erro ao gerar diagnóstico: %w", err)
+	}
+
+	logFiles, err := filepath.Glob(filepath.Join(s.logDir, logging.FileName+"*"))
+	if err != nil {
+		return i18n.Errorf("erro ao ler logs: %w", err)
+	}
+	sort.Strings(logFiles)
+
+	for i, path := range logFiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addFileToZip(archive, path, "logs/"+filepath.Base(path)); err != nil {
+			return err
+		}
+		ReportProgress(ctx, i+1, len(logFiles))
+	}
+
+	if err := archive.Close(); err != nil {
+		return i18n.Errorf("erro ao gerar diagnóstico: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DiagnosticsService) databaseStats(ctx context.Context) (models.DatabaseStats, error) {
+	stats := models.DatabaseStats{Tables: map[string]int{}}
+
+	pragmas := []struct {
+		name   string
+		target interface{}
+	}{
+		{"page_size", &stats.PageSize},
+		{"page_count", &stats.PageCount},
+		{"freelist_count", &stats.FreelistCount},
+		{"journal_mode", &stats.JournalMode},
+	}
+	for _, pragma := range pragmas {
+		if err := s.db.QueryRowContext(ctx, "PRAGMA "+pragma.name).Scan(pragma.target); err != nil {
+			return stats, i18n.Errorf("erro ao ler estatísticas do banco: %w", err)
+		}
+	}
+	stats.SizeBytes = stats.PageSize * stats.PageCount
+
+	rows, err := s.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return stats, i18n.Errorf("erro ao ler estatísticas do banco: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return stats, i18n.Errorf("erro ao ler estatísticas do banco: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		var count int
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "`+table+`"`).Scan(&count); err != nil {
+			return stats, i18n.Errorf("erro ao ler estatísticas do banco: %w", err)
+		}
+		stats.Tables[table] = count
+	}
+
+	return stats, nil
+}
+
+// integrityCheck roda o PRAGMA integrity_check; um banco íntegro responde "ok"
+func (s *DiagnosticsService) integrityCheck(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, i18n.Errorf("erro ao verificar integridade do banco: %w", err)
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, i18n.Errorf("erro ao verificar integridade do banco: %w", err)
+		}
+		result = append(result, line)
+	}
+
+	return result, nil
+}
+
+func addFileToZip(archive *zip.Writer, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return i18n.Errorf("erro ao ler logs: %w", err)
+	}
+	defer file.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return i18n.Errorf("erro ao gerar diagnóstico: %w", err)
+	}
+
+	if _, err := io.Copy(entry, file); err != nil {
+		return i18n.Errorf("erro ao gerar diagnóstico: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/repository"
+)
+
+// Códigos dos campos inválidos, estáveis para o frontend traduzir e destacar
+const (
+	CodeRequired      = "required"       // campo vazio
+	CodeInvalid       = "invalid"        // formato inválido
+	CodeInvalidChoice = "invalid_choice" // valor fora da lista aceita
+	CodeInvalidColor  = "invalid_color"  // cor fora do formato #rrggbb
+	CodeOutOfRange    = "out_of_range"   // número ou data fora do intervalo
+)
+
+// FieldError é um campo com problema
+type FieldError struct {
+	Field   string `json:"field"`   // nome do campo no JSON (ex.: "title")
+	Code    string `json:"code"`    // um dos Code*
+	Message string `json:"message"` // texto em português para exibir direto
+}
+
+// ValidationError junta todos os campos inválidos de uma operação
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Required marca um campo obrigatório vazio. label é o nome exibido, em
+// português (traduzido pelo catálogo).
+func (e *ValidationError) Required(field, label string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: CodeRequired, Message: i18n.T(label)})
+}
+
+// Invalid marca um campo preenchido com valor inválido
+func (e *ValidationError) Invalid(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: i18n.T(message)})
+}
+
+// HasErrors indica se algum campo foi marcado
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+// Error mantém o texto de sempre: "Campos obrigatórios:\n- título"
+func (e *ValidationError) Error() string {
+	var required, invalid []string
+	for _, field := range e.Fields {
+		if field.Code == CodeRequired {
+			required = append(required, field.Message)
+		} else {
+			invalid = append(invalid, field.Message)
+		}
+	}
+
+	var blocks []string
+	if len(required) > 0 {
+		blocks = append(blocks, i18n.T("Campos obrigatórios:")+"\n- "+strings.Join(required, "\n- "))
+	}
+	if len(invalid) > 0 {
+		blocks = append(blocks, i18n.T("Campos inválidos:")+"\n- "+strings.Join(invalid, "\n- "))
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+// requiredID é o erro das operações chamadas sem o ID da entidade
+func requiredID(label string) error {
+	erros := &ValidationError{}
+	erros.Required("id", label)
+	return erros
+}
+
+// NotFoundError indica que a entidade pedida não existe
+type NotFoundError struct {
+	Entity string `json:"entity"` // "task", "note", "event", ...
+	ID     int    `json:"id,omitempty"`
+}
+
+// Mensagens de "não encontrado" por entidade (com o gênero certo)
+var notFoundMessages = map[string]string{
+	"task":        "tarefa não encontrada",
+	"note":        "nota não encontrada",
+	"event":       "evento não encontrado",
+	"category":    "categoria não encontrada",
+	"notebook":    "caderno não encontrado",
+	"template":    "modelo não encontrado",
+	"attachment":  "anexo não encontrado",
+	"journal":     "entrada do diário não encontrada",
+	"account":     "conta não encontrada",
+	"transaction": "transação não encontrada",
+	"budget":      "orçamento não encontrado",
+	"recurring":   "recorrência não encontrada",
+	"webhook":     "webhook não encontrado",
+}
+
+func (e *NotFoundError) Error() string {
+	if message, ok := notFoundMessages[e.Entity]; ok {
+		return i18n.T(message)
+	}
+	return i18n.Sprintf("%s não encontrado", e.Entity)
+}
+
+func notFound(entity string, id int) error {
+	return &NotFoundError{Entity: entity, ID: id}
+}
+
+// fromRepository troca o repository.ErrNotFound pelo NotFoundError da entidade
+func fromRepository(err error, entity string, id int) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return notFound(entity, id)
+	}
+	return err
+}
+
+// ConflictError indica que a operação viola uma regra de unicidade (ex.:
+// categoria com nome repetido)
+type ConflictError struct {
+	Entity  string `json:"entity"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// duplicateName é o conflito das tabelas com nome único ("uma categoria")
+func duplicateName(entity, label string) error {
+	return &ConflictError{Entity: entity, Field: "name", Message: i18n.Sprintf("já existe %s com esse nome", i18n.T(label))}
+}
+
+// UnavailableError indica que o app está em modo de recuperação: o banco não
+// abriu e nenhuma operação com dados pode ser feita
+type UnavailableError struct {
+	Cause error `json:"-"`
+}
+
+func (e *UnavailableError) Error() string {
+	return i18n.Sprintf("banco de dados indisponível: %v", e.Cause)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Cause
+}
+
+// isUniqueViolation reconhece o erro de UNIQUE do SQLite
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// ═══════════════════════════════════════════════════════════
+// FORMATO PARA O FRONTEND E A API
+// ═══════════════════════════════════════════════════════════
+
+// Códigos de ErrorPayload
+const (
+	ErrorCodeValidation  = "validation"
+	ErrorCodeNotFound    = "not_found"
+	ErrorCodeConflict    = "conflict"
+	ErrorCodeUnavailable = "unavailable"
+	ErrorCodeCanceled    = "canceled"
+	ErrorCodeTimeout     = "timeout"
+	ErrorCodeInternal    = "error"
+)
+
+// ErrorPayload é o JSON de erro entregue ao React (via Wails) e à API HTTP
+type ErrorPayload struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Entity  string       `json:"entity,omitempty"`
+	ID      int          `json:"id,omitempty"`
+	Field   string       `json:"field,omitempty"`
+}
+
+// NewErrorPayload monta o JSON a partir de qualquer erro; os tipados trazem
+// os detalhes, os demais só a mensagem
+func NewErrorPayload(err error) ErrorPayload {
+	var (
+		validation  *ValidationError
+		notFound    *NotFoundError
+		conflict    *ConflictError
+		unavailable *UnavailableError
+	)
+
+	switch {
+	case errors.As(err, &validation):
+		return ErrorPayload{Code: ErrorCodeValidation, Message: validation.Error(), Fields: validation.Fields}
+	case errors.As(err, &notFound):
+		return ErrorPayload{Code: ErrorCodeNotFound, Message: notFound.Error(), Entity: notFound.Entity, ID: notFound.ID}
+	case errors.As(err, &conflict):
+		return ErrorPayload{Code: ErrorCodeConflict, Message: conflict.Message, Entity: conflict.Entity, Field: conflict.Field}
+	case errors.As(err, &unavailable):
+		return ErrorPayload{Code: ErrorCodeUnavailable, Message: unavailable.Error()}
+	case errors.Is(err, context.Canceled):
+		return ErrorPayload{Code: ErrorCodeCanceled, Message: i18n.T("operação cancelada")}
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorPayload{Code: ErrorCodeTimeout, Message: i18n.T("tempo limite da operação esgotado")}
+	default:
+		return ErrorPayload{Code: ErrorCodeInternal, Message: err.Error()}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// VALORES ACEITOS
+// ═══════════════════════════════════════════════════════════
+
+// Espelham os CHECK das tabelas, para o erro sair antes do banco recusar
+var (
+	taskStatuses    = []string{"pending", "completed", "cancelled"}
+	taskPriorities  = []string{"low", "medium", "high"}
+	categoryTypes   = []string{"task", "note", "general", "finance"}
+	accountTypes    = []string{"checking", "savings", "credit", "cash", "investment"}
+	recurringFreqs  = []string{"weekly", "monthly", "yearly"}
+	attachmentKinds = []string{"note", "task", "event"}
+)
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor aceita vazio (usa a cor padrão) ou #rgb/#rrggbb
+func validColor(color string) bool {
+	return color == "" || hexColorPattern.MatchString(color)
+}
+
+func oneOf(value string, options []string) bool {
+	for _, option := range options {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+// choiceMessage monta "prioridade (low, medium, high)"
+func choiceMessage(label string, options []string) string {
+	return i18n.T(label) + " (" + strings.Join(options, ", ") + ")"
+}
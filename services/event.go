@@ -1,269 +1,222 @@
 package services
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"strings"
 	"time"
 
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
 	"personal-cockpit/models"
+	"personal-cockpit/repository"
+	"personal-cockpit/timezone"
 )
 
 type EventService struct {
-	db *sql.DB
+	store repository.UnitOfWork
+	bus   *events.Bus
 }
 
-func NewEventService(db *sql.DB) *EventService {
-	return &EventService{db: db}
+func NewEventService(db *sql.DB, bus *events.Bus) *EventService {
+	return NewEventServiceWithStore(repository.NewSQLite(db), bus)
 }
 
-func (s *EventService) CreateEvent(event models.Event) (int64, error) {
-	var erros []string
+// NewEventServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewEventServiceWithStore(store repository.UnitOfWork, bus *events.Bus) *EventService {
+	return &EventService{store: store, bus: bus}
+}
 
-	if event.Title == "" {
-		erros = append(erros, "título")
-	}
+// CreateEvent grava o evento e retorna o ID com os eventos que ocupam o
+// mesmo horário, como aviso
+func (s *EventService) CreateEvent(ctx context.Context, event models.Event) (_ *models.EventSaveResult, err error) {
+	defer logging.Track("EventService.CreateEvent", &err)()
 
-	// IsZero() verifica se time.Time está vazio (valor zero)
-	if event.StartDate.IsZero() {
-		erros = append(erros, "data de início")
+	if err := validateEvent(event); err != nil {
+		return nil, err
 	}
 
-	if event.EndDate.IsZero() {
-		erros = append(erros, "data de término")
-	}
-
-	if !event.StartDate.IsZero() && !event.EndDate.IsZero() && event.EndDate.Before(event.StartDate) {
-		erros = append(erros, "data de término deve ser após data de início")
-	}
+	// Blocos de tempo nascem só em SchedulingService.ScheduleTask
+	event.TaskID, event.IsDone = nil, false
 
-	if len(erros) > 0 {
-		mensagem := "Campos obrigatórios:\n- " + strings.Join(erros, "\n- ")
-		return 0, fmt.Errorf(mensagem)
+	id, err := s.store.Events().Create(ctx, event)
+	if err != nil {
+		return nil, err
 	}
 
-	query := `
-		INSERT INTO events (title, description, start_date, end_date, all_day, color, location, reminder_minutes)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	result, err := s.db.Exec(
-		query,
-		event.Title,
-		event.Description,
-		event.StartDate,
-		event.EndDate,
-		event.AllDay,
-		event.Color,
-		event.Location,
-		event.ReminderMinutes,
-	)
+	publishEntity(ctx, s.bus, events.EventCreated, int(id), s.GetEventByID)
 
-	if err != nil {
-		return 0, fmt.Errorf("erro ao criar evento: %w", err)
-	}
+	event.ID = int(id)
+	return s.saveResult(ctx, event), nil
+}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("erro ao obter ID: %w", err)
-	}
+func (s *EventService) GetAllEvents(ctx context.Context) (_ []models.Event, err error) {
+	defer logging.Track("EventService.GetAllEvents", &err)()
 
-	return id, nil
+	return s.store.Events().List(ctx)
 }
 
-func (s *EventService) GetAllEvents() ([]models.Event, error) {
-	query := `
-		SELECT id, title, description, start_date, end_date, all_day, color, location, 
-		       reminder_minutes, created_at, updated_at
-		FROM events
-		ORDER BY start_date ASC
-	`
+func (s *EventService) GetEventByID(ctx context.Context, id int) (_ *models.Event, err error) {
+	defer logging.Track("EventService.GetEventByID", &err)()
 
-	rows, err := s.db.Query(query)
+	event, err := s.store.Events().GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar eventos: %w", err)
+		return nil, fromRepository(err, "event", id)
 	}
-	defer rows.Close()
-
-	var events []models.Event
-
-	for rows.Next() {
-		var event models.Event
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.StartDate,
-			&event.EndDate,
-			&event.AllDay,
-			&event.Color,
-			&event.Location,
-			&event.ReminderMinutes,
-			&event.CreatedAt,
-			&event.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler evento: %w", err)
-		}
 
-		events = append(events, event)
-	}
-
-	return events, nil
+	return event, nil
 }
 
-func (s *EventService) GetEventByID(id int) (*models.Event, error) {
-	query := `
-		SELECT id, title, description, start_date, end_date, all_day, color, location, 
-		       reminder_minutes, created_at, updated_at
-		FROM events
-		WHERE id = ?
-	`
-
-	var event models.Event
-	err := s.db.QueryRow(query, id).Scan(
-		&event.ID,
-		&event.Title,
-		&event.Description,
-		&event.StartDate,
-		&event.EndDate,
-		&event.AllDay,
-		&event.Color,
-		&event.Location,
-		&event.ReminderMinutes,
-		&event.CreatedAt,
-		&event.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("evento não encontrado")
+// UpdateEvent altera o evento e retorna os eventos que passaram a ocupar o
+// mesmo horário, como aviso
+func (s *EventService) UpdateEvent(ctx context.Context, event models.Event) (_ *models.EventSaveResult, err error) {
+	defer logging.Track("EventService.UpdateEvent", &err)()
+
+	if event.ID == 0 {
+		return nil, requiredID("ID do evento")
 	}
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar evento: %w", err)
+
+	if err := validateEvent(event); err != nil {
+		return nil, err
 	}
 
-	return &event, nil
-}
+	var taskID int
 
-func (s *EventService) UpdateEvent(event models.Event) error {
-	if event.ID == 0 {
-		return fmt.Errorf("ID do evento é obrigatório")
-	}
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		old, err := tx.Events().GetByID(ctx, event.ID)
+		if err != nil {
+			return err
+		}
 
-	query := `
-		UPDATE events 
-		SET title = ?, description = ?, start_date = ?, end_date = ?, all_day = ?, 
-		    color = ?, location = ?, reminder_minutes = ?
-		WHERE id = ?
-	`
-
-	result, err := s.db.Exec(
-		query,
-		event.Title,
-		event.Description,
-		event.StartDate,
-		event.EndDate,
-		event.AllDay,
-		event.Color,
-		event.Location,
-		event.ReminderMinutes,
-		event.ID,
-	)
+		if err := tx.Events().Update(ctx, event); err != nil {
+			return err
+		}
 
+		taskID, err = syncTaskSchedule(ctx, tx, *old, event)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar evento: %w", err)
+		return nil, fromRepository(err, "event", event.ID)
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	publishEntity(ctx, s.bus, events.EventUpdated, event.ID, s.GetEventByID)
+	if taskID != 0 {
+		taskService := NewTaskServiceWithStore(s.store, nil)
+		publishEntity(ctx, s.bus, events.TaskUpdated, taskID, taskService.GetTaskByID)
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("evento não encontrado")
+	return s.saveResult(ctx, event), nil
+}
+
+func (s *EventService) DeleteEvent(ctx context.Context, id int) (err error) {
+	defer logging.Track("EventService.DeleteEvent", &err)()
+
+	if err := s.store.Events().Delete(ctx, id); err != nil {
+		return fromRepository(err, "event", id)
 	}
 
+	s.bus.Publish(events.EventDeleted, id, nil)
+
 	return nil
 }
 
-func (s *EventService) DeleteEvent(id int) error {
-	query := "DELETE FROM events WHERE id = ?"
+// PublishStartingEvents publica "event.starting" para os eventos que
+// começam no intervalo (from, to]. Chamado periodicamente pelo app.
+func (s *EventService) PublishStartingEvents(ctx context.Context, from, to time.Time) (err error) {
+	defer logging.Track("EventService.PublishStartingEvents", &err)()
 
-	result, err := s.db.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("erro ao deletar evento: %w", err)
+	if !s.bus.HasSubscribers() {
+		return nil
 	}
 
-	rows, err := result.RowsAffected()
+	starting, err := s.store.Events().ListStartingBetween(ctx, from, to)
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("evento não encontrado")
+	for _, event := range starting {
+		if event.StartDate.After(from) {
+			s.bus.Publish(events.EventStarting, event.ID, event)
+		}
 	}
 
 	return nil
 }
 
-// GetEventsByDateRange busca eventos entre duas datas
-func (s *EventService) GetEventsByDateRange(startDate, endDate time.Time) ([]models.Event, error) {
-	query := `
-		SELECT id, title, description, start_date, end_date, all_day, color, location, 
-		       reminder_minutes, created_at, updated_at
-		FROM events
-		WHERE start_date >= ? AND start_date <= ?
-		ORDER BY start_date ASC
-	`
-
-	rows, err := s.db.Query(query, startDate, endDate)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar eventos: %w", err)
+// ReminderText monta o texto do lembrete de um evento que está começando,
+// no idioma em uso
+func ReminderText(event models.Event) string {
+	if event.AllDay {
+		return i18n.Sprintf("Hoje: %s", event.Title)
 	}
-	defer rows.Close()
-
-	var events []models.Event
-
-	for rows.Next() {
-		var event models.Event
-		err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.StartDate,
-			&event.EndDate,
-			&event.AllDay,
-			&event.Color,
-			&event.Location,
-			&event.ReminderMinutes,
-			&event.CreatedAt,
-			&event.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler evento: %w", err)
-		}
 
-		events = append(events, event)
+	text := i18n.Sprintf("%s começa às %s", event.Title, i18n.FormatTime(timezone.In(event.StartDate)))
+	if event.Location != "" {
+		text += " — " + event.Location
 	}
+	return text
+}
+
+// GetEventsByDateRange busca os eventos que ocupam algum momento de
+// [startDate, endDate): os que começam no intervalo e também os que começaram
+// antes e ainda não terminaram
+func (s *EventService) GetEventsByDateRange(ctx context.Context, startDate, endDate time.Time) (_ []models.Event, err error) {
+	defer logging.Track("EventService.GetEventsByDateRange", &err)()
 
-	return events, nil
+	return s.store.Events().ListOverlapping(ctx, startDate, endDate)
 }
 
 // GetTodayEvents retorna eventos de hoje
-func (s *EventService) GetTodayEvents() ([]models.Event, error) {
-	now := time.Now()
+func (s *EventService) GetTodayEvents(ctx context.Context) (_ []models.Event, err error) {
+	defer logging.Track("EventService.GetTodayEvents", &err)()
 
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startOfDay := timezone.StartOfDay(time.Now())
 
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	// AddDate em vez de 24h: dias com mudança de horário têm 23 ou 25 horas
+	endOfDay := startOfDay.AddDate(0, 0, 1)
 
-	return s.GetEventsByDateRange(startOfDay, endOfDay)
+	return s.GetEventsByDateRange(ctx, startOfDay, endOfDay)
 }
 
-func (s *EventService) GetUpcomingEvents() ([]models.Event, error) {
+func (s *EventService) GetUpcomingEvents(ctx context.Context) (_ []models.Event, err error) {
+	defer logging.Track("EventService.GetUpcomingEvents", &err)()
+
 	now := time.Now()
 
 	future := now.Add(7 * 24 * time.Hour)
 
-	return s.GetEventsByDateRange(now, future)
+	return s.GetEventsByDateRange(ctx, now, future)
+}
+
+// validateEvent confere os campos antes de gravar, com todos os erros de uma vez
+func validateEvent(event models.Event) error {
+	erros := &ValidationError{}
+
+	if strings.TrimSpace(event.Title) == "" {
+		erros.Required("title", "título")
+	}
+
+	// IsZero() verifica se time.Time está vazio (valor zero)
+	if event.StartDate.IsZero() {
+		erros.Required("start_date", "data de início")
+	}
+
+	if event.EndDate.IsZero() {
+		erros.Required("end_date", "data de término")
+	}
+
+	if !event.StartDate.IsZero() && !event.EndDate.IsZero() && event.EndDate.Before(event.StartDate) {
+		erros.Invalid("end_date", CodeOutOfRange, "data de término deve ser após data de início")
+	}
+
+	if !validColor(event.Color) {
+		erros.Invalid("color", CodeInvalidColor, "cor (formato #rrggbb)")
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+
+	return nil
 }
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// ExportService gera exportações completas dos dados do app
+type ExportService struct {
+	db         *sql.DB
+	appVersion string
+}
+
+// NewExportService cria novo serviço de exportação
+func NewExportService(db *sql.DB, appVersion string) *ExportService {
+	return &ExportService{db: db, appVersion: appVersion}
+}
+
+// Collect reúne todos os dados do banco em uma única estrutura
+func (s *ExportService) Collect(ctx context.Context) (_ *models.ExportData, err error) {
+	defer logging.Track("ExportService.Collect", &err)()
+
+	// Só leitura: os services não precisam do barramento de eventos
+	taskService := NewTaskService(s.db, nil)
+	noteService := NewNoteService(s.db, nil, taskService)
+	eventService := NewEventService(s.db, nil)
+	financeService := NewFinanceService(s.db, nil)
+
+	data := &models.ExportData{
+		App:        "Personal Cockpit",
+		Version:    s.appVersion,
+		ExportedAt: time.Now(),
+	}
+
+	err = s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&data.SchemaVersion)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao buscar versão do schema: %w", err)
+	}
+
+	if data.Categories, err = NewCategoryService(s.db, nil).GetAllCategories(ctx); err != nil {
+		return nil, err
+	}
+
+	if data.Tasks, err = taskService.GetAllTasks(ctx); err != nil {
+		return nil, err
+	}
+
+	if data.Notebooks, err = NewNotebookService(s.db, nil).GetNotebookTree(ctx); err != nil {
+		return nil, err
+	}
+
+	if data.Notes, err = noteService.GetAllNotes(ctx); err != nil {
+		return nil, err
+	}
+
+	if data.Events, err = eventService.GetAllEvents(ctx); err != nil {
+		return nil, err
+	}
+
+	if data.Templates, err = NewTemplateService(s.db, nil, noteService, taskService).GetAllTemplates(ctx); err != nil {
+		return nil, err
+	}
+
+	journalService := NewJournalService(s.db, nil, taskService, eventService)
+	if data.JournalEntries, err = journalService.SearchEntries(ctx, models.JournalFilter{}); err != nil {
+		return nil, err
+	}
+
+	if data.Accounts, err = financeService.GetAllAccounts(ctx); err != nil {
+		return nil, err
+	}
+
+	if data.Transactions, err = financeService.GetTransactions(ctx, models.TransactionFilter{}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ExportJSON escreve a exportação completa em JSON
+func (s *ExportService) ExportJSON(ctx context.Context, w io.Writer) (err error) {
+	defer logging.Track("ExportService.ExportJSON", &err)()
+
+	data, err := s.Collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return i18n.Errorf("erro ao gerar JSON: %w", err)
+	}
+
+	return nil
+}
+
+// ExportMarkdown escreve um relatório legível com tarefas, notas, eventos e diário
+func (s *ExportService) ExportMarkdown(ctx context.Context, w io.Writer) (err error) {
+	defer logging.Track("ExportService.ExportMarkdown", &err)()
+
+	data, err := s.Collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", data.App)
+	fmt.Fprintf(&b, i18n.T("Exportado em %s (v%s)")+"\n\n", i18n.FormatDateTime(timezone.In(data.ExportedAt)), data.Version)
+
+	b.WriteString("## " + i18n.T("Tarefas") + "\n\n")
+	for _, task := range data.Tasks {
+		mark := " "
+		if task.Status == "completed" {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s", mark, task.Title)
+		if task.DueDate != nil {
+			fmt.Fprintf(&b, " ("+i18n.T("vence %s")+")", i18n.FormatDate(*task.DueDate))
+		}
+		fmt.Fprintf(&b, " `%s`\n", task.Priority)
+	}
+
+	b.WriteString("\n## " + i18n.T("Eventos") + "\n\n")
+	for _, event := range data.Events {
+		if event.AllDay {
+			fmt.Fprintf(&b, "- %s — %s (%s)", i18n.FormatDate(event.StartDate), event.Title, i18n.T("dia inteiro"))
+		} else {
+			fmt.Fprintf(&b, "- %s — %s", i18n.FormatDateTime(timezone.In(event.StartDate)), event.Title)
+		}
+		if event.Location != "" {
+			fmt.Fprintf(&b, " @ %s", event.Location)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## " + i18n.T("Notas") + "\n")
+	for _, note := range data.Notes {
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", note.Title, strings.TrimSpace(note.Content))
+	}
+
+	if len(data.JournalEntries) > 0 {
+		b.WriteString("\n## " + i18n.T("Diário") + "\n")
+		for _, entry := range data.JournalEntries {
+			fmt.Fprintf(&b, "\n### %s\n\n", entry.Date)
+			if entry.Mood != nil {
+				fmt.Fprintf(&b, i18n.T("Humor: %d/5")+"  \n", *entry.Mood)
+			}
+			if entry.Energy != nil {
+				fmt.Fprintf(&b, i18n.T("Energia: %d/5")+"  \n", *entry.Energy)
+			}
+			if len(entry.Tags) > 0 {
+				fmt.Fprintf(&b, i18n.T("Tags: %s")+"  \n", strings.Join(entry.Tags, ", "))
+			}
+			fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(entry.Content))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return i18n.Errorf("erro ao gravar exportação: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,557 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// Percentual do orçamento a partir do qual o alerta "warning" é emitido
+const budgetWarningPercent = 80
+
+// FinanceService gerencia contas, transações, orçamentos e lançamentos recorrentes
+type FinanceService struct {
+	store repository.UnitOfWork
+	bus   *events.Bus
+}
+
+// NewFinanceService cria novo serviço financeiro
+func NewFinanceService(db *sql.DB, bus *events.Bus) *FinanceService {
+	return NewFinanceServiceWithStore(repository.NewSQLite(db), bus)
+}
+
+// NewFinanceServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewFinanceServiceWithStore(store repository.UnitOfWork, bus *events.Bus) *FinanceService {
+	return &FinanceService{store: store, bus: bus}
+}
+
+// ═══════════════════════════════════════════════════════════
+// CONTAS
+// ═══════════════════════════════════════════════════════════
+
+// CreateAccount cria uma nova conta
+func (s *FinanceService) CreateAccount(ctx context.Context, account models.Account) (_ int64, err error) {
+	defer logging.Track("FinanceService.CreateAccount", &err)()
+
+	if account.Type == "" {
+		account.Type = "checking"
+	}
+
+	erros := &ValidationError{}
+
+	if strings.TrimSpace(account.Name) == "" {
+		erros.Required("name", "nome")
+	}
+
+	checkCurrency(erros, account.Currency)
+	checkAccountType(erros, account.Type)
+
+	if erros.HasErrors() {
+		return 0, erros
+	}
+
+	account.Currency = strings.ToUpper(account.Currency)
+
+	id, err := s.store.Accounts().Create(ctx, account)
+	if isUniqueViolation(err) {
+		return 0, duplicateName("account", "uma conta")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	publishEntity(ctx, s.bus, events.AccountCreated, int(id), s.GetAccountByID)
+
+	return id, nil
+}
+
+// GetAllAccounts retorna todas as contas com o saldo atual
+func (s *FinanceService) GetAllAccounts(ctx context.Context) (_ []models.Account, err error) {
+	defer logging.Track("FinanceService.GetAllAccounts", &err)()
+
+	return s.store.Accounts().List(ctx)
+}
+
+// GetAccountByID busca conta por ID
+func (s *FinanceService) GetAccountByID(ctx context.Context, id int) (_ *models.Account, err error) {
+	defer logging.Track("FinanceService.GetAccountByID", &err)()
+
+	account, err := s.store.Accounts().GetByID(ctx, id)
+	if err != nil {
+		return nil, fromRepository(err, "account", id)
+	}
+
+	return account, nil
+}
+
+// UpdateAccount atualiza nome, tipo e saldo inicial. A moeda não muda depois
+// de criada, pois as transações existentes estão nela.
+func (s *FinanceService) UpdateAccount(ctx context.Context, account models.Account) (err error) {
+	defer logging.Track("FinanceService.UpdateAccount", &err)()
+
+	if account.ID == 0 {
+		return requiredID("ID da conta")
+	}
+
+	erros := &ValidationError{}
+
+	if strings.TrimSpace(account.Name) == "" {
+		erros.Required("name", "nome")
+	}
+
+	checkAccountType(erros, account.Type)
+
+	if erros.HasErrors() {
+		return erros
+	}
+
+	err = s.store.Accounts().Update(ctx, account)
+	if isUniqueViolation(err) {
+		return duplicateName("account", "uma conta")
+	}
+	if err != nil {
+		return fromRepository(err, "account", account.ID)
+	}
+
+	publishEntity(ctx, s.bus, events.AccountUpdated, account.ID, s.GetAccountByID)
+
+	return nil
+}
+
+// DeleteAccount deleta a conta e todas as suas transações
+func (s *FinanceService) DeleteAccount(ctx context.Context, id int) (err error) {
+	defer logging.Track("FinanceService.DeleteAccount", &err)()
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		if err := tx.Transactions().DeleteByAccount(ctx, id); err != nil {
+			return err
+		}
+		if err := tx.Recurring().DeleteByAccount(ctx, id); err != nil {
+			return err
+		}
+		return tx.Accounts().Delete(ctx, id)
+	})
+	if err != nil {
+		return fromRepository(err, "account", id)
+	}
+
+	s.bus.Publish(events.AccountDeleted, id, nil)
+
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// TRANSAÇÕES
+// ═══════════════════════════════════════════════════════════
+
+// CreateTransaction registra uma transação. A moeda é sempre a da conta.
+func (s *FinanceService) CreateTransaction(ctx context.Context, transaction models.Transaction) (_ int64, err error) {
+	defer logging.Track("FinanceService.CreateTransaction", &err)()
+
+	account, err := s.validateTransaction(ctx, transaction)
+	if err != nil {
+		return 0, err
+	}
+
+	transaction.Currency = account.Currency
+
+	id, err := s.store.Transactions().Create(ctx, transaction)
+	if err != nil {
+		return 0, err
+	}
+
+	transaction.ID = int(id)
+	s.bus.Publish(events.TransactionCreated, transaction.ID, transaction)
+
+	return id, nil
+}
+
+func (s *FinanceService) validateTransaction(ctx context.Context, transaction models.Transaction) (*models.Account, error) {
+	erros := &ValidationError{}
+
+	if transaction.AccountID == 0 {
+		erros.Required("account_id", "conta")
+	}
+
+	if transaction.Amount == 0 {
+		erros.Required("amount", "valor")
+	}
+
+	switch _, err := time.Parse(journalDateLayout, transaction.Date); {
+	case transaction.Date == "":
+		erros.Required("date", "data (AAAA-MM-DD)")
+	case err != nil:
+		erros.Invalid("date", CodeInvalid, "data (AAAA-MM-DD)")
+	}
+
+	if erros.HasErrors() {
+		return nil, erros
+	}
+
+	return s.GetAccountByID(ctx, transaction.AccountID)
+}
+
+// GetTransactions busca transações com filtros
+func (s *FinanceService) GetTransactions(ctx context.Context, filter models.TransactionFilter) (_ []models.Transaction, err error) {
+	defer logging.Track("FinanceService.GetTransactions", &err)()
+
+	return s.store.Transactions().List(ctx, filter)
+}
+
+// UpdateTransaction atualiza uma transação
+func (s *FinanceService) UpdateTransaction(ctx context.Context, transaction models.Transaction) (err error) {
+	defer logging.Track("FinanceService.UpdateTransaction", &err)()
+
+	if transaction.ID == 0 {
+		return requiredID("ID da transação")
+	}
+
+	account, err := s.validateTransaction(ctx, transaction)
+	if err != nil {
+		return err
+	}
+
+	transaction.Currency = account.Currency
+
+	if err := s.store.Transactions().Update(ctx, transaction); err != nil {
+		return fromRepository(err, "transaction", transaction.ID)
+	}
+
+	s.bus.Publish(events.TransactionUpdated, transaction.ID, transaction)
+
+	return nil
+}
+
+// DeleteTransaction deleta uma transação
+func (s *FinanceService) DeleteTransaction(ctx context.Context, id int) (err error) {
+	defer logging.Track("FinanceService.DeleteTransaction", &err)()
+
+	if err := s.store.Transactions().Delete(ctx, id); err != nil {
+		return fromRepository(err, "transaction", id)
+	}
+
+	s.bus.Publish(events.TransactionDeleted, id, nil)
+
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// RESUMO MENSAL
+// ═══════════════════════════════════════════════════════════
+
+// GetMonthlySummary soma receitas e despesas do mês por categoria e moeda
+func (s *FinanceService) GetMonthlySummary(ctx context.Context, year, month int) (_ *models.MonthlySummary, err error) {
+	defer logging.Track("FinanceService.GetMonthlySummary", &err)()
+
+	start, end := monthBounds(year, month)
+
+	categories, err := s.store.Transactions().Summarize(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.MonthlySummary{
+		Year:       year,
+		Month:      month,
+		Categories: []models.CategorySummary{},
+		Totals:     []models.CurrencyTotal{},
+	}
+	totals := make(map[string]*models.CurrencyTotal)
+
+	for _, category := range categories {
+		summary.Categories = append(summary.Categories, category)
+
+		total, ok := totals[category.Currency]
+		if !ok {
+			summary.Totals = append(summary.Totals, models.CurrencyTotal{Currency: category.Currency})
+			total = &summary.Totals[len(summary.Totals)-1]
+			totals[category.Currency] = total
+		}
+		total.Income += category.Income
+		total.Expense += category.Expense
+		total.Net = total.Income - total.Expense
+	}
+
+	return summary, nil
+}
+
+// monthBounds retorna o primeiro dia do mês e o primeiro dia do mês seguinte
+func monthBounds(year, month int) (string, string) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format(journalDateLayout), start.AddDate(0, 1, 0).Format(journalDateLayout)
+}
+
+// ═══════════════════════════════════════════════════════════
+// ORÇAMENTOS
+// ═══════════════════════════════════════════════════════════
+
+// SetBudget define (ou substitui) o limite mensal de gastos da categoria
+func (s *FinanceService) SetBudget(ctx context.Context, budget models.Budget) (_ int64, err error) {
+	defer logging.Track("FinanceService.SetBudget", &err)()
+
+	erros := &ValidationError{}
+
+	if budget.CategoryID == 0 {
+		erros.Required("category_id", "categoria")
+	}
+
+	switch {
+	case budget.Amount == 0:
+		erros.Required("amount", "limite (maior que zero)")
+	case budget.Amount < 0:
+		erros.Invalid("amount", CodeOutOfRange, "limite (maior que zero)")
+	}
+
+	checkCurrency(erros, budget.Currency)
+
+	if erros.HasErrors() {
+		return 0, erros
+	}
+
+	budget.Currency = strings.ToUpper(budget.Currency)
+
+	id, err := s.store.Budgets().Save(ctx, budget)
+	if err != nil {
+		return 0, err
+	}
+
+	budget.ID = int(id)
+	s.bus.Publish(events.BudgetUpdated, budget.ID, budget)
+
+	return id, nil
+}
+
+// DeleteBudget remove um orçamento
+func (s *FinanceService) DeleteBudget(ctx context.Context, id int) (err error) {
+	defer logging.Track("FinanceService.DeleteBudget", &err)()
+
+	if err := s.store.Budgets().Delete(ctx, id); err != nil {
+		return fromRepository(err, "budget", id)
+	}
+
+	s.bus.Publish(events.BudgetDeleted, id, nil)
+
+	return nil
+}
+
+// GetBudgetStatus compara cada orçamento com os gastos do mês
+func (s *FinanceService) GetBudgetStatus(ctx context.Context, year, month int) (_ []models.BudgetStatus, err error) {
+	defer logging.Track("FinanceService.GetBudgetStatus", &err)()
+
+	start, end := monthBounds(year, month)
+
+	statuses, err := s.store.Budgets().ListSpent(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range statuses {
+		status := &statuses[i]
+		status.Remaining = status.Budget.Amount - status.Spent
+		status.Percent = float64(status.Spent) * 100 / float64(status.Budget.Amount)
+
+		switch {
+		case status.Spent > status.Budget.Amount:
+			status.Alert = "exceeded"
+		case status.Percent >= budgetWarningPercent:
+			status.Alert = "warning"
+		}
+	}
+
+	return statuses, nil
+}
+
+// GetBudgetAlerts retorna apenas os orçamentos em alerta no mês
+func (s *FinanceService) GetBudgetAlerts(ctx context.Context, year, month int) (_ []models.BudgetStatus, err error) {
+	defer logging.Track("FinanceService.GetBudgetAlerts", &err)()
+
+	statuses, err := s.GetBudgetStatus(ctx, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []models.BudgetStatus{}
+	for _, status := range statuses {
+		if status.Alert != "" {
+			alerts = append(alerts, status)
+		}
+	}
+
+	return alerts, nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// RECORRÊNCIAS
+// ═══════════════════════════════════════════════════════════
+
+// CreateRecurring cadastra um lançamento recorrente. A primeira ocorrência
+// é gerada em NextDate por ProcessRecurring.
+func (s *FinanceService) CreateRecurring(ctx context.Context, recurring models.RecurringTransaction) (_ int64, err error) {
+	defer logging.Track("FinanceService.CreateRecurring", &err)()
+
+	account, err := s.validateTransaction(ctx, models.Transaction{
+		AccountID: recurring.AccountID,
+		Amount:    recurring.Amount,
+		Date:      recurring.NextDate,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if recurring.Frequency == "" {
+		recurring.Frequency = "monthly"
+	}
+
+	if !oneOf(recurring.Frequency, recurringFreqs) {
+		erros := &ValidationError{}
+		erros.Invalid("frequency", CodeInvalidChoice, choiceMessage("frequência", recurringFreqs))
+		return 0, erros
+	}
+
+	recurring.Currency = account.Currency
+
+	id, err := s.store.Recurring().Create(ctx, recurring)
+	if err != nil {
+		return 0, err
+	}
+
+	recurring.ID = int(id)
+	s.bus.Publish(events.RecurringCreated, recurring.ID, recurring)
+
+	return id, nil
+}
+
+// GetAllRecurring retorna todos os lançamentos recorrentes
+func (s *FinanceService) GetAllRecurring(ctx context.Context) (_ []models.RecurringTransaction, err error) {
+	defer logging.Track("FinanceService.GetAllRecurring", &err)()
+
+	return s.store.Recurring().List(ctx)
+}
+
+// DeleteRecurring remove o lançamento recorrente (as transações já geradas permanecem)
+func (s *FinanceService) DeleteRecurring(ctx context.Context, id int) (err error) {
+	defer logging.Track("FinanceService.DeleteRecurring", &err)()
+
+	if err := s.store.Recurring().Delete(ctx, id); err != nil {
+		return fromRepository(err, "recurring", id)
+	}
+
+	s.bus.Publish(events.RecurringDeleted, id, nil)
+
+	return nil
+}
+
+// ProcessRecurring gera as transações de todas as recorrências vencidas até
+// a data informada (inclusive) e avança a próxima data de cada uma.
+// Retorna quantas transações foram criadas.
+func (s *FinanceService) ProcessRecurring(ctx context.Context, until time.Time) (_ int, err error) {
+	defer logging.Track("FinanceService.ProcessRecurring", &err)()
+
+	recurrings, err := s.GetAllRecurring(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	limit := until.Format(journalDateLayout)
+	created := 0
+
+	for _, recurring := range recurrings {
+		var generated []models.Transaction
+
+		err := s.store.WithTx(ctx, func(tx repository.Store) error {
+			next := recurring.NextDate
+			for next <= limit && (recurring.EndDate == nil || next <= *recurring.EndDate) {
+				recurringID := recurring.ID
+				transaction := models.Transaction{
+					AccountID:   recurring.AccountID,
+					CategoryID:  recurring.CategoryID,
+					Amount:      recurring.Amount,
+					Currency:    recurring.Currency,
+					Description: recurring.Description,
+					Date:        next,
+					RecurringID: &recurringID,
+				}
+
+				id, err := tx.Transactions().Create(ctx, transaction)
+				if err != nil {
+					return err
+				}
+				transaction.ID = int(id)
+				generated = append(generated, transaction)
+
+				next, err = advanceRecurrence(next, recurring.Frequency)
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(generated) == 0 {
+				return nil
+			}
+			return tx.Recurring().SetNextDate(ctx, recurring.ID, next)
+		})
+		if err != nil {
+			return created, err
+		}
+		created += len(generated)
+
+		for _, transaction := range generated {
+			s.bus.Publish(events.TransactionCreated, transaction.ID, transaction)
+		}
+	}
+
+	return created, nil
+}
+
+func advanceRecurrence(date string, frequency string) (string, error) {
+	current, err := time.Parse(journalDateLayout, date)
+	if err != nil {
+		return "", i18n.Errorf("data inválida na recorrência: %w", err)
+	}
+
+	switch frequency {
+	case "weekly":
+		current = current.AddDate(0, 0, 7)
+	case "yearly":
+		current = current.AddDate(1, 0, 0)
+	default:
+		current = current.AddDate(0, 1, 0)
+	}
+
+	return current.Format(journalDateLayout), nil
+}
+
+// checkCurrency marca a moeda vazia ou fora do formato ISO
+func checkCurrency(erros *ValidationError, code string) {
+	switch {
+	case code == "":
+		erros.Required("currency", "moeda (código ISO de 3 letras)")
+	case !validCurrency(code):
+		erros.Invalid("currency", CodeInvalid, "moeda (código ISO de 3 letras)")
+	}
+}
+
+func checkAccountType(erros *ValidationError, accountType string) {
+	if !oneOf(accountType, accountTypes) {
+		erros.Invalid("type", CodeInvalidChoice, choiceMessage("tipo", accountTypes))
+	}
+}
+
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range strings.ToUpper(code) {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// Moedas sem casas decimais; as demais usam 2
+var zeroDecimalCurrencies = map[string]bool{"JPY": true, "KRW": true, "CLP": true, "PYG": true, "VND": true}
+
+// ImportStatement importa um extrato bancário para a conta, escolhendo o
+// formato pela extensão do arquivo (.ofx/.qfx ou .csv)
+func (s *FinanceService) ImportStatement(ctx context.Context, accountID int, path string) (_ *models.ImportResult, err error) {
+	defer logging.Track("FinanceService.ImportStatement", &err)()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir extrato: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ofx", ".qfx":
+		return s.ImportOFX(ctx, accountID, file)
+	case ".csv", ".txt":
+		return s.ImportCSV(ctx, accountID, file)
+	default:
+		return nil, i18n.Errorf("formato de extrato não suportado: %s", filepath.Ext(path))
+	}
+}
+
+// ImportCSV importa transações de um CSV com cabeçalho. São reconhecidas as
+// colunas de data, descrição e valor (em português ou inglês), separadas por
+// vírgula ou ponto e vírgula.
+func (s *FinanceService) ImportCSV(ctx context.Context, accountID int, r io.Reader) (_ *models.ImportResult, err error) {
+	defer logging.Track("FinanceService.ImportCSV", &err)()
+
+	account, err := s.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler extrato: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectCSVSeparator(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler CSV: %w", err)
+	}
+
+	if len(records) < 2 {
+		return nil, i18n.Errorf("extrato CSV vazio")
+	}
+
+	dateCol, descCol, amountCol := -1, -1, -1
+	for i, header := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(header)) {
+		case "data", "date", "data lançamento", "data lancamento":
+			dateCol = i
+		case "descrição", "descricao", "description", "histórico", "historico", "memo":
+			descCol = i
+		case "valor", "amount", "value", "quantia":
+			amountCol = i
+		}
+	}
+
+	if dateCol < 0 || amountCol < 0 {
+		return nil, i18n.Errorf("CSV precisa das colunas de data e valor")
+	}
+
+	var entries []statementEntry
+	var parseErrors []string
+
+	for line, record := range records[1:] {
+		get := func(col int) string {
+			if col < 0 || col >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[col])
+		}
+
+		date, err := parseStatementDate(get(dateCol))
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("linha %d: %v", line+2, err))
+			continue
+		}
+
+		amount, err := parseMinorUnits(get(amountCol), account.Currency)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("linha %d: %v", line+2, err))
+			continue
+		}
+
+		entries = append(entries, statementEntry{Date: date, Amount: amount, Description: get(descCol)})
+	}
+
+	assignContentImportIDs(entries)
+
+	result, err := s.importEntries(ctx, account, entries)
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = append(parseErrors, result.Errors...)
+
+	return result, nil
+}
+
+// ofxTag captura "<TAG>valor" tanto no OFX 1.x (SGML, sem fechamento) quanto no 2.x (XML)
+var ofxTag = regexp.MustCompile(`<([A-Za-z0-9.]+)>([^<\r\n]*)`)
+
+// ImportOFX importa as transações (STMTTRN) de um extrato OFX/QFX.
+// O FITID do banco é usado para não importar a mesma transação duas vezes.
+func (s *FinanceService) ImportOFX(ctx context.Context, accountID int, r io.Reader) (_ *models.ImportResult, err error) {
+	defer logging.Track("FinanceService.ImportOFX", &err)()
+
+	account, err := s.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler extrato: %w", err)
+	}
+
+	content := string(data)
+	upper := strings.ToUpper(content)
+
+	if curdef := ofxValue(content, "CURDEF"); curdef != "" && !strings.EqualFold(curdef, account.Currency) {
+		return nil, i18n.Errorf("extrato em %s, mas a conta usa %s", curdef, account.Currency)
+	}
+
+	var entries []statementEntry
+	var parseErrors []string
+
+	for {
+		start := strings.Index(upper, "<STMTTRN>")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(upper[start:], "</STMTTRN>")
+		if end < 0 {
+			end = len(upper) - start
+		}
+
+		block := content[start : start+end]
+		content, upper = content[start+end:], upper[start+end:]
+		if len(content) > 0 {
+			content, upper = content[1:], upper[1:]
+		}
+
+		fitID := ofxValue(block, "FITID")
+
+		date, err := parseStatementDate(ofxValue(block, "DTPOSTED"))
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("transação %s: %v", fitID, err))
+			continue
+		}
+
+		amount, err := parseOFXAmount(ofxValue(block, "TRNAMT"), account.Currency)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("transação %s: %v", fitID, err))
+			continue
+		}
+
+		description := ofxValue(block, "NAME")
+		if memo := ofxValue(block, "MEMO"); memo != "" && memo != description {
+			description = strings.TrimSpace(description + " " + memo)
+		}
+
+		entry := statementEntry{Date: date, Amount: amount, Description: description}
+		if fitID != "" {
+			entry.ImportID = "ofx:" + fitID
+		}
+		entries = append(entries, entry)
+	}
+
+	assignContentImportIDs(entries)
+
+	result, err := s.importEntries(ctx, account, entries)
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = append(parseErrors, result.Errors...)
+
+	return result, nil
+}
+
+// statementEntry é uma linha de extrato já normalizada
+type statementEntry struct {
+	Date        string
+	Amount      int64
+	Description string
+	ImportID    string
+}
+
+// importEntries grava as transações em uma única transação do banco,
+// ignorando as que já foram importadas
+func (s *FinanceService) importEntries(ctx context.Context, account *models.Account, entries []statementEntry) (*models.ImportResult, error) {
+	result := &models.ImportResult{Errors: []string{}}
+
+	err := s.store.WithTx(ctx, func(tx repository.Store) error {
+		for i, entry := range entries {
+			// Cancelada no meio, a importação inteira é desfeita
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			inserted, err := tx.Transactions().Import(ctx, models.Transaction{
+				AccountID:   account.ID,
+				Amount:      entry.Amount,
+				Currency:    account.Currency,
+				Description: entry.Description,
+				Date:        entry.Date,
+				ImportID:    entry.ImportID,
+			})
+			ReportProgress(ctx, i+1, len(entries))
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s %s: %v", entry.Date, entry.Description, err))
+				continue
+			}
+
+			if inserted {
+				result.Imported++
+			} else {
+				result.Skipped++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Imported > 0 {
+		s.bus.Publish(events.TransactionsImported, account.ID, result)
+	}
+
+	return result, nil
+}
+
+// assignContentImportIDs gera um ID estável para linhas sem identificador do
+// banco, a partir de data, valor, descrição e ordem de repetição no arquivo
+func assignContentImportIDs(entries []statementEntry) {
+	seen := make(map[string]int)
+
+	for i := range entries {
+		if entries[i].ImportID != "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%d|%s", entries[i].Date, entries[i].Amount, strings.ToLower(entries[i].Description))
+		seen[key]++
+
+		sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, seen[key])))
+		entries[i].ImportID = "hash:" + hex.EncodeToString(sum[:])
+	}
+}
+
+func ofxValue(block string, tag string) string {
+	for _, match := range ofxTag.FindAllStringSubmatch(block, -1) {
+		if strings.EqualFold(match[1], tag) {
+			return strings.TrimSpace(match[2])
+		}
+	}
+	return ""
+}
+
+// parseStatementDate aceita AAAA-MM-DD, DD/MM/AAAA e o formato OFX (AAAAMMDD...)
+func parseStatementDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "02/01/06", "20060102"} {
+		candidate := value
+		if layout == "20060102" && len(candidate) > 8 {
+			candidate = candidate[:8]
+		}
+		if date, err := time.Parse(layout, candidate); err == nil {
+			return date.Format(journalDateLayout), nil
+		}
+	}
+
+	return "", i18n.Errorf("data inválida: %q", value)
+}
+
+// parseMinorUnits converte valores de extrato CSV, como "1.234,56",
+// "1,234.56", "-12.5", "R$ -10,00" ou "(10,00)", em unidades mínimas da
+// moeda, sem passar por float. Um "-" antes do primeiro dígito, no final ou
+// os parênteses indicam valor negativo.
+func parseMinorUnits(value string, currency string) (int64, error) {
+	original := value
+	value = strings.TrimSpace(value)
+
+	first := strings.IndexAny(value, "0123456789")
+	if first < 0 {
+		return 0, i18n.Errorf("valor inválido: %q", original)
+	}
+
+	negative := strings.Contains(value[:first], "-") || strings.HasSuffix(value, "-") ||
+		(strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")"))
+
+	var cleaned strings.Builder
+	for _, r := range value[first:] {
+		if (r >= '0' && r <= '9') || r == ',' || r == '.' {
+			cleaned.WriteRune(r)
+		}
+	}
+	value = cleaned.String()
+
+	// O último separador é o decimal, a não ser que só separe milhares
+	intPart, fracPart := value, ""
+	if i := strings.LastIndexAny(value, ",."); i >= 0 && !thousandsOnly(value, value[i]) {
+		intPart, fracPart = value[:i], value[i+1:]
+	}
+	intPart = strings.NewReplacer(",", "", ".", "").Replace(intPart)
+
+	return minorUnits(negative, intPart, fracPart, currency, original)
+}
+
+// thousandsOnly diz se sep, sem o outro separador no valor, divide o número
+// em grupos de milhar ("1.234", "12,345,678")
+func thousandsOnly(value string, sep byte) bool {
+	other := "."
+	if sep == '.' {
+		other = ","
+	}
+	if strings.Contains(value, other) {
+		return false
+	}
+
+	groups := strings.Split(value, string(sep))
+	if len(groups[0]) == 0 || len(groups[0]) > 3 || groups[0][0] == '0' {
+		return false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// parseOFXAmount converte o TRNAMT do OFX: sinal opcional, dígitos e o ponto
+// como separador decimal, sem separador de milhar
+func parseOFXAmount(value string, currency string) (int64, error) {
+	original := value
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if intPart+fracPart == "" || !onlyDigits(intPart) || !onlyDigits(fracPart) {
+		return 0, i18n.Errorf("valor inválido: %q", original)
+	}
+
+	return minorUnits(negative, intPart, fracPart, currency, original)
+}
+
+// minorUnits junta as partes inteira e decimal (só dígitos) em unidades
+// mínimas da moeda. Casas decimais além das da moeda são erro, a não ser
+// que sejam zeros.
+func minorUnits(negative bool, intPart, fracPart, currency, original string) (int64, error) {
+	decimals := 2
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		decimals = 0
+	}
+
+	if len(fracPart) > decimals {
+		if strings.Trim(fracPart[decimals:], "0") != "" {
+			return 0, i18n.Errorf("valor com mais casas decimais do que a moeda permite (%d): %q", decimals, original)
+		}
+		fracPart = fracPart[:decimals]
+	}
+	for len(fracPart) < decimals {
+		fracPart += "0"
+	}
+
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	amount, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, i18n.Errorf("valor inválido: %q", original)
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+func onlyDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// detectCSVSeparator escolhe entre vírgula e ponto e vírgula pela primeira linha
+func detectCSVSeparator(data []byte) rune {
+	firstLine, _ := bufio.NewReader(bytes.NewReader(data)).ReadString('\n')
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		return ';'
+	}
+	return ','
+}
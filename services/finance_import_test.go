@@ -0,0 +1,78 @@
+package services
+
+import "testing"
+
+func TestParseMinorUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"1.234,56", "BRL", 123456, false},
+		{"1,234.56", "USD", 123456, false},
+		{"-12.5", "BRL", -1250, false},
+		{"R$ 10,00", "BRL", 1000, false},
+		{"R$ -10,00", "BRL", -1000, false},
+		{"-R$ 10,00", "BRL", -1000, false},
+		{"10,00-", "BRL", -1000, false},
+		{"(10,00)", "BRL", -1000, false},
+		{"1.234", "BRL", 123400, false},
+		{"12,345,678", "USD", 1234567800, false},
+		{"1234.5", "BRL", 123450, false},
+		{"0,99", "BRL", 99, false},
+		{"1.234.567,8", "BRL", 123456780, false},
+		{"1.500", "JPY", 1500, false},
+		{"2.000,00", "BRL", 200000, false},
+		{"1234.500", "BRL", 123450, false},
+		{"-1234.567", "BRL", 0, true},
+		{"0.125", "USD", 0, true},
+		{"1.234,567", "BRL", 0, true},
+		{"1500.5", "JPY", 0, true},
+		{"", "BRL", 0, true},
+		{"R$", "BRL", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseMinorUnits(tt.value, tt.currency)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMinorUnits(%q, %s) erro = %v, quero erro = %v", tt.value, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseMinorUnits(%q, %s) = %d, quero %d", tt.value, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseOFXAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"-45.90", "BRL", -4590, false},
+		{"+1200.00", "BRL", 120000, false},
+		{"1200", "BRL", 120000, false},
+		{"1.234", "BRL", 0, true},
+		{"-.5", "USD", -50, false},
+		{"1500", "JPY", 1500, false},
+		{"1,50", "BRL", 0, true},
+		{"1,234.56", "USD", 0, true},
+		{"-1234.567", "BRL", 0, true},
+		{"12.3.4", "BRL", 0, true},
+		{"-", "BRL", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseOFXAmount(tt.value, tt.currency)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseOFXAmount(%q, %s) erro = %v, quero erro = %v", tt.value, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseOFXAmount(%q, %s) = %d, quero %d", tt.value, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+func newMemoryFinanceService() (*FinanceService, *repository.Memory) {
+	store := repository.NewMemory()
+	return NewFinanceServiceWithStore(store, events.NewBus()), store
+}
+
+func TestFinanceCRUD(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newMemoryFinanceService()
+
+	accountID, err := s.CreateAccount(ctx, models.Account{Name: "Conta corrente", Currency: "brl", InitialBalance: 10000})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	var conflict *ConflictError
+	if _, err := s.CreateAccount(ctx, models.Account{Name: "Conta corrente", Currency: "BRL"}); !errors.As(err, &conflict) {
+		t.Fatalf("CreateAccount com nome repetido: %v", err)
+	}
+
+	transactionID, err := s.CreateTransaction(ctx, models.Transaction{
+		AccountID:   int(accountID),
+		Amount:      -2550,
+		Description: "Mercado",
+		Date:        "2024-05-10",
+	})
+	if err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+
+	account, err := s.GetAccountByID(ctx, int(accountID))
+	if err != nil {
+		t.Fatalf("GetAccountByID: %v", err)
+	}
+	if account.Currency != "BRL" || account.Balance != 7450 {
+		t.Fatalf("conta = %+v", account)
+	}
+
+	err = s.UpdateTransaction(ctx, models.Transaction{
+		ID:          int(transactionID),
+		AccountID:   int(accountID),
+		Amount:      -3000,
+		Description: "Mercado e feira",
+		Date:        "2024-05-11",
+	})
+	if err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+
+	transactions, err := s.GetTransactions(ctx, models.TransactionFilter{Query: "feira"})
+	if err != nil || len(transactions) != 1 {
+		t.Fatalf("GetTransactions = %+v, %v", transactions, err)
+	}
+	if got := transactions[0]; got.Amount != -3000 || got.Currency != "BRL" || got.Date != "2024-05-11" {
+		t.Fatalf("transação atualizada = %+v", got)
+	}
+
+	summary, err := s.GetMonthlySummary(ctx, 2024, 5)
+	if err != nil {
+		t.Fatalf("GetMonthlySummary: %v", err)
+	}
+	if len(summary.Totals) != 1 || summary.Totals[0].Expense != 3000 {
+		t.Fatalf("resumo = %+v", summary)
+	}
+
+	if err := s.DeleteTransaction(ctx, int(transactionID)); err != nil {
+		t.Fatalf("DeleteTransaction: %v", err)
+	}
+
+	var notFound *NotFoundError
+	if err := s.DeleteTransaction(ctx, int(transactionID)); !errors.As(err, &notFound) {
+		t.Fatalf("DeleteTransaction de novo: %v", err)
+	}
+
+	if err := s.DeleteAccount(ctx, int(accountID)); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if _, err := s.GetAccountByID(ctx, int(accountID)); !errors.As(err, &notFound) {
+		t.Fatalf("GetAccountByID depois de deletar: %v", err)
+	}
+}
+
+func TestBudgetStatus(t *testing.T) {
+	ctx := context.Background()
+	s, store := newMemoryFinanceService()
+
+	categoryID, err := store.Categories().Create(ctx, models.Category{Name: "Mercado", Type: "finance"})
+	if err != nil {
+		t.Fatalf("criar categoria: %v", err)
+	}
+	category := int(categoryID)
+
+	accountID, err := s.CreateAccount(ctx, models.Account{Name: "Carteira", Type: "cash", Currency: "BRL"})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	if _, err := s.SetBudget(ctx, models.Budget{CategoryID: category, Amount: 10000, Currency: "BRL"}); err != nil {
+		t.Fatalf("SetBudget: %v", err)
+	}
+	// Mesma categoria e moeda: troca o limite
+	if _, err := s.SetBudget(ctx, models.Budget{CategoryID: category, Amount: 5000, Currency: "BRL"}); err != nil {
+		t.Fatalf("SetBudget de novo: %v", err)
+	}
+
+	for _, date := range []string{"2024-05-02", "2024-05-20", "2024-06-01"} {
+		_, err := s.CreateTransaction(ctx, models.Transaction{AccountID: int(accountID), CategoryID: &category, Amount: -2000, Date: date})
+		if err != nil {
+			t.Fatalf("CreateTransaction: %v", err)
+		}
+	}
+
+	statuses, err := s.GetBudgetStatus(ctx, 2024, 5)
+	if err != nil {
+		t.Fatalf("GetBudgetStatus: %v", err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("orçamentos = %+v", statuses)
+	}
+	if got := statuses[0]; got.Budget.Amount != 5000 || got.Spent != 4000 || got.Alert != "warning" {
+		t.Fatalf("status = %+v", got)
+	}
+}
+
+func TestProcessRecurringRollsBack(t *testing.T) {
+	ctx := context.Background()
+	s, store := newMemoryFinanceService()
+
+	accountID, err := s.CreateAccount(ctx, models.Account{Name: "Conta", Currency: "BRL"})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	if _, err := s.CreateRecurring(ctx, models.RecurringTransaction{
+		AccountID:   int(accountID),
+		Amount:      -9990,
+		Description: "Academia",
+		NextDate:    "2024-01-15",
+	}); err != nil {
+		t.Fatalf("CreateRecurring: %v", err)
+	}
+
+	created, err := s.ProcessRecurring(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || created != 2 {
+		t.Fatalf("ProcessRecurring = %d, %v", created, err)
+	}
+
+	// Data fora do formato (gravada por fora da validação): a transação é
+	// criada e a falha ao calcular a próxima data desfaz tudo
+	if _, err := store.Recurring().Create(ctx, models.RecurringTransaction{
+		AccountID: int(accountID),
+		Amount:    -100,
+		Currency:  "BRL",
+		Frequency: "monthly",
+		NextDate:  "2024-02-3",
+	}); err != nil {
+		t.Fatalf("criar recorrência: %v", err)
+	}
+
+	if _, err := s.ProcessRecurring(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Fatal("ProcessRecurring com data inválida deveria falhar")
+	}
+
+	transactions, err := s.GetTransactions(ctx, models.TransactionFilter{})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(transactions) != 2 {
+		t.Fatalf("transações depois do rollback = %+v", transactions)
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+)
+
+// frontMatter é o cabeçalho YAML ("---" ... "---") de um arquivo Markdown.
+// Só pares "chave: valor" simples são interpretados; as outras linhas (listas,
+// chaves de outros editores) ficam em extra, na ordem, para serem regravadas.
+type frontMatter struct {
+	values map[string]string
+	extra  []string
+}
+
+// splitFrontMatter separa o cabeçalho do corpo. Sem cabeçalho, tudo é corpo.
+func splitFrontMatter(data string) (frontMatter, string) {
+	fm := frontMatter{values: map[string]string{}}
+
+	text := strings.TrimPrefix(data, "\ufeff")
+	if !strings.HasPrefix(text, "---\n") && !strings.HasPrefix(text, "---\r\n") {
+		return fm, data
+	}
+
+	rest := text[strings.Index(text, "\n")+1:]
+	var header []string
+	body := ""
+	closed := false
+
+	for rest != "" {
+		line, next, found := strings.Cut(rest, "\n")
+		if !found {
+			next = ""
+		}
+		line = strings.TrimRight(line, "\r")
+		rest = next
+		if line == "---" {
+			closed = true
+			body = rest
+			break
+		}
+		header = append(header, line)
+	}
+	if !closed {
+		return fm, data
+	}
+
+	for _, line := range header {
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "#") {
+			fm.extra = append(fm.extra, line)
+			continue
+		}
+		if !isFrontMatterKey(key) {
+			fm.extra = append(fm.extra, line)
+			continue
+		}
+		fm.values[key] = unquoteYAML(strings.TrimSpace(value))
+	}
+
+	return fm, body
+}
+
+// Chaves escritas pelo app; as outras são preservadas como vieram
+var frontMatterKeys = []string{"id", "title", "category", "favorite", "created", "updated"}
+
+func isFrontMatterKey(key string) bool {
+	return oneOf(key, frontMatterKeys)
+}
+
+// renderFrontMatter escreve o cabeçalho com as chaves do app na ordem de
+// frontMatterKeys, seguidas das linhas preservadas
+func renderFrontMatter(values map[string]string, extra []string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, key := range frontMatterKeys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		b.WriteString(key + ": " + quoteYAML(value) + "\n")
+	}
+	for _, line := range extra {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("---\n")
+	return b.String()
+}
+
+// quoteYAML põe aspas só quando o valor seria lido de outro jeito sem elas
+func quoteYAML(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	plain := !strings.ContainsAny(value, ":#\"'\n\\[]{}") &&
+		strings.TrimSpace(value) == value &&
+		!strings.ContainsAny(value[:1], "-?!&*|>%@`,")
+	if plain {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func unquoteYAML(value string) string {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	// Comentário no fim da linha ("favorite: true # fixada")
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+	"personal-cockpit/timezone"
+)
+
+const journalDateLayout = "2006-01-02"
+
+// JournalService gerencia as entradas do diário (uma por dia)
+type JournalService struct {
+	store        repository.UnitOfWork
+	bus          *events.Bus
+	taskService  *TaskService
+	eventService *EventService
+}
+
+// NewJournalService cria novo serviço de diário
+func NewJournalService(db *sql.DB, bus *events.Bus, taskService *TaskService, eventService *EventService) *JournalService {
+	return NewJournalServiceWithStore(repository.NewSQLite(db), bus, taskService, eventService)
+}
+
+// NewJournalServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewJournalServiceWithStore(store repository.UnitOfWork, bus *events.Bus, taskService *TaskService, eventService *EventService) *JournalService {
+	return &JournalService{store: store, bus: bus, taskService: taskService, eventService: eventService}
+}
+
+// GetOrCreateToday retorna a entrada de hoje, criando-a se ainda não existir
+func (s *JournalService) GetOrCreateToday(ctx context.Context) (_ *models.JournalEntry, err error) {
+	defer logging.Track("JournalService.GetOrCreateToday", &err)()
+
+	return s.GetOrCreateEntry(ctx, timezone.In(time.Now()).Format(journalDateLayout))
+}
+
+// GetOrCreateEntry retorna a entrada da data, criando-a se ainda não existir
+func (s *JournalService) GetOrCreateEntry(ctx context.Context, date string) (_ *models.JournalEntry, err error) {
+	defer logging.Track("JournalService.GetOrCreateEntry", &err)()
+
+	if err := validateJournalDate(date); err != nil {
+		return nil, err
+	}
+
+	created, err := s.store.Journal().Ensure(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.GetEntryByDate(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		s.bus.Publish(events.JournalCreated, entry.ID, entry)
+	}
+
+	return entry, nil
+}
+
+// GetEntryByDate busca a entrada de uma data, com as tarefas e eventos do dia
+func (s *JournalService) GetEntryByDate(ctx context.Context, date string) (_ *models.JournalEntry, err error) {
+	defer logging.Track("JournalService.GetEntryByDate", &err)()
+
+	entry, err := s.store.Journal().GetByDate(ctx, date)
+	if err != nil {
+		return nil, fromRepository(err, "journal", 0)
+	}
+
+	if err := s.attachDayReferences(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetPreviousEntry retorna a entrada existente mais próxima antes da data
+func (s *JournalService) GetPreviousEntry(ctx context.Context, date string) (_ *models.JournalEntry, err error) {
+	defer logging.Track("JournalService.GetPreviousEntry", &err)()
+
+	return s.adjacentEntry(ctx, date, false)
+}
+
+// GetNextEntry retorna a entrada existente mais próxima depois da data
+func (s *JournalService) GetNextEntry(ctx context.Context, date string) (_ *models.JournalEntry, err error) {
+	defer logging.Track("JournalService.GetNextEntry", &err)()
+
+	return s.adjacentEntry(ctx, date, true)
+}
+
+func (s *JournalService) adjacentEntry(ctx context.Context, date string, after bool) (*models.JournalEntry, error) {
+	found, err := s.store.Journal().Adjacent(ctx, date, after)
+	if err != nil {
+		return nil, fromRepository(err, "journal", 0)
+	}
+
+	return s.GetEntryByDate(ctx, found)
+}
+
+// UpdateEntry atualiza humor, energia, tags e texto da entrada
+func (s *JournalService) UpdateEntry(ctx context.Context, entry models.JournalEntry) (err error) {
+	defer logging.Track("JournalService.UpdateEntry", &err)()
+
+	if entry.ID == 0 {
+		return requiredID("ID da entrada")
+	}
+
+	erros := &ValidationError{}
+
+	if entry.Mood != nil && (*entry.Mood < 1 || *entry.Mood > 5) {
+		erros.Invalid("mood", CodeOutOfRange, "humor deve estar entre 1 e 5")
+	}
+
+	if entry.Energy != nil && (*entry.Energy < 1 || *entry.Energy > 5) {
+		erros.Invalid("energy", CodeOutOfRange, "energia deve estar entre 1 e 5")
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+
+	err = s.store.Journal().Update(ctx, entry)
+	if err != nil {
+		return fromRepository(err, "journal", entry.ID)
+	}
+
+	publishEntity(ctx, s.bus, events.JournalUpdated, entry.ID, s.getEntryByID)
+
+	return nil
+}
+
+// getEntryByID busca a entrada pelo ID, com as referências do dia
+func (s *JournalService) getEntryByID(ctx context.Context, id int) (*models.JournalEntry, error) {
+	entry, err := s.store.Journal().GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound("journal", id)
+	}
+	return s.GetEntryByDate(ctx, entry.Date)
+}
+
+// DeleteEntry deleta uma entrada do diário
+func (s *JournalService) DeleteEntry(ctx context.Context, id int) (err error) {
+	defer logging.Track("JournalService.DeleteEntry", &err)()
+
+	err = s.store.Journal().Delete(ctx, id)
+	if err != nil {
+		return fromRepository(err, "journal", id)
+	}
+
+	s.bus.Publish(events.JournalDeleted, id, nil)
+
+	return nil
+}
+
+// SearchEntries busca entradas por período, texto e tag. As referências a
+// tarefas e eventos não são carregadas na listagem.
+func (s *JournalService) SearchEntries(ctx context.Context, filter models.JournalFilter) (_ []models.JournalEntry, err error) {
+	defer logging.Track("JournalService.SearchEntries", &err)()
+
+	return s.store.Journal().Search(ctx, filter)
+}
+
+// GetMoodTrend retorna a série de humor e energia no período (datas inclusivas)
+func (s *JournalService) GetMoodTrend(ctx context.Context, startDate, endDate string) (_ []models.MoodPoint, err error) {
+	defer logging.Track("JournalService.GetMoodTrend", &err)()
+
+	series, err := s.store.Journal().MoodSeries(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []models.MoodPoint{}
+	var window []int
+
+	for _, point := range series {
+		if point.Mood != nil {
+			window = append(window, *point.Mood)
+			if len(window) > 7 {
+				window = window[1:]
+			}
+		}
+
+		if len(window) > 0 {
+			sum := 0
+			for _, mood := range window {
+				sum += mood
+			}
+			point.MoodAverage = float64(sum) / float64(len(window))
+		}
+
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// attachDayReferences preenche as tarefas concluídas e os eventos que já
+// aconteceram no dia da entrada
+func (s *JournalService) attachDayReferences(ctx context.Context, entry *models.JournalEntry) error {
+	day, err := timezone.ParseDate(entry.Date)
+	if err != nil {
+		return i18n.Errorf("data inválida: %w", err)
+	}
+
+	tasks, err := s.taskService.GetTasksCompletedOn(ctx, entry.Date)
+	if err != nil {
+		return err
+	}
+	entry.CompletedTasks = tasks
+
+	events, err := s.eventService.GetEventsByDateRange(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry.Events = []models.Event{}
+	for _, event := range events {
+		if event.StartDate.Before(now) {
+			entry.Events = append(entry.Events, event)
+		}
+	}
+
+	return nil
+}
+
+func validateJournalDate(date string) error {
+	if _, err := time.Parse(journalDateLayout, date); err != nil {
+		return i18n.Errorf("data inválida, use o formato AAAA-MM-DD")
+	}
+	return nil
+}
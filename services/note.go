@@ -1,263 +1,325 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
+	"personal-cockpit/events"
+	"personal-cockpit/logging"
 	"personal-cockpit/models"
+	"personal-cockpit/repository"
 )
 
 type NoteService struct {
-	db *sql.DB
+	store       repository.UnitOfWork
+	bus         *events.Bus
+	taskService *TaskService
 }
 
-func NewNoteService(db *sql.DB) *NoteService {
-	return &NoteService{db: db}
+func NewNoteService(db *sql.DB, bus *events.Bus, taskService *TaskService) *NoteService {
+	return NewNoteServiceWithStore(repository.NewSQLite(db), bus, taskService)
 }
 
-func (s *NoteService) CreateNote(note models.Note) (int64, error) {
-	var erros []string
+// NewNoteServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewNoteServiceWithStore(store repository.UnitOfWork, bus *events.Bus, taskService *TaskService) *NoteService {
+	return &NoteService{store: store, bus: bus, taskService: taskService}
+}
+
+func (s *NoteService) CreateNote(ctx context.Context, note models.Note) (_ int64, err error) {
+	defer logging.Track("NoteService.CreateNote", &err)()
+
+	erros := &ValidationError{}
 
 	if note.Title == "" {
-		erros = append(erros, "título")
+		erros.Required("title", "título")
 	}
 
-	if len(erros) > 0 {
-		mensagem := "Campos obrigatórios:\n- " + strings.Join(erros, "\n- ")
-		return 0, fmt.Errorf(mensagem)
+	if erros.HasErrors() {
+		return 0, erros
 	}
 
-	query := `
-		INSERT INTO notes (title, content, category_id, is_favorite)
-		VALUES (?, ?, ?, ?)
-	`
-
-	result, err := s.db.Exec(
-		query,
-		note.Title,
-		note.Content,
-		note.CategoryID,
-		note.IsFavorite,
-	)
-
+	id, err := s.store.Notes().Create(ctx, note)
 	if err != nil {
-		return 0, fmt.Errorf("erro ao criar nota: %w", err)
+		return 0, err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("erro ao obter ID: %w", err)
-	}
+	publishEntity(ctx, s.bus, events.NoteCreated, int(id), s.GetNoteByID)
 
 	return id, nil
 }
 
-func (s *NoteService) GetAllNotes() ([]models.Note, error) {
-	query := `
-		SELECT id, title, content, category_id, is_favorite, created_at, updated_at
-		FROM notes
-		ORDER BY updated_at DESC
-	`
+func (s *NoteService) GetAllNotes(ctx context.Context) (_ []models.Note, err error) {
+	defer logging.Track("NoteService.GetAllNotes", &err)()
 
-	rows, err := s.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar notas: %w", err)
-	}
-	defer rows.Close()
-
-	var notes []models.Note
-
-	for rows.Next() {
-		var note models.Note
-		err := rows.Scan(
-			&note.ID,
-			&note.Title,
-			&note.Content,
-			&note.CategoryID,
-			&note.IsFavorite,
-			&note.CreatedAt,
-			&note.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler nota: %w", err)
-		}
-
-		notes = append(notes, note)
-	}
-
-	return notes, nil
+	return s.store.Notes().List(ctx, repository.NoteQuery{})
 }
 
-func (s *NoteService) GetNoteByID(id int) (*models.Note, error) {
-	query := `
-		SELECT id, title, content, category_id, is_favorite, created_at, updated_at
-		FROM notes
-		WHERE id = ?
-	`
-
-	var note models.Note
-	err := s.db.QueryRow(query, id).Scan(
-		&note.ID,
-		&note.Title,
-		&note.Content,
-		&note.CategoryID,
-		&note.IsFavorite,
-		&note.CreatedAt,
-		&note.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("nota não encontrada")
-	}
+func (s *NoteService) GetNoteByID(ctx context.Context, id int) (_ *models.Note, err error) {
+	defer logging.Track("NoteService.GetNoteByID", &err)()
+
+	note, err := s.store.Notes().GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar nota: %w", err)
+		return nil, fromRepository(err, "note", id)
 	}
 
-	return &note, nil
+	return note, nil
 }
 
-func (s *NoteService) UpdateNote(note models.Note) error {
+// UpdateNote salva a nota e, na mesma transação, o status das tarefas
+// vinculadas aos itens de checklist marcados ou desmarcados
+func (s *NoteService) UpdateNote(ctx context.Context, note models.Note) (err error) {
+	defer logging.Track("NoteService.UpdateNote", &err)()
+
 	if note.ID == 0 {
-		return fmt.Errorf("ID da nota é obrigatório")
+		return requiredID("ID da nota")
 	}
 
-	query := `
-		UPDATE notes 
-		SET title = ?, content = ?, category_id = ?, is_favorite = ?
-		WHERE id = ?
-	`
-
-	result, err := s.db.Exec(
-		query,
-		note.Title,
-		note.Content,
-		note.CategoryID,
-		note.IsFavorite,
-		note.ID,
-	)
+	var changes []taskStatusChange
 
-	if err != nil {
-		return fmt.Errorf("erro ao atualizar nota: %w", err)
-	}
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		if err := tx.Notes().Update(ctx, note); err != nil {
+			return err
+		}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+		var err error
+		changes, err = syncChecklistTasks(ctx, tx, note.ID, note.Content)
 		return err
+	})
+	if err != nil {
+		return fromRepository(err, "note", note.ID)
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("nota não encontrada")
+	publishEntity(ctx, s.bus, events.NoteUpdated, note.ID, s.GetNoteByID)
+	for _, change := range changes {
+		publishEntity(ctx, s.bus, change.event, change.taskID, s.taskService.GetTaskByID)
 	}
 
 	return nil
 }
 
-func (s *NoteService) DeleteNote(id int) error {
-	query := "DELETE FROM notes WHERE id = ?"
+func (s *NoteService) DeleteNote(ctx context.Context, id int) (err error) {
+	defer logging.Track("NoteService.DeleteNote", &err)()
 
-	result, err := s.db.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("erro ao deletar nota: %w", err)
+	if err := s.store.Notes().Delete(ctx, id); err != nil {
+		return fromRepository(err, "note", id)
 	}
 
-	rows, err := result.RowsAffected()
+	s.bus.Publish(events.NoteDeleted, id, nil)
+
+	return nil
+}
+
+func (s *NoteService) ToggleFavorite(ctx context.Context, id int) (err error) {
+	defer logging.Track("NoteService.ToggleFavorite", &err)()
+
+	note, err := s.GetNoteByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("nota não encontrada")
+	if err := s.store.Notes().SetFavorite(ctx, id, !note.IsFavorite); err != nil {
+		return fromRepository(err, "note", id)
 	}
 
+	publishEntity(ctx, s.bus, events.NoteUpdated, id, s.GetNoteByID)
+
 	return nil
 }
 
-func (s *NoteService) ToggleFavorite(id int) error {
-	note, err := s.GetNoteByID(id)
+func (s *NoteService) GetFavoriteNotes(ctx context.Context) (_ []models.Note, err error) {
+	defer logging.Track("NoteService.GetFavoriteNotes", &err)()
+
+	return s.store.Notes().List(ctx, repository.NoteQuery{FavoritesOnly: true})
+}
+
+func (s *NoteService) SearchNotes(ctx context.Context, searchQuery string) (_ []models.Note, err error) {
+	defer logging.Track("NoteService.SearchNotes", &err)()
+
+	return s.store.Notes().List(ctx, repository.NoteQuery{Search: searchQuery})
+}
+
+// ExtractTasks cria uma tarefa para cada item de checklist não marcado da nota.
+// Cada linha convertida recebe o marcador "^task-ID", que mantém a linha e a
+// tarefa sincronizadas nos dois sentidos. As tarefas e a nota são gravadas
+// juntas: se algo falhar, nenhuma tarefa fica sem o marcador na nota. Tarefas
+// pendentes da nota que já estejam sem marcador são religadas pelo título, e
+// repetir a extração não duplica nada. Marcadores de tarefas que não existem
+// mais contam como linhas sem marcador.
+func (s *NoteService) ExtractTasks(ctx context.Context, noteID int) (_ []models.Task, err error) {
+	defer logging.Track("NoteService.ExtractTasks", &err)()
+
+	var tasks []models.Task
+	changed := false
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		note, err := tx.Notes().GetByID(ctx, noteID)
+		if err != nil {
+			return err
+		}
+
+		content := note.Content
+		items := parseChecklist(note.Content)
+
+		// Marcador de tarefa apagada (ou de outra base): a linha volta a ser
+		// um item sem tarefa
+		for i, item := range items {
+			if item.TaskID == 0 {
+				continue
+			}
+			if _, err := tx.Tasks().GetByID(ctx, item.TaskID); !errors.Is(err, repository.ErrNotFound) {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			items[i].TaskID = 0
+			if item.Checked {
+				content = unlinkChecklistLine(content, item.Line)
+				changed = true
+			}
+		}
+
+		orphans, err := unlinkedNoteTasks(ctx, tx, note.ID, items)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if item.Checked || item.TaskID != 0 {
+				continue
+			}
+
+			// Tarefa já criada para a linha, mas sem o marcador na nota
+			// (extração interrompida): religa em vez de duplicar
+			key := strings.ToLower(item.Text)
+			if pending := orphans[key]; len(pending) > 0 {
+				orphans[key] = pending[1:]
+				content = linkChecklistLine(content, item.Line, pending[0].ID)
+				changed = true
+				continue
+			}
+
+			task := models.Task{
+				Title:       item.Text,
+				Description: fmt.Sprintf("Extraída da nota \"%s\"", note.Title),
+				Status:      "pending",
+				Priority:    "medium",
+				NoteID:      &note.ID,
+			}
+
+			if err := validateTask(task); err != nil {
+				return err
+			}
+
+			id, err := tx.Tasks().Create(ctx, task)
+			if err != nil {
+				return err
+			}
+
+			created, err := tx.Tasks().GetByID(ctx, int(id))
+			if err != nil {
+				return err
+			}
+
+			content = linkChecklistLine(content, item.Line, created.ID)
+			tasks = append(tasks, *created)
+		}
+
+		if len(tasks) == 0 && !changed {
+			return nil
+		}
+
+		return tx.Notes().SetContent(ctx, noteID, content)
+	})
 	if err != nil {
-		return err
+		return nil, fromRepository(err, "note", noteID)
+	}
+
+	if len(tasks) == 0 && !changed {
+		return tasks, nil
 	}
 
-	query := "UPDATE notes SET is_favorite = ? WHERE id = ?"
-	_, err = s.db.Exec(query, !note.IsFavorite, id)
+	for _, task := range tasks {
+		s.bus.Publish(events.TaskCreated, task.ID, task)
+	}
+	publishEntity(ctx, s.bus, events.NoteUpdated, noteID, s.GetNoteByID)
 
-	return err
+	return tasks, nil
 }
 
-func (s *NoteService) GetFavoriteNotes() ([]models.Note, error) {
-	query := `
-		SELECT id, title, content, category_id, is_favorite, created_at, updated_at
-		FROM notes
-		WHERE is_favorite = 1
-		ORDER BY updated_at DESC
-	`
-	// SQLite: 1 = true, 0 = false
+// unlinkedNoteTasks agrupa pelo título (em minúsculas) as tarefas pendentes
+// da nota que nenhuma linha do conteúdo referencia
+func unlinkedNoteTasks(ctx context.Context, tx repository.Store, noteID int, items []checklistItem) (map[string][]models.Task, error) {
+	referenced := make(map[int]bool)
+	for _, item := range items {
+		if item.TaskID != 0 {
+			referenced[item.TaskID] = true
+		}
+	}
 
-	rows, err := s.db.Query(query)
+	all, err := tx.Tasks().List(ctx, models.TaskFilter{Status: "pending"})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar notas: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	var notes []models.Note
-
-	for rows.Next() {
-		var note models.Note
-		err := rows.Scan(
-			&note.ID,
-			&note.Title,
-			&note.Content,
-			&note.CategoryID,
-			&note.IsFavorite,
-			&note.CreatedAt,
-			&note.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler nota: %w", err)
-		}
 
-		notes = append(notes, note)
+	orphans := make(map[string][]models.Task)
+	for _, task := range all {
+		if task.NoteID == nil || *task.NoteID != noteID || referenced[task.ID] {
+			continue
+		}
+		key := strings.ToLower(task.Title)
+		orphans[key] = append(orphans[key], task)
 	}
+	return orphans, nil
+}
 
-	return notes, nil
+// taskStatusChange é uma tarefa concluída ou reaberta pela nota
+type taskStatusChange struct {
+	taskID int
+	event  events.Type
 }
 
-func (s *NoteService) SearchNotes(searchQuery string) ([]models.Note, error) {
-	sqlQuery := `
-		SELECT id, title, content, category_id, is_favorite, created_at, updated_at
-		FROM notes
-		WHERE title LIKE ? OR content LIKE ?
-		ORDER BY updated_at DESC
-	`
+// syncChecklistTasks atualiza o status das tarefas vinculadas conforme
+// os itens marcados ou desmarcados no conteúdo da nota
+func syncChecklistTasks(ctx context.Context, tx repository.Store, noteID int, content string) ([]taskStatusChange, error) {
+	var changes []taskStatusChange
 
-	searchTerm := "%" + searchQuery + "%"
+	for _, item := range parseChecklist(content) {
+		if item.TaskID == 0 {
+			continue
+		}
 
-	rows, err := s.db.Query(sqlQuery, searchTerm, searchTerm)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar notas: %w", err)
-	}
-	defer rows.Close()
-
-	var notes []models.Note
-
-	for rows.Next() {
-		var note models.Note
-		err := rows.Scan(
-			&note.ID,
-			&note.Title,
-			&note.Content,
-			&note.CategoryID,
-			&note.IsFavorite,
-			&note.CreatedAt,
-			&note.UpdatedAt,
-		)
+		changed, err := tx.Tasks().SetStatusFromNote(ctx, item.TaskID, noteID, item.Checked)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler nota: %w", err)
+			return nil, err
 		}
 
-		notes = append(notes, note)
+		if changed {
+			eventType := events.TaskReopened
+			if item.Checked {
+				eventType = events.TaskCompleted
+			}
+			changes = append(changes, taskStatusChange{taskID: item.TaskID, event: eventType})
+		}
 	}
 
-	return notes, nil
+	return changes, nil
+}
+
+// GetFavoriteNotesInNotebook retorna as notas favoritas do caderno e de todos os subcadernos
+func (s *NoteService) GetFavoriteNotesInNotebook(ctx context.Context, notebookID int) (_ []models.Note, err error) {
+	defer logging.Track("NoteService.GetFavoriteNotesInNotebook", &err)()
+
+	return s.store.Notes().List(ctx, repository.NoteQuery{FavoritesOnly: true, NotebookID: &notebookID})
+}
+
+// SearchNotesInNotebook busca por título ou conteúdo dentro do caderno e de todos os subcadernos
+func (s *NoteService) SearchNotesInNotebook(ctx context.Context, notebookID int, searchQuery string) (_ []models.Note, err error) {
+	defer logging.Track("NoteService.SearchNotesInNotebook", &err)()
+
+	return s.store.Notes().List(ctx, repository.NoteQuery{Search: searchQuery, NotebookID: &notebookID})
 }
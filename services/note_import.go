@@ -0,0 +1,444 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/importers"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+)
+
+// Origens e destinos das pastas aceitos na importação de notas
+var (
+	noteImportSources   = []string{"enex", "folder"}
+	noteImportFoldersAs = []string{"notebooks", "categories", "none"}
+)
+
+// NoteImportService traz notas do Evernote (.enex) e de pastas de Markdown,
+// como os cofres do Obsidian. As pastas viram cadernos (ou categorias), os
+// arquivos usados pelas notas viram anexos e as datas originais são
+// mantidas. Notas iguais a uma existente (mesmo título e data de criação)
+// são puladas, então importar a mesma pasta de novo só traz as novas.
+type NoteImportService struct {
+	noteService       *NoteService
+	notebookService   *NotebookService
+	categoryService   *CategoryService
+	attachmentService *AttachmentService
+}
+
+// NewNoteImportService cria novo serviço de importação de notas
+func NewNoteImportService(noteService *NoteService, notebookService *NotebookService, categoryService *CategoryService, attachmentService *AttachmentService) *NoteImportService {
+	return &NoteImportService{
+		noteService:       noteService,
+		notebookService:   notebookService,
+		categoryService:   categoryService,
+		attachmentService: attachmentService,
+	}
+}
+
+// noteImport é o estado de uma importação em andamento
+type noteImport struct {
+	options    models.NoteImportOptions
+	report     *models.NoteImportReport
+	notebooks  map[string]int // caminho em minúsculas ("a/b") -> ID
+	categories map[string]int // nome em minúsculas -> ID
+	titles     map[string]string
+}
+
+// Import importa as notas de path: um arquivo .enex ou uma pasta. Itens com
+// problema não interrompem os outros: vão para Failed com o motivo.
+func (s *NoteImportService) Import(ctx context.Context, path string, options models.NoteImportOptions) (_ *models.NoteImportReport, err error) {
+	defer logging.Track("NoteImportService.Import", &err)()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	if options.Source == "" {
+		options.Source = "enex"
+		if info.IsDir() {
+			options.Source = "folder"
+		}
+	}
+	if options.FoldersAs == "" {
+		options.FoldersAs = "notebooks"
+	}
+
+	erros := &ValidationError{}
+	if !oneOf(options.Source, noteImportSources) {
+		erros.Invalid("source", CodeInvalidChoice, choiceMessage("origem", noteImportSources))
+	}
+	if !oneOf(options.FoldersAs, noteImportFoldersAs) {
+		erros.Invalid("folders_as", CodeInvalidChoice, choiceMessage("destino das pastas", noteImportFoldersAs))
+	}
+	if erros.HasErrors() {
+		return nil, erros
+	}
+
+	var notes []importers.Note
+	var failures []importers.Failure
+
+	switch options.Source {
+	case "enex":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, i18n.Errorf("erro ao abrir arquivo: %w", err)
+		}
+		notes, failures, err = importers.ParseENEX(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		// Cada exportação do Evernote é um caderno, com o nome do arquivo
+		notebook := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for i := range notes {
+			notes[i].Folder = []string{notebook}
+		}
+	case "folder":
+		notes, failures, err = importers.ReadNoteFolder(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	run := &noteImport{
+		options: options,
+		report: &models.NoteImportReport{
+			Source:            options.Source,
+			DryRun:            options.DryRun,
+			Created:           []models.NoteImportItem{},
+			Skipped:           []models.NoteImportItem{},
+			Failed:            []models.NoteImportItem{},
+			NotebooksCreated:  []string{},
+			CategoriesCreated: []string{},
+		},
+	}
+	for _, failure := range failures {
+		run.report.Failed = append(run.report.Failed, models.NoteImportItem{Ref: failure.Ref, Title: failure.Title, Reason: failure.Reason})
+	}
+
+	existing, err := s.noteService.GetAllNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadContainers(ctx, run); err != nil {
+		return nil, err
+	}
+
+	// Notas existentes e as já importadas desta origem, por título e criação
+	duplicates := make(map[string]string, len(existing))
+	for _, note := range existing {
+		duplicates[noteImportKey(note.Title, note.CreatedAt)] = i18n.T("já existe uma nota igual")
+	}
+
+	run.titles = linkTargets(existing, notes)
+
+	for i, item := range notes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ReportProgress(ctx, i+1, len(notes))
+
+		entry := models.NoteImportItem{Ref: item.Ref, Title: item.Title}
+		if options.FoldersAs != "none" {
+			entry.Folder = strings.Join(item.Folder, "/")
+			if options.FoldersAs == "categories" && len(item.Folder) > 0 {
+				entry.Folder = item.Folder[0]
+			}
+		}
+
+		if strings.TrimSpace(item.Title) == "" {
+			entry.Reason = i18n.T("sem título")
+			run.report.Failed = append(run.report.Failed, entry)
+			continue
+		}
+
+		key := noteImportKey(item.Title, item.CreatedAt)
+		if reason := duplicates[key]; reason != "" {
+			entry.Reason = reason
+			run.report.Skipped = append(run.report.Skipped, entry)
+			continue
+		}
+
+		id, err := s.importNote(ctx, run, item, &entry)
+		if err != nil {
+			entry.Reason = err.Error()
+			run.report.Failed = append(run.report.Failed, entry)
+			continue
+		}
+
+		duplicates[key] = i18n.T("repetida na importação")
+		entry.NoteID = id
+		run.report.Created = append(run.report.Created, entry)
+	}
+
+	return run.report, nil
+}
+
+// importNote cria uma nota: grava os anexos, troca as referências a eles
+// pelo link attachment:ID, acerta os links [[...]] e as tags e, por último,
+// cria a nota com as datas originais e vincula os anexos a ela. No dry run
+// nada é gravado.
+func (s *NoteImportService) importNote(ctx context.Context, run *noteImport, item importers.Note, entry *models.NoteImportItem) (int, error) {
+	note := models.Note{
+		Title:     strings.TrimSpace(item.Title),
+		Content:   item.Content,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+
+	note.Content, entry.UnresolvedLinks = importers.RewriteWikiLinks(note.Content, func(target string) (string, bool) {
+		title, ok := run.titles[linkKey(target)]
+		return title, ok
+	})
+	note.Content = appendTags(note.Content, item.Tags)
+
+	switch run.options.FoldersAs {
+	case "notebooks":
+		id, err := s.resolveNotebook(ctx, run, item.Folder)
+		if err != nil {
+			return 0, err
+		}
+		if id != 0 {
+			note.NotebookID = &id
+		}
+	case "categories":
+		if len(item.Folder) > 0 {
+			id, err := s.resolveCategory(ctx, run, item.Folder[0])
+			if err != nil {
+				return 0, err
+			}
+			if id != 0 {
+				note.CategoryID = &id
+			}
+		}
+	}
+
+	if run.options.DryRun {
+		run.report.Attachments += len(item.Attachments)
+		return 0, nil
+	}
+
+	// Anexos primeiro, sem vínculo: o conteúdo da nota já é criado com os
+	// links para eles (atualizar a nota depois mudaria updated_at)
+	var attachments []*models.Attachment
+	discard := func() {
+		for _, attachment := range attachments {
+			s.attachmentService.DeleteAttachment(ctx, attachment.ID)
+		}
+	}
+
+	for _, file := range item.Attachments {
+		var attachment *models.Attachment
+		var err error
+		if file.Path != "" {
+			attachment, err = s.attachmentService.AddFromPath(ctx, file.Path, "", nil, nil)
+		} else {
+			attachment, err = s.attachmentService.AddFromBytes(ctx, file.Data, file.FileName, "", nil, nil)
+		}
+		if err != nil {
+			discard()
+			return 0, err
+		}
+		attachments = append(attachments, attachment)
+		note.Content = strings.ReplaceAll(note.Content, importers.AttachmentTarget(file.Key), "](attachment:"+strconv.Itoa(attachment.ID)+")")
+	}
+
+	id, err := s.noteService.CreateNote(ctx, note)
+	if err != nil {
+		discard()
+		return 0, err
+	}
+
+	noteID := int(id)
+	for _, attachment := range attachments {
+		attachment.EntityType = "note"
+		attachment.EntityID = &noteID
+		if err := s.attachmentService.UpdateAttachment(ctx, *attachment); err != nil {
+			slog.Warn("erro ao vincular anexo importado", "attachment", attachment.ID, "note", noteID, "err", err)
+			continue
+		}
+		run.report.Attachments++
+	}
+
+	return noteID, nil
+}
+
+// loadContainers carrega os cadernos (pelo caminho) e as categorias (pelo
+// nome) que já existem
+func (s *NoteImportService) loadContainers(ctx context.Context, run *noteImport) error {
+	run.notebooks = make(map[string]int)
+	run.categories = make(map[string]int)
+
+	tree, err := s.notebookService.GetNotebookTree(ctx)
+	if err != nil {
+		return err
+	}
+	var walk func(notebooks []models.Notebook, prefix string)
+	walk = func(notebooks []models.Notebook, prefix string) {
+		for _, notebook := range notebooks {
+			path := prefix + strings.ToLower(notebook.Name)
+			if _, ok := run.notebooks[path]; !ok {
+				run.notebooks[path] = notebook.ID
+			}
+			walk(notebook.Children, path+"/")
+		}
+	}
+	walk(tree, "")
+
+	categories, err := s.categoryService.GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	// O nome é único entre todos os tipos: qualquer categoria com o nome
+	// serve, com preferência para as de notas
+	for _, category := range categories {
+		key := strings.ToLower(category.Name)
+		if _, ok := run.categories[key]; !ok || category.Type == "note" {
+			run.categories[key] = category.ID
+		}
+	}
+
+	return nil
+}
+
+// resolveNotebook retorna o caderno da pasta, criando os que faltam no
+// caminho. No dry run eles só entram no relatório.
+func (s *NoteImportService) resolveNotebook(ctx context.Context, run *noteImport, folder []string) (int, error) {
+	var parentID *int
+	id := 0
+
+	for i, name := range folder {
+		path := strings.ToLower(strings.Join(folder[:i+1], "/"))
+		if existing, ok := run.notebooks[path]; ok {
+			id = existing
+		} else if run.options.DryRun {
+			id = 0
+			run.notebooks[path] = 0
+			run.report.NotebooksCreated = append(run.report.NotebooksCreated, strings.Join(folder[:i+1], "/"))
+		} else {
+			created, err := s.notebookService.CreateNotebook(ctx, models.Notebook{Name: name, ParentID: parentID})
+			if err != nil {
+				return 0, err
+			}
+			id = int(created)
+			run.notebooks[path] = id
+			run.report.NotebooksCreated = append(run.report.NotebooksCreated, strings.Join(folder[:i+1], "/"))
+		}
+
+		if id != 0 {
+			parent := id
+			parentID = &parent
+		}
+	}
+
+	return id, nil
+}
+
+// resolveCategory retorna a categoria de notas com o nome, criando-a se não
+// existir. No dry run ela só entra no relatório.
+func (s *NoteImportService) resolveCategory(ctx context.Context, run *noteImport, name string) (int, error) {
+	key := strings.ToLower(name)
+	if id, ok := run.categories[key]; ok {
+		return id, nil
+	}
+
+	if run.options.DryRun {
+		run.categories[key] = 0
+		run.report.CategoriesCreated = append(run.report.CategoriesCreated, name)
+		return 0, nil
+	}
+
+	id, created, err := s.categoryService.FindOrCreateCategory(ctx, name, "note")
+	if err != nil {
+		return 0, err
+	}
+	run.categories[key] = id
+	if created {
+		run.report.CategoriesCreated = append(run.report.CategoriesCreated, name)
+	}
+	return id, nil
+}
+
+// linkTargets indexa os nomes pelos quais um link [[...]] chega a uma nota:
+// o título e os aliases. As notas existentes entram pelo título; entre as
+// importadas, a primeira com cada nome ganha (as pastas mais rasas vêm
+// antes, como no Obsidian).
+func linkTargets(existing []models.Note, notes []importers.Note) map[string]string {
+	titles := make(map[string]string)
+	add := func(name, title string) {
+		if key := linkKey(name); key != "" {
+			if _, ok := titles[key]; !ok {
+				titles[key] = title
+			}
+		}
+	}
+
+	for _, note := range notes {
+		title := strings.TrimSpace(note.Title)
+		if title == "" {
+			continue
+		}
+		add(title, title)
+		for _, alias := range note.Aliases {
+			add(alias, title)
+		}
+	}
+	for _, note := range existing {
+		add(note.Title, note.Title)
+	}
+
+	return titles
+}
+
+// linkKey normaliza o alvo de um link [[...]]: sem extensão .md e sem
+// diferenciar maiúsculas
+func linkKey(target string) string {
+	target = strings.TrimSpace(target)
+	if ext := strings.ToLower(filepath.Ext(target)); ext == ".md" || ext == ".markdown" || ext == ".txt" {
+		target = strings.TrimSuffix(target, filepath.Ext(target))
+	}
+	return strings.ToLower(target)
+}
+
+// appendTags acrescenta ao fim do conteúdo as tags (#tag) que ainda não
+// aparecem nele
+func appendTags(content string, tags []string) string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = "#" + strings.Join(strings.Fields(tag), "-")
+		if tag == "#" || seen[tag] || strings.Contains(content, tag) {
+			continue
+		}
+		seen[tag] = true
+		missing = append(missing, tag)
+	}
+	if len(missing) == 0 {
+		return content
+	}
+
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		content += "\n\n"
+	}
+	return content + strings.Join(missing, " ") + "\n"
+}
+
+// noteImportKey identifica notas iguais: mesmo título (sem diferenciar
+// maiúsculas e espaços) e mesmo instante de criação
+func noteImportKey(title string, createdAt time.Time) string {
+	key := strings.ToLower(strings.Join(strings.Fields(title), " "))
+	if !createdAt.IsZero() {
+		key += "|" + strconv.FormatInt(createdAt.Unix(), 10)
+	}
+	return key
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"personal-cockpit/events"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+func newMemoryNoteService() (*NoteService, *TaskService, repository.UnitOfWork) {
+	store := repository.NewMemory()
+	bus := events.NewBus()
+	taskService := NewTaskServiceWithStore(store, bus)
+	return NewNoteServiceWithStore(store, bus, taskService), taskService, store
+}
+
+func TestNoteCRUD(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newMemoryNoteService()
+
+	if _, err := s.CreateNote(ctx, models.Note{}); err == nil {
+		t.Fatal("CreateNote sem título deveria falhar")
+	}
+
+	id, err := s.CreateNote(ctx, models.Note{Title: "Reunião", Content: "pauta"})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	note, err := s.GetNoteByID(ctx, int(id))
+	if err != nil {
+		t.Fatalf("GetNoteByID: %v", err)
+	}
+
+	note.Content = "pauta revisada"
+	if err := s.UpdateNote(ctx, *note); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	if err := s.ToggleFavorite(ctx, note.ID); err != nil {
+		t.Fatalf("ToggleFavorite: %v", err)
+	}
+
+	favorites, err := s.GetFavoriteNotes(ctx)
+	if err != nil || len(favorites) != 1 || favorites[0].Content != "pauta revisada" {
+		t.Fatalf("GetFavoriteNotes = %+v, %v", favorites, err)
+	}
+
+	found, err := s.SearchNotes(ctx, "REVISADA")
+	if err != nil || len(found) != 1 {
+		t.Fatalf("SearchNotes = %+v, %v", found, err)
+	}
+
+	if err := s.DeleteNote(ctx, note.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+
+	var notFound *NotFoundError
+	if _, err := s.GetNoteByID(ctx, note.ID); !errors.As(err, &notFound) {
+		t.Fatalf("GetNoteByID depois de deletar: %v", err)
+	}
+}
+
+func TestExtractTasksLinksChecklist(t *testing.T) {
+	ctx := context.Background()
+	s, taskService, _ := newMemoryNoteService()
+
+	id, err := s.CreateNote(ctx, models.Note{Title: "Mercado", Content: "- [ ] leite\n- [x] pão\n"})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	tasks, err := s.ExtractTasks(ctx, int(id))
+	if err != nil {
+		t.Fatalf("ExtractTasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "leite" {
+		t.Fatalf("tarefas extraídas = %+v", tasks)
+	}
+
+	// Repetir não duplica
+	if again, err := s.ExtractTasks(ctx, int(id)); err != nil || len(again) != 0 {
+		t.Fatalf("ExtractTasks de novo = %+v, %v", again, err)
+	}
+
+	// Concluir a tarefa marca a linha da nota
+	if err := taskService.ToggleTaskStatus(ctx, tasks[0].ID); err != nil {
+		t.Fatalf("ToggleTaskStatus: %v", err)
+	}
+
+	note, err := s.GetNoteByID(ctx, int(id))
+	if err != nil {
+		t.Fatalf("GetNoteByID: %v", err)
+	}
+	if !strings.Contains(note.Content, "- [x] leite") {
+		t.Fatalf("checklist não acompanhou a tarefa:\n%s", note.Content)
+	}
+}
+
+func TestWithTxRollback(t *testing.T) {
+	ctx := context.Background()
+	s, _, store := newMemoryNoteService()
+
+	failure := errors.New("falha")
+	err := store.WithTx(ctx, func(tx repository.Store) error {
+		if _, err := tx.Notes().Create(ctx, models.Note{Title: "Rascunho"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("WithTx = %v", err)
+	}
+
+	notes, err := s.GetAllNotes(ctx)
+	if err != nil || len(notes) != 0 {
+		t.Fatalf("nota gravada apesar do rollback: %+v, %v", notes, err)
+	}
+}
+
+func TestDeleteTaskUnlinksChecklist(t *testing.T) {
+	ctx := context.Background()
+	s, taskService, _ := newMemoryNoteService()
+
+	id, err := s.CreateNote(ctx, models.Note{Title: "Casa", Content: "- [ ] trocar lâmpada"})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	tasks, err := s.ExtractTasks(ctx, int(id))
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("ExtractTasks = %+v, %v", tasks, err)
+	}
+
+	if err := taskService.DeleteTask(ctx, tasks[0].ID); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	note, err := s.GetNoteByID(ctx, int(id))
+	if err != nil {
+		t.Fatalf("GetNoteByID: %v", err)
+	}
+	if note.Content != "- [ ] trocar lâmpada" {
+		t.Fatalf("marcador continuou na nota: %q", note.Content)
+	}
+}
+
+func TestExtractTasksReplacesMissingTask(t *testing.T) {
+	ctx := context.Background()
+	s, taskService, _ := newMemoryNoteService()
+
+	// Marcadores de tarefas que nunca existiram (nota copiada de outra base)
+	id, err := s.CreateNote(ctx, models.Note{Title: "Viagem", Content: "- [ ] passaporte ^task-41\n- [x] malas ^task-42"})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	tasks, err := s.ExtractTasks(ctx, int(id))
+	if err != nil {
+		t.Fatalf("ExtractTasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "passaporte" {
+		t.Fatalf("tarefas extraídas = %+v", tasks)
+	}
+
+	note, err := s.GetNoteByID(ctx, int(id))
+	if err != nil {
+		t.Fatalf("GetNoteByID: %v", err)
+	}
+	want := "- [ ] passaporte ^task-" + strconv.Itoa(tasks[0].ID) + "\n- [x] malas"
+	if note.Content != want {
+		t.Fatalf("conteúdo = %q, quero %q", note.Content, want)
+	}
+
+	// A tarefa nova fica ligada à linha
+	if err := taskService.ToggleTaskStatus(ctx, tasks[0].ID); err != nil {
+		t.Fatalf("ToggleTaskStatus: %v", err)
+	}
+	if note, _ := s.GetNoteByID(ctx, int(id)); !strings.HasPrefix(note.Content, "- [x] passaporte") {
+		t.Fatalf("checklist não acompanhou a tarefa: %q", note.Content)
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+
+	"personal-cockpit/events"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// NotebookService gerencia a hierarquia de cadernos de notas
+type NotebookService struct {
+	store repository.UnitOfWork
+	bus   *events.Bus
+}
+
+// NewNotebookService cria novo serviço de cadernos
+func NewNotebookService(db *sql.DB, bus *events.Bus) *NotebookService {
+	return NewNotebookServiceWithStore(repository.NewSQLite(db), bus)
+}
+
+// NewNotebookServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewNotebookServiceWithStore(store repository.UnitOfWork, bus *events.Bus) *NotebookService {
+	return &NotebookService{store: store, bus: bus}
+}
+
+// CreateNotebook cria um caderno, na raiz ou dentro de outro caderno
+func (s *NotebookService) CreateNotebook(ctx context.Context, notebook models.Notebook) (_ int64, err error) {
+	defer logging.Track("NotebookService.CreateNotebook", &err)()
+
+	erros := &ValidationError{}
+
+	if strings.TrimSpace(notebook.Name) == "" {
+		erros.Required("name", "nome")
+	}
+
+	if erros.HasErrors() {
+		return 0, erros
+	}
+
+	if notebook.ParentID != nil {
+		if _, err := s.GetNotebookByID(ctx, *notebook.ParentID); err != nil {
+			return 0, err
+		}
+	}
+
+	id, err := s.store.Notebooks().Create(ctx, notebook)
+	if err != nil {
+		return 0, err
+	}
+
+	publishEntity(ctx, s.bus, events.NotebookCreated, int(id), s.GetNotebookByID)
+
+	return id, nil
+}
+
+// GetNotebookByID busca caderno por ID (sem filhos nem contagens)
+func (s *NotebookService) GetNotebookByID(ctx context.Context, id int) (_ *models.Notebook, err error) {
+	defer logging.Track("NotebookService.GetNotebookByID", &err)()
+
+	notebook, err := s.store.Notebooks().GetByID(ctx, id)
+	if err != nil {
+		return nil, fromRepository(err, "notebook", id)
+	}
+
+	return notebook, nil
+}
+
+// GetNotebookTree retorna todos os cadernos organizados em árvore,
+// com a contagem de notas de cada um
+func (s *NotebookService) GetNotebookTree(ctx context.Context) (_ []models.Notebook, err error) {
+	defer logging.Track("NotebookService.GetNotebookTree", &err)()
+
+	notebooks, err := s.store.Notebooks().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildNotebookTree(notebooks, nil), nil
+}
+
+// buildNotebookTree monta os filhos de parentID recursivamente e soma as contagens
+func buildNotebookTree(all []models.Notebook, parentID *int) []models.Notebook {
+	tree := []models.Notebook{}
+
+	for _, notebook := range all {
+		if !sameParent(notebook.ParentID, parentID) {
+			continue
+		}
+
+		id := notebook.ID
+		notebook.Children = buildNotebookTree(all, &id)
+		notebook.TotalCount = notebook.NoteCount
+		for _, child := range notebook.Children {
+			notebook.TotalCount += child.TotalCount
+		}
+
+		tree = append(tree, notebook)
+	}
+
+	return tree
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// RenameNotebook altera o nome do caderno
+func (s *NotebookService) RenameNotebook(ctx context.Context, id int, name string) (err error) {
+	defer logging.Track("NotebookService.RenameNotebook", &err)()
+
+	if strings.TrimSpace(name) == "" {
+		erros := &ValidationError{}
+		erros.Required("name", "nome")
+		return erros
+	}
+
+	err = s.store.Notebooks().Rename(ctx, id, name)
+	if err != nil {
+		return fromRepository(err, "notebook", id)
+	}
+
+	publishEntity(ctx, s.bus, events.NotebookUpdated, id, s.GetNotebookByID)
+
+	return nil
+}
+
+// MoveNotebook move o caderno para dentro de outro (ou para a raiz, com parentID nil).
+// Não permite mover um caderno para dentro dele mesmo ou de um descendente.
+func (s *NotebookService) MoveNotebook(ctx context.Context, id int, parentID *int) (err error) {
+	defer logging.Track("NotebookService.MoveNotebook", &err)()
+
+	if _, err := s.GetNotebookByID(ctx, id); err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		if _, err := s.GetNotebookByID(ctx, *parentID); err != nil {
+			return err
+		}
+
+		subtree, err := s.store.Notebooks().Subtree(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(subtree, *parentID) {
+			erros := &ValidationError{}
+			erros.Invalid("parent_id", CodeInvalid, "não é possível mover um caderno para dentro dele mesmo")
+			return erros
+		}
+	}
+
+	if err := s.store.Notebooks().SetParent(ctx, id, parentID); err != nil {
+		return err
+	}
+
+	publishEntity(ctx, s.bus, events.NotebookUpdated, id, s.GetNotebookByID)
+
+	return nil
+}
+
+// MoveNote move a nota para o caderno informado (ou remove do caderno, com notebookID nil)
+func (s *NotebookService) MoveNote(ctx context.Context, noteID int, notebookID *int) (err error) {
+	defer logging.Track("NotebookService.MoveNote", &err)()
+
+	if notebookID != nil {
+		if _, err := s.GetNotebookByID(ctx, *notebookID); err != nil {
+			return err
+		}
+	}
+
+	err = s.store.Notes().SetNotebook(ctx, noteID, notebookID)
+	if err != nil {
+		return fromRepository(err, "note", noteID)
+	}
+
+	noteService := NewNoteServiceWithStore(s.store, nil, nil)
+	publishEntity(ctx, s.bus, events.NoteUpdated, noteID, noteService.GetNoteByID)
+
+	return nil
+}
+
+// DeleteNotebook remove o caderno.
+// Com deleteContents, apaga também todos os subcadernos e suas notas.
+// Caso contrário, subcadernos e notas sobem para o caderno pai.
+func (s *NotebookService) DeleteNotebook(ctx context.Context, id int, deleteContents bool) (err error) {
+	defer logging.Track("NotebookService.DeleteNotebook", &err)()
+
+	notebook, err := s.GetNotebookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Notas apagadas ou movidas junto, e subcadernos movidos, para publicar
+	// cada alteração depois do commit
+	var deletedNotes, movedNotes, movedNotebooks []int
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		// Notas do caderno e de todos os subcadernos
+		notes, err := tx.Notes().List(ctx, repository.NoteQuery{NotebookID: &id})
+		if err != nil {
+			return err
+		}
+
+		if deleteContents {
+			subtree, err := tx.Notebooks().Subtree(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			for _, note := range notes {
+				if err := tx.Notes().Delete(ctx, note.ID); err != nil {
+					return err
+				}
+				deletedNotes = append(deletedNotes, note.ID)
+			}
+
+			// Dos mais fundos para a raiz, para nenhum ficar sem pai
+			for i := len(subtree) - 1; i >= 0; i-- {
+				if err := tx.Notebooks().Delete(ctx, subtree[i]); err != nil && !errors.Is(err, repository.ErrNotFound) {
+					return err
+				}
+			}
+			return nil
+		}
+
+		for _, note := range notes {
+			if note.NotebookID != nil && *note.NotebookID == id {
+				if err := tx.Notes().SetNotebook(ctx, note.ID, notebook.ParentID); err != nil {
+					return err
+				}
+				movedNotes = append(movedNotes, note.ID)
+			}
+		}
+
+		notebooks, err := tx.Notebooks().List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, child := range notebooks {
+			if child.ParentID != nil && *child.ParentID == id {
+				if err := tx.Notebooks().SetParent(ctx, child.ID, notebook.ParentID); err != nil {
+					return err
+				}
+				movedNotebooks = append(movedNotebooks, child.ID)
+			}
+		}
+
+		return tx.Notebooks().Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, noteID := range deletedNotes {
+		s.bus.Publish(events.NoteDeleted, noteID, nil)
+	}
+
+	noteService := NewNoteServiceWithStore(s.store, nil, nil)
+	for _, noteID := range movedNotes {
+		publishEntity(ctx, s.bus, events.NoteUpdated, noteID, noteService.GetNoteByID)
+	}
+	for _, childID := range movedNotebooks {
+		publishEntity(ctx, s.bus, events.NotebookUpdated, childID, s.GetNotebookByID)
+	}
+	s.bus.Publish(events.NotebookDeleted, id, nil)
+
+	return nil
+}
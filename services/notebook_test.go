@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"personal-cockpit/events"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+func TestDeleteNotebookPublishesMoved(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemory()
+	bus := events.NewBus()
+	s := NewNotebookServiceWithStore(store, bus)
+	notes := NewNoteServiceWithStore(store, nil, nil)
+
+	parentID, err := s.CreateNotebook(ctx, models.Notebook{Name: "Trabalho"})
+	if err != nil {
+		t.Fatalf("CreateNotebook: %v", err)
+	}
+	parent := int(parentID)
+	childID, err := s.CreateNotebook(ctx, models.Notebook{Name: "Projetos", ParentID: &parent})
+	if err != nil {
+		t.Fatalf("CreateNotebook: %v", err)
+	}
+	child := int(childID)
+	grandchildID, err := s.CreateNotebook(ctx, models.Notebook{Name: "Antigos", ParentID: &child})
+	if err != nil {
+		t.Fatalf("CreateNotebook: %v", err)
+	}
+	noteID, err := notes.CreateNote(ctx, models.Note{Title: "Pauta", NotebookID: &child})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	var published []events.Event
+	bus.Subscribe(func(e events.Event) { published = append(published, e) }, "*")
+
+	if err := s.DeleteNotebook(ctx, child, false); err != nil {
+		t.Fatalf("DeleteNotebook: %v", err)
+	}
+
+	note, err := notes.GetNoteByID(ctx, int(noteID))
+	if err != nil {
+		t.Fatalf("GetNoteByID: %v", err)
+	}
+	if note.NotebookID == nil || *note.NotebookID != parent {
+		t.Errorf("nota ficou no caderno %v, quero %d", note.NotebookID, parent)
+	}
+
+	want := []events.Event{
+		{Type: events.NoteUpdated, EntityID: int(noteID)},
+		{Type: events.NotebookUpdated, EntityID: int(grandchildID)},
+		{Type: events.NotebookDeleted, EntityID: child},
+	}
+	got := make([]events.Event, len(published))
+	for i, e := range published {
+		got[i] = events.Event{Type: e.Type, EntityID: e.EntityID}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("eventos publicados = %+v, quero %+v", got, want)
+	}
+}
+
+func TestMoveNotebookIntoDescendant(t *testing.T) {
+	ctx := context.Background()
+	s := NewNotebookServiceWithStore(repository.NewMemory(), nil)
+
+	parentID, err := s.CreateNotebook(ctx, models.Notebook{Name: "Trabalho"})
+	if err != nil {
+		t.Fatalf("CreateNotebook: %v", err)
+	}
+	parent := int(parentID)
+	childID, err := s.CreateNotebook(ctx, models.Notebook{Name: "Projetos", ParentID: &parent})
+	if err != nil {
+		t.Fatalf("CreateNotebook: %v", err)
+	}
+	child := int(childID)
+
+	err = s.MoveNotebook(ctx, parent, &child)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("MoveNotebook = %v, quero ValidationError", err)
+	}
+	if len(validation.Fields) != 1 || validation.Fields[0].Field != "parent_id" || validation.Fields[0].Code != CodeInvalid {
+		t.Errorf("campos = %+v, quero parent_id inválido", validation.Fields)
+	}
+}
@@ -0,0 +1,902 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+	"personal-cockpit/timezone"
+)
+
+// SettingNotesFolder é a pasta sincronizada com as notas (vazia = desligada)
+const SettingNotesFolder = "notes_folder"
+
+// Intervalo da varredura que detecta edições feitas por outros programas
+const noteFolderPollInterval = 2 * time.Second
+
+// NoteFolderService mantém cada nota como um arquivo .md numa pasta escolhida
+// pelo usuário, para editar em outros programas e versionar com git. O
+// arquivo tem um cabeçalho YAML (id, título, categoria, favorita, datas) e o
+// conteúdo da nota.
+//
+// A sincronização compara três versões: o arquivo (hash), a nota
+// (updated_at) e a da última sincronização (note_files). Uma varredura a
+// cada 2 segundos importa as edições externas; gravações pelo NoteService
+// acordam a varredura na hora, pelo barramento. Quando os dois lados mudaram,
+// nada se perde: a versão do arquivo vira uma nota "(conflito ...)" e a nota
+// original regrava o arquivo.
+type NoteFolderService struct {
+	store           repository.UnitOfWork
+	bus             *events.Bus
+	settings        *SettingsService
+	noteService     *NoteService
+	categoryService *CategoryService
+
+	// Uma reconciliação por vez; files é o cache de hashes da pasta
+	syncMu sync.Mutex
+	files  map[string]fileState
+
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	unsubscribe func()
+	wake        chan struct{}
+	wg          sync.WaitGroup
+}
+
+// fileState evita reler arquivos que não mudaram de tamanho nem de data
+type fileState struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// noteFile é o vínculo entre uma nota e seu arquivo (caminho relativo à
+// pasta, com "/")
+type noteFile struct {
+	noteID        int
+	path          string
+	hash          string
+	syncedVersion time.Time
+}
+
+// NewNoteFolderService cria novo serviço da pasta de notas (a sincronização
+// só começa com Start)
+func NewNoteFolderService(db *sql.DB, bus *events.Bus, settings *SettingsService, noteService *NoteService, categoryService *CategoryService) *NoteFolderService {
+	return NewNoteFolderServiceWithStore(repository.NewSQLite(db), bus, settings, noteService, categoryService)
+}
+
+// NewNoteFolderServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewNoteFolderServiceWithStore(store repository.UnitOfWork, bus *events.Bus, settings *SettingsService, noteService *NoteService, categoryService *CategoryService) *NoteFolderService {
+	return &NoteFolderService{
+		store:           store,
+		bus:             bus,
+		settings:        settings,
+		noteService:     noteService,
+		categoryService: categoryService,
+		files:           make(map[string]fileState),
+		wake:            make(chan struct{}, 1),
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// CONFIGURAÇÃO
+// ═══════════════════════════════════════════════════════════
+
+// GetFolder retorna a pasta configurada (vazia se desligada)
+func (s *NoteFolderService) GetFolder(ctx context.Context) (_ string, err error) {
+	defer logging.Track("NoteFolderService.GetFolder", &err)()
+
+	return s.settings.Get(ctx, SettingNotesFolder, "")
+}
+
+// SetFolder troca a pasta e reinicia a sincronização. Trocar de pasta
+// esquece os vínculos: todas as notas são escritas na nova, e os .md que já
+// estiverem lá são importados. Vazio desliga (os arquivos ficam).
+func (s *NoteFolderService) SetFolder(ctx context.Context, folder string) (err error) {
+	defer logging.Track("NoteFolderService.SetFolder", &err)()
+
+	folder = strings.TrimSpace(folder)
+	if folder != "" {
+		if !filepath.IsAbs(folder) {
+			erros := &ValidationError{}
+			erros.Invalid("folder", CodeInvalid, "caminho absoluto da pasta")
+			return erros
+		}
+		folder = filepath.Clean(folder)
+		if err := os.MkdirAll(folder, 0o755); err != nil {
+			return i18n.Errorf("erro ao criar pasta das notas: %w", err)
+		}
+	}
+
+	current, err := s.GetFolder(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.Stop()
+
+	if current != folder {
+		if err := s.settings.Set(ctx, SettingNotesFolder, folder); err != nil {
+			return err
+		}
+		if err := s.store.NoteFiles().DeleteAll(ctx); err != nil {
+			return err
+		}
+		s.syncMu.Lock()
+		s.files = make(map[string]fileState)
+		s.syncMu.Unlock()
+	}
+
+	return s.Start()
+}
+
+// ═══════════════════════════════════════════════════════════
+// OBSERVAÇÃO DA PASTA
+// ═══════════════════════════════════════════════════════════
+
+// Start começa a observar a pasta configurada (nada acontece sem pasta)
+func (s *NoteFolderService) Start() error {
+	folder, err := s.GetFolder(context.Background())
+	if err != nil {
+		return err
+	}
+	if folder == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	// O barramento só acorda a varredura: o trabalho fica todo na goroutine
+	// dela, então uma nota gravada pela própria importação não trava nada
+	s.unsubscribe = s.bus.Subscribe(func(events.Event) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}, "note.*")
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.watch(ctx, folder)
+	}()
+
+	return nil
+}
+
+// Stop para de observar a pasta e espera a varredura em andamento
+func (s *NoteFolderService) Stop() {
+	s.mu.Lock()
+	if s.cancel == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.unsubscribe()
+	s.unsubscribe = nil
+	s.cancel()
+	s.cancel = nil
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// watch varre a pasta a cada noteFolderPollInterval ou quando uma nota muda.
+// Chama reconcile direto, sem o SyncNow e seu logging.Track: uma varredura
+// sem nada a fazer não deixa rastro no log.
+func (s *NoteFolderService) watch(ctx context.Context, folder string) {
+	ticker := time.NewTicker(noteFolderPollInterval)
+	defer ticker.Stop()
+
+	lastErr := ""
+	for {
+		s.syncMu.Lock()
+		result, err := s.reconcile(ctx, folder)
+		s.syncMu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil && result.Imported+result.Exported+result.Renamed+result.Deleted+result.Conflicts > 0 {
+			slog.Info("pasta de notas sincronizada",
+				"imported", result.Imported,
+				"exported", result.Exported,
+				"renamed", result.Renamed,
+				"deleted", result.Deleted,
+				"conflicts", result.Conflicts,
+			)
+		}
+
+		// Só registra quando o problema muda, para não repetir a cada varredura
+		message := ""
+		if err != nil {
+			message = err.Error()
+		} else if len(result.Errors) > 0 {
+			message = strings.Join(result.Errors, "; ")
+		}
+		if message != "" && message != lastErr {
+			slog.Warn("erro na sincronização da pasta de notas", "err", message)
+		}
+		lastErr = message
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// SINCRONIZAÇÃO
+// ═══════════════════════════════════════════════════════════
+
+// noteDoc é um arquivo lido da pasta
+type noteDoc struct {
+	path string
+	hash string
+	fm   frontMatter
+	body string
+}
+
+// SyncNow reconcilia a pasta com as notas agora. Erros de um arquivo não
+// interrompem os outros: vão para Errors.
+func (s *NoteFolderService) SyncNow(ctx context.Context) (_ *models.NoteFolderSyncResult, err error) {
+	defer logging.Track("NoteFolderService.SyncNow", &err)()
+
+	folder, err := s.GetFolder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if folder == "" {
+		erros := &ValidationError{}
+		erros.Required("folder", "pasta das notas")
+		return nil, erros
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	return s.reconcile(ctx, folder)
+}
+
+func (s *NoteFolderService) reconcile(ctx context.Context, folder string) (*models.NoteFolderSyncResult, error) {
+	result := &models.NoteFolderSyncResult{Errors: []string{}}
+	fail := func(what string, err error) {
+		result.Errors = append(result.Errors, what+": "+err.Error())
+	}
+
+	files, err := s.scanFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := s.loadMappings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pasta vazia com notas vinculadas costuma ser um disco desmontado ou uma
+	// sincronização externa pela metade: apagar todas as notas seria pior
+	if len(files) == 0 && len(mappings) > 0 {
+		slog.Warn("pasta de notas vazia com notas vinculadas, nada foi apagado", "folder", folder, "linked", len(mappings))
+		fail(folder, i18n.Errorf("a pasta está vazia, mas %d notas estão vinculadas a ela; nada foi apagado", len(mappings)))
+		return result, nil
+	}
+
+	versions, err := s.noteVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]*noteFile, len(mappings))
+	byNote := make(map[int]*noteFile, len(mappings))
+	for _, m := range mappings {
+		byPath[m.path] = m
+		byNote[m.noteID] = m
+	}
+
+	// Arquivos novos; os que trazem o id de uma nota cujo arquivo sumiu são
+	// esse arquivo renomeado ou movido
+	var created []*noteDoc
+	renamed := make(map[int]bool)
+	for p := range files {
+		if byPath[p] != nil {
+			continue
+		}
+		doc, err := s.readDoc(folder, p, files[p].hash)
+		if err != nil {
+			fail(p, err)
+			continue
+		}
+
+		id, _ := strconv.Atoi(doc.fm.values["id"])
+		if m := byNote[id]; m != nil {
+			if _, stillThere := files[m.path]; !stillThere {
+				delete(byPath, m.path)
+				m.path = p
+				byPath[p] = m
+				renamed[m.noteID] = true
+				result.Renamed++
+				continue
+			}
+		}
+		created = append(created, doc)
+	}
+
+	// Arquivos conhecidos: alterados por fora ou apagados
+	for _, m := range mappings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		version, noteExists := versions[m.noteID]
+		localChanged := noteExists && !version.Equal(m.syncedVersion)
+		state, fileExists := files[m.path]
+
+		switch {
+		case !fileExists && !noteExists:
+			s.dropMapping(ctx, m, fail)
+
+		case !fileExists && localChanged:
+			// Apagado lá, alterado aqui: a alteração vence e o arquivo volta
+			result.Conflicts++
+			s.dropMapping(ctx, m, fail)
+			delete(byNote, m.noteID)
+
+		case !fileExists:
+			if err := s.noteService.DeleteNote(ctx, m.noteID); err != nil {
+				fail(m.path, err)
+				continue
+			}
+			s.dropMapping(ctx, m, fail)
+			delete(byNote, m.noteID)
+			result.Deleted++
+
+		case state.hash == m.hash && !noteExists:
+			if err := os.Remove(s.abs(folder, m.path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				fail(m.path, err)
+				continue
+			}
+			delete(s.files, m.path)
+			s.dropMapping(ctx, m, fail)
+			result.Deleted++
+
+		case state.hash == m.hash && renamed[m.noteID]:
+			// Só renomeado: o nome do arquivo vira o título
+			if err := s.retitle(ctx, m, folder, localChanged); err != nil {
+				fail(m.path, err)
+			}
+
+		case state.hash == m.hash:
+			// Sem edição externa; alterações da nota vão na exportação
+
+		default:
+			doc, err := s.readDoc(folder, m.path, state.hash)
+			if err != nil {
+				fail(m.path, err)
+				continue
+			}
+
+			switch {
+			case !noteExists:
+				// Apagada aqui, alterada lá: a alteração vence e a nota volta
+				result.Conflicts++
+				s.dropMapping(ctx, m, fail)
+				delete(byNote, m.noteID)
+				if id, err := s.createFromDoc(ctx, doc, ""); err != nil {
+					fail(m.path, err)
+				} else {
+					byNote[id] = s.adopt(ctx, id, doc, fail)
+					result.Imported++
+				}
+
+			case localChanged:
+				// Os dois mudaram: a versão do arquivo vira outra nota e a
+				// nota regrava o arquivo na exportação
+				result.Conflicts++
+				suffix := i18n.Sprintf(" (conflito %s)", timezone.In(time.Now()).Format("2006-01-02 15.04"))
+				if _, err := s.createFromDoc(ctx, doc, suffix); err != nil {
+					fail(m.path, err)
+				}
+
+			default:
+				if err := s.importInto(ctx, m, doc); err != nil {
+					fail(m.path, err)
+					continue
+				}
+				result.Imported++
+			}
+		}
+	}
+
+	// Arquivos novos de verdade viram notas
+	for _, doc := range created {
+		id, _ := strconv.Atoi(doc.fm.values["id"])
+		_, noteExists := versions[id]
+		if noteExists && byNote[id] == nil {
+			// Arquivo de uma nota sem vínculo (pasta nova com arquivos de
+			// outra sincronização): igual à nota, só vincula
+			if same, err := s.sameAsNote(ctx, id, doc); err == nil && same {
+				byNote[id] = s.adopt(ctx, id, doc, fail)
+				continue
+			}
+			result.Conflicts++
+		}
+
+		newID, err := s.createFromDoc(ctx, doc, "")
+		if err != nil {
+			fail(doc.path, err)
+			continue
+		}
+		// synced zerado: a exportação grava o id no cabeçalho
+		m := s.adopt(ctx, newID, doc, fail)
+		if m != nil {
+			m.syncedVersion = time.Time{}
+		}
+		byNote[newID] = m
+		result.Imported++
+	}
+
+	// Notas novas ou alteradas aqui vão para a pasta
+	versions, err = s.noteVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taken := s.takenPaths(files, byNote)
+	for id, version := range versions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		m := byNote[id]
+		if m != nil && version.Equal(m.syncedVersion) {
+			continue
+		}
+		if err := s.export(ctx, folder, id, m, taken); err != nil {
+			fail(strconv.Itoa(id), err)
+			continue
+		}
+		result.Exported++
+	}
+
+	return result, nil
+}
+
+// importInto aplica o arquivo à nota vinculada
+func (s *NoteFolderService) importInto(ctx context.Context, m *noteFile, doc *noteDoc) error {
+	note, err := s.noteService.GetNoteByID(ctx, m.noteID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.applyDoc(ctx, note, doc, ""); err != nil {
+		return err
+	}
+	if err := s.noteService.UpdateNote(ctx, *note); err != nil {
+		return err
+	}
+
+	version, err := s.noteVersion(ctx, m.noteID)
+	if err != nil {
+		return err
+	}
+	m.hash, m.syncedVersion = doc.hash, version
+	return s.saveMapping(ctx, m)
+}
+
+// retitle aplica à nota o título do arquivo renomeado. Com a nota alterada
+// no app, o vínculo fica desatualizado e a exportação regrava o arquivo.
+func (s *NoteFolderService) retitle(ctx context.Context, m *noteFile, folder string, localChanged bool) error {
+	doc, err := s.readDoc(folder, m.path, m.hash)
+	if err != nil {
+		return err
+	}
+	note, err := s.noteService.GetNoteByID(ctx, m.noteID)
+	if err != nil {
+		return err
+	}
+
+	fromFile := *note
+	if err := s.applyDoc(ctx, &fromFile, doc, ""); err != nil {
+		return err
+	}
+	if fromFile.Title != note.Title {
+		note.Title = fromFile.Title
+		if err := s.noteService.UpdateNote(ctx, *note); err != nil {
+			return err
+		}
+	}
+
+	if !localChanged {
+		version, err := s.noteVersion(ctx, m.noteID)
+		if err != nil {
+			return err
+		}
+		m.syncedVersion = version
+	}
+	return s.saveMapping(ctx, m)
+}
+
+// createFromDoc cria uma nota com o conteúdo do arquivo
+func (s *NoteFolderService) createFromDoc(ctx context.Context, doc *noteDoc, titleSuffix string) (int, error) {
+	note := &models.Note{}
+	if err := s.applyDoc(ctx, note, doc, titleSuffix); err != nil {
+		return 0, err
+	}
+
+	id, err := s.noteService.CreateNote(ctx, *note)
+	return int(id), err
+}
+
+// adopt vincula o arquivo à nota, como sincronizado na versão atual dela
+func (s *NoteFolderService) adopt(ctx context.Context, id int, doc *noteDoc, fail func(string, error)) *noteFile {
+	version, err := s.noteVersion(ctx, id)
+	if err != nil {
+		fail(doc.path, err)
+		return nil
+	}
+
+	m := &noteFile{noteID: id, path: doc.path, hash: doc.hash, syncedVersion: version}
+	if err := s.saveMapping(ctx, m); err != nil {
+		fail(doc.path, err)
+		return nil
+	}
+	return m
+}
+
+// applyDoc copia para a nota o título, o conteúdo, a categoria e a favorita
+// do arquivo. O título é o do cabeçalho, a não ser que o arquivo tenha sido
+// renomeado: aí vale o nome do arquivo.
+func (s *NoteFolderService) applyDoc(ctx context.Context, note *models.Note, doc *noteDoc, titleSuffix string) error {
+	base := strings.TrimSuffix(path.Base(doc.path), path.Ext(doc.path))
+	title := base
+	if fmTitle := strings.TrimSpace(doc.fm.values["title"]); fmTitle != "" && noteFileName(fmTitle) == copySuffix.ReplaceAllString(base, "") {
+		title = fmTitle
+	}
+
+	note.Title = title + titleSuffix
+	note.Content = doc.body
+
+	if value, ok := doc.fm.values["favorite"]; ok {
+		note.IsFavorite = value == "true" || value == "yes"
+	}
+
+	if name, ok := doc.fm.values["category"]; ok {
+		note.CategoryID = nil
+		if strings.TrimSpace(name) != "" {
+			id, _, err := s.categoryService.FindOrCreateCategory(ctx, name, "note")
+			if err != nil {
+				return err
+			}
+			note.CategoryID = &id
+		}
+	}
+
+	return nil
+}
+
+// sameAsNote diz se o arquivo tem o mesmo título e conteúdo da nota
+func (s *NoteFolderService) sameAsNote(ctx context.Context, id int, doc *noteDoc) (bool, error) {
+	note, err := s.noteService.GetNoteByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	fromFile := *note
+	if err := s.applyDoc(ctx, &fromFile, doc, ""); err != nil {
+		return false, err
+	}
+	return fromFile.Title == note.Title && fromFile.Content == note.Content, nil
+}
+
+// export escreve a nota no arquivo vinculado, renomeando-o se o título
+// mudou, ou num arquivo novo
+func (s *NoteFolderService) export(ctx context.Context, folder string, id int, m *noteFile, taken map[string]bool) error {
+	note, err := s.noteService.GetNoteByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	name := noteFileName(note.Title)
+	var extra []string
+
+	target := ""
+	if m != nil {
+		target = m.path
+		dir, base := path.Split(m.path)
+		current := copySuffix.ReplaceAllString(strings.TrimSuffix(base, path.Ext(base)), "")
+		if current != name {
+			delete(taken, strings.ToLower(m.path))
+			target = uniqueNotePath(dir+name, taken)
+			if err := os.Rename(s.abs(folder, m.path), s.abs(folder, target)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			delete(s.files, m.path)
+		}
+
+		// Chaves de outros programas no cabeçalho continuam lá
+		if data, err := os.ReadFile(s.abs(folder, target)); err == nil {
+			fm, _ := splitFrontMatter(string(data))
+			extra = fm.extra
+		}
+	} else {
+		target = uniqueNotePath(name, taken)
+		m = &noteFile{noteID: id}
+	}
+	taken[strings.ToLower(target)] = true
+
+	category := ""
+	if note.CategoryID != nil {
+		if c, err := s.categoryService.GetCategoryByID(ctx, *note.CategoryID); err == nil {
+			category = c.Name
+		}
+	}
+
+	data := renderNoteFile(*note, category, extra)
+	if err := writeFileAtomic(s.abs(folder, target), []byte(data)); err != nil {
+		return err
+	}
+
+	hash := hashString(data)
+	if info, err := os.Stat(s.abs(folder, target)); err == nil {
+		s.files[target] = fileState{size: info.Size(), modTime: info.ModTime(), hash: hash}
+	}
+
+	m.path, m.hash, m.syncedVersion = target, hash, note.UpdatedAt
+	return s.saveMapping(ctx, m)
+}
+
+// renderNoteFile monta o .md: cabeçalho e conteúdo, sem linha em branco
+// entre eles, para o conteúdo voltar igual na importação
+func renderNoteFile(note models.Note, category string, extra []string) string {
+	values := map[string]string{
+		"id":       strconv.Itoa(note.ID),
+		"title":    note.Title,
+		"favorite": strconv.FormatBool(note.IsFavorite),
+		"created":  timezone.In(note.CreatedAt).Format(time.RFC3339),
+		"updated":  timezone.In(note.UpdatedAt).Format(time.RFC3339),
+	}
+	if category != "" {
+		values["category"] = category
+	}
+
+	return renderFrontMatter(values, extra) + note.Content
+}
+
+// ═══════════════════════════════════════════════════════════
+// AUXILIARES
+// ═══════════════════════════════════════════════════════════
+
+// copySuffix é o " (2)" acrescentado quando o nome já está em uso
+var copySuffix = regexp.MustCompile(` \(\d+\)$`)
+
+var invalidFileChars = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]+`)
+
+// noteFileName converte o título num nome de arquivo válido em qualquer
+// sistema (sem a extensão)
+func noteFileName(title string) string {
+	name := invalidFileChars.ReplaceAllString(title, "-")
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.Trim(name, " .")
+
+	for utf8.RuneCountInString(name) > 100 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	name = strings.TrimRight(name, " .")
+
+	if name == "" {
+		return i18n.T("sem título")
+	}
+	return name
+}
+
+// uniqueNotePath escolhe "nome.md", "nome (2).md"... livre. taken guarda
+// os caminhos em minúsculas, por causa dos sistemas que não diferenciam.
+func uniqueNotePath(name string, taken map[string]bool) string {
+	candidate := name + ".md"
+	for i := 2; taken[strings.ToLower(candidate)]; i++ {
+		candidate = name + " (" + strconv.Itoa(i) + ").md"
+	}
+	return candidate
+}
+
+func (s *NoteFolderService) takenPaths(files map[string]fileState, byNote map[int]*noteFile) map[string]bool {
+	taken := make(map[string]bool, len(files))
+	for p := range files {
+		taken[strings.ToLower(p)] = true
+	}
+	for _, m := range byNote {
+		if m != nil {
+			taken[strings.ToLower(m.path)] = true
+		}
+	}
+	return taken
+}
+
+// scanFolder lista os .md da pasta e subpastas (menos as ocultas, como .git
+// e .obsidian), relendo só os que mudaram desde a última varredura.
+//
+// Um arquivo que falta na lista tem a nota apagada, então só fica de fora o
+// que comprovadamente sumiu. Arquivo que não deu para ler mantém o estado da
+// varredura anterior; sem ele, ou com uma subpasta ilegível, a varredura
+// inteira falha e nada é apagado.
+func (s *NoteFolderService) scanFolder(folder string) (map[string]fileState, error) {
+	files := make(map[string]fileState)
+
+	err := filepath.WalkDir(folder, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p != folder && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		hidden := strings.HasPrefix(entry.Name(), ".") && p != folder
+		if entry.IsDir() {
+			if hidden {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if hidden || !strings.EqualFold(filepath.Ext(p), ".md") || !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(folder, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		cached, known := s.files[rel]
+
+		// unreadable decide o que fazer com um arquivo que não deu para ler
+		unreadable := func(err error) error {
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				return nil
+			case known:
+				files[rel] = cached
+				return nil
+			default:
+				return err
+			}
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return unreadable(err)
+		}
+		if known && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+			files[rel] = cached
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return unreadable(err)
+		}
+		files[rel] = fileState{size: info.Size(), modTime: info.ModTime(), hash: hashString(string(data))}
+		return nil
+	})
+	if err != nil {
+		return nil, i18n.Errorf("erro ao ler pasta das notas: %w", err)
+	}
+
+	s.files = files
+	return files, nil
+}
+
+func (s *NoteFolderService) readDoc(folder, rel, hash string) (*noteDoc, error) {
+	data, err := os.ReadFile(s.abs(folder, rel))
+	if err != nil {
+		return nil, err
+	}
+
+	fm, body := splitFrontMatter(string(data))
+	return &noteDoc{path: rel, hash: hash, fm: fm, body: body}, nil
+}
+
+func (s *NoteFolderService) abs(folder, rel string) string {
+	return filepath.Join(folder, filepath.FromSlash(rel))
+}
+
+// writeFileAtomic grava num arquivo oculto e renomeia, para um editor
+// aberto nunca ler o arquivo pela metade
+func writeFileAtomic(target string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func hashString(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// ═══════════════════════════════════════════════════════════
+// VÍNCULOS
+// ═══════════════════════════════════════════════════════════
+
+func (s *NoteFolderService) loadMappings(ctx context.Context) ([]*noteFile, error) {
+	files, err := s.store.NoteFiles().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make([]*noteFile, len(files))
+	for i, file := range files {
+		mappings[i] = &noteFile{noteID: file.NoteID, path: file.Path, hash: file.Hash, syncedVersion: file.SyncedVersion}
+	}
+
+	return mappings, nil
+}
+
+func (s *NoteFolderService) saveMapping(ctx context.Context, m *noteFile) error {
+	return s.store.NoteFiles().Save(ctx, repository.NoteFile{
+		NoteID:        m.noteID,
+		Path:          m.path,
+		Hash:          m.hash,
+		SyncedVersion: m.syncedVersion,
+	})
+}
+
+func (s *NoteFolderService) dropMapping(ctx context.Context, m *noteFile, fail func(string, error)) {
+	if err := s.store.NoteFiles().DeleteByNote(ctx, m.noteID); err != nil {
+		fail(m.path, err)
+	}
+}
+
+// noteVersions traz o updated_at de todas as notas, sem o conteúdo
+func (s *NoteFolderService) noteVersions(ctx context.Context) (map[int]time.Time, error) {
+	return s.store.Notes().Versions(ctx)
+}
+
+func (s *NoteFolderService) noteVersion(ctx context.Context, id int) (time.Time, error) {
+	note, err := s.store.Notes().GetByID(ctx, id)
+	if err != nil {
+		return time.Time{}, fromRepository(err, "note", id)
+	}
+	return note.UpdatedAt, nil
+}
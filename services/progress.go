@@ -0,0 +1,21 @@
+package services
+
+import "context"
+
+// ProgressFunc recebe o andamento de uma operação longa: done itens de total
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// WithProgress associa ao contexto a função que acompanha o andamento.
+// Os services chamam ReportProgress; sem função no contexto, nada acontece.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress informa o andamento à função do contexto, se houver
+func ReportProgress(ctx context.Context, done, total int) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(done, total)
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+
+	"personal-cockpit/events"
+)
+
+// publishEntity publica o evento com a entidade recém-gravada. A busca no
+// banco só acontece se houver alguém inscrito no barramento. A gravação já
+// foi confirmada, então o cancelamento de ctx não impede o evento.
+func publishEntity[T any](ctx context.Context, bus *events.Bus, eventType events.Type, id int, load func(context.Context, int) (T, error)) {
+	if !bus.HasSubscribers() {
+		return
+	}
+
+	entity, err := load(context.WithoutCancel(ctx), id)
+	if err != nil {
+		return
+	}
+
+	bus.Publish(eventType, id, entity)
+}
@@ -0,0 +1,893 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// Cor das categorias criadas pela criação rápida (mesmo padrão do banco)
+const quickAddCategoryColor = "#3b82f6"
+
+// QuickAddService cria tarefas e eventos a partir de uma linha de texto, como
+// "Ligar pro banco amanhã 15h !alta #finanças" ou
+// "Dentist fri 10:00-11:00 @Downtown"
+type QuickAddService struct {
+	taskService     *TaskService
+	eventService    *EventService
+	categoryService *CategoryService
+}
+
+// NewQuickAddService cria novo serviço de criação rápida
+func NewQuickAddService(taskService *TaskService, eventService *EventService, categoryService *CategoryService) *QuickAddService {
+	return &QuickAddService{
+		taskService:     taskService,
+		eventService:    eventService,
+		categoryService: categoryService,
+	}
+}
+
+// Preview interpreta o texto sem gravar nada
+func (s *QuickAddService) Preview(ctx context.Context, text string, now time.Time) (_ *models.QuickAddResult, err error) {
+	defer logging.Track("QuickAddService.Preview", &err)()
+
+	if strings.TrimSpace(text) == "" {
+		erros := &ValidationError{}
+		erros.Required("text", "texto")
+		return nil, erros
+	}
+
+	result := ParseQuickAdd(text, now)
+
+	if result.Task != nil && result.CategoryName != "" {
+		categoryID, err := s.findTaskCategory(ctx, result.CategoryName)
+		if err != nil {
+			return nil, err
+		}
+
+		if categoryID != 0 {
+			result.Task.CategoryID = &categoryID
+		} else {
+			result.NewCategory = true
+		}
+	}
+
+	if result.Event != nil {
+		conflicts, err := s.eventService.FindConflicts(ctx, *result.Event)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, conflict := range conflicts {
+			result.Warnings = append(result.Warnings, i18n.Sprintf("no mesmo horário: %s", conflict.Title))
+		}
+	}
+
+	return &result, nil
+}
+
+// Create interpreta o texto e grava a tarefa ou o evento, criando a
+// categoria do #marcador se ela ainda não existir
+func (s *QuickAddService) Create(ctx context.Context, text string, now time.Time) (_ *models.QuickAddResult, err error) {
+	defer logging.Track("QuickAddService.Create", &err)()
+
+	result, err := s.Preview(ctx, text, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+
+	switch result.Kind {
+	case "task":
+		if !result.NewCategory {
+			if id, err = s.taskService.CreateTask(ctx, *result.Task); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		// Categoria nova e tarefa na mesma transação: se a tarefa não for
+		// gravada, a categoria também não fica
+		if id, err = s.createTaskWithCategory(ctx, result); err != nil {
+			return nil, err
+		}
+
+	case "event":
+		saved, err := s.eventService.CreateEvent(ctx, *result.Event)
+		if err != nil {
+			return nil, err
+		}
+		id = int64(saved.ID)
+	}
+
+	result.ID = int(id)
+	return result, nil
+}
+
+// createTaskWithCategory grava a categoria nova e a tarefa juntas
+func (s *QuickAddService) createTaskWithCategory(ctx context.Context, result *models.QuickAddResult) (int64, error) {
+	category := models.Category{
+		Name:  result.CategoryName,
+		Color: quickAddCategoryColor,
+		Type:  "task",
+	}
+	if err := validateCategory(category); err != nil {
+		return 0, err
+	}
+
+	task := *result.Task
+	if task.Status == "" {
+		task.Status = "pending"
+	}
+	if err := validateTask(task); err != nil {
+		return 0, err
+	}
+
+	var categoryID, taskID int64
+
+	err := s.taskService.store.WithTx(ctx, func(tx repository.Store) error {
+		var err error
+		if categoryID, err = tx.Categories().Create(ctx, category); err != nil {
+			return err
+		}
+
+		taskCategoryID := int(categoryID)
+		task.CategoryID = &taskCategoryID
+
+		taskID, err = tx.Tasks().Create(ctx, task)
+		return err
+	})
+	if isUniqueViolation(err) {
+		// Criada por outro processo depois da prévia: usa a que já existe
+		existing, findErr := s.findTaskCategory(ctx, result.CategoryName)
+		if findErr != nil || existing == 0 {
+			return 0, duplicateName("category", "uma categoria")
+		}
+		result.Task.CategoryID = &existing
+		result.NewCategory = false
+		return s.taskService.CreateTask(ctx, *result.Task)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	result.Task.CategoryID = task.CategoryID
+	publishEntity(ctx, s.taskService.bus, events.CategoryCreated, int(categoryID), s.categoryService.GetCategoryByID)
+	publishEntity(ctx, s.taskService.bus, events.TaskCreated, int(taskID), s.taskService.GetTaskByID)
+
+	return taskID, nil
+}
+
+// findTaskCategory procura a categoria pelo nome, sem diferenciar
+// maiúsculas. O nome é único entre todos os tipos, então vale também a de
+// outro tipo (a de tarefas e as gerais têm preferência). Retorna 0 se não
+// existir.
+func (s *QuickAddService) findTaskCategory(ctx context.Context, name string) (int, error) {
+	id, _, err := s.categoryService.findCategoryByName(ctx, name, "task")
+	return id, err
+}
+
+// ═══════════════════════════════════════════════════════════
+// INTERPRETAÇÃO DO TEXTO
+// ═══════════════════════════════════════════════════════════
+
+// Vocabulário aceito, em português e inglês
+
+var quickAddPriorities = map[string]string{
+	"high": "high", "alta": "high", "h": "high", "a": "high", "1": "high",
+	"medium": "medium", "media": "medium", "média": "medium", "m": "medium", "2": "medium",
+	"low": "low", "baixa": "low", "l": "low", "b": "low", "3": "low",
+}
+
+// Abreviações que também são palavras comuns ("ter", "sex", "sat", "sun")
+// ficaram de fora
+var quickAddWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "domingo": time.Sunday, "dom": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday, "segunda": time.Monday, "segunda-feira": time.Monday, "seg": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "terça": time.Tuesday, "terca": time.Tuesday, "terça-feira": time.Tuesday, "terca-feira": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "quarta": time.Wednesday, "quarta-feira": time.Wednesday, "qua": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday, "quinta": time.Thursday, "quinta-feira": time.Thursday, "qui": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "sexta": time.Friday, "sexta-feira": time.Friday,
+	"saturday": time.Saturday, "sábado": time.Saturday, "sabado": time.Saturday, "sáb": time.Saturday, "sab": time.Saturday,
+}
+
+// Dias relativos, dos mais longos para os mais curtos
+var quickAddRelativeDays = []struct {
+	words []string
+	days  int
+}{
+	{[]string{"day", "after", "tomorrow"}, 2},
+	{[]string{"depois", "de", "amanhã"}, 2},
+	{[]string{"depois", "de", "amanha"}, 2},
+	{[]string{"today"}, 0},
+	{[]string{"hoje"}, 0},
+	{[]string{"tomorrow"}, 1},
+	{[]string{"amanhã"}, 1},
+	{[]string{"amanha"}, 1},
+}
+
+var quickAddNext = map[string]bool{
+	"next": true, "próxima": true, "proxima": true, "próximo": true, "proximo": true,
+}
+
+// Palavras de ligação removidas do título quando vêm logo antes de uma data
+// ou horário ("no sábado", "at 3pm", "às 15h")
+var quickAddConnectors = map[string]bool{
+	"on": true, "at": true, "by": true, "until": true, "due": true,
+	"no": true, "na": true, "às": true, "as": true, "dia": true, "em": true, "até": true, "ate": true,
+}
+
+var quickAddRangeSeparators = map[string]bool{
+	"-": true, "–": true, "to": true, "até": true, "ate": true, "às": true, "as": true, "a": true,
+}
+
+var quickAddDurationWords = map[string]bool{
+	"for": true, "por": true, "durante": true,
+}
+
+type clock struct {
+	hour, minute int
+	meridiem     string // "am", "pm" ou vazio
+}
+
+type quickAddParser struct {
+	now    time.Time
+	tokens []string // como digitados
+	words  []string // minúsculos, sem pontuação no fim
+	used   []bool
+
+	kind     string
+	date     *time.Time
+	start    *clock
+	end      *clock
+	duration time.Duration
+	priority string
+	category string
+	location string
+	warnings []string
+}
+
+// ParseQuickAdd interpreta o texto relativo a now. Reconhece:
+//   - datas: hoje/today, amanhã/tomorrow, dia da semana (seg, fri, próxima sexta),
+//     "em 3 dias"/"in 2 weeks", 2026-10-20 e 20/10[/2026]
+//   - horários: 15h, 15h30, 15:00, 3pm, meio-dia/noon, faixas 10:00-11:00 e duração "por 1h"
+//   - !alta/!high, #categoria e @local (até a próxima data, horário ou
+//     marcador, ou entre aspas: @Sala 3 amanhã, @"Rua Augusta, 500")
+//   - prefixo "tarefa:"/"task:" ou "evento:"/"event:" para forçar o tipo
+//
+// Faixa de horário, duração ou local viram evento; o resto vira tarefa.
+func ParseQuickAdd(text string, now time.Time) models.QuickAddResult {
+	p := &quickAddParser{now: now, tokens: strings.Fields(text)}
+	p.words = make([]string, len(p.tokens))
+	p.used = make([]bool, len(p.tokens))
+	for i, token := range p.tokens {
+		p.words[i] = strings.TrimRight(strings.ToLower(token), ",.;")
+	}
+
+	p.parse()
+	return p.result()
+}
+
+func (p *quickAddParser) parse() {
+	if len(p.words) > 0 {
+		switch p.words[0] {
+		case "task:", "tarefa:":
+			p.kind = "task"
+			p.used[0] = true
+		case "event:", "evento:":
+			p.kind = "event"
+			p.used[0] = true
+		}
+	}
+
+	for i := 0; i < len(p.words); i++ {
+		if p.used[i] {
+			continue
+		}
+
+		word := p.words[i]
+
+		switch {
+		case strings.HasPrefix(word, "!") && len(word) > 1:
+			if priority, ok := quickAddPriorities[strings.TrimPrefix(word, "!")]; ok {
+				p.priority = priority
+				p.used[i] = true
+			}
+			continue
+
+		case strings.HasPrefix(word, "#") && len(word) > 1:
+			p.category = markerValue(p.tokens[i])
+			p.used[i] = true
+			continue
+
+		case strings.HasPrefix(word, "@") && len(word) > 1:
+			i = p.parseLocation(i)
+			continue
+		}
+
+		if n := p.matchDateOrTime(i); n > 0 {
+			for j := i; j < i+n; j++ {
+				p.used[j] = true
+			}
+			if i > 0 && !p.used[i-1] && quickAddConnectors[p.words[i-1]] {
+				p.used[i-1] = true
+			}
+			i += n - 1
+		}
+	}
+}
+
+// parseLocation lê o @local da posição i: entre aspas (@"Sala 3, bloco B")
+// ou as palavras seguintes até o próximo marcador, data ou horário, ou até
+// uma vírgula ("@Sala 3 amanhã"). Retorna a última posição usada.
+func (p *quickAddParser) parseLocation(i int) int {
+	if strings.HasPrefix(p.tokens[i], `@"`) {
+		var parts []string
+		for j := i; j < len(p.tokens); j++ {
+			token := p.tokens[j]
+			if j == i {
+				token = token[2:]
+			}
+			p.used[j] = true
+			if before, _, closed := strings.Cut(token, `"`); closed {
+				p.location = strings.TrimSpace(strings.Join(append(parts, before), " "))
+				return j
+			}
+			parts = append(parts, token)
+		}
+		// Sem as aspas de fechamento, vai até o fim
+		p.location = strings.TrimSpace(strings.Join(parts, " "))
+		return len(p.tokens) - 1
+	}
+
+	p.used[i] = true
+	parts := []string{markerValue(p.tokens[i])}
+	last := i
+	for j := i + 1; j < len(p.tokens) && !strings.HasSuffix(p.tokens[last], ","); j++ {
+		if p.used[j] || p.startsMarker(j) {
+			break
+		}
+		p.used[j] = true
+		parts = append(parts, strings.TrimRight(p.tokens[j], ",.;"))
+		last = j
+	}
+
+	p.location = strings.Join(parts, " ")
+	return last
+}
+
+// startsMarker diz se a palavra da posição i começa outro trecho reconhecido:
+// !prioridade, #categoria, @local, data ou horário (com ou sem conector,
+// como "às 15h")
+func (p *quickAddParser) startsMarker(i int) bool {
+	word := p.words[i]
+	if len(word) > 1 && strings.ContainsAny(word[:1], "!#@") {
+		return true
+	}
+
+	// Os formatos gravam o que encontram: testa numa cópia
+	matches := func(j int) bool {
+		probe := *p
+		probe.warnings = nil
+		return j < len(p.words) && probe.matchDateOrTime(j) > 0
+	}
+	return matches(i) || (quickAddConnectors[word] && matches(i+1))
+}
+
+// matchDateOrTime tenta os formatos de data e horário na posição i e retorna
+// quantas palavras foram consumidas
+func (p *quickAddParser) matchDateOrTime(i int) int {
+	matchers := []func(int) int{
+		p.matchRelativeDay,
+		p.matchWeekday,
+		p.matchIn,
+		p.matchDate,
+		p.matchDuration,
+		p.matchTimeRange,
+		p.matchTime,
+	}
+
+	for _, match := range matchers {
+		if n := match(i); n > 0 {
+			return n
+		}
+	}
+
+	return 0
+}
+
+func (p *quickAddParser) matchRelativeDay(i int) int {
+	for _, relative := range quickAddRelativeDays {
+		if p.hasWords(i, relative.words) {
+			p.setDate(p.today().AddDate(0, 0, relative.days))
+			return len(relative.words)
+		}
+	}
+	return 0
+}
+
+func (p *quickAddParser) matchWeekday(i int) int {
+	next := quickAddNext[p.words[i]]
+	offset := 0
+	if next {
+		offset = 1
+	}
+
+	if i+offset >= len(p.words) {
+		return 0
+	}
+
+	weekday, ok := quickAddWeekdays[p.words[i+offset]]
+	if !ok {
+		return 0
+	}
+
+	// Sem "próxima", o próprio dia de hoje vale
+	days := (int(weekday) - int(p.now.Weekday()) + 7) % 7
+	if next && days == 0 {
+		days = 7
+	}
+
+	p.setDate(p.today().AddDate(0, 0, days))
+	return offset + 1
+}
+
+// matchIn trata "em 3 dias", "in 2 weeks", "daqui a 30 minutos"
+func (p *quickAddParser) matchIn(i int) int {
+	start := i
+	switch {
+	case p.hasWords(i, []string{"daqui", "a"}):
+		start = i + 2
+	case p.words[i] == "in" || p.words[i] == "em" || p.words[i] == "daqui":
+		start = i + 1
+	default:
+		return 0
+	}
+
+	if start+1 >= len(p.words) {
+		return 0
+	}
+
+	amount, err := strconv.Atoi(p.words[start])
+	if err != nil || amount < 0 {
+		return 0
+	}
+
+	unit := p.words[start+1]
+	today := p.today()
+
+	switch {
+	case isAnyOf(unit, "day", "days", "dia", "dias"):
+		p.setDate(today.AddDate(0, 0, amount))
+	case isAnyOf(unit, "week", "weeks", "semana", "semanas"):
+		p.setDate(today.AddDate(0, 0, 7*amount))
+	case isAnyOf(unit, "month", "months", "mês", "mes", "meses"):
+		p.setDate(today.AddDate(0, amount, 0))
+	case isAnyOf(unit, "hour", "hours", "hora", "horas", "h"):
+		p.setMoment(p.now.Add(time.Duration(amount) * time.Hour))
+	case isAnyOf(unit, "minute", "minutes", "min", "mins", "minuto", "minutos"):
+		p.setMoment(p.now.Add(time.Duration(amount) * time.Minute))
+	default:
+		return 0
+	}
+
+	return start + 2 - i
+}
+
+// matchDate trata 2026-10-20, 20/10 e 20/10/2026 (dia antes do mês)
+func (p *quickAddParser) matchDate(i int) int {
+	word := p.words[i]
+
+	if date, err := time.ParseInLocation("2006-01-02", word, p.now.Location()); err == nil {
+		p.setDate(date)
+		return 1
+	}
+
+	parts := strings.Split(word, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0
+	}
+
+	day, errDay := strconv.Atoi(parts[0])
+	month, errMonth := strconv.Atoi(parts[1])
+	if errDay != nil || errMonth != nil || month < 1 || month > 12 || day < 1 || day > 31 {
+		return 0
+	}
+
+	year := p.now.Year()
+	explicitYear := len(parts) == 3
+	if explicitYear {
+		var err error
+		if year, err = strconv.Atoi(parts[2]); err != nil {
+			return 0
+		}
+		if year < 100 {
+			year += 2000
+		}
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, p.now.Location())
+	if date.Day() != day {
+		return 0 // 31/02 e afins
+	}
+
+	// Sem ano, uma data que já passou é a do ano que vem
+	if !explicitYear && date.Before(p.today()) {
+		date = date.AddDate(1, 0, 0)
+	}
+
+	p.setDate(date)
+	return 1
+}
+
+// matchDuration trata "por 2h", "for 45min", "durante 1h30"
+func (p *quickAddParser) matchDuration(i int) int {
+	if !quickAddDurationWords[p.words[i]] || i+1 >= len(p.words) {
+		return 0
+	}
+
+	duration, n := parseDuration(p.words[i+1:])
+	if n == 0 {
+		return 0
+	}
+
+	p.duration = duration
+	return n + 1
+}
+
+// matchTimeRange trata "10:00-11:00", "10-11am", "15h às 16h", "3pm to 4pm"
+func (p *quickAddParser) matchTimeRange(i int) int {
+	// Números soltos ("capítulos 3-4") não são faixa de horário
+	word := p.words[i]
+	marked := strings.ContainsAny(word, ":h") || strings.HasSuffix(word, "am") || strings.HasSuffix(word, "pm")
+
+	if left, right, found := strings.Cut(word, "-"); found && marked {
+		start, okStart := parseClock(left, true)
+		end, okEnd := parseClock(right, true)
+		if okStart && okEnd {
+			p.setRange(start, end)
+			return 1
+		}
+	}
+
+	start, ok := parseClock(word, false)
+	if !ok || i+2 >= len(p.words) || !quickAddRangeSeparators[p.words[i+1]] {
+		return 0
+	}
+
+	end, ok := parseClock(p.words[i+2], true)
+	if !ok {
+		return 0
+	}
+
+	p.setRange(start, end)
+	return 3
+}
+
+func (p *quickAddParser) matchTime(i int) int {
+	// "3 pm" em duas palavras
+	if i+1 < len(p.words) && isAnyOf(p.words[i+1], "am", "pm") {
+		if t, ok := parseClock(p.words[i]+p.words[i+1], false); ok {
+			p.start = &t
+			return 2
+		}
+	}
+
+	// Número solto só é horário depois de "às"/"at": "às 3"
+	bare := i > 0 && isAnyOf(p.words[i-1], "às", "at")
+	t, ok := parseClock(p.words[i], bare)
+	if !ok {
+		return 0
+	}
+
+	p.start = &t
+	return 1
+}
+
+func (p *quickAddParser) hasWords(i int, words []string) bool {
+	if i+len(words) > len(p.words) {
+		return false
+	}
+	for j, word := range words {
+		if p.words[i+j] != word {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *quickAddParser) today() time.Time {
+	year, month, day := p.now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, p.now.Location())
+}
+
+func (p *quickAddParser) setDate(date time.Time) {
+	p.date = &date
+}
+
+// setMoment guarda data e horário exatos ("em 2 horas")
+func (p *quickAddParser) setMoment(moment time.Time) {
+	year, month, day := moment.Date()
+	p.setDate(time.Date(year, month, day, 0, 0, 0, 0, moment.Location()))
+	p.start = &clock{hour: moment.Hour(), minute: moment.Minute()}
+}
+
+// setRange aplica o am/pm do fim ao início quando só o fim tem ("10-11am")
+func (p *quickAddParser) setRange(start, end clock) {
+	if start.meridiem == "" && end.meridiem == "pm" && start.hour < 12 && start.hour+12 <= end.hour {
+		start.hour += 12
+	}
+	p.start = &start
+	p.end = &end
+}
+
+// ═══════════════════════════════════════════════════════════
+// MONTAGEM DO RESULTADO
+// ═══════════════════════════════════════════════════════════
+
+func (p *quickAddParser) result() models.QuickAddResult {
+	var title []string
+	for i, token := range p.tokens {
+		if !p.used[i] {
+			title = append(title, token)
+		}
+	}
+
+	result := models.QuickAddResult{
+		Kind:         p.kind,
+		CategoryName: p.category,
+	}
+
+	if result.Kind == "" {
+		result.Kind = "task"
+		if p.end != nil || p.duration > 0 || p.location != "" {
+			result.Kind = "event"
+		}
+	}
+
+	if len(title) == 0 {
+		p.warnings = append(p.warnings, i18n.T("informe o título"))
+	}
+
+	if result.Kind == "event" {
+		result.Event = p.buildEvent(strings.Join(title, " "))
+		result.CategoryName = ""
+	} else {
+		result.Task = p.buildTask(strings.Join(title, " "))
+	}
+
+	result.Warnings = p.warnings
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+
+	return result
+}
+
+func (p *quickAddParser) buildTask(title string) *models.Task {
+	task := &models.Task{
+		Title:    title,
+		Status:   "pending",
+		Priority: p.priority,
+	}
+
+	if task.Priority == "" {
+		task.Priority = "medium"
+	}
+
+	if p.date != nil || p.start != nil {
+		due := p.startTime()
+		task.DueDate = &due
+	}
+
+	if p.location != "" {
+		p.warnings = append(p.warnings, i18n.Sprintf("tarefas não têm local: @%s ignorado", p.location))
+	}
+	if p.end != nil || p.duration > 0 {
+		p.warnings = append(p.warnings, i18n.T("tarefas não têm duração: horário de término ignorado"))
+	}
+
+	return task
+}
+
+func (p *quickAddParser) buildEvent(title string) *models.Event {
+	event := &models.Event{
+		Title:    title,
+		Location: p.location,
+	}
+
+	switch {
+	case p.start != nil:
+		event.StartDate = p.startTime()
+		switch {
+		case p.end != nil:
+			event.EndDate = atClock(event.StartDate, *p.end)
+			if !event.EndDate.After(event.StartDate) {
+				event.EndDate = event.EndDate.AddDate(0, 0, 1) // termina depois da meia-noite
+			}
+		case p.duration > 0:
+			event.EndDate = event.StartDate.Add(p.duration)
+		default:
+			event.EndDate = event.StartDate.Add(time.Hour)
+		}
+
+	case p.date != nil:
+		event.AllDay = true
+		event.StartDate = *p.date
+		event.EndDate = p.date.AddDate(0, 0, 1)
+
+	default:
+		p.warnings = append(p.warnings, i18n.T("informe a data do evento"))
+	}
+
+	if p.priority != "" {
+		p.warnings = append(p.warnings, i18n.Sprintf("eventos não têm prioridade: !%s ignorado", p.priority))
+	}
+	if p.category != "" {
+		p.warnings = append(p.warnings, i18n.Sprintf("eventos não têm categoria: #%s ignorado", p.category))
+	}
+
+	return event
+}
+
+// startTime junta data e horário. Só com horário, vale hoje, ou amanhã se
+// o horário já passou.
+func (p *quickAddParser) startTime() time.Time {
+	if p.start == nil {
+		return *p.date
+	}
+
+	if p.date != nil {
+		return atClock(*p.date, *p.start)
+	}
+
+	start := atClock(p.today(), *p.start)
+	if start.Before(p.now) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
+
+func atClock(day time.Time, c clock) time.Time {
+	year, month, date := day.Date()
+	return time.Date(year, month, date, c.hour, c.minute, 0, 0, day.Location())
+}
+
+// ═══════════════════════════════════════════════════════════
+// HORÁRIOS E DURAÇÕES
+// ═══════════════════════════════════════════════════════════
+
+// parseClock aceita 15:00, 15h, 15h30, 3pm, 3:30pm, noon e meio-dia. Número
+// solto ("15") só quando bare é verdadeiro.
+func parseClock(word string, bare bool) (clock, bool) {
+	word = strings.TrimRight(word, ",.;")
+
+	if isAnyOf(word, "noon", "meio-dia", "meiodia") {
+		return clock{hour: 12}, true
+	}
+
+	var c clock
+	for _, meridiem := range []string{"am", "pm"} {
+		if strings.HasSuffix(word, meridiem) {
+			c.meridiem = meridiem
+			word = strings.TrimSuffix(word, meridiem)
+			break
+		}
+	}
+
+	hourPart, minutePart := word, ""
+	switch {
+	case strings.Contains(word, ":"):
+		hourPart, minutePart, _ = strings.Cut(word, ":")
+	case strings.Contains(word, "h"):
+		hourPart, minutePart, _ = strings.Cut(word, "h")
+	case c.meridiem == "" && !bare:
+		return clock{}, false
+	}
+
+	hour, err := strconv.Atoi(hourPart)
+	if err != nil {
+		return clock{}, false
+	}
+
+	minute := 0
+	if minutePart != "" {
+		if minute, err = strconv.Atoi(minutePart); err != nil || len(minutePart) != 2 {
+			return clock{}, false
+		}
+	}
+
+	if c.meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return clock{}, false
+		}
+		if c.meridiem == "pm" && hour != 12 {
+			hour += 12
+		}
+		if c.meridiem == "am" && hour == 12 {
+			hour = 0
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return clock{}, false
+	}
+
+	c.hour, c.minute = hour, minute
+	return c, true
+}
+
+// parseDuration aceita "2h", "45min", "1h30" ou "2 horas"/"30 minutes" e
+// retorna quantas palavras usou
+func parseDuration(words []string) (time.Duration, int) {
+	if len(words) == 0 {
+		return 0, 0
+	}
+
+	word := words[0]
+
+	if len(words) > 1 {
+		if amount, err := strconv.Atoi(word); err == nil && amount > 0 {
+			unit := words[1]
+			switch {
+			case isAnyOf(unit, "hour", "hours", "hora", "horas", "h"):
+				return time.Duration(amount) * time.Hour, 2
+			case isAnyOf(unit, "minute", "minutes", "min", "mins", "minuto", "minutos"):
+				return time.Duration(amount) * time.Minute, 2
+			}
+		}
+	}
+
+	for _, suffix := range []string{"mins", "min", "m"} {
+		if minutes, err := strconv.Atoi(strings.TrimSuffix(word, suffix)); err == nil && strings.HasSuffix(word, suffix) && minutes > 0 {
+			return time.Duration(minutes) * time.Minute, 1
+		}
+	}
+
+	hourPart, minutePart, found := strings.Cut(word, "h")
+	if !found {
+		return 0, 0
+	}
+
+	hours, err := strconv.Atoi(hourPart)
+	if err != nil {
+		return 0, 0
+	}
+
+	minutes := 0
+	if minutePart != "" {
+		if minutes, err = strconv.Atoi(minutePart); err != nil {
+			return 0, 0
+		}
+	}
+
+	duration := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	if duration <= 0 {
+		return 0, 0
+	}
+
+	return duration, 1
+}
+
+// markerValue tira o # ou @ e troca _ por espaço
+func markerValue(token string) string {
+	value := strings.TrimRight(token[1:], ",.;")
+	return strings.ReplaceAll(value, "_", " ")
+}
+
+func isAnyOf(word string, options ...string) bool {
+	for _, option := range options {
+		if word == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	// Segunda-feira
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	const layout = "2006-01-02 15:04"
+
+	tests := []struct {
+		text     string
+		kind     string
+		title    string
+		start    string // prazo da tarefa ou início do evento
+		end      string
+		allDay   bool
+		priority string
+		category string
+		location string
+	}{
+		{
+			text:     "Call bank tomorrow 3pm !high #finance",
+			kind:     "task",
+			title:    "Call bank",
+			start:    "2026-10-20 15:00",
+			priority: "high",
+			category: "finance",
+		},
+		{
+			text:     "Dentist fri 10:00-11:00 @Downtown",
+			kind:     "event",
+			title:    "Dentist",
+			start:    "2026-10-23 10:00",
+			end:      "2026-10-23 11:00",
+			location: "Downtown",
+		},
+		{
+			text:     "Reunião amanhã às 15h por 30min @Sala 3",
+			kind:     "event",
+			title:    "Reunião",
+			start:    "2026-10-20 15:00",
+			end:      "2026-10-20 15:30",
+			location: "Sala 3",
+		},
+		{
+			text:     `Almoço @"Rua Augusta, 500" sexta 12:00`,
+			kind:     "event",
+			title:    "Almoço",
+			start:    "2026-10-23 12:00",
+			end:      "2026-10-23 13:00",
+			location: "Rua Augusta, 500",
+		},
+		{
+			text:     "Pagar aluguel 05/11 !alta",
+			kind:     "task",
+			title:    "Pagar aluguel",
+			start:    "2026-11-05 00:00",
+			priority: "high",
+		},
+		{
+			text:   "evento: Feriado 2026-11-02",
+			kind:   "event",
+			title:  "Feriado",
+			start:  "2026-11-02 00:00",
+			end:    "2026-11-03 00:00",
+			allDay: true,
+		},
+		{
+			text:     "Ligar para a escola às 8h",
+			kind:     "task",
+			title:    "Ligar para a escola",
+			start:    "2026-10-20 08:00",
+			priority: "medium",
+		},
+		{
+			text:     "Comprar pão",
+			kind:     "task",
+			title:    "Comprar pão",
+			priority: "medium",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			result := ParseQuickAdd(tt.text, now)
+			if result.Kind != tt.kind {
+				t.Fatalf("tipo = %q, quero %q", result.Kind, tt.kind)
+			}
+
+			var title, start, end, priority, location string
+			var allDay bool
+			if result.Kind == "task" {
+				title, priority = result.Task.Title, result.Task.Priority
+				if result.Task.DueDate != nil {
+					start = result.Task.DueDate.Format(layout)
+				}
+			} else {
+				title, location, allDay = result.Event.Title, result.Event.Location, result.Event.AllDay
+				start = result.Event.StartDate.Format(layout)
+				end = result.Event.EndDate.Format(layout)
+			}
+
+			if title != tt.title {
+				t.Errorf("título = %q, quero %q", title, tt.title)
+			}
+			if start != tt.start {
+				t.Errorf("início = %q, quero %q", start, tt.start)
+			}
+			if end != tt.end {
+				t.Errorf("fim = %q, quero %q", end, tt.end)
+			}
+			if allDay != tt.allDay {
+				t.Errorf("dia inteiro = %v, quero %v", allDay, tt.allDay)
+			}
+			if tt.priority != "" && priority != tt.priority {
+				t.Errorf("prioridade = %q, quero %q", priority, tt.priority)
+			}
+			if result.CategoryName != tt.category {
+				t.Errorf("categoria = %q, quero %q", result.CategoryName, tt.category)
+			}
+			if location != tt.location {
+				t.Errorf("local = %q, quero %q", location, tt.location)
+			}
+			if len(result.Warnings) > 0 {
+				t.Errorf("avisos = %q, quero nenhum", result.Warnings)
+			}
+		})
+	}
+}
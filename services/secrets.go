@@ -0,0 +1,134 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"personal-cockpit/i18n"
+)
+
+// Prefixo dos valores cifrados nas configurações
+const sealedPrefix = "enc:v1:"
+
+// SecretBox cifra segredos guardados nas configurações (AES-256-GCM). A
+// chave é aleatória e fica no arquivo secret.key da pasta de dados, legível
+// só pelo usuário: protege a senha de quem tem só o banco (uma cópia, um
+// backup), não de quem lê a pasta de dados inteira.
+type SecretBox struct {
+	dir string
+
+	mu  sync.Mutex
+	key []byte
+}
+
+// NewSecretBox cria o cofre com a chave em dir (criada no primeiro uso)
+func NewSecretBox(dir string) *SecretBox {
+	return &SecretBox{dir: dir}
+}
+
+// Seal cifra o texto; vazio continua vazio
+func (b *SecretBox) Seal(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	aead, err := b.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decifra um valor de Seal. Valores sem o prefixo (gravados à mão nas
+// configurações) voltam como estão.
+func (b *SecretBox) Open(value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil {
+		return "", i18n.Errorf("segredo inválido: %w", err)
+	}
+
+	aead, err := b.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return "", i18n.Errorf("segredo inválido")
+	}
+
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return "", i18n.Errorf("não foi possível decifrar o segredo (a chave secret.key mudou?)")
+	}
+
+	return string(plain), nil
+}
+
+func (b *SecretBox) cipher() (cipher.AEAD, error) {
+	key, err := b.loadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadKey lê a chave do arquivo ou cria uma nova
+func (b *SecretBox) loadKey() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.key != nil {
+		return b.key, nil
+	}
+
+	path := filepath.Join(b.dir, "secret.key")
+
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, i18n.Errorf("chave inválida em %s", path)
+		}
+		b.key = key
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, i18n.Errorf("erro ao ler chave: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return nil, i18n.Errorf("erro ao criar pasta da chave: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, i18n.Errorf("erro ao gravar chave: %w", err)
+	}
+
+	b.key = key
+	return key, nil
+}
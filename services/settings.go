@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// SettingsService gerencia as configurações chave/valor do app
+type SettingsService struct {
+	store repository.UnitOfWork
+}
+
+// NewSettingsService cria novo serviço de configurações
+func NewSettingsService(db *sql.DB) *SettingsService {
+	return NewSettingsServiceWithStore(repository.NewSQLite(db))
+}
+
+// NewSettingsServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewSettingsServiceWithStore(store repository.UnitOfWork) *SettingsService {
+	return &SettingsService{store: store}
+}
+
+// Get retorna o valor da configuração ou defaultValue se ela não existir
+func (s *SettingsService) Get(ctx context.Context, key string, defaultValue string) (_ string, err error) {
+	defer logging.Track("SettingsService.Get", &err)()
+
+	value, err := s.store.Settings().Get(ctx, key)
+	if errors.Is(err, repository.ErrNotFound) {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// GetBool lê uma configuração booleana ("true"/"false")
+func (s *SettingsService) GetBool(ctx context.Context, key string, defaultValue bool) (_ bool, err error) {
+	defer logging.Track("SettingsService.GetBool", &err)()
+
+	value, err := s.Get(ctx, key, strconv.FormatBool(defaultValue))
+	if err != nil {
+		return false, err
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue, nil
+	}
+
+	return parsed, nil
+}
+
+// GetInt lê uma configuração numérica
+func (s *SettingsService) GetInt(ctx context.Context, key string, defaultValue int) (_ int, err error) {
+	defer logging.Track("SettingsService.GetInt", &err)()
+
+	value, err := s.Get(ctx, key, strconv.Itoa(defaultValue))
+	if err != nil {
+		return 0, err
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue, nil
+	}
+
+	return parsed, nil
+}
+
+// Set grava a configuração, criando-a se ainda não existir
+func (s *SettingsService) Set(ctx context.Context, key string, value string) (err error) {
+	defer logging.Track("SettingsService.Set", &err)()
+
+	if key == "" {
+		return i18n.Errorf("chave da configuração é obrigatória")
+	}
+
+	return s.store.Settings().Set(ctx, key, value)
+}
+
+// GetAll retorna todas as configurações
+func (s *SettingsService) GetAll(ctx context.Context) (_ []models.Setting, err error) {
+	defer logging.Track("SettingsService.GetAll", &err)()
+
+	settings, err := s.store.Settings().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings == nil {
+		settings = []models.Setting{}
+	}
+
+	return settings, nil
+}
+
+// Delete remove a configuração, voltando ao valor padrão
+func (s *SettingsService) Delete(ctx context.Context, key string) (err error) {
+	defer logging.Track("SettingsService.Delete", &err)()
+
+	return s.store.Settings().Delete(ctx, key)
+}
@@ -1,302 +1,338 @@
 package services
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"strings"
 
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
 	"personal-cockpit/models"
+	"personal-cockpit/repository"
+	"personal-cockpit/timezone"
 )
 
 // TaskService gerencia operações de tarefas
 type TaskService struct {
-	db *sql.DB
+	store repository.UnitOfWork
+	bus   *events.Bus
 }
 
 // NewTaskService cria novo serviço de tarefas
-func NewTaskService(db *sql.DB) *TaskService {
-	return &TaskService{db: db}
+func NewTaskService(db *sql.DB, bus *events.Bus) *TaskService {
+	return NewTaskServiceWithStore(repository.NewSQLite(db), bus)
 }
 
-// CreateTask cria uma nova tarefa
-func (s *TaskService) CreateTask(task models.Task) (int64, error) {
-
-	// Validações
-	var erros []string
-
-	if task.Title == "" {
-		erros = append(erros, "título")
-	}
+// NewTaskServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewTaskServiceWithStore(store repository.UnitOfWork, bus *events.Bus) *TaskService {
+	return &TaskService{store: store, bus: bus}
+}
 
-	if task.Priority == "" {
-		erros = append(erros, "prioridade")
-	}
+// CreateTask cria uma nova tarefa
+func (s *TaskService) CreateTask(ctx context.Context, task models.Task) (_ int64, err error) {
+	defer logging.Track("TaskService.CreateTask", &err)()
 
-	if task.Description == "" {
-		erros = append(erros, "descrição")
+	if task.Status == "" {
+		task.Status = "pending"
 	}
 
-	// Se tem erros, retornar todos de uma vez
-	if len(erros) > 0 {
-		mensagem := "Campos obrigatórios:\n- " + strings.Join(erros, "\n- ")
-		return 0, fmt.Errorf(mensagem)
+	// Validações
+	if err := validateTask(task); err != nil {
+		return 0, err
 	}
 
-	// Query SQL
-	query := `
-		INSERT INTO tasks (title, description, status, priority, category_id, due_date)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`
-
-	result, err := s.db.Exec(
-		query,
-		task.Title,
-		task.Description,
-		task.Status,
-		task.Priority,
-		task.CategoryID,
-		task.DueDate,
-	)
-
+	id, err := s.store.Tasks().Create(ctx, task)
 	if err != nil {
-		return 0, fmt.Errorf("erro ao criar tarefa: %w", err)
+		return 0, err
 	}
 
-	// Retornar ID da tarefa criada
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("erro ao obter ID: %w", err)
-	}
+	publishEntity(ctx, s.bus, events.TaskCreated, int(id), s.GetTaskByID)
 
 	return id, nil
 }
 
 // GetAllTasks retorna todas as tarefas
-func (s *TaskService) GetAllTasks() ([]models.Task, error) {
-	query := `
-		SELECT id, title, description, status, priority, category_id, 
-		       due_date, completed_at, created_at, updated_at
-		FROM tasks
-		ORDER BY created_at DESC
-	`
-
-	rows, err := s.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar tarefas: %w", err)
-	}
-	defer rows.Close()
-
-	var tasks []models.Task
-
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.CategoryID,
-			&task.DueDate,
-			&task.CompletedAt,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler tarefa: %w", err)
-		}
-
-		tasks = append(tasks, task)
-	}
+func (s *TaskService) GetAllTasks(ctx context.Context) (_ []models.Task, err error) {
+	defer logging.Track("TaskService.GetAllTasks", &err)()
 
-	return tasks, nil
+	return s.store.Tasks().List(ctx, models.TaskFilter{})
 }
 
 // GetTaskByID busca tarefa por ID
-func (s *TaskService) GetTaskByID(id int) (*models.Task, error) {
-	query := `
-		SELECT id, title, description, status, priority, category_id, 
-		       due_date, completed_at, created_at, updated_at
-		FROM tasks
-		WHERE id = ?
-	`
-
-	var task models.Task
-	err := s.db.QueryRow(query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.Priority,
-		&task.CategoryID,
-		&task.DueDate,
-		&task.CompletedAt,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("tarefa não encontrada")
-	}
+func (s *TaskService) GetTaskByID(ctx context.Context, id int) (_ *models.Task, err error) {
+	defer logging.Track("TaskService.GetTaskByID", &err)()
+
+	task, err := s.store.Tasks().GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar tarefa: %w", err)
+		return nil, fromRepository(err, "task", id)
 	}
 
-	return &task, nil
+	return task, nil
 }
 
-// UpdateTask atualiza uma tarefa
-func (s *TaskService) UpdateTask(task models.Task) error {
+// UpdateTask atualiza uma tarefa e, se ela veio de uma nota, o item de
+// checklist correspondente, na mesma transação
+func (s *TaskService) UpdateTask(ctx context.Context, task models.Task) (err error) {
+	defer logging.Track("TaskService.UpdateTask", &err)()
+
 	if task.ID == 0 {
-		return fmt.Errorf("ID da tarefa é obrigatório")
+		return requiredID("ID da tarefa")
 	}
 
-	query := `
-		UPDATE tasks 
-		SET title = ?, description = ?, status = ?, priority = ?, 
-		    category_id = ?, due_date = ?
-		WHERE id = ?
-	`
-
-	result, err := s.db.Exec(
-		query,
-		task.Title,
-		task.Description,
-		task.Status,
-		task.Priority,
-		task.CategoryID,
-		task.DueDate,
-		task.ID,
-	)
-
-	if err != nil {
-		return fmt.Errorf("erro ao atualizar tarefa: %w", err)
+	if err := validateTask(task); err != nil {
+		return err
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+	var oldStatus string
+	var noteID, blockID int
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		// Status anterior, para saber se a tarefa foi concluída ou reaberta
+		old, err := tx.Tasks().GetByID(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		oldStatus = old.Status
+
+		if err := tx.Tasks().Update(ctx, task); err != nil {
+			return err
+		}
+
+		if blockID, err = syncTimeBlock(ctx, tx, *old, task); err != nil {
+			return err
+		}
+
+		noteID, err = syncNoteChecklist(ctx, tx, task.ID)
 		return err
+	})
+	if err != nil {
+		return fromRepository(err, "task", task.ID)
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("tarefa não encontrada")
-	}
+	publishEntity(ctx, s.bus, statusEvent(oldStatus, task.Status), task.ID, s.GetTaskByID)
+	s.publishNoteUpdated(ctx, noteID)
+	s.publishTimeBlock(ctx, blockID)
 
 	return nil
 }
 
-// DeleteTask deleta uma tarefa
-func (s *TaskService) DeleteTask(id int) error {
-	query := "DELETE FROM tasks WHERE id = ?"
+// DeleteTask deleta uma tarefa e, na mesma transação, tira o marcador
+// "^task-ID" da linha da nota de origem
+func (s *TaskService) DeleteTask(ctx context.Context, id int) (err error) {
+	defer logging.Track("TaskService.DeleteTask", &err)()
+
+	var noteID int
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		task, err := tx.Tasks().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Tasks().Delete(ctx, id); err != nil {
+			return err
+		}
+
+		if task.NoteID == nil {
+			return nil
+		}
 
-	result, err := s.db.Exec(query, id)
+		noteID, err = unlinkNoteChecklist(ctx, tx, *task.NoteID, id)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao deletar tarefa: %w", err)
+		return fromRepository(err, "task", id)
 	}
 
-	rows, err := result.RowsAffected()
+	s.bus.Publish(events.TaskDeleted, id, nil)
+	s.publishNoteUpdated(ctx, noteID)
+
+	return nil
+}
+
+// unlinkNoteChecklist tira da nota o marcador da tarefa apagada, para a
+// linha voltar a ser um item comum. Retorna o ID da nota, se ela mudou.
+func unlinkNoteChecklist(ctx context.Context, tx repository.Store, noteID, taskID int) (int, error) {
+	note, err := tx.Notes().GetByID(ctx, noteID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("tarefa não encontrada")
+	updated, changed := unlinkChecklistTask(note.Content, taskID)
+	if !changed {
+		return 0, nil
 	}
 
-	return nil
+	if err := tx.Notes().SetContent(ctx, note.ID, updated); err != nil {
+		return 0, err
+	}
+
+	return note.ID, nil
 }
 
 // ToggleTaskStatus alterna status entre pending e completed
-func (s *TaskService) ToggleTaskStatus(id int) error {
-	// Buscar tarefa
-	task, err := s.GetTaskByID(id)
-	if err != nil {
+func (s *TaskService) ToggleTaskStatus(ctx context.Context, id int) (err error) {
+	defer logging.Track("TaskService.ToggleTaskStatus", &err)()
+
+	var oldStatus, newStatus string
+	var noteID, blockID int
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		task, err := tx.Tasks().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Alternar status
+		oldStatus = task.Status
+		newStatus = "pending"
+		if task.Status == "pending" {
+			newStatus = "completed"
+		}
+
+		if err := tx.Tasks().SetStatus(ctx, id, newStatus); err != nil {
+			return err
+		}
+
+		// is_done do bloco já mudou pelo gatilho; o ID é só para avisar
+		if blockID, err = timeBlockID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		noteID, err = syncNoteChecklist(ctx, tx, id)
 		return err
+	})
+	if err != nil {
+		return fromRepository(err, "task", id)
 	}
 
-	// Alternar status
-	newStatus := "pending"
-	if task.Status == "pending" {
-		newStatus = "completed"
-	}
+	publishEntity(ctx, s.bus, statusEvent(oldStatus, newStatus), id, s.GetTaskByID)
+	s.publishNoteUpdated(ctx, noteID)
+	s.publishTimeBlock(ctx, blockID)
 
-	query := "UPDATE tasks SET status = ? WHERE id = ?"
-	_, err = s.db.Exec(query, newStatus, id)
+	return nil
+}
 
-	return err
+// statusEvent escolhe o evento de acordo com a mudança de status
+func statusEvent(oldStatus, newStatus string) events.Type {
+	switch {
+	case oldStatus != "completed" && newStatus == "completed":
+		return events.TaskCompleted
+	case oldStatus == "completed" && newStatus != "completed":
+		return events.TaskReopened
+	default:
+		return events.TaskUpdated
+	}
 }
 
-// GetTasksByFilter busca tarefas com filtros
-func (s *TaskService) GetTasksByFilter(filter models.TaskFilter) ([]models.Task, error) {
-	query := `
-		SELECT id, title, description, status, priority, category_id, 
-		       due_date, completed_at, created_at, updated_at
-		FROM tasks
-		WHERE 1=1
-	`
-
-	args := []interface{}{}
-
-	// Filtro por status
-	if filter.Status != "" {
-		query += " AND status = ?"
-		args = append(args, filter.Status)
+// syncNoteChecklist marca ou desmarca o item de checklist da nota de origem
+// para refletir o status atual da tarefa. Retorna o ID da nota, se ela mudou.
+func syncNoteChecklist(ctx context.Context, tx repository.Store, taskID int) (int, error) {
+	task, err := tx.Tasks().GetByID(ctx, taskID)
+	if err != nil {
+		return 0, err
 	}
 
-	// Filtro por prioridade
-	if filter.Priority != "" {
-		query += " AND priority = ?"
-		args = append(args, filter.Priority)
+	if task.NoteID == nil {
+		return 0, nil
 	}
 
-	// Filtro por categoria
-	if filter.CategoryID != nil {
-		query += " AND category_id = ?"
-		args = append(args, *filter.CategoryID)
+	note, err := tx.Notes().GetByID(ctx, *task.NoteID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
 	}
 
-	query += " ORDER BY created_at DESC"
+	updated, changed := setChecklistItem(note.Content, taskID, task.Status == "completed")
+	if !changed {
+		return 0, nil
+	}
 
-	rows, err := s.db.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar tarefas: %w", err)
+	if err := tx.Notes().SetContent(ctx, note.ID, updated); err != nil {
+		return 0, err
 	}
-	defer rows.Close()
-
-	var tasks []models.Task
-
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.CategoryID,
-			&task.DueDate,
-			&task.CompletedAt,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler tarefa: %w", err)
-		}
 
-		tasks = append(tasks, task)
+	return note.ID, nil
+}
+
+// publishNoteUpdated avisa que a nota de origem mudou (0 = nenhuma)
+func (s *TaskService) publishNoteUpdated(ctx context.Context, noteID int) {
+	if noteID == 0 {
+		return
 	}
 
-	return tasks, nil
+	noteService := NewNoteServiceWithStore(s.store, nil, s)
+	publishEntity(ctx, s.bus, events.NoteUpdated, noteID, noteService.GetNoteByID)
+}
+
+// GetTasksByFilter busca tarefas com filtros
+func (s *TaskService) GetTasksByFilter(ctx context.Context, filter models.TaskFilter) (_ []models.Task, err error) {
+	defer logging.Track("TaskService.GetTasksByFilter", &err)()
+
+	return s.store.Tasks().List(ctx, filter)
 }
 
 // GetPendingTasks retorna apenas tarefas pendentes
-func (s *TaskService) GetPendingTasks() ([]models.Task, error) {
+func (s *TaskService) GetPendingTasks(ctx context.Context) (_ []models.Task, err error) {
+	defer logging.Track("TaskService.GetPendingTasks", &err)()
+
 	filter := models.TaskFilter{Status: "pending"}
-	return s.GetTasksByFilter(filter)
+	return s.GetTasksByFilter(ctx, filter)
 }
 
 // GetCompletedTasks retorna apenas tarefas concluídas
-func (s *TaskService) GetCompletedTasks() ([]models.Task, error) {
+func (s *TaskService) GetCompletedTasks(ctx context.Context) (_ []models.Task, err error) {
+	defer logging.Track("TaskService.GetCompletedTasks", &err)()
+
 	filter := models.TaskFilter{Status: "completed"}
-	return s.GetTasksByFilter(filter)
+	return s.GetTasksByFilter(ctx, filter)
+}
+
+// GetTasksCompletedOn retorna as tarefas concluídas no dia informado (AAAA-MM-DD, no fuso do usuário)
+func (s *TaskService) GetTasksCompletedOn(ctx context.Context, date string) (_ []models.Task, err error) {
+	defer logging.Track("TaskService.GetTasksCompletedOn", &err)()
+
+	day, err := timezone.ParseDate(date)
+	if err != nil {
+		return nil, i18n.Errorf("data inválida, use o formato AAAA-MM-DD")
+	}
+
+	return s.store.Tasks().ListCompletedBetween(ctx, day, day.AddDate(0, 0, 1))
+}
+
+// validateTask confere os campos antes de gravar, com todos os erros de uma vez
+func validateTask(task models.Task) error {
+	erros := &ValidationError{}
+
+	if strings.TrimSpace(task.Title) == "" {
+		erros.Required("title", "título")
+	}
+
+	switch {
+	case task.Priority == "":
+		erros.Required("priority", "prioridade")
+	case !oneOf(task.Priority, taskPriorities):
+		erros.Invalid("priority", CodeInvalidChoice, choiceMessage("prioridade", taskPriorities))
+	}
+
+	if !oneOf(task.Status, taskStatuses) {
+		erros.Invalid("status", CodeInvalidChoice, choiceMessage("status", taskStatuses))
+	}
+
+	if task.EstimatedMinutes != nil && *task.EstimatedMinutes <= 0 {
+		erros.Invalid("estimated_minutes", CodeOutOfRange, "duração estimada deve ser positiva")
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+
+	return nil
 }
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"personal-cockpit/i18n"
+	"personal-cockpit/importers"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/timezone"
+)
+
+// TaskImportService traz tarefas de outros programas (formatos do pacote
+// importers). Categorias que não existem são criadas, e tarefas iguais a
+// uma existente (mesmo título e prazo) são puladas. Com DryRun nada é
+// gravado: o relatório é a prévia.
+type TaskImportService struct {
+	taskService     *TaskService
+	categoryService *CategoryService
+}
+
+// NewTaskImportService cria novo serviço de importação de tarefas
+func NewTaskImportService(taskService *TaskService, categoryService *CategoryService) *TaskImportService {
+	return &TaskImportService{
+		taskService:     taskService,
+		categoryService: categoryService,
+	}
+}
+
+// Formats lista os formatos aceitos
+func (s *TaskImportService) Formats() []string {
+	return importers.Names()
+}
+
+// PreviewCSV lê o cabeçalho de um CSV para o usuário escolher as colunas
+func (s *TaskImportService) PreviewCSV(ctx context.Context, path string) (_ *models.CSVPreview, err error) {
+	defer logging.Track("TaskImportService.PreviewCSV", &err)()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	return importers.Columns(data)
+}
+
+// ImportFile importa as tarefas de um arquivo; sem formato nas opções, ele é
+// escolhido pela extensão e pelo conteúdo
+func (s *TaskImportService) ImportFile(ctx context.Context, path string, options models.TaskImportOptions) (_ *models.TaskImportReport, err error) {
+	defer logging.Track("TaskImportService.ImportFile", &err)()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	if options.Format == "" {
+		options.Format = importers.Detect(path, data)
+	}
+
+	return s.Import(ctx, data, options)
+}
+
+// Import importa as tarefas de data, no formato options.Format. Itens com
+// problema não interrompem os outros: vão para Failed com o motivo.
+func (s *TaskImportService) Import(ctx context.Context, data []byte, options models.TaskImportOptions) (_ *models.TaskImportReport, err error) {
+	defer logging.Track("TaskImportService.Import", &err)()
+
+	format, err := importers.Get(options.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	items, failures, err := format.Parse(data, importers.Options{CSVMapping: options.CSVMapping})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TaskImportReport{
+		Format:            format.Name(),
+		DryRun:            options.DryRun,
+		Created:           []models.TaskImportItem{},
+		Skipped:           []models.TaskImportItem{},
+		Failed:            []models.TaskImportItem{},
+		CategoriesCreated: []string{},
+	}
+	for _, failure := range failures {
+		report.Failed = append(report.Failed, models.TaskImportItem{Ref: failure.Ref, Title: failure.Title, Reason: failure.Reason})
+	}
+
+	// Tarefas já existentes e as já importadas deste arquivo, por título e prazo
+	existing, err := s.taskService.GetAllTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := make(map[string]string, len(existing))
+	for _, task := range existing {
+		duplicates[importKey(task.Title, task.DueDate)] = i18n.T("já existe uma tarefa igual")
+	}
+
+	categoryIDs, err := s.taskCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ReportProgress(ctx, i+1, len(items))
+
+		entry := models.TaskImportItem{Ref: item.Ref, Title: item.Title, Category: item.Category}
+		key := importKey(item.Title, item.DueDate)
+
+		if strings.TrimSpace(item.Title) == "" {
+			entry.Reason = i18n.T("sem título")
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		reason := ""
+		switch {
+		case item.Skip != "":
+			reason = item.Skip
+		case item.Completed && !options.IncludeCompleted:
+			reason = i18n.T("concluída")
+		case duplicates[key] != "":
+			reason = duplicates[key]
+		}
+		if reason != "" {
+			entry.Reason = reason
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+
+		task := models.Task{
+			Title:       item.Title,
+			Description: item.Description,
+			Priority:    item.Priority,
+			Status:      "pending",
+			DueDate:     item.DueDate,
+		}
+		if task.Priority == "" {
+			task.Priority = "medium"
+		}
+		if item.Completed {
+			task.Status = "completed"
+		}
+
+		if err := validateTask(task); err != nil {
+			entry.Reason = err.Error()
+			report.Failed = append(report.Failed, entry)
+			continue
+		}
+
+		if categoryID, err := s.resolveCategory(ctx, item.Category, categoryIDs, report); err != nil {
+			entry.Reason = err.Error()
+			report.Failed = append(report.Failed, entry)
+			continue
+		} else if categoryID != 0 {
+			task.CategoryID = &categoryID
+		}
+
+		if !options.DryRun {
+			id, err := s.taskService.CreateTask(ctx, task)
+			if err != nil {
+				entry.Reason = err.Error()
+				report.Failed = append(report.Failed, entry)
+				continue
+			}
+			task.ID = int(id)
+		}
+
+		duplicates[key] = i18n.T("repetida no arquivo")
+		entry.Task = &task
+		report.Created = append(report.Created, entry)
+	}
+
+	return report, nil
+}
+
+// taskCategories mapeia o nome (em minúsculas) das categorias para o ID. O
+// nome é único entre todos os tipos, então entram todas; com nomes que só
+// diferem nas maiúsculas, vale a de tarefas, depois a geral (como no
+// FindOrCreateCategory).
+func (s *TaskImportService) taskCategories(ctx context.Context) (map[string]int, error) {
+	categories, err := s.categoryService.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]int, len(categories))
+	ranks := make(map[string]int, len(categories))
+	for _, category := range categories {
+		rank := 1
+		switch category.Type {
+		case "task":
+			rank = 3
+		case "general":
+			rank = 2
+		}
+
+		key := strings.ToLower(category.Name)
+		if rank > ranks[key] {
+			ids[key], ranks[key] = category.ID, rank
+		}
+	}
+	return ids, nil
+}
+
+// resolveCategory retorna o ID da categoria, criando-a se não existir. No dry
+// run ela só entra no relatório, e a tarefa fica sem categoria na prévia.
+func (s *TaskImportService) resolveCategory(ctx context.Context, name string, ids map[string]int, report *models.TaskImportReport) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, nil
+	}
+
+	key := strings.ToLower(name)
+	if id, ok := ids[key]; ok {
+		return id, nil
+	}
+
+	if report.DryRun {
+		ids[key] = 0
+		report.CategoriesCreated = append(report.CategoriesCreated, name)
+		return 0, nil
+	}
+
+	id, created, err := s.categoryService.FindOrCreateCategory(ctx, name, "task")
+	if err != nil {
+		return 0, err
+	}
+	ids[key] = id
+	if created {
+		report.CategoriesCreated = append(report.CategoriesCreated, name)
+	}
+	return id, nil
+}
+
+// importKey identifica tarefas iguais: mesmo título (sem diferenciar
+// maiúsculas e espaços) e mesmo dia de prazo
+func importKey(title string, due *time.Time) string {
+	key := strings.ToLower(strings.Join(strings.Fields(title), " "))
+	if due != nil {
+		key += "|" + timezone.In(*due).Format("2006-01-02")
+	}
+	return key
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"personal-cockpit/events"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+func newMemoryTaskService() *TaskService {
+	return NewTaskServiceWithStore(repository.NewMemory(), events.NewBus())
+}
+
+func TestTaskCRUD(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryTaskService()
+
+	id, err := s.CreateTask(ctx, models.Task{Title: "Pagar boleto", Priority: "high"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	task, err := s.GetTaskByID(ctx, int(id))
+	if err != nil {
+		t.Fatalf("GetTaskByID: %v", err)
+	}
+	if task.Title != "Pagar boleto" || task.Status != "pending" || task.Priority != "high" {
+		t.Fatalf("tarefa criada = %+v", task)
+	}
+
+	task.Title = "Pagar boleto do condomínio"
+	if err := s.UpdateTask(ctx, *task); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	if err := s.ToggleTaskStatus(ctx, task.ID); err != nil {
+		t.Fatalf("ToggleTaskStatus: %v", err)
+	}
+
+	task, err = s.GetTaskByID(ctx, int(id))
+	if err != nil {
+		t.Fatalf("GetTaskByID: %v", err)
+	}
+	if task.Title != "Pagar boleto do condomínio" || task.Status != "completed" || task.CompletedAt == nil {
+		t.Fatalf("tarefa atualizada = %+v", task)
+	}
+
+	completed, err := s.GetCompletedTasks(ctx)
+	if err != nil || len(completed) != 1 {
+		t.Fatalf("GetCompletedTasks = %v, %v", completed, err)
+	}
+
+	if err := s.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+
+	var notFound *NotFoundError
+	if _, err := s.GetTaskByID(ctx, task.ID); !errors.As(err, &notFound) {
+		t.Fatalf("GetTaskByID depois de deletar: %v", err)
+	}
+	if err := s.DeleteTask(ctx, task.ID); !errors.As(err, &notFound) {
+		t.Fatalf("DeleteTask de novo: %v", err)
+	}
+}
+
+func TestCreateTaskValidation(t *testing.T) {
+	s := newMemoryTaskService()
+
+	_, err := s.CreateTask(context.Background(), models.Task{Priority: "urgent"})
+
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("CreateTask sem título: %v", err)
+	}
+
+	tasks, _ := s.GetAllTasks(context.Background())
+	if len(tasks) != 0 {
+		t.Fatalf("tarefa inválida foi gravada: %+v", tasks)
+	}
+}
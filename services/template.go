@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+	"personal-cockpit/timezone"
+)
+
+// placeholder reconhece variáveis como {{date}}, {{date+7}} e {{prompt:Participantes}}
+var placeholder = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+var weekdays = [...]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+
+// TemplateService gerencia modelos de notas e de listas de tarefas
+type TemplateService struct {
+	store       repository.UnitOfWork
+	bus         *events.Bus
+	noteService *NoteService
+	taskService *TaskService
+}
+
+// NewTemplateService cria novo serviço de modelos
+func NewTemplateService(db *sql.DB, bus *events.Bus, noteService *NoteService, taskService *TaskService) *TemplateService {
+	return NewTemplateServiceWithStore(repository.NewSQLite(db), bus, noteService, taskService)
+}
+
+// NewTemplateServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewTemplateServiceWithStore(store repository.UnitOfWork, bus *events.Bus, noteService *NoteService, taskService *TaskService) *TemplateService {
+	return &TemplateService{store: store, bus: bus, noteService: noteService, taskService: taskService}
+}
+
+// CreateTemplate cria um novo modelo
+func (s *TemplateService) CreateTemplate(ctx context.Context, template models.Template) (_ int64, err error) {
+	defer logging.Track("TemplateService.CreateTemplate", &err)()
+
+	if err := validateTemplate(template); err != nil {
+		return 0, err
+	}
+
+	id, err := s.store.Templates().Create(ctx, template)
+	if isUniqueViolation(err) {
+		return 0, duplicateName("template", "um modelo")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	publishEntity(ctx, s.bus, events.TemplateCreated, int(id), s.GetTemplateByID)
+
+	return id, nil
+}
+
+// GetAllTemplates retorna todos os modelos
+func (s *TemplateService) GetAllTemplates(ctx context.Context) (_ []models.Template, err error) {
+	defer logging.Track("TemplateService.GetAllTemplates", &err)()
+
+	return s.store.Templates().List(ctx)
+}
+
+// GetTemplateByID busca modelo por ID
+func (s *TemplateService) GetTemplateByID(ctx context.Context, id int) (_ *models.Template, err error) {
+	defer logging.Track("TemplateService.GetTemplateByID", &err)()
+
+	template, err := s.store.Templates().GetByID(ctx, id)
+	if err != nil {
+		return nil, fromRepository(err, "template", id)
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate atualiza um modelo
+func (s *TemplateService) UpdateTemplate(ctx context.Context, template models.Template) (err error) {
+	defer logging.Track("TemplateService.UpdateTemplate", &err)()
+
+	if template.ID == 0 {
+		return requiredID("ID do modelo")
+	}
+
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+
+	err = s.store.Templates().Update(ctx, template)
+	if isUniqueViolation(err) {
+		return duplicateName("template", "um modelo")
+	}
+	if err != nil {
+		return fromRepository(err, "template", template.ID)
+	}
+
+	publishEntity(ctx, s.bus, events.TemplateUpdated, template.ID, s.GetTemplateByID)
+
+	return nil
+}
+
+// DeleteTemplate deleta um modelo
+func (s *TemplateService) DeleteTemplate(ctx context.Context, id int) (err error) {
+	defer logging.Track("TemplateService.DeleteTemplate", &err)()
+
+	if err := s.store.Templates().Delete(ctx, id); err != nil {
+		return fromRepository(err, "template", id)
+	}
+
+	s.bus.Publish(events.TemplateDeleted, id, nil)
+
+	return nil
+}
+
+// GetTemplatePrompts lista os {{prompt:...}} do modelo, na ordem em que aparecem,
+// para o frontend perguntar os valores antes de renderizar
+func (s *TemplateService) GetTemplatePrompts(ctx context.Context, id int) (_ []string, err error) {
+	defer logging.Track("TemplateService.GetTemplatePrompts", &err)()
+
+	template, err := s.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	prompts := []string{}
+
+	for _, match := range placeholder.FindAllStringSubmatch(template.Title+"\n"+template.Content, -1) {
+		label, ok := strings.CutPrefix(match[1], "prompt:")
+		label = strings.TrimSpace(label)
+		if !ok || seen[label] {
+			continue
+		}
+		seen[label] = true
+		prompts = append(prompts, label)
+	}
+
+	return prompts, nil
+}
+
+// RenderTemplate preenche as variáveis do modelo com os valores de vars
+func (s *TemplateService) RenderTemplate(ctx context.Context, id int, vars map[string]string) (_ *models.RenderedTemplate, err error) {
+	defer logging.Track("TemplateService.RenderTemplate", &err)()
+
+	template, err := s.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTemplate(template, vars, timezone.In(time.Now())), nil
+}
+
+// CreateNoteFromTemplate cria uma nota a partir de um modelo do tipo "note"
+func (s *TemplateService) CreateNoteFromTemplate(ctx context.Context, id int, vars map[string]string) (_ int64, err error) {
+	defer logging.Track("TemplateService.CreateNoteFromTemplate", &err)()
+
+	template, err := s.GetTemplateByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if template.Kind != "note" {
+		return 0, i18n.Errorf("modelo \"%s\" não é um modelo de nota", template.Name)
+	}
+
+	rendered := renderTemplate(template, vars, timezone.In(time.Now()))
+
+	return s.noteService.CreateNote(ctx, models.Note{
+		Title:      rendered.Title,
+		Content:    rendered.Content,
+		CategoryID: template.CategoryID,
+	})
+}
+
+// CreateTasksFromTemplate cria uma tarefa para cada linha de um modelo do tipo "tasks".
+// Cada linha aceita "!high", "!medium" ou "!low" para a prioridade e
+// "due:AAAA-MM-DD" para o vencimento, ex.: "- Criar conta !high due:{{date+1}}".
+func (s *TemplateService) CreateTasksFromTemplate(ctx context.Context, id int, vars map[string]string) (_ []int64, err error) {
+	defer logging.Track("TemplateService.CreateTasksFromTemplate", &err)()
+
+	template, err := s.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.Kind != "tasks" {
+		return nil, i18n.Errorf("modelo \"%s\" não é um modelo de tarefas", template.Name)
+	}
+
+	rendered := renderTemplate(template, vars, timezone.In(time.Now()))
+
+	var ids []int64
+	for _, line := range strings.Split(rendered.Content, "\n") {
+		task, ok := parseTemplateTaskLine(line)
+		if !ok {
+			continue
+		}
+
+		task.Description = i18n.Sprintf("%s (modelo \"%s\")", rendered.Title, template.Name)
+		task.CategoryID = template.CategoryID
+
+		id, err := s.taskService.CreateTask(ctx, task)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func validateTemplate(template models.Template) error {
+	erros := &ValidationError{}
+
+	if strings.TrimSpace(template.Name) == "" {
+		erros.Required("name", "nome")
+	}
+
+	switch template.Kind {
+	case "note", "tasks":
+	case "":
+		erros.Required("kind", "tipo (note ou tasks)")
+	default:
+		erros.Invalid("kind", CodeInvalidChoice, "tipo (note ou tasks)")
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+
+	return nil
+}
+
+// renderTemplate substitui as variáveis no título e no conteúdo
+func renderTemplate(template *models.Template, vars map[string]string, now time.Time) *models.RenderedTemplate {
+	title, _ := renderText(template.Title, vars, now)
+	content, cursor := renderText(template.Content, vars, now)
+
+	return &models.RenderedTemplate{
+		Title:        strings.TrimSpace(title),
+		Content:      content,
+		CursorOffset: cursor,
+	}
+}
+
+// renderText substitui os placeholders do texto. Retorna também a posição do
+// primeiro {{cursor}} (em unidades UTF-16) ou -1 se não houver.
+func renderText(text string, vars map[string]string, now time.Time) (string, int) {
+	var out strings.Builder
+	cursor := -1
+	last := 0
+
+	for _, loc := range placeholder.FindAllStringSubmatchIndex(text, -1) {
+		out.WriteString(text[last:loc[0]])
+		last = loc[1]
+
+		name := text[loc[2]:loc[3]]
+		if name == "cursor" {
+			if cursor < 0 {
+				cursor = len(utf16.Encode([]rune(out.String())))
+			}
+			continue
+		}
+
+		value, ok := resolvePlaceholder(name, vars, now)
+		if !ok {
+			// Variável desconhecida fica como está
+			value = text[loc[0]:loc[1]]
+		}
+		out.WriteString(value)
+	}
+
+	out.WriteString(text[last:])
+
+	return out.String(), cursor
+}
+
+func resolvePlaceholder(name string, vars map[string]string, now time.Time) (string, bool) {
+	if label, ok := strings.CutPrefix(name, "prompt:"); ok {
+		return vars[strings.TrimSpace(label)], true
+	}
+
+	if value, ok := vars[name]; ok {
+		return value, true
+	}
+
+	switch name {
+	case "date":
+		return now.Format("2006-01-02"), true
+	case "time":
+		return now.Format("15:04"), true
+	case "weekday":
+		return weekdays[now.Weekday()], true
+	case "year":
+		return strconv.Itoa(now.Year()), true
+	case "month":
+		return fmt.Sprintf("%02d", int(now.Month())), true
+	case "week":
+		_, week := now.ISOWeek()
+		return fmt.Sprintf("%02d", week), true
+	}
+
+	// {{date+7}} / {{date-1}}: data relativa em dias
+	if offset, ok := strings.CutPrefix(name, "date"); ok {
+		if days, err := strconv.Atoi(offset); err == nil {
+			return now.AddDate(0, 0, days).Format("2006-01-02"), true
+		}
+	}
+
+	return "", false
+}
+
+// parseTemplateTaskLine converte uma linha do modelo em tarefa
+func parseTemplateTaskLine(line string) (models.Task, bool) {
+	line = strings.TrimSpace(line)
+	for _, prefix := range []string{"- [ ]", "- [x]", "-", "*", "+"} {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			line = strings.TrimSpace(rest)
+			break
+		}
+	}
+
+	task := models.Task{Status: "pending", Priority: "medium"}
+	var words []string
+
+	for _, word := range strings.Fields(line) {
+		switch {
+		case word == "!high" || word == "!medium" || word == "!low":
+			task.Priority = word[1:]
+		case strings.HasPrefix(word, "due:"):
+			if due, err := timezone.ParseDate(word[4:]); err == nil {
+				task.DueDate = &due
+				continue
+			}
+			words = append(words, word)
+		default:
+			words = append(words, word)
+		}
+	}
+
+	task.Title = strings.Join(words, " ")
+
+	return task, task.Title != ""
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+	"personal-cockpit/timezone"
+)
+
+// DefaultTaskMinutes é a duração do bloco quando a tarefa não tem estimativa
+const DefaultTaskMinutes = 30
+
+// SchedulingService encaixa tarefas na agenda como blocos de tempo: eventos
+// com task_id. Cada tarefa tem no máximo um bloco, e os dois ficam coerentes:
+// o prazo da tarefa é o início do bloco (mover um move o outro), o título do
+// bloco segue o da tarefa e concluir a tarefa marca o bloco como feito.
+type SchedulingService struct {
+	store        repository.UnitOfWork
+	bus          *events.Bus
+	eventService *EventService
+}
+
+// NewSchedulingService cria novo serviço de blocos de tempo
+func NewSchedulingService(db *sql.DB, bus *events.Bus, eventService *EventService) *SchedulingService {
+	return NewSchedulingServiceWithStore(repository.NewSQLite(db), bus, eventService)
+}
+
+// NewSchedulingServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewSchedulingServiceWithStore(store repository.UnitOfWork, bus *events.Bus, eventService *EventService) *SchedulingService {
+	return &SchedulingService{store: store, bus: bus, eventService: eventService}
+}
+
+// ScheduleTask reserva na agenda o horário da tarefa a partir de start, com
+// minutes de duração (0 usa a estimativa da tarefa ou DefaultTaskMinutes).
+// Se a tarefa já tem bloco, ele é movido. Conflitos voltam como aviso.
+func (s *SchedulingService) ScheduleTask(ctx context.Context, taskID int, start time.Time, minutes int) (_ *models.EventSaveResult, err error) {
+	defer logging.Track("SchedulingService.ScheduleTask", &err)()
+
+	erros := &ValidationError{}
+	if start.IsZero() {
+		erros.Required("start", "início do bloco")
+	}
+	if minutes < 0 {
+		erros.Invalid("minutes", CodeOutOfRange, "duração estimada deve ser positiva")
+	}
+	if erros.HasErrors() {
+		return nil, erros
+	}
+
+	var block models.Event
+	created := false
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		task, err := tx.Tasks().GetByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		if task.Status == "completed" {
+			erros.Invalid("task_id", CodeInvalid, "tarefa já concluída")
+			return erros
+		}
+
+		duration := time.Duration(taskMinutes(*task, minutes)) * time.Minute
+
+		existing, err := tx.Events().GetByTask(ctx, taskID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			created = true
+			block = models.Event{Title: task.Title, TaskID: &task.ID}
+		case err != nil:
+			return err
+		default:
+			block = *existing
+		}
+
+		block.Title = task.Title
+		block.AllDay = false
+		block.StartDate = timezone.In(start)
+		block.EndDate = block.StartDate.Add(duration)
+
+		if created {
+			id, err := tx.Events().Create(ctx, block)
+			if err != nil {
+				return err
+			}
+			block.ID = int(id)
+		} else if err := tx.Events().Update(ctx, block); err != nil {
+			return err
+		}
+
+		task.DueDate = &block.StartDate
+		return tx.Tasks().Update(ctx, *task)
+	})
+	if err != nil {
+		return nil, fromRepository(err, "task", taskID)
+	}
+
+	eventType := events.EventUpdated
+	if created {
+		eventType = events.EventCreated
+	}
+	publishEntity(ctx, s.bus, eventType, block.ID, s.eventService.GetEventByID)
+	taskService := NewTaskServiceWithStore(s.store, nil)
+	publishEntity(ctx, s.bus, events.TaskUpdated, taskID, taskService.GetTaskByID)
+
+	return s.eventService.saveResult(ctx, block), nil
+}
+
+// AutoSchedule encaixa as tarefas nos horários livres entre from e to
+// (nunca antes de agora), dentro do expediente. Vão primeiro as de maior
+// prioridade e, entre elas, as de prazo mais próximo; cada uma ocupa o
+// primeiro intervalo livre que comporta sua duração. Tarefas concluídas são
+// ignoradas e as que já têm bloco ficam onde estão.
+func (s *SchedulingService) AutoSchedule(ctx context.Context, taskIDs []int, from, to time.Time, hours models.WorkingHours) (_ *models.AutoScheduleResult, err error) {
+	defer logging.Track("SchedulingService.AutoSchedule", &err)()
+
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+
+	result := &models.AutoScheduleResult{Scheduled: []models.Event{}, Unscheduled: []models.Task{}}
+
+	var tasks []models.Task
+	for _, id := range taskIDs {
+		task, err := s.store.Tasks().GetByID(ctx, id)
+		if err != nil {
+			return nil, fromRepository(err, "task", id)
+		}
+
+		if task.Status == "completed" {
+			continue
+		}
+
+		block, err := s.store.Events().GetByTask(ctx, id)
+		if err == nil {
+			result.Scheduled = append(result.Scheduled, *block)
+			continue
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+
+		tasks = append(tasks, *task)
+	}
+
+	sortForScheduling(tasks)
+
+	for i, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		minutes := taskMinutes(task, 0)
+		slots, err := s.eventService.FindFreeSlots(ctx, from, to, time.Duration(minutes)*time.Minute, hours)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(slots) == 0 {
+			result.Unscheduled = append(result.Unscheduled, task)
+		} else {
+			saved, err := s.ScheduleTask(ctx, task.ID, slots[0].Start, minutes)
+			if err != nil {
+				return nil, err
+			}
+
+			block, err := s.eventService.GetEventByID(ctx, saved.ID)
+			if err != nil {
+				return nil, err
+			}
+			result.Scheduled = append(result.Scheduled, *block)
+		}
+
+		ReportProgress(ctx, i+1, len(tasks))
+	}
+
+	return result, nil
+}
+
+// taskMinutes é a duração do bloco: a pedida, a estimada ou a padrão
+func taskMinutes(task models.Task, minutes int) int {
+	switch {
+	case minutes > 0:
+		return minutes
+	case task.EstimatedMinutes != nil && *task.EstimatedMinutes > 0:
+		return *task.EstimatedMinutes
+	default:
+		return DefaultTaskMinutes
+	}
+}
+
+// sortForScheduling ordena por prioridade (high primeiro), depois pelo prazo
+// (sem prazo por último) e pelo ID
+func sortForScheduling(tasks []models.Task) {
+	rank := map[string]int{"high": 0, "medium": 1, "low": 2}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if rank[a.Priority] != rank[b.Priority] {
+			return rank[a.Priority] < rank[b.Priority]
+		}
+		switch {
+		case a.DueDate != nil && b.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Before(*b.DueDate)
+		case (a.DueDate == nil) != (b.DueDate == nil):
+			return a.DueDate != nil
+		}
+		return a.ID < b.ID
+	})
+}
+
+// ═══════════════════════════════════════════════════════════
+// SINCRONIZAÇÃO ENTRE TAREFA E BLOCO
+// ═══════════════════════════════════════════════════════════
+
+// timeBlockID retorna o ID do bloco da tarefa (0 se não houver)
+func timeBlockID(ctx context.Context, tx repository.Store, taskID int) (int, error) {
+	block, err := tx.Events().GetByTask(ctx, taskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return block.ID, nil
+}
+
+// syncTimeBlock leva para o bloco as mudanças da tarefa: o título e, se o
+// prazo mudou, o horário (com a mesma duração). Um prazo só com a data
+// (meia-noite) mantém o bloco no mesmo horário do novo dia. Retorna o ID do
+// bloco, se a tarefa tem um, para avisar da mudança (is_done muda pelo
+// gatilho do status).
+func syncTimeBlock(ctx context.Context, tx repository.Store, old, task models.Task) (int, error) {
+	block, err := tx.Events().GetByTask(ctx, task.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	changed := false
+	if block.Title != task.Title {
+		block.Title = task.Title
+		changed = true
+	}
+
+	if task.DueDate != nil && !block.IsDone && !sameTime(old.DueDate, task.DueDate) {
+		start := timezone.In(*task.DueDate)
+		if start.Equal(timezone.StartOfDay(start)) {
+			start = atClock(start, clock{hour: block.StartDate.Hour(), minute: block.StartDate.Minute()})
+		}
+
+		if !start.Equal(block.StartDate) {
+			duration := block.EndDate.Sub(block.StartDate)
+			block.StartDate, block.EndDate = start, start.Add(duration)
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := tx.Events().Update(ctx, *block); err != nil {
+			return 0, err
+		}
+	}
+
+	if !changed && old.Status == task.Status {
+		return 0, nil
+	}
+	return block.ID, nil
+}
+
+// syncTaskSchedule leva para a tarefa o novo horário do bloco: o prazo passa
+// a ser o início do bloco. Retorna o ID da tarefa, se ela mudou.
+func syncTaskSchedule(ctx context.Context, tx repository.Store, old, block models.Event) (int, error) {
+	if old.TaskID == nil || block.StartDate.Equal(old.StartDate) {
+		return 0, nil
+	}
+
+	task, err := tx.Tasks().GetByID(ctx, *old.TaskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	start := timezone.In(block.StartDate)
+	task.DueDate = &start
+	if err := tx.Tasks().Update(ctx, *task); err != nil {
+		return 0, err
+	}
+
+	return task.ID, nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// publishTimeBlock avisa que o bloco da tarefa mudou (0 = nenhum)
+func (s *TaskService) publishTimeBlock(ctx context.Context, blockID int) {
+	if blockID == 0 {
+		return
+	}
+
+	eventService := NewEventServiceWithStore(s.store, nil)
+	publishEntity(ctx, s.bus, events.EventUpdated, blockID, eventService.GetEventByID)
+}
@@ -0,0 +1,498 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"personal-cockpit/events"
+	"personal-cockpit/i18n"
+	"personal-cockpit/logging"
+	"personal-cockpit/models"
+	"personal-cockpit/repository"
+)
+
+// Espera antes de cada tentativa de entrega (a primeira é imediata)
+var webhookRetryDelays = []time.Duration{0, 10 * time.Second, time.Minute, 5 * time.Minute, 30 * time.Minute}
+
+// Quantas tentativas ficam no log de cada webhook
+const webhookDeliveryLogSize = 200
+
+// Tamanho da fila de entregas e quantas são feitas ao mesmo tempo
+const (
+	webhookQueueSize = 256
+	webhookWorkers   = 4
+)
+
+// webhookJob é uma tentativa de entrega esperando na fila
+type webhookJob struct {
+	webhook models.Webhook
+	event   events.Event
+	attempt int
+}
+
+// WebhookService gerencia os webhooks e entrega os eventos do barramento
+// para as URLs cadastradas, com assinatura HMAC e novas tentativas.
+type WebhookService struct {
+	store  repository.UnitOfWork
+	bus    *events.Bus
+	client *http.Client
+
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	unsubscribe func()
+	wg          sync.WaitGroup
+
+	// Webhooks ativos, lidos do banco na primeira entrega e de novo depois
+	// de cada alteração no cadastro
+	cacheMu  sync.Mutex
+	active   []models.Webhook
+	loaded   bool
+	revision int
+}
+
+// NewWebhookService cria novo serviço de webhooks (as entregas só começam com Start)
+func NewWebhookService(db *sql.DB, bus *events.Bus) *WebhookService {
+	return NewWebhookServiceWithStore(repository.NewSQLite(db), bus)
+}
+
+// NewWebhookServiceWithStore cria o serviço sobre outro Store (ex.: um falso nos testes)
+func NewWebhookServiceWithStore(store repository.UnitOfWork, bus *events.Bus) *WebhookService {
+	return &WebhookService{
+		store:  store,
+		bus:    bus,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ═══════════════════════════════════════════════════════════
+// CADASTRO
+// ═══════════════════════════════════════════════════════════
+
+// CreateWebhook cadastra um webhook. Se o segredo vier vazio, um é gerado.
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook models.Webhook) (_ int64, err error) {
+	defer logging.Track("WebhookService.CreateWebhook", &err)()
+
+	if err := validateWebhook(webhook); err != nil {
+		return 0, err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return 0, err
+		}
+		webhook.Secret = secret
+	}
+
+	id, err := s.store.Webhooks().Create(ctx, webhook)
+	if err != nil {
+		return 0, err
+	}
+
+	s.bus.Publish(events.WebhookCreated, int(id), nil)
+
+	return id, nil
+}
+
+// GetAllWebhooks retorna todos os webhooks
+func (s *WebhookService) GetAllWebhooks(ctx context.Context) (_ []models.Webhook, err error) {
+	defer logging.Track("WebhookService.GetAllWebhooks", &err)()
+
+	return s.listWebhooks(ctx, false)
+}
+
+// GetWebhookByID busca webhook por ID
+func (s *WebhookService) GetWebhookByID(ctx context.Context, id int) (_ *models.Webhook, err error) {
+	defer logging.Track("WebhookService.GetWebhookByID", &err)()
+
+	webhook, err := s.store.Webhooks().GetByID(ctx, id)
+	if err != nil {
+		return nil, fromRepository(err, "webhook", id)
+	}
+
+	return webhook, nil
+}
+
+// UpdateWebhook atualiza URL, filtros, segredo e status. Segredo vazio mantém o atual.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhook models.Webhook) (err error) {
+	defer logging.Track("WebhookService.UpdateWebhook", &err)()
+
+	if webhook.ID == 0 {
+		return requiredID("ID do webhook")
+	}
+
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	if err := s.store.Webhooks().Update(ctx, webhook); err != nil {
+		return fromRepository(err, "webhook", webhook.ID)
+	}
+
+	s.bus.Publish(events.WebhookUpdated, webhook.ID, nil)
+
+	return nil
+}
+
+// DeleteWebhook remove o webhook e o seu log de entregas
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int) (err error) {
+	defer logging.Track("WebhookService.DeleteWebhook", &err)()
+
+	err = s.store.WithTx(ctx, func(tx repository.Store) error {
+		return tx.Webhooks().Delete(ctx, id)
+	})
+	if err != nil {
+		return fromRepository(err, "webhook", id)
+	}
+
+	s.bus.Publish(events.WebhookDeleted, id, nil)
+
+	return nil
+}
+
+// GetDeliveries retorna as últimas tentativas de entrega do webhook
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID int, limit int) (_ []models.WebhookDelivery, err error) {
+	defer logging.Track("WebhookService.GetDeliveries", &err)()
+
+	if limit <= 0 || limit > webhookDeliveryLogSize {
+		limit = webhookDeliveryLogSize
+	}
+
+	deliveries, err := s.store.Webhooks().ListDeliveries(ctx, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+// ═══════════════════════════════════════════════════════════
+// ENTREGA
+// ═══════════════════════════════════════════════════════════
+
+// Start passa a entregar os eventos publicados no barramento. As entregas
+// entram numa fila única, atendida por webhookWorkers goroutines.
+func (s *WebhookService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.unsubscribe != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	queue := make(chan webhookJob, webhookQueueSize)
+	for i := 0; i < webhookWorkers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.work(ctx, queue)
+		}()
+	}
+
+	s.unsubscribe = s.bus.Subscribe(func(event events.Event) {
+		s.dispatch(ctx, queue, event)
+	})
+}
+
+// Stop para de ouvir o barramento e descarta as entregas e novas tentativas pendentes
+func (s *WebhookService) Stop() {
+	s.mu.Lock()
+	if s.unsubscribe == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.unsubscribe()
+	s.unsubscribe = nil
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// TestWebhook envia um evento "webhook.ping" uma única vez e retorna o resultado
+func (s *WebhookService) TestWebhook(ctx context.Context, id int) (_ *models.WebhookDelivery, err error) {
+	defer logging.Track("WebhookService.TestWebhook", &err)()
+
+	webhook, err := s.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	event := events.Event{
+		ID:         "ping-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		Type:       events.WebhookPing,
+		EntityID:   webhook.ID,
+		OccurredAt: time.Now().UTC(),
+	}
+
+	delivery, _ := s.attempt(ctx, webhook, event, 1)
+	return delivery, nil
+}
+
+// dispatch põe na fila uma entrega para cada webhook ativo cujo filtro casa
+// com o tipo. Mudanças nos próprios webhooks só renovam a lista.
+func (s *WebhookService) dispatch(ctx context.Context, queue chan<- webhookJob, event events.Event) {
+	switch event.Type {
+	case events.WebhookCreated, events.WebhookUpdated, events.WebhookDeleted:
+		s.invalidateWebhooks()
+		return
+	}
+
+	webhooks, err := s.activeWebhooks(ctx)
+	if err != nil {
+		slog.Error("erro ao buscar webhooks", "err", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if events.MatchesAny(webhook.Events, event.Type) {
+			s.enqueue(ctx, queue, webhookJob{webhook: webhook, event: event, attempt: 1})
+		}
+	}
+}
+
+// enqueue põe a entrega na fila sem bloquear; com a fila cheia ela é descartada
+func (s *WebhookService) enqueue(ctx context.Context, queue chan<- webhookJob, job webhookJob) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	select {
+	case queue <- job:
+	default:
+		slog.Warn("fila de webhooks cheia, entrega descartada", "webhook", job.webhook.ID, "event", job.event.ID)
+	}
+}
+
+// work atende a fila até o serviço parar. Uma tentativa que falha volta para
+// a fila depois da espera seguinte de webhookRetryDelays.
+func (s *WebhookService) work(ctx context.Context, queue chan webhookJob) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-queue:
+			_, retry := s.attempt(ctx, &job.webhook, job.event, job.attempt)
+			if !retry || job.attempt >= len(webhookRetryDelays) {
+				continue
+			}
+
+			delay := webhookRetryDelays[job.attempt]
+			job.attempt++
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				select {
+				case <-ctx.Done():
+				case <-time.After(delay):
+					s.enqueue(ctx, queue, job)
+				}
+			}()
+		}
+	}
+}
+
+// attempt faz uma tentativa de entrega e registra no log. Retorna se vale a
+// pena tentar de novo (falha de rede, timeout, 408, 429 ou 5xx).
+func (s *WebhookService) attempt(ctx context.Context, webhook *models.Webhook, event events.Event, attempt int) (*models.WebhookDelivery, bool) {
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: string(event.Type),
+		Attempt:   attempt,
+		CreatedAt: time.Now(),
+	}
+
+	retry := false
+	started := time.Now()
+
+	statusCode, err := s.post(ctx, webhook, event)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+		retry = ctx.Err() == nil
+	case statusCode >= 200 && statusCode < 300:
+		delivery.StatusCode = &statusCode
+		delivery.Success = true
+	default:
+		delivery.StatusCode = &statusCode
+		delivery.Error = fmt.Sprintf("resposta HTTP %d", statusCode)
+		retry = statusCode >= 500 || statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+	}
+
+	if err := s.logDelivery(ctx, delivery); err != nil {
+		slog.Error("erro ao registrar entrega de webhook", "webhook", webhook.ID, "err", err)
+	}
+
+	return delivery, retry
+}
+
+// post envia o evento assinado. A assinatura é o HMAC-SHA256 de
+// "<timestamp>.<corpo>" com o segredo do webhook, em hexadecimal.
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, event events.Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, i18n.Errorf("erro ao gerar JSON: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, i18n.Errorf("erro ao montar requisição: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PersonalCockpit-Webhook/1.0")
+	req.Header.Set("X-Cockpit-Event", string(event.Type))
+	req.Header.Set("X-Cockpit-Delivery", event.ID)
+	req.Header.Set("X-Cockpit-Timestamp", timestamp)
+	req.Header.Set("X-Cockpit-Signature", "sha256="+SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload calcula a assinatura enviada em X-Cockpit-Signature
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookService) logDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	// Mantém só as últimas entregas de cada webhook
+	return s.store.Webhooks().LogDelivery(ctx, delivery, webhookDeliveryLogSize)
+}
+
+// ═══════════════════════════════════════════════════════════
+// AUXILIARES
+// ═══════════════════════════════════════════════════════════
+
+// activeWebhooks retorna os webhooks ativos, do cache quando possível
+func (s *WebhookService) activeWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	s.cacheMu.Lock()
+	if s.loaded {
+		webhooks := s.active
+		s.cacheMu.Unlock()
+		return webhooks, nil
+	}
+	revision := s.revision
+	s.cacheMu.Unlock()
+
+	webhooks, err := s.listWebhooks(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Só guarda se o cadastro não mudou durante a leitura
+	s.cacheMu.Lock()
+	if s.revision == revision {
+		s.active = webhooks
+		s.loaded = true
+	}
+	s.cacheMu.Unlock()
+
+	return webhooks, nil
+}
+
+// invalidateWebhooks faz a próxima entrega ler os webhooks do banco de novo
+func (s *WebhookService) invalidateWebhooks() {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	s.active = nil
+	s.loaded = false
+	s.revision++
+}
+
+func (s *WebhookService) listWebhooks(ctx context.Context, activeOnly bool) ([]models.Webhook, error) {
+	webhooks, err := s.store.Webhooks().List(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+
+	return webhooks, nil
+}
+
+func validateWebhook(webhook models.Webhook) error {
+	erros := &ValidationError{}
+
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		erros.Invalid("url", CodeInvalid, "URL (http:// ou https://)")
+	}
+
+	for _, filter := range webhook.Events {
+		if strings.TrimSpace(filter) != "" && !validEventFilter(filter) {
+			erros.Invalid("events", CodeInvalidChoice, i18n.Sprintf("evento desconhecido: %s", filter))
+		}
+	}
+
+	if erros.HasErrors() {
+		return erros
+	}
+
+	return nil
+}
+
+// validEventFilter aceita "*", um tipo conhecido ou "entidade.*"
+func validEventFilter(filter string) bool {
+	filter = strings.TrimSpace(filter)
+	if filter == "*" {
+		return true
+	}
+
+	for _, eventType := range events.AllTypes {
+		if string(eventType) == filter || eventType.Entity()+".*" == filter {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", i18n.Errorf("erro ao gerar segredo: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
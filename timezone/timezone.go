@@ -0,0 +1,87 @@
+// Package timezone guarda o fuso horário do usuário. O banco grava tudo em
+// UTC; é aqui que "hoje", "esta semana" e os eventos de dia inteiro ganham
+// um fuso, na leitura e nas consultas por data.
+package timezone
+
+import (
+	"sync/atomic"
+	"time"
+
+	// Base de fusos embutida: no Windows não há /usr/share/zoneinfo
+	_ "time/tzdata"
+
+	"personal-cockpit/i18n"
+)
+
+// SettingTimezone é a chave da configuração com o fuso escolhido (nome IANA,
+// ex.: "America/Sao_Paulo"). Vazia, vale o fuso do sistema.
+const SettingTimezone = "timezone"
+
+// System é o nome exibido quando o fuso segue o do sistema
+const System = "Local"
+
+var current atomic.Pointer[time.Location]
+
+func init() {
+	current.Store(time.Local)
+}
+
+// Set troca o fuso em uso e retorna o nome aplicado. Nome vazio ou "Local"
+// volta para o fuso do sistema.
+func Set(name string) (string, error) {
+	if name == "" || name == System {
+		current.Store(time.Local)
+		return System, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return "", i18n.Errorf("fuso horário desconhecido: %s", name)
+	}
+
+	current.Store(location)
+	return location.String(), nil
+}
+
+// Location retorna o fuso em uso
+func Location() *time.Location {
+	return current.Load()
+}
+
+// Name retorna o nome do fuso em uso ("Local" se for o do sistema)
+func Name() string {
+	return Location().String()
+}
+
+// In converte o instante para o fuso em uso
+func In(t time.Time) time.Time {
+	return t.In(Location())
+}
+
+// StartOfDay é a meia-noite, no fuso em uso, do dia em que t cai
+func StartOfDay(t time.Time) time.Time {
+	t = In(t)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Location())
+}
+
+// Floating põe uma data "flutuante" (sem fuso, como a de um evento de dia
+// inteiro) à meia-noite do fuso em uso: 19/10 é 19/10 em qualquer fuso.
+func Floating(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, Location())
+}
+
+// DateOf é o dia de um evento de dia inteiro, à meia-noite do fuso em uso.
+// Meia-noite no próprio fuso de t ("2026-10-19T00:00:00Z" de um cliente da
+// API) já é a data; qualquer outro horário é um instante (a meia-noite local
+// do navegador enviada em UTC), e vale o dia em que ele cai no fuso em uso.
+func DateOf(t time.Time) time.Time {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return Floating(t)
+	}
+	return Floating(In(t))
+}
+
+// ParseDate lê "AAAA-MM-DD" como a meia-noite do dia no fuso em uso
+func ParseDate(date string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", date, Location())
+}